@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/discord-gophers/goapi-gen/pkg/changelog"
+	"github.com/discord-gophers/goapi-gen/pkg/config"
+	"github.com/urfave/cli/v2"
+)
+
+// diff writes the changelog between the two specs given to the diff command.
+// With --breaking-only, only the breaking changes are written, and it fails
+// if there are any, so that it can be used to check pull requests.
+func diff(c *cli.Context) error {
+	if c.Args().Len() != 2 {
+		return fmt.Errorf("expected an old and a new spec, got %d arguments", c.Args().Len())
+	}
+
+	maxRedirects := c.Int(MaxRedirectsKey)
+	cfg := &config.Config{HTTPTimeout: c.Duration(HTTPTimeoutKey), MaxRedirects: &maxRedirects}
+	oldSpec, err := loadSpec(c.Args().Get(0), cfg)
+	if err != nil {
+		return fmt.Errorf("could not load old spec: %v", err)
+	}
+	newSpec, err := loadSpec(c.Args().Get(1), cfg)
+	if err != nil {
+		return fmt.Errorf("could not load new spec: %v", err)
+	}
+
+	log := changelog.Compare(oldSpec, newSpec)
+	if c.Bool("breaking-only") {
+		log = log.BreakingOnly()
+	}
+
+	var write func(io.Writer) error
+	switch format := c.String("format"); format {
+	case "text":
+		write = log.WriteText
+	case "json":
+		write = log.WriteJSON
+	case "markdown":
+		write = log.WriteMarkdown
+	default:
+		return fmt.Errorf("unknown format %q, expected one of text, json, markdown", format)
+	}
+
+	out := os.Stdout
+	if c.IsSet("out") {
+		fi, err := os.Create(c.String("out"))
+		if err != nil {
+			return fmt.Errorf("could not create changelog: %v", err)
+		}
+		defer fi.Close()
+		out = fi
+	}
+	if err := write(out); err != nil {
+		return fmt.Errorf("could not write changelog: %v", err)
+	}
+
+	if c.Bool("breaking-only") && log.HasBreaking() {
+		return cli.Exit(fmt.Sprintf("error: %d breaking changes", len(log.Changes)), 1)
+	}
+	return nil
+}
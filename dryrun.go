@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// errOutOfDate is returned by a dry run when generated files differ from the
+// files on disk.
+var errOutOfDate = errors.New("generated code is out of date")
+
+// fileWriter writes the generated files. On a dry run, it prints how they
+// differ from the files on disk instead.
+type fileWriter struct {
+	dryRun bool
+	out    io.Writer
+
+	// changed is set when a dry run found a file that differs.
+	changed bool
+}
+
+// WriteFile writes data to the file name, or prints a unified diff of the
+// file on disk against data on a dry run.
+func (w *fileWriter) WriteFile(name string, data []byte) error {
+	if !w.dryRun {
+		return os.WriteFile(name, data, 0o644)
+	}
+
+	current, err := os.ReadFile(name)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if string(current) == string(data) {
+		return nil
+	}
+	w.changed = true
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(current),
+		B:        splitLines(data),
+		FromFile: name,
+		ToFile:   name,
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(w.out, diff)
+	return err
+}
+
+// splitLines splits data into the lines for a diff. Empty data, such as a
+// file which doesn't exist yet, has no lines.
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return difflib.SplitLines(string(data))
+}
+
+// Err returns errOutOfDate if a dry run found a file that differs.
+func (w *fileWriter) Err() error {
+	if w.changed {
+		return errOutOfDate
+	}
+	return nil
+}
@@ -1,20 +1,25 @@
 // Package api provides primitives to interact with the openapi HTTP API.
 //
 // Code generated by github.com/discord-gophers/goapi-gen version (devel) DO NOT EDIT.
+//
+//nolint:revive,godot
 package api
 
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
 
+	"github.com/discord-gophers/goapi-gen/pkg/apierrors"
 	"github.com/discord-gophers/goapi-gen/pkg/runtime"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
@@ -57,6 +62,74 @@ type FindPetsParams struct {
 // AddPetJSONBody defines parameters for AddPet.
 type AddPetJSONBody NewPet
 
+// DeletePetPathParams defines parameters for DeletePet.
+type DeletePetPathParams struct {
+	// ID of pet to delete
+	ID int64 `json:"id"`
+}
+
+// FindPetByIDPathParams defines parameters for FindPetByID.
+type FindPetByIDPathParams struct {
+	// ID of pet to fetch
+	ID int64 `json:"id"`
+}
+
+// BindDeletePetPathParams binds the path parameters of DeletePet from rctx.
+func BindDeletePetPathParams(rctx *chi.Context) (*DeletePetPathParams, error) { //nolint:wsl,cyclop
+	var params DeletePetPathParams
+
+	// ------------- Path parameter "id" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "id", rctx.URLParam("id"), &params.ID); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter id: %w", err)
+	}
+
+	return &params, nil
+}
+
+// DeletePetPathParamsFromRequest binds the path parameters of DeletePet from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func DeletePetPathParamsFromRequest(r *http.Request) (*DeletePetPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindDeletePetPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindFindPetByIDPathParams binds the path parameters of FindPetByID from rctx.
+func BindFindPetByIDPathParams(rctx *chi.Context) (*FindPetByIDPathParams, error) { //nolint:wsl,cyclop
+	var params FindPetByIDPathParams
+
+	// ------------- Path parameter "id" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "id", rctx.URLParam("id"), &params.ID); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter id: %w", err)
+	}
+
+	return &params, nil
+}
+
+// FindPetByIDPathParamsFromRequest binds the path parameters of FindPetByID from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func FindPetByIDPathParamsFromRequest(r *http.Request) (*FindPetByIDPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindFindPetByIDPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
 // AddPetJSONRequestBody defines body for AddPet for application/json ContentType.
 type AddPetJSONRequestBody AddPetJSONBody
 
@@ -175,18 +248,95 @@ func FindPetByIDJSONDefaultResponse(body Error) *Response {
 	}
 }
 
+// Override default JSON handling for Pet to merge the objects of its
+// allOf schemas. Fields found in several of them take the value of the last one.
+func (a Pet) MarshalJSON() ([]byte, error) {
+	var err error
+	var part []byte
+	object := make(map[string]json.RawMessage)
+
+	part, err = json.Marshal(a.NewPet)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling 'NewPet': %w", err)
+	}
+	err = json.Unmarshal(part, &object)
+	if err != nil {
+		return nil, fmt.Errorf("error merging 'NewPet': %w", err)
+	}
+
+	object["id"], err = json.Marshal(a.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling 'id': %w", err)
+	}
+
+	return json.Marshal(object)
+}
+
+// Override default JSON handling for Pet to read every one of its
+// allOf schemas from the same object.
+func (a *Pet) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(b, &a.NewPet)
+	if err != nil {
+		return fmt.Errorf("error reading 'NewPet': %w", err)
+	}
+
+	if raw, found := object["id"]; found {
+		err = json.Unmarshal(raw, &a.ID)
+		if err != nil {
+			return fmt.Errorf("error reading 'id': %w", err)
+		}
+	}
+
+	return nil
+}
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 	// Returns all pets
+	//
+	// Returns all pets from the system that the user has access to
+	// Nam sed condimentum est. Maecenas tempor sagittis sapien, nec rhoncus sem
+	// sagittis sit amet. Aenean at gravida augue, ac iaculis sem. Curabitur odio
+	// lorem, ornare eget elementum nec, cursus id lectus. Duis mi turpis, pulvinar
+	// ac eros ac, tincidunt varius justo. In hac habitasse platea dictumst. Integer
+	// at adipiscing ante, a sagittis ligula. Aenean pharetra tempor ante molestie
+	// imperdiet. Vivamus id aliquam diam. Cras quis velit non tortor eleifend
+	// sagittis. Praesent at enim pharetra urna volutpat venenatis eget eget mauris.
+	// In eleifend fermentum facilisis. Praesent enim enim, gravida ac sodales sed,
+	// placerat id erat. Suspendisse lacus dolor, consectetur non augue vel,
+	// vehicula interdum libero. Morbi euismod sagittis libero sed lacinia.
+	//
+	// Sed tempus felis lobortis leo pulvinar rutrum. Nam mattis velit nisl, eu
+	// condimentum ligula luctus nec. Phasellus semper velit eget aliquet faucibus.
+	// In a mattis elit. Phasellus vel urna viverra, condimentum lorem id, rhoncus
+	// nibh. Ut pellentesque posuere elementum. Sed a varius odio. Morbi rhoncus
+	// ligula libero, vel eleifend nunc tristique vitae. Fusce et sem dui. Aenean
+	// nec scelerisque tortor. Fusce malesuada accumsan magna vel tempus. Quisque
+	// mollis felis eu dolor tristique, sit amet auctor felis gravida. Sed libero
+	// lorem, molestie sed nisl in, accumsan tempor nisi. Fusce sollicitudin massa
+	// ut lacinia mattis. Sed vel eleifend lorem. Pellentesque vitae felis pretium,
+	// pulvinar elit eu, euismod sapien.
 	// (GET /pets)
 	FindPets(w http.ResponseWriter, r *http.Request, params FindPetsParams)
 	// Creates a new pet
+	//
+	// Creates a new pet in the store. Duplicates are allowed
 	// (POST /pets)
 	AddPet(w http.ResponseWriter, r *http.Request)
 	// Deletes a pet by ID
+	//
+	// deletes a single pet based on the ID supplied
 	// (DELETE /pets/{id})
 	DeletePet(w http.ResponseWriter, r *http.Request, id int64)
 	// Returns a pet by ID
+	//
+	// Returns a pet based on a single ID
 	// (GET /pets/{id})
 	FindPetByID(w http.ResponseWriter, r *http.Request, id int64)
 }
@@ -199,8 +349,8 @@ type ServerInterfaceWrapper struct {
 }
 
 // FindPets operation middleware
-func (siw *ServerInterfaceWrapper) FindPets(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) FindPets(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params FindPetsParams
@@ -225,23 +375,57 @@ func (siw *ServerInterfaceWrapper) FindPets(w http.ResponseWriter, r *http.Reque
 		siw.Handler.FindPets(w, r, params)
 	})
 
+	handler = FindPetsContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// FindPetsContext is middleware setting the metadata of the FindPets
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func FindPetsContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "findPets",
+			tags: nil,
+			path: "/pets",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // AddPet operation middleware
-func (siw *ServerInterfaceWrapper) AddPet(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) AddPet(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.AddPet(w, r)
 	})
 
+	handler = AddPetContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// AddPetContext is middleware setting the metadata of the AddPet
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func AddPetContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "addPet",
+			tags: nil,
+			path: "/pets",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // DeletePet operation middleware
-func (siw *ServerInterfaceWrapper) DeletePet(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) DeletePet(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "id" -------------
 	var id int64
@@ -256,12 +440,29 @@ func (siw *ServerInterfaceWrapper) DeletePet(w http.ResponseWriter, r *http.Requ
 		siw.Handler.DeletePet(w, r, id)
 	})
 
+	handler = DeletePetContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// DeletePetContext is middleware setting the metadata of the DeletePet
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func DeletePetContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "deletePet",
+			tags: nil,
+			path: "/pets/{id}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // FindPetByID operation middleware
-func (siw *ServerInterfaceWrapper) FindPetByID(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) FindPetByID(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "id" -------------
 	var id int64
@@ -276,9 +477,84 @@ func (siw *ServerInterfaceWrapper) FindPetByID(w http.ResponseWriter, r *http.Re
 		siw.Handler.FindPetByID(w, r, id)
 	})
 
+	handler = FindPetByIDContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// FindPetByIDContext is middleware setting the metadata of the FindPetByID
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func FindPetByIDContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "findPetByID",
+			tags: nil,
+			path: "/pets/{id}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// operationContextKey is the context key of the operationMetadata set by the
+// <OperationID>Context middleware.
+type operationContextKey struct{}
+
+// operationMetadata describes the operation of a request.
+type operationMetadata struct {
+	id   string
+	tags []string
+	path string
+}
+
+// OperationIDFromContext returns the operationId of the operation of the
+// request of ctx, or "" outside of the operations of the server.
+func OperationIDFromContext(ctx context.Context) string {
+	op, _ := ctx.Value(operationContextKey{}).(operationMetadata)
+	return op.id
+}
+
+// OperationTagsFromContext returns the tags of the operation of the request
+// of ctx, which the caller must not modify.
+func OperationTagsFromContext(ctx context.Context) []string {
+	op, _ := ctx.Value(operationContextKey{}).(operationMetadata)
+	return op.tags
+}
+
+// OperationPathFromContext returns the path template of the operation of the
+// request of ctx in the spec, like /pets/{id}.
+func OperationPathFromContext(ctx context.Context) string {
+	op, _ := ctx.Value(operationContextKey{}).(operationMetadata)
+	return op.path
+}
+
+// errorHandlerContextKey is the context key of the ErrorHandlerFunc of the
+// server, for HandleError.
+type errorHandlerContextKey struct{}
+
+// HandleError writes err, which a handler failed with, with the
+// ErrorHandlerFunc of the server of r. Handlers pass an
+// *apierrors.APIError, like apierrors.ErrNotFound, to respond with its
+// status code; other errors are responded to with an HTTP/500.
+func HandleError(w http.ResponseWriter, r *http.Request, err error) {
+	if !errors.As(err, new(*apierrors.APIError)) {
+		err = apierrors.Wrap(http.StatusInternalServerError, err)
+	}
+	handle, ok := r.Context().Value(errorHandlerContextKey{}).(func(w http.ResponseWriter, r *http.Request, err error))
+	if !ok || handle == nil {
+		handle = defaultErrorHandler
+	}
+	handle(w, r, err)
+}
+
+// defaultErrorHandler writes the errors of the server as plain text, with
+// the status code of an *apierrors.APIError, or an HTTP/400 for the errors
+// binding the parameters of requests.
+func defaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	apierrors.Write(w, err, http.StatusBadRequest)
+}
+
 type UnescapedCookieParamError struct {
 	error
 }
@@ -303,19 +579,23 @@ type ServerOptions struct {
 	BaseRouter       chi.Router
 	Middlewares      map[string]func(http.Handler) http.Handler
 	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+	// Tag restricts the routes to the operations with this tag, none if
+	// empty.
+	Tag string
 }
 
 type ServerOption func(*ServerOptions)
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface, opts ...ServerOption) http.Handler {
+// Handler creates http.Handler with routing matching OpenAPI spec. The routes
+// are registered on a chi router of its own, unless another one is given
+// with WithRouter, so that the handler can be served as it is, by an
+// http.Server or httptest.NewServer.
+func Handler(si ServerInterface, opts ...ServerOption) http.Handler { //nolint:wsl,cyclop
 	options := &ServerOptions{
-		BaseURL:     "/",
-		BaseRouter:  chi.NewRouter(),
-		Middlewares: make(map[string]func(http.Handler) http.Handler),
-		ErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		},
+		BaseURL:          "/",
+		BaseRouter:       chi.NewRouter(),
+		Middlewares:      make(map[string]func(http.Handler) http.Handler),
+		ErrorHandlerFunc: defaultErrorHandler,
 	}
 
 	for _, f := range opts {
@@ -329,6 +609,10 @@ func Handler(si ServerInterface, opts ...ServerOption) http.Handler {
 		ErrorHandlerFunc: options.ErrorHandlerFunc,
 	}
 
+	if options.Tag != "" {
+		panic("goapi-gen: no operation is tagged " + options.Tag)
+	}
+
 	r.Route(options.BaseURL, func(r chi.Router) {
 		r.Get("/pets", wrapper.FindPets)
 		r.Post("/pets", wrapper.AddPet)
@@ -339,6 +623,25 @@ func Handler(si ServerInterface, opts ...ServerOption) http.Handler {
 	return r
 }
 
+// HandlerFromMux registers the routes of the OpenAPI spec on r, and returns r.
+func HandlerFromMux(r chi.Router, si ServerInterface, opts ...ServerOption) http.Handler {
+	return Handler(si, append([]ServerOption{WithRouter(r)}, opts...)...)
+}
+
+// HandlerFromMuxWithBaseURL registers the routes of the OpenAPI spec on r,
+// under baseURL, and returns r.
+func HandlerFromMuxWithBaseURL(r chi.Router, si ServerInterface, baseURL string, opts ...ServerOption) http.Handler {
+	return Handler(si, append([]ServerOption{WithRouter(r), WithServerBaseURL(baseURL)}, opts...)...)
+}
+
+// HandlerFromMuxForTag registers the routes of the operations of the OpenAPI
+// spec tagged with tag on r, under baseURL, and returns r. The routes of
+// different tags can be registered on the groups of a router, each with its
+// own middleware stack.
+func HandlerFromMuxForTag(r chi.Router, si ServerInterface, tag string, baseURL string, opts ...ServerOption) http.Handler {
+	return Handler(si, append([]ServerOption{WithRouter(r), WithServerBaseURL(baseURL), WithTag(tag)}, opts...)...)
+}
+
 func WithRouter(r chi.Router) ServerOption {
 	return func(s *ServerOptions) {
 		s.BaseRouter = r
@@ -351,6 +654,13 @@ func WithServerBaseURL(url string) ServerOption {
 	}
 }
 
+// WithTag restricts the routes to the operations tagged with tag.
+func WithTag(tag string) ServerOption {
+	return func(s *ServerOptions) {
+		s.Tag = tag
+	}
+}
+
 func WithMiddleware(key string, middleware func(http.Handler) http.Handler) ServerOption {
 	return func(s *ServerOptions) {
 		s.Middlewares[key] = middleware
@@ -1,20 +1,25 @@
 // Package components provides primitives to interact with the openapi HTTP API.
 //
 // Code generated by github.com/discord-gophers/goapi-gen version (devel) DO NOT EDIT.
+//
+//nolint:revive,godot
 package components
 
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
 
+	"github.com/discord-gophers/goapi-gen/pkg/apierrors"
 	"github.com/discord-gophers/goapi-gen/pkg/runtime"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
@@ -30,6 +35,8 @@ type AdditionalPropertiesObject1 struct {
 }
 
 // Does not allow additional properties
+//
+// Additional properties are rejected by the spec, and dropped when unmarshaling.
 type AdditionalPropertiesObject2 struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
@@ -59,6 +66,18 @@ type AdditionalPropertiesObject5 struct {
 	AdditionalProperties map[string]SchemaObject `json:"-"`
 }
 
+// Cat defines model for Cat.
+type Cat struct {
+	Meows   *bool  `json:"meows,omitempty"`
+	PetType string `json:"petType"`
+}
+
+// Dog defines model for Dog.
+type Dog struct {
+	Barks   *bool  `json:"barks,omitempty"`
+	PetType string `json:"petType"`
+}
+
 // ObjectWithJSONField defines model for ObjectWithJsonField.
 type ObjectWithJSONField struct {
 	Name   string          `json:"name"`
@@ -66,6 +85,11 @@ type ObjectWithJSONField struct {
 	Value2 json.RawMessage `json:"value2,omitempty"`
 }
 
+// Pet defines model for Pet.
+type Pet struct {
+	Value PetVariant
+}
+
 // SchemaObject defines model for SchemaObject.
 type SchemaObject struct {
 	FirstName string `json:"firstName"`
@@ -116,6 +140,14 @@ type BodyWithAddPropsJSONBody_Inner struct {
 	AdditionalProperties map[string]int `json:"-"`
 }
 
+// AddPetJSONBody defines parameters for AddPet.
+type AddPetJSONBody struct {
+	Value AddPetJSONBodyVariant
+}
+
+// ReplacePetJSONBody defines parameters for ReplacePet.
+type ReplacePetJSONBody = Pet
+
 // EnsureEverythingIsReferencedJSONRequestBody defines body for EnsureEverythingIsReferenced for application/json ContentType.
 type EnsureEverythingIsReferencedJSONRequestBody RequestBody
 
@@ -132,6 +164,12 @@ func (BodyWithAddPropsJSONRequestBody) Bind(*http.Request) error {
 	return nil
 }
 
+// AddPetJSONRequestBody defines body for AddPet for application/json ContentType.
+type AddPetJSONRequestBody = AddPetJSONBody
+
+// ReplacePetJSONRequestBody defines body for ReplacePet for application/json ContentType.
+type ReplacePetJSONRequestBody = ReplacePetJSONBody
+
 // Response is a common response struct for all the API calls.
 // A Response object may be instantiated via functions for specific operation responses.
 type Response struct {
@@ -448,6 +486,54 @@ func (a BodyWithAddPropsJSONBody_Inner) MarshalJSON() ([]byte, error) {
 	return json.Marshal(object)
 }
 
+// AddPetJSONBodyVariant is implemented by every type a AddPetJSONBody can hold.
+type AddPetJSONBodyVariant interface {
+	isAddPetJSONBody()
+}
+
+func (Cat) isAddPetJSONBody() {}
+
+func (Dog) isAddPetJSONBody() {}
+
+// UnmarshalJSON implements json.Unmarshaler. The "petType" property
+// decides which variant is decoded into AddPetJSONBody.Value.
+func (u *AddPetJSONBody) UnmarshalJSON(b []byte) error {
+	var discriminator struct {
+		Value string `json:"petType"`
+	}
+	if err := json.Unmarshal(b, &discriminator); err != nil {
+		return err
+	}
+
+	switch discriminator.Value {
+	case "Cat":
+		var v Cat
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		u.Value = v
+	case "Dog":
+		var v Dog
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		u.Value = v
+	default:
+		return fmt.Errorf("unknown petType %q for AddPetJSONBody", discriminator.Value)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. It marshals AddPetJSONBody.Value.
+func (u AddPetJSONBody) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.Value)
+}
+
+// Bind implements render.Binder.
+func (AddPetJSONBody) Bind(*http.Request) error {
+	return nil
+}
+
 // Getter for additional properties for AdditionalPropertiesObject1. Returns the specified
 // element and whether it was found
 func (a AdditionalPropertiesObject1) Get(fieldName string) (value int, found bool) {
@@ -806,17 +892,74 @@ func (a AdditionalPropertiesObject5) MarshalJSON() ([]byte, error) {
 	return json.Marshal(object)
 }
 
+// PetVariant is implemented by every type a Pet can hold.
+type PetVariant interface {
+	isPet()
+}
+
+func (Cat) isPet() {}
+
+func (Dog) isPet() {}
+
+// UnmarshalJSON implements json.Unmarshaler. The "petType" property
+// decides which variant is decoded into Pet.Value.
+func (u *Pet) UnmarshalJSON(b []byte) error {
+	var discriminator struct {
+		Value string `json:"petType"`
+	}
+	if err := json.Unmarshal(b, &discriminator); err != nil {
+		return err
+	}
+
+	switch discriminator.Value {
+	case "Cat":
+		var v Cat
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		u.Value = v
+	case "Dog":
+		var v Dog
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		u.Value = v
+	default:
+		return fmt.Errorf("unknown petType %q for Pet", discriminator.Value)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. It marshals Pet.Value.
+func (u Pet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.Value)
+}
+
+// Bind implements render.Binder.
+func (Pet) Bind(*http.Request) error {
+	return nil
+}
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
-
+	// This endpoint exists so that components can be created in this
+	// spec and not be pruned
 	// (GET /ensure-everything-is-referenced)
 	EnsureEverythingIsReferenced(w http.ResponseWriter, r *http.Request)
-
+	// A path with parameters and a body which require additional properties
 	// (GET /params_with_add_props)
 	ParamsWithAddProps(w http.ResponseWriter, r *http.Request, params ParamsWithAddPropsParams)
-
+	// Has a request body which contains a direct additionalProperties, and
+	// an anonymous inner property with additionalProperties
 	// (POST /params_with_add_props)
 	BodyWithAddProps(w http.ResponseWriter, r *http.Request)
+	// Has an inline discriminated union as its request body, which has to
+	// keep the JSON methods of the union to be decoded
+	// (POST /pets)
+	AddPet(w http.ResponseWriter, r *http.Request)
+	// Has a reference to a discriminated union as its request body
+	// (PUT /pets)
+	ReplacePet(w http.ResponseWriter, r *http.Request)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -827,19 +970,36 @@ type ServerInterfaceWrapper struct {
 }
 
 // EnsureEverythingIsReferenced operation middleware
-func (siw *ServerInterfaceWrapper) EnsureEverythingIsReferenced(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) EnsureEverythingIsReferenced(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.EnsureEverythingIsReferenced(w, r)
 	})
 
+	handler = EnsureEverythingIsReferencedContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// EnsureEverythingIsReferencedContext is middleware setting the metadata of the EnsureEverythingIsReferenced
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func EnsureEverythingIsReferencedContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "ensureEverythingIsReferenced",
+			tags: nil,
+			path: "/ensure-everything-is-referenced",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // ParamsWithAddProps operation middleware
-func (siw *ServerInterfaceWrapper) ParamsWithAddProps(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) ParamsWithAddProps(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params ParamsWithAddPropsParams
@@ -864,20 +1024,168 @@ func (siw *ServerInterfaceWrapper) ParamsWithAddProps(w http.ResponseWriter, r *
 		siw.Handler.ParamsWithAddProps(w, r, params)
 	})
 
+	handler = ParamsWithAddPropsContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// ParamsWithAddPropsContext is middleware setting the metadata of the ParamsWithAddProps
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func ParamsWithAddPropsContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "ParamsWithAddProps",
+			tags: nil,
+			path: "/params_with_add_props",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // BodyWithAddProps operation middleware
-func (siw *ServerInterfaceWrapper) BodyWithAddProps(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) BodyWithAddProps(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.BodyWithAddProps(w, r)
 	})
 
+	handler = BodyWithAddPropsContext(handler).ServeHTTP
+
+	handler(w, r.WithContext(ctx))
+}
+
+// BodyWithAddPropsContext is middleware setting the metadata of the BodyWithAddProps
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func BodyWithAddPropsContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "BodyWithAddProps",
+			tags: nil,
+			path: "/params_with_add_props",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// AddPet operation middleware
+func (siw *ServerInterfaceWrapper) AddPet(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.AddPet(w, r)
+	})
+
+	handler = AddPetContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// AddPetContext is middleware setting the metadata of the AddPet
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func AddPetContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "AddPet",
+			tags: nil,
+			path: "/pets",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ReplacePet operation middleware
+func (siw *ServerInterfaceWrapper) ReplacePet(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ReplacePet(w, r)
+	})
+
+	handler = ReplacePetContext(handler).ServeHTTP
+
+	handler(w, r.WithContext(ctx))
+}
+
+// ReplacePetContext is middleware setting the metadata of the ReplacePet
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func ReplacePetContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "ReplacePet",
+			tags: nil,
+			path: "/pets",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// operationContextKey is the context key of the operationMetadata set by the
+// <OperationID>Context middleware.
+type operationContextKey struct{}
+
+// operationMetadata describes the operation of a request.
+type operationMetadata struct {
+	id   string
+	tags []string
+	path string
+}
+
+// OperationIDFromContext returns the operationId of the operation of the
+// request of ctx, or "" outside of the operations of the server.
+func OperationIDFromContext(ctx context.Context) string {
+	op, _ := ctx.Value(operationContextKey{}).(operationMetadata)
+	return op.id
+}
+
+// OperationTagsFromContext returns the tags of the operation of the request
+// of ctx, which the caller must not modify.
+func OperationTagsFromContext(ctx context.Context) []string {
+	op, _ := ctx.Value(operationContextKey{}).(operationMetadata)
+	return op.tags
+}
+
+// OperationPathFromContext returns the path template of the operation of the
+// request of ctx in the spec, like /pets/{id}.
+func OperationPathFromContext(ctx context.Context) string {
+	op, _ := ctx.Value(operationContextKey{}).(operationMetadata)
+	return op.path
+}
+
+// errorHandlerContextKey is the context key of the ErrorHandlerFunc of the
+// server, for HandleError.
+type errorHandlerContextKey struct{}
+
+// HandleError writes err, which a handler failed with, with the
+// ErrorHandlerFunc of the server of r. Handlers pass an
+// *apierrors.APIError, like apierrors.ErrNotFound, to respond with its
+// status code; other errors are responded to with an HTTP/500.
+func HandleError(w http.ResponseWriter, r *http.Request, err error) {
+	if !errors.As(err, new(*apierrors.APIError)) {
+		err = apierrors.Wrap(http.StatusInternalServerError, err)
+	}
+	handle, ok := r.Context().Value(errorHandlerContextKey{}).(func(w http.ResponseWriter, r *http.Request, err error))
+	if !ok || handle == nil {
+		handle = defaultErrorHandler
+	}
+	handle(w, r, err)
+}
+
+// defaultErrorHandler writes the errors of the server as plain text, with
+// the status code of an *apierrors.APIError, or an HTTP/400 for the errors
+// binding the parameters of requests.
+func defaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	apierrors.Write(w, err, http.StatusBadRequest)
+}
+
 type UnescapedCookieParamError struct {
 	error
 }
@@ -902,19 +1210,23 @@ type ServerOptions struct {
 	BaseRouter       chi.Router
 	Middlewares      map[string]func(http.Handler) http.Handler
 	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+	// Tag restricts the routes to the operations with this tag, none if
+	// empty.
+	Tag string
 }
 
 type ServerOption func(*ServerOptions)
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface, opts ...ServerOption) http.Handler {
+// Handler creates http.Handler with routing matching OpenAPI spec. The routes
+// are registered on a chi router of its own, unless another one is given
+// with WithRouter, so that the handler can be served as it is, by an
+// http.Server or httptest.NewServer.
+func Handler(si ServerInterface, opts ...ServerOption) http.Handler { //nolint:wsl,cyclop
 	options := &ServerOptions{
-		BaseURL:     "/",
-		BaseRouter:  chi.NewRouter(),
-		Middlewares: make(map[string]func(http.Handler) http.Handler),
-		ErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		},
+		BaseURL:          "/",
+		BaseRouter:       chi.NewRouter(),
+		Middlewares:      make(map[string]func(http.Handler) http.Handler),
+		ErrorHandlerFunc: defaultErrorHandler,
 	}
 
 	for _, f := range opts {
@@ -928,15 +1240,40 @@ func Handler(si ServerInterface, opts ...ServerOption) http.Handler {
 		ErrorHandlerFunc: options.ErrorHandlerFunc,
 	}
 
+	if options.Tag != "" {
+		panic("goapi-gen: no operation is tagged " + options.Tag)
+	}
+
 	r.Route(options.BaseURL, func(r chi.Router) {
 		r.Get("/ensure-everything-is-referenced", wrapper.EnsureEverythingIsReferenced)
 		r.Get("/params_with_add_props", wrapper.ParamsWithAddProps)
 		r.Post("/params_with_add_props", wrapper.BodyWithAddProps)
+		r.Post("/pets", wrapper.AddPet)
+		r.Put("/pets", wrapper.ReplacePet)
 
 	})
 	return r
 }
 
+// HandlerFromMux registers the routes of the OpenAPI spec on r, and returns r.
+func HandlerFromMux(r chi.Router, si ServerInterface, opts ...ServerOption) http.Handler {
+	return Handler(si, append([]ServerOption{WithRouter(r)}, opts...)...)
+}
+
+// HandlerFromMuxWithBaseURL registers the routes of the OpenAPI spec on r,
+// under baseURL, and returns r.
+func HandlerFromMuxWithBaseURL(r chi.Router, si ServerInterface, baseURL string, opts ...ServerOption) http.Handler {
+	return Handler(si, append([]ServerOption{WithRouter(r), WithServerBaseURL(baseURL)}, opts...)...)
+}
+
+// HandlerFromMuxForTag registers the routes of the operations of the OpenAPI
+// spec tagged with tag on r, under baseURL, and returns r. The routes of
+// different tags can be registered on the groups of a router, each with its
+// own middleware stack.
+func HandlerFromMuxForTag(r chi.Router, si ServerInterface, tag string, baseURL string, opts ...ServerOption) http.Handler {
+	return Handler(si, append([]ServerOption{WithRouter(r), WithServerBaseURL(baseURL), WithTag(tag)}, opts...)...)
+}
+
 func WithRouter(r chi.Router) ServerOption {
 	return func(s *ServerOptions) {
 		s.BaseRouter = r
@@ -949,6 +1286,13 @@ func WithServerBaseURL(url string) ServerOption {
 	}
 }
 
+// WithTag restricts the routes to the operations tagged with tag.
+func WithTag(tag string) ServerOption {
+	return func(s *ServerOptions) {
+		s.Tag = tag
+	}
+}
+
 func WithMiddleware(key string, middleware func(http.Handler) http.Handler) ServerOption {
 	return func(s *ServerOptions) {
 		s.Middlewares[key] = middleware
@@ -2,8 +2,12 @@ package components
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/go-chi/render"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -70,3 +74,20 @@ func TestAdditionalProperties(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, bossSchema, obj5.AdditionalProperties["boss"])
 }
+
+func TestUnionRequestBodies(t *testing.T) {
+	const buf = `{"petType":"Cat","meows":true}`
+	meows := true
+
+	var add AddPetJSONRequestBody
+	if assert.NoError(t, json.Unmarshal([]byte(buf), &add)) {
+		assert.Equal(t, Cat{PetType: "Cat", Meows: &meows}, add.Value)
+	}
+
+	var replace ReplacePetJSONRequestBody
+	r := httptest.NewRequest(http.MethodPut, "/pets", strings.NewReader(buf))
+	r.Header.Set("Content-Type", "application/json")
+	if assert.NoError(t, render.Bind(r, &replace)) {
+		assert.Equal(t, Cat{PetType: "Cat", Meows: &meows}, replace.Value)
+	}
+}
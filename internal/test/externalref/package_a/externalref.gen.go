@@ -1,6 +1,8 @@
 // Package package_a provides primitives to interact with the openapi HTTP API.
 //
 // Code generated by github.com/discord-gophers/goapi-gen version (devel) DO NOT EDIT.
+//
+//nolint:revive,godot
 package package_a
 
 import (
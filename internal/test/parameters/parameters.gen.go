@@ -1,20 +1,25 @@
 // Package parameters provides primitives to interact with the openapi HTTP API.
 //
 // Code generated by github.com/discord-gophers/goapi-gen version (devel) DO NOT EDIT.
+//
+//nolint:revive,godot
 package parameters
 
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
 
+	"github.com/discord-gophers/goapi-gen/pkg/apierrors"
 	"github.com/discord-gophers/goapi-gen/pkg/runtime"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
@@ -34,6 +39,11 @@ type Object struct {
 	Role      string `json:"role"`
 }
 
+// GetContentObjectPathParams defines parameters for GetContentObject.
+type GetContentObjectPathParams struct {
+	Param ComplexObject `json:"param"`
+}
+
 // GetCookieParams defines parameters for GetCookie.
 type GetCookieParams struct {
 	// primitive
@@ -88,6 +98,51 @@ type GetHeaderParams struct {
 	N1StartingWithNumber *string `json:"1-Starting-With-Number,omitempty"`
 }
 
+// GetLabelExplodeArrayPathParams defines parameters for GetLabelExplodeArray.
+type GetLabelExplodeArrayPathParams struct {
+	Param []int32 `json:"param"`
+}
+
+// GetLabelExplodeObjectPathParams defines parameters for GetLabelExplodeObject.
+type GetLabelExplodeObjectPathParams struct {
+	Param Object `json:"param"`
+}
+
+// GetLabelNoExplodeArrayPathParams defines parameters for GetLabelNoExplodeArray.
+type GetLabelNoExplodeArrayPathParams struct {
+	Param []int32 `json:"param"`
+}
+
+// GetLabelNoExplodeObjectPathParams defines parameters for GetLabelNoExplodeObject.
+type GetLabelNoExplodeObjectPathParams struct {
+	Param Object `json:"param"`
+}
+
+// GetMatrixExplodeArrayPathParams defines parameters for GetMatrixExplodeArray.
+type GetMatrixExplodeArrayPathParams struct {
+	ID []int32 `json:"id"`
+}
+
+// GetMatrixExplodeObjectPathParams defines parameters for GetMatrixExplodeObject.
+type GetMatrixExplodeObjectPathParams struct {
+	ID Object `json:"id"`
+}
+
+// GetMatrixNoExplodeArrayPathParams defines parameters for GetMatrixNoExplodeArray.
+type GetMatrixNoExplodeArrayPathParams struct {
+	ID []int32 `json:"id"`
+}
+
+// GetMatrixNoExplodeObjectPathParams defines parameters for GetMatrixNoExplodeObject.
+type GetMatrixNoExplodeObjectPathParams struct {
+	ID Object `json:"id"`
+}
+
+// GetPassThroughPathParams defines parameters for GetPassThrough.
+type GetPassThroughPathParams struct {
+	Param string `json:"param"`
+}
+
 // GetDeepObjectParams defines parameters for GetDeepObject.
 type GetDeepObjectParams struct {
 	// deep object
@@ -124,6 +179,480 @@ type GetQueryFormParams struct {
 	N1s *string `json:"1s,omitempty"`
 }
 
+// GetSimpleExplodeArrayPathParams defines parameters for GetSimpleExplodeArray.
+type GetSimpleExplodeArrayPathParams struct {
+	Param []int32 `json:"param"`
+}
+
+// GetSimpleExplodeObjectPathParams defines parameters for GetSimpleExplodeObject.
+type GetSimpleExplodeObjectPathParams struct {
+	Param Object `json:"param"`
+}
+
+// GetSimpleNoExplodeArrayPathParams defines parameters for GetSimpleNoExplodeArray.
+type GetSimpleNoExplodeArrayPathParams struct {
+	Param []int32 `json:"param"`
+}
+
+// GetSimpleNoExplodeObjectPathParams defines parameters for GetSimpleNoExplodeObject.
+type GetSimpleNoExplodeObjectPathParams struct {
+	Param Object `json:"param"`
+}
+
+// GetSimplePrimitivePathParams defines parameters for GetSimplePrimitive.
+type GetSimplePrimitivePathParams struct {
+	Param int32 `json:"param"`
+}
+
+// GetStartingWithNumberPathParams defines parameters for GetStartingWithNumber.
+type GetStartingWithNumberPathParams struct {
+	N1param string `json:"1param"`
+}
+
+// BindGetContentObjectPathParams binds the path parameters of GetContentObject from rctx.
+func BindGetContentObjectPathParams(rctx *chi.Context) (*GetContentObjectPathParams, error) { //nolint:wsl,cyclop
+	var params GetContentObjectPathParams
+
+	// ------------- Path parameter "param" -------------
+
+	if err := json.Unmarshal([]byte(rctx.URLParam("param")), &params.Param); err != nil {
+		return nil, fmt.Errorf("error unmarshaling parameter 'param' as JSON: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetContentObjectPathParamsFromRequest binds the path parameters of GetContentObject from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetContentObjectPathParamsFromRequest(r *http.Request) (*GetContentObjectPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetContentObjectPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetLabelExplodeArrayPathParams binds the path parameters of GetLabelExplodeArray from rctx.
+func BindGetLabelExplodeArrayPathParams(rctx *chi.Context) (*GetLabelExplodeArrayPathParams, error) { //nolint:wsl,cyclop
+	var params GetLabelExplodeArrayPathParams
+
+	// ------------- Path parameter "param" -------------
+
+	if err := runtime.BindStyledParameter("label", true, "param", rctx.URLParam("param"), &params.Param); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter param: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetLabelExplodeArrayPathParamsFromRequest binds the path parameters of GetLabelExplodeArray from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetLabelExplodeArrayPathParamsFromRequest(r *http.Request) (*GetLabelExplodeArrayPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetLabelExplodeArrayPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetLabelExplodeObjectPathParams binds the path parameters of GetLabelExplodeObject from rctx.
+func BindGetLabelExplodeObjectPathParams(rctx *chi.Context) (*GetLabelExplodeObjectPathParams, error) { //nolint:wsl,cyclop
+	var params GetLabelExplodeObjectPathParams
+
+	// ------------- Path parameter "param" -------------
+
+	if err := runtime.BindStyledParameter("label", true, "param", rctx.URLParam("param"), &params.Param); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter param: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetLabelExplodeObjectPathParamsFromRequest binds the path parameters of GetLabelExplodeObject from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetLabelExplodeObjectPathParamsFromRequest(r *http.Request) (*GetLabelExplodeObjectPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetLabelExplodeObjectPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetLabelNoExplodeArrayPathParams binds the path parameters of GetLabelNoExplodeArray from rctx.
+func BindGetLabelNoExplodeArrayPathParams(rctx *chi.Context) (*GetLabelNoExplodeArrayPathParams, error) { //nolint:wsl,cyclop
+	var params GetLabelNoExplodeArrayPathParams
+
+	// ------------- Path parameter "param" -------------
+
+	if err := runtime.BindStyledParameter("label", false, "param", rctx.URLParam("param"), &params.Param); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter param: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetLabelNoExplodeArrayPathParamsFromRequest binds the path parameters of GetLabelNoExplodeArray from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetLabelNoExplodeArrayPathParamsFromRequest(r *http.Request) (*GetLabelNoExplodeArrayPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetLabelNoExplodeArrayPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetLabelNoExplodeObjectPathParams binds the path parameters of GetLabelNoExplodeObject from rctx.
+func BindGetLabelNoExplodeObjectPathParams(rctx *chi.Context) (*GetLabelNoExplodeObjectPathParams, error) { //nolint:wsl,cyclop
+	var params GetLabelNoExplodeObjectPathParams
+
+	// ------------- Path parameter "param" -------------
+
+	if err := runtime.BindStyledParameter("label", false, "param", rctx.URLParam("param"), &params.Param); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter param: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetLabelNoExplodeObjectPathParamsFromRequest binds the path parameters of GetLabelNoExplodeObject from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetLabelNoExplodeObjectPathParamsFromRequest(r *http.Request) (*GetLabelNoExplodeObjectPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetLabelNoExplodeObjectPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetMatrixExplodeArrayPathParams binds the path parameters of GetMatrixExplodeArray from rctx.
+func BindGetMatrixExplodeArrayPathParams(rctx *chi.Context) (*GetMatrixExplodeArrayPathParams, error) { //nolint:wsl,cyclop
+	var params GetMatrixExplodeArrayPathParams
+
+	// ------------- Path parameter "id" -------------
+
+	if err := runtime.BindStyledParameter("matrix", true, "id", rctx.URLParam("id"), &params.ID); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter id: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetMatrixExplodeArrayPathParamsFromRequest binds the path parameters of GetMatrixExplodeArray from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetMatrixExplodeArrayPathParamsFromRequest(r *http.Request) (*GetMatrixExplodeArrayPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetMatrixExplodeArrayPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetMatrixExplodeObjectPathParams binds the path parameters of GetMatrixExplodeObject from rctx.
+func BindGetMatrixExplodeObjectPathParams(rctx *chi.Context) (*GetMatrixExplodeObjectPathParams, error) { //nolint:wsl,cyclop
+	var params GetMatrixExplodeObjectPathParams
+
+	// ------------- Path parameter "id" -------------
+
+	if err := runtime.BindStyledParameter("matrix", true, "id", rctx.URLParam("id"), &params.ID); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter id: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetMatrixExplodeObjectPathParamsFromRequest binds the path parameters of GetMatrixExplodeObject from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetMatrixExplodeObjectPathParamsFromRequest(r *http.Request) (*GetMatrixExplodeObjectPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetMatrixExplodeObjectPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetMatrixNoExplodeArrayPathParams binds the path parameters of GetMatrixNoExplodeArray from rctx.
+func BindGetMatrixNoExplodeArrayPathParams(rctx *chi.Context) (*GetMatrixNoExplodeArrayPathParams, error) { //nolint:wsl,cyclop
+	var params GetMatrixNoExplodeArrayPathParams
+
+	// ------------- Path parameter "id" -------------
+
+	if err := runtime.BindStyledParameter("matrix", false, "id", rctx.URLParam("id"), &params.ID); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter id: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetMatrixNoExplodeArrayPathParamsFromRequest binds the path parameters of GetMatrixNoExplodeArray from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetMatrixNoExplodeArrayPathParamsFromRequest(r *http.Request) (*GetMatrixNoExplodeArrayPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetMatrixNoExplodeArrayPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetMatrixNoExplodeObjectPathParams binds the path parameters of GetMatrixNoExplodeObject from rctx.
+func BindGetMatrixNoExplodeObjectPathParams(rctx *chi.Context) (*GetMatrixNoExplodeObjectPathParams, error) { //nolint:wsl,cyclop
+	var params GetMatrixNoExplodeObjectPathParams
+
+	// ------------- Path parameter "id" -------------
+
+	if err := runtime.BindStyledParameter("matrix", false, "id", rctx.URLParam("id"), &params.ID); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter id: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetMatrixNoExplodeObjectPathParamsFromRequest binds the path parameters of GetMatrixNoExplodeObject from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetMatrixNoExplodeObjectPathParamsFromRequest(r *http.Request) (*GetMatrixNoExplodeObjectPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetMatrixNoExplodeObjectPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetPassThroughPathParams binds the path parameters of GetPassThrough from rctx.
+func BindGetPassThroughPathParams(rctx *chi.Context) (*GetPassThroughPathParams, error) { //nolint:wsl,cyclop
+	var params GetPassThroughPathParams
+
+	// ------------- Path parameter "param" -------------
+
+	params.Param = rctx.URLParam("param")
+
+	return &params, nil
+}
+
+// GetPassThroughPathParamsFromRequest binds the path parameters of GetPassThrough from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetPassThroughPathParamsFromRequest(r *http.Request) (*GetPassThroughPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetPassThroughPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetSimpleExplodeArrayPathParams binds the path parameters of GetSimpleExplodeArray from rctx.
+func BindGetSimpleExplodeArrayPathParams(rctx *chi.Context) (*GetSimpleExplodeArrayPathParams, error) { //nolint:wsl,cyclop
+	var params GetSimpleExplodeArrayPathParams
+
+	// ------------- Path parameter "param" -------------
+
+	if err := runtime.BindStyledParameter("simple", true, "param", rctx.URLParam("param"), &params.Param); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter param: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetSimpleExplodeArrayPathParamsFromRequest binds the path parameters of GetSimpleExplodeArray from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetSimpleExplodeArrayPathParamsFromRequest(r *http.Request) (*GetSimpleExplodeArrayPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetSimpleExplodeArrayPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetSimpleExplodeObjectPathParams binds the path parameters of GetSimpleExplodeObject from rctx.
+func BindGetSimpleExplodeObjectPathParams(rctx *chi.Context) (*GetSimpleExplodeObjectPathParams, error) { //nolint:wsl,cyclop
+	var params GetSimpleExplodeObjectPathParams
+
+	// ------------- Path parameter "param" -------------
+
+	if err := runtime.BindStyledParameter("simple", true, "param", rctx.URLParam("param"), &params.Param); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter param: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetSimpleExplodeObjectPathParamsFromRequest binds the path parameters of GetSimpleExplodeObject from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetSimpleExplodeObjectPathParamsFromRequest(r *http.Request) (*GetSimpleExplodeObjectPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetSimpleExplodeObjectPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetSimpleNoExplodeArrayPathParams binds the path parameters of GetSimpleNoExplodeArray from rctx.
+func BindGetSimpleNoExplodeArrayPathParams(rctx *chi.Context) (*GetSimpleNoExplodeArrayPathParams, error) { //nolint:wsl,cyclop
+	var params GetSimpleNoExplodeArrayPathParams
+
+	// ------------- Path parameter "param" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "param", rctx.URLParam("param"), &params.Param); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter param: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetSimpleNoExplodeArrayPathParamsFromRequest binds the path parameters of GetSimpleNoExplodeArray from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetSimpleNoExplodeArrayPathParamsFromRequest(r *http.Request) (*GetSimpleNoExplodeArrayPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetSimpleNoExplodeArrayPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetSimpleNoExplodeObjectPathParams binds the path parameters of GetSimpleNoExplodeObject from rctx.
+func BindGetSimpleNoExplodeObjectPathParams(rctx *chi.Context) (*GetSimpleNoExplodeObjectPathParams, error) { //nolint:wsl,cyclop
+	var params GetSimpleNoExplodeObjectPathParams
+
+	// ------------- Path parameter "param" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "param", rctx.URLParam("param"), &params.Param); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter param: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetSimpleNoExplodeObjectPathParamsFromRequest binds the path parameters of GetSimpleNoExplodeObject from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetSimpleNoExplodeObjectPathParamsFromRequest(r *http.Request) (*GetSimpleNoExplodeObjectPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetSimpleNoExplodeObjectPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetSimplePrimitivePathParams binds the path parameters of GetSimplePrimitive from rctx.
+func BindGetSimplePrimitivePathParams(rctx *chi.Context) (*GetSimplePrimitivePathParams, error) { //nolint:wsl,cyclop
+	var params GetSimplePrimitivePathParams
+
+	// ------------- Path parameter "param" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "param", rctx.URLParam("param"), &params.Param); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter param: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetSimplePrimitivePathParamsFromRequest binds the path parameters of GetSimplePrimitive from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetSimplePrimitivePathParamsFromRequest(r *http.Request) (*GetSimplePrimitivePathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetSimplePrimitivePathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetStartingWithNumberPathParams binds the path parameters of GetStartingWithNumber from rctx.
+func BindGetStartingWithNumberPathParams(rctx *chi.Context) (*GetStartingWithNumberPathParams, error) { //nolint:wsl,cyclop
+	var params GetStartingWithNumberPathParams
+
+	// ------------- Path parameter "1param" -------------
+
+	params.N1param = rctx.URLParam("1param")
+
+	return &params, nil
+}
+
+// GetStartingWithNumberPathParamsFromRequest binds the path parameters of GetStartingWithNumber from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetStartingWithNumberPathParamsFromRequest(r *http.Request) (*GetStartingWithNumberPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetStartingWithNumberPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
 // Response is a common response struct for all the API calls.
 // A Response object may be instantiated via functions for specific operation responses.
 type Response struct {
@@ -236,8 +765,8 @@ type ServerInterfaceWrapper struct {
 }
 
 // GetContentObject operation middleware
-func (siw *ServerInterfaceWrapper) GetContentObject(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetContentObject(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "param" -------------
 	var param ComplexObject
@@ -252,12 +781,29 @@ func (siw *ServerInterfaceWrapper) GetContentObject(w http.ResponseWriter, r *ht
 		siw.Handler.GetContentObject(w, r, param)
 	})
 
+	handler = GetContentObjectContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetContentObjectContext is middleware setting the metadata of the GetContentObject
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetContentObjectContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getContentObject",
+			tags: nil,
+			path: "/contentObject/{param}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetCookie operation middleware
-func (siw *ServerInterfaceWrapper) GetCookie(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetCookie(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params GetCookieParams
@@ -364,12 +910,29 @@ func (siw *ServerInterfaceWrapper) GetCookie(w http.ResponseWriter, r *http.Requ
 		siw.Handler.GetCookie(w, r, params)
 	})
 
+	handler = GetCookieContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetCookieContext is middleware setting the metadata of the GetCookie
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetCookieContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getCookie",
+			tags: nil,
+			path: "/cookie",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetHeader operation middleware
-func (siw *ServerInterfaceWrapper) GetHeader(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetHeader(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params GetHeaderParams
@@ -540,12 +1103,29 @@ func (siw *ServerInterfaceWrapper) GetHeader(w http.ResponseWriter, r *http.Requ
 		siw.Handler.GetHeader(w, r, params)
 	})
 
+	handler = GetHeaderContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetHeaderContext is middleware setting the metadata of the GetHeader
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetHeaderContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getHeader",
+			tags: nil,
+			path: "/header",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetLabelExplodeArray operation middleware
-func (siw *ServerInterfaceWrapper) GetLabelExplodeArray(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetLabelExplodeArray(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "param" -------------
 	var param []int32
@@ -560,12 +1140,29 @@ func (siw *ServerInterfaceWrapper) GetLabelExplodeArray(w http.ResponseWriter, r
 		siw.Handler.GetLabelExplodeArray(w, r, param)
 	})
 
+	handler = GetLabelExplodeArrayContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetLabelExplodeArrayContext is middleware setting the metadata of the GetLabelExplodeArray
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetLabelExplodeArrayContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getLabelExplodeArray",
+			tags: nil,
+			path: "/labelExplodeArray/{.param*}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetLabelExplodeObject operation middleware
-func (siw *ServerInterfaceWrapper) GetLabelExplodeObject(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetLabelExplodeObject(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "param" -------------
 	var param Object
@@ -580,12 +1177,29 @@ func (siw *ServerInterfaceWrapper) GetLabelExplodeObject(w http.ResponseWriter,
 		siw.Handler.GetLabelExplodeObject(w, r, param)
 	})
 
+	handler = GetLabelExplodeObjectContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetLabelExplodeObjectContext is middleware setting the metadata of the GetLabelExplodeObject
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetLabelExplodeObjectContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getLabelExplodeObject",
+			tags: nil,
+			path: "/labelExplodeObject/{.param*}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetLabelNoExplodeArray operation middleware
-func (siw *ServerInterfaceWrapper) GetLabelNoExplodeArray(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetLabelNoExplodeArray(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "param" -------------
 	var param []int32
@@ -600,12 +1214,29 @@ func (siw *ServerInterfaceWrapper) GetLabelNoExplodeArray(w http.ResponseWriter,
 		siw.Handler.GetLabelNoExplodeArray(w, r, param)
 	})
 
+	handler = GetLabelNoExplodeArrayContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetLabelNoExplodeArrayContext is middleware setting the metadata of the GetLabelNoExplodeArray
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetLabelNoExplodeArrayContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getLabelNoExplodeArray",
+			tags: nil,
+			path: "/labelNoExplodeArray/{.param}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetLabelNoExplodeObject operation middleware
-func (siw *ServerInterfaceWrapper) GetLabelNoExplodeObject(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetLabelNoExplodeObject(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "param" -------------
 	var param Object
@@ -620,12 +1251,29 @@ func (siw *ServerInterfaceWrapper) GetLabelNoExplodeObject(w http.ResponseWriter
 		siw.Handler.GetLabelNoExplodeObject(w, r, param)
 	})
 
+	handler = GetLabelNoExplodeObjectContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetLabelNoExplodeObjectContext is middleware setting the metadata of the GetLabelNoExplodeObject
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetLabelNoExplodeObjectContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getLabelNoExplodeObject",
+			tags: nil,
+			path: "/labelNoExplodeObject/{.param}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetMatrixExplodeArray operation middleware
-func (siw *ServerInterfaceWrapper) GetMatrixExplodeArray(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetMatrixExplodeArray(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "id" -------------
 	var id []int32
@@ -640,12 +1288,29 @@ func (siw *ServerInterfaceWrapper) GetMatrixExplodeArray(w http.ResponseWriter,
 		siw.Handler.GetMatrixExplodeArray(w, r, id)
 	})
 
+	handler = GetMatrixExplodeArrayContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetMatrixExplodeArrayContext is middleware setting the metadata of the GetMatrixExplodeArray
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetMatrixExplodeArrayContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getMatrixExplodeArray",
+			tags: nil,
+			path: "/matrixExplodeArray/{.id*}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetMatrixExplodeObject operation middleware
-func (siw *ServerInterfaceWrapper) GetMatrixExplodeObject(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetMatrixExplodeObject(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "id" -------------
 	var id Object
@@ -660,12 +1325,29 @@ func (siw *ServerInterfaceWrapper) GetMatrixExplodeObject(w http.ResponseWriter,
 		siw.Handler.GetMatrixExplodeObject(w, r, id)
 	})
 
+	handler = GetMatrixExplodeObjectContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetMatrixExplodeObjectContext is middleware setting the metadata of the GetMatrixExplodeObject
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetMatrixExplodeObjectContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getMatrixExplodeObject",
+			tags: nil,
+			path: "/matrixExplodeObject/{.id*}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetMatrixNoExplodeArray operation middleware
-func (siw *ServerInterfaceWrapper) GetMatrixNoExplodeArray(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetMatrixNoExplodeArray(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "id" -------------
 	var id []int32
@@ -680,12 +1362,29 @@ func (siw *ServerInterfaceWrapper) GetMatrixNoExplodeArray(w http.ResponseWriter
 		siw.Handler.GetMatrixNoExplodeArray(w, r, id)
 	})
 
+	handler = GetMatrixNoExplodeArrayContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetMatrixNoExplodeArrayContext is middleware setting the metadata of the GetMatrixNoExplodeArray
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetMatrixNoExplodeArrayContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getMatrixNoExplodeArray",
+			tags: nil,
+			path: "/matrixNoExplodeArray/{.id}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetMatrixNoExplodeObject operation middleware
-func (siw *ServerInterfaceWrapper) GetMatrixNoExplodeObject(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetMatrixNoExplodeObject(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "id" -------------
 	var id Object
@@ -700,12 +1399,29 @@ func (siw *ServerInterfaceWrapper) GetMatrixNoExplodeObject(w http.ResponseWrite
 		siw.Handler.GetMatrixNoExplodeObject(w, r, id)
 	})
 
+	handler = GetMatrixNoExplodeObjectContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetMatrixNoExplodeObjectContext is middleware setting the metadata of the GetMatrixNoExplodeObject
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetMatrixNoExplodeObjectContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getMatrixNoExplodeObject",
+			tags: nil,
+			path: "/matrixNoExplodeObject/{.id}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetPassThrough operation middleware
-func (siw *ServerInterfaceWrapper) GetPassThrough(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetPassThrough(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "param" -------------
 	var param string
@@ -716,12 +1432,29 @@ func (siw *ServerInterfaceWrapper) GetPassThrough(w http.ResponseWriter, r *http
 		siw.Handler.GetPassThrough(w, r, param)
 	})
 
+	handler = GetPassThroughContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetPassThroughContext is middleware setting the metadata of the GetPassThrough
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetPassThroughContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getPassThrough",
+			tags: nil,
+			path: "/passThrough/{param}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetDeepObject operation middleware
-func (siw *ServerInterfaceWrapper) GetDeepObject(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetDeepObject(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params GetDeepObjectParams
@@ -738,12 +1471,29 @@ func (siw *ServerInterfaceWrapper) GetDeepObject(w http.ResponseWriter, r *http.
 		siw.Handler.GetDeepObject(w, r, params)
 	})
 
+	handler = GetDeepObjectContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetDeepObjectContext is middleware setting the metadata of the GetDeepObject
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetDeepObjectContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getDeepObject",
+			tags: nil,
+			path: "/queryDeepObject",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetQueryForm operation middleware
-func (siw *ServerInterfaceWrapper) GetQueryForm(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetQueryForm(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params GetQueryFormParams
@@ -830,12 +1580,29 @@ func (siw *ServerInterfaceWrapper) GetQueryForm(w http.ResponseWriter, r *http.R
 		siw.Handler.GetQueryForm(w, r, params)
 	})
 
+	handler = GetQueryFormContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetQueryFormContext is middleware setting the metadata of the GetQueryForm
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetQueryFormContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getQueryForm",
+			tags: nil,
+			path: "/queryForm",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetSimpleExplodeArray operation middleware
-func (siw *ServerInterfaceWrapper) GetSimpleExplodeArray(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetSimpleExplodeArray(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "param" -------------
 	var param []int32
@@ -850,12 +1617,29 @@ func (siw *ServerInterfaceWrapper) GetSimpleExplodeArray(w http.ResponseWriter,
 		siw.Handler.GetSimpleExplodeArray(w, r, param)
 	})
 
+	handler = GetSimpleExplodeArrayContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetSimpleExplodeArrayContext is middleware setting the metadata of the GetSimpleExplodeArray
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetSimpleExplodeArrayContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getSimpleExplodeArray",
+			tags: nil,
+			path: "/simpleExplodeArray/{param*}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetSimpleExplodeObject operation middleware
-func (siw *ServerInterfaceWrapper) GetSimpleExplodeObject(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetSimpleExplodeObject(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "param" -------------
 	var param Object
@@ -870,12 +1654,29 @@ func (siw *ServerInterfaceWrapper) GetSimpleExplodeObject(w http.ResponseWriter,
 		siw.Handler.GetSimpleExplodeObject(w, r, param)
 	})
 
+	handler = GetSimpleExplodeObjectContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetSimpleExplodeObjectContext is middleware setting the metadata of the GetSimpleExplodeObject
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetSimpleExplodeObjectContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getSimpleExplodeObject",
+			tags: nil,
+			path: "/simpleExplodeObject/{param*}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetSimpleNoExplodeArray operation middleware
-func (siw *ServerInterfaceWrapper) GetSimpleNoExplodeArray(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetSimpleNoExplodeArray(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "param" -------------
 	var param []int32
@@ -890,12 +1691,29 @@ func (siw *ServerInterfaceWrapper) GetSimpleNoExplodeArray(w http.ResponseWriter
 		siw.Handler.GetSimpleNoExplodeArray(w, r, param)
 	})
 
+	handler = GetSimpleNoExplodeArrayContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetSimpleNoExplodeArrayContext is middleware setting the metadata of the GetSimpleNoExplodeArray
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetSimpleNoExplodeArrayContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getSimpleNoExplodeArray",
+			tags: nil,
+			path: "/simpleNoExplodeArray/{param}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetSimpleNoExplodeObject operation middleware
-func (siw *ServerInterfaceWrapper) GetSimpleNoExplodeObject(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetSimpleNoExplodeObject(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "param" -------------
 	var param Object
@@ -910,12 +1728,29 @@ func (siw *ServerInterfaceWrapper) GetSimpleNoExplodeObject(w http.ResponseWrite
 		siw.Handler.GetSimpleNoExplodeObject(w, r, param)
 	})
 
+	handler = GetSimpleNoExplodeObjectContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetSimpleNoExplodeObjectContext is middleware setting the metadata of the GetSimpleNoExplodeObject
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetSimpleNoExplodeObjectContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getSimpleNoExplodeObject",
+			tags: nil,
+			path: "/simpleNoExplodeObject/{param}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetSimplePrimitive operation middleware
-func (siw *ServerInterfaceWrapper) GetSimplePrimitive(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetSimplePrimitive(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "param" -------------
 	var param int32
@@ -930,12 +1765,29 @@ func (siw *ServerInterfaceWrapper) GetSimplePrimitive(w http.ResponseWriter, r *
 		siw.Handler.GetSimplePrimitive(w, r, param)
 	})
 
+	handler = GetSimplePrimitiveContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetSimplePrimitiveContext is middleware setting the metadata of the GetSimplePrimitive
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetSimplePrimitiveContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getSimplePrimitive",
+			tags: nil,
+			path: "/simplePrimitive/{param}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetStartingWithNumber operation middleware
-func (siw *ServerInterfaceWrapper) GetStartingWithNumber(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetStartingWithNumber(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "1param" -------------
 	var n1param string
@@ -946,9 +1798,84 @@ func (siw *ServerInterfaceWrapper) GetStartingWithNumber(w http.ResponseWriter,
 		siw.Handler.GetStartingWithNumber(w, r, n1param)
 	})
 
+	handler = GetStartingWithNumberContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetStartingWithNumberContext is middleware setting the metadata of the GetStartingWithNumber
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetStartingWithNumberContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getStartingWithNumber",
+			tags: nil,
+			path: "/startingWithNumber/{1param}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// operationContextKey is the context key of the operationMetadata set by the
+// <OperationID>Context middleware.
+type operationContextKey struct{}
+
+// operationMetadata describes the operation of a request.
+type operationMetadata struct {
+	id   string
+	tags []string
+	path string
+}
+
+// OperationIDFromContext returns the operationId of the operation of the
+// request of ctx, or "" outside of the operations of the server.
+func OperationIDFromContext(ctx context.Context) string {
+	op, _ := ctx.Value(operationContextKey{}).(operationMetadata)
+	return op.id
+}
+
+// OperationTagsFromContext returns the tags of the operation of the request
+// of ctx, which the caller must not modify.
+func OperationTagsFromContext(ctx context.Context) []string {
+	op, _ := ctx.Value(operationContextKey{}).(operationMetadata)
+	return op.tags
+}
+
+// OperationPathFromContext returns the path template of the operation of the
+// request of ctx in the spec, like /pets/{id}.
+func OperationPathFromContext(ctx context.Context) string {
+	op, _ := ctx.Value(operationContextKey{}).(operationMetadata)
+	return op.path
+}
+
+// errorHandlerContextKey is the context key of the ErrorHandlerFunc of the
+// server, for HandleError.
+type errorHandlerContextKey struct{}
+
+// HandleError writes err, which a handler failed with, with the
+// ErrorHandlerFunc of the server of r. Handlers pass an
+// *apierrors.APIError, like apierrors.ErrNotFound, to respond with its
+// status code; other errors are responded to with an HTTP/500.
+func HandleError(w http.ResponseWriter, r *http.Request, err error) {
+	if !errors.As(err, new(*apierrors.APIError)) {
+		err = apierrors.Wrap(http.StatusInternalServerError, err)
+	}
+	handle, ok := r.Context().Value(errorHandlerContextKey{}).(func(w http.ResponseWriter, r *http.Request, err error))
+	if !ok || handle == nil {
+		handle = defaultErrorHandler
+	}
+	handle(w, r, err)
+}
+
+// defaultErrorHandler writes the errors of the server as plain text, with
+// the status code of an *apierrors.APIError, or an HTTP/400 for the errors
+// binding the parameters of requests.
+func defaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	apierrors.Write(w, err, http.StatusBadRequest)
+}
+
 type UnescapedCookieParamError struct {
 	error
 }
@@ -973,19 +1900,23 @@ type ServerOptions struct {
 	BaseRouter       chi.Router
 	Middlewares      map[string]func(http.Handler) http.Handler
 	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+	// Tag restricts the routes to the operations with this tag, none if
+	// empty.
+	Tag string
 }
 
 type ServerOption func(*ServerOptions)
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface, opts ...ServerOption) http.Handler {
+// Handler creates http.Handler with routing matching OpenAPI spec. The routes
+// are registered on a chi router of its own, unless another one is given
+// with WithRouter, so that the handler can be served as it is, by an
+// http.Server or httptest.NewServer.
+func Handler(si ServerInterface, opts ...ServerOption) http.Handler { //nolint:wsl,cyclop
 	options := &ServerOptions{
-		BaseURL:     "/",
-		BaseRouter:  chi.NewRouter(),
-		Middlewares: make(map[string]func(http.Handler) http.Handler),
-		ErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		},
+		BaseURL:          "/",
+		BaseRouter:       chi.NewRouter(),
+		Middlewares:      make(map[string]func(http.Handler) http.Handler),
+		ErrorHandlerFunc: defaultErrorHandler,
 	}
 
 	for _, f := range opts {
@@ -999,6 +1930,10 @@ func Handler(si ServerInterface, opts ...ServerOption) http.Handler {
 		ErrorHandlerFunc: options.ErrorHandlerFunc,
 	}
 
+	if options.Tag != "" {
+		panic("goapi-gen: no operation is tagged " + options.Tag)
+	}
+
 	r.Route(options.BaseURL, func(r chi.Router) {
 		r.Get("/contentObject/{param}", wrapper.GetContentObject)
 		r.Get("/cookie", wrapper.GetCookie)
@@ -1025,6 +1960,25 @@ func Handler(si ServerInterface, opts ...ServerOption) http.Handler {
 	return r
 }
 
+// HandlerFromMux registers the routes of the OpenAPI spec on r, and returns r.
+func HandlerFromMux(r chi.Router, si ServerInterface, opts ...ServerOption) http.Handler {
+	return Handler(si, append([]ServerOption{WithRouter(r)}, opts...)...)
+}
+
+// HandlerFromMuxWithBaseURL registers the routes of the OpenAPI spec on r,
+// under baseURL, and returns r.
+func HandlerFromMuxWithBaseURL(r chi.Router, si ServerInterface, baseURL string, opts ...ServerOption) http.Handler {
+	return Handler(si, append([]ServerOption{WithRouter(r), WithServerBaseURL(baseURL)}, opts...)...)
+}
+
+// HandlerFromMuxForTag registers the routes of the operations of the OpenAPI
+// spec tagged with tag on r, under baseURL, and returns r. The routes of
+// different tags can be registered on the groups of a router, each with its
+// own middleware stack.
+func HandlerFromMuxForTag(r chi.Router, si ServerInterface, tag string, baseURL string, opts ...ServerOption) http.Handler {
+	return Handler(si, append([]ServerOption{WithRouter(r), WithServerBaseURL(baseURL), WithTag(tag)}, opts...)...)
+}
+
 func WithRouter(r chi.Router) ServerOption {
 	return func(s *ServerOptions) {
 		s.BaseRouter = r
@@ -1037,6 +1991,13 @@ func WithServerBaseURL(url string) ServerOption {
 	}
 }
 
+// WithTag restricts the routes to the operations tagged with tag.
+func WithTag(tag string) ServerOption {
+	return func(s *ServerOptions) {
+		s.Tag = tag
+	}
+}
+
 func WithMiddleware(key string, middleware func(http.Handler) http.Handler) ServerOption {
 	return func(s *ServerOptions) {
 		s.Middlewares[key] = middleware
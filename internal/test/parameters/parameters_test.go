@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -487,3 +488,26 @@ func TestParameterBinding(t *testing.T) {
 	assert.EqualValues(t, &expectedN1Param, ts.n1param)
 	ts.reset()
 }
+
+func TestBindPathParams(t *testing.T) {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("param", "5")
+
+	params, err := BindGetSimplePrimitivePathParams(rctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), params.Param)
+
+	rctx = chi.NewRouteContext()
+	rctx.URLParams.Add("param", ".role=admin.firstName=Alex")
+
+	objParams, err := BindGetLabelExplodeObjectPathParams(rctx)
+	require.NoError(t, err)
+	assert.Equal(t, Object{Role: "admin", FirstName: "Alex"}, objParams.Param)
+
+	// Values which can't be converted to the parameter type are rejected
+	rctx = chi.NewRouteContext()
+	rctx.URLParams.Add("param", "five")
+
+	_, err = BindGetSimplePrimitivePathParams(rctx)
+	assert.Error(t, err)
+}
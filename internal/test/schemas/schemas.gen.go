@@ -1,6 +1,8 @@
 // Package schemas provides primitives to interact with the openapi HTTP API.
 //
 // Code generated by github.com/discord-gophers/goapi-gen version (devel) DO NOT EDIT.
+//
+//nolint:revive,godot
 package schemas
 
 import (
@@ -10,12 +12,14 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
 
+	"github.com/discord-gophers/goapi-gen/pkg/apierrors"
 	"github.com/discord-gophers/goapi-gen/pkg/runtime"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
@@ -35,6 +39,14 @@ var (
 	EnumInObjInArrayValSecond = EnumInObjInArrayVal{"second"}
 )
 
+// EnumInObjInArrayValValues returns all the values of EnumInObjInArrayVal, in the order of their names.
+func EnumInObjInArrayValValues() []EnumInObjInArrayVal {
+	return []EnumInObjInArrayVal{
+		EnumInObjInArrayValFirst,
+		EnumInObjInArrayValSecond,
+	}
+}
+
 // This schema name starts with a number
 type N5startsWithNumber map[string]interface{}
 
@@ -100,10 +112,29 @@ func (t *EnumInObjInArrayVal) FromValue(value string) error {
 	}
 	return fmt.Errorf("unknown enum value: %v", value)
 }
+func (t EnumInObjInArrayVal) String() string {
+	return t.value
+}
 
 // Issue185JSONBody defines parameters for Issue185.
 type Issue185JSONBody NullableProperties
 
+// Issue209PathParams defines parameters for Issue209.
+type Issue209PathParams struct {
+	// A string path parameter
+	Str StringInPath `json:"str"`
+}
+
+// Issue30PathParams defines parameters for Issue30.
+type Issue30PathParams struct {
+	Fallthrough string `json:"fallthrough"`
+}
+
+// Issue41PathParams defines parameters for Issue41.
+type Issue41PathParams struct {
+	N1param N5startsWithNumber `json:"1param"`
+}
+
 // Issue9JSONBody defines parameters for Issue9.
 type Issue9JSONBody interface{}
 
@@ -112,6 +143,90 @@ type Issue9Params struct {
 	Foo string `json:"foo"`
 }
 
+// BindIssue209PathParams binds the path parameters of Issue209 from rctx.
+func BindIssue209PathParams(rctx *chi.Context) (*Issue209PathParams, error) { //nolint:wsl,cyclop
+	var params Issue209PathParams
+
+	// ------------- Path parameter "str" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "str", rctx.URLParam("str"), &params.Str); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter str: %w", err)
+	}
+
+	return &params, nil
+}
+
+// Issue209PathParamsFromRequest binds the path parameters of Issue209 from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func Issue209PathParamsFromRequest(r *http.Request) (*Issue209PathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindIssue209PathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindIssue30PathParams binds the path parameters of Issue30 from rctx.
+func BindIssue30PathParams(rctx *chi.Context) (*Issue30PathParams, error) { //nolint:wsl,cyclop
+	var params Issue30PathParams
+
+	// ------------- Path parameter "fallthrough" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "fallthrough", rctx.URLParam("fallthrough"), &params.Fallthrough); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter fallthrough: %w", err)
+	}
+
+	return &params, nil
+}
+
+// Issue30PathParamsFromRequest binds the path parameters of Issue30 from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func Issue30PathParamsFromRequest(r *http.Request) (*Issue30PathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindIssue30PathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindIssue41PathParams binds the path parameters of Issue41 from rctx.
+func BindIssue41PathParams(rctx *chi.Context) (*Issue41PathParams, error) { //nolint:wsl,cyclop
+	var params Issue41PathParams
+
+	// ------------- Path parameter "1param" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "1param", rctx.URLParam("1param"), &params.N1param); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter 1param: %w", err)
+	}
+
+	return &params, nil
+}
+
+// Issue41PathParamsFromRequest binds the path parameters of Issue41 from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func Issue41PathParamsFromRequest(r *http.Request) (*Issue41PathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindIssue41PathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
 // Issue185JSONRequestBody defines body for Issue185 for application/json ContentType.
 type Issue185JSONRequestBody Issue185JSONBody
 
@@ -233,28 +348,31 @@ func GetIssues375JSON200Response(body EnumInObjInArray) *Response {
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
-
+	// This endpoint exists so that components can be created in this
+	// spec and not be pruned
 	// (GET /ensure-everything-is-referenced)
 	EnsureEverythingIsReferenced(w http.ResponseWriter, r *http.Request)
-
+	// Make sure unsupported context types don't preempt supported types.
 	// (GET /issues/127)
 	Issue127(w http.ResponseWriter, r *http.Request)
-
+	// Type generation when optional/required properties are nullable.
 	// (GET /issues/185)
 	Issue185(w http.ResponseWriter, r *http.Request)
-
+	// Checks if parameters are declared properly
 	// (GET /issues/209/${str})
 	Issue209(w http.ResponseWriter, r *http.Request, str StringInPath)
 
 	// (GET /issues/30/{fallthrough})
 	Issue30(w http.ResponseWriter, r *http.Request, pFallthrough string)
-
+	// Enum declaration was generated twice if the enum was in an object
+	// which was inside of an array.
 	// (GET /issues/375)
 	GetIssues375(w http.ResponseWriter, r *http.Request)
-
+	// Parameter name starting with number
 	// (GET /issues/41/{1param})
 	Issue41(w http.ResponseWriter, r *http.Request, n1param N5startsWithNumber)
-
+	// Client params type incorrectly included for request with body and
+	// parameters.
 	// (GET /issues/9)
 	Issue9(w http.ResponseWriter, r *http.Request, params Issue9Params)
 }
@@ -267,8 +385,8 @@ type ServerInterfaceWrapper struct {
 }
 
 // EnsureEverythingIsReferenced operation middleware
-func (siw *ServerInterfaceWrapper) EnsureEverythingIsReferenced(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) EnsureEverythingIsReferenced(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	ctx = context.WithValue(ctx, AccessTokenScopes, []string{""})
 
@@ -276,12 +394,29 @@ func (siw *ServerInterfaceWrapper) EnsureEverythingIsReferenced(w http.ResponseW
 		siw.Handler.EnsureEverythingIsReferenced(w, r)
 	})
 
+	handler = EnsureEverythingIsReferencedContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// EnsureEverythingIsReferencedContext is middleware setting the metadata of the EnsureEverythingIsReferenced
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func EnsureEverythingIsReferencedContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "ensureEverythingIsReferenced",
+			tags: nil,
+			path: "/ensure-everything-is-referenced",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // Issue127 operation middleware
-func (siw *ServerInterfaceWrapper) Issue127(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) Issue127(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	ctx = context.WithValue(ctx, AccessTokenScopes, []string{""})
 
@@ -289,12 +424,29 @@ func (siw *ServerInterfaceWrapper) Issue127(w http.ResponseWriter, r *http.Reque
 		siw.Handler.Issue127(w, r)
 	})
 
+	handler = Issue127Context(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// Issue127Context is middleware setting the metadata of the Issue127
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func Issue127Context(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "Issue127",
+			tags: nil,
+			path: "/issues/127",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // Issue185 operation middleware
-func (siw *ServerInterfaceWrapper) Issue185(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) Issue185(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	ctx = context.WithValue(ctx, AccessTokenScopes, []string{""})
 
@@ -302,12 +454,29 @@ func (siw *ServerInterfaceWrapper) Issue185(w http.ResponseWriter, r *http.Reque
 		siw.Handler.Issue185(w, r)
 	})
 
+	handler = Issue185Context(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// Issue185Context is middleware setting the metadata of the Issue185
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func Issue185Context(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "Issue185",
+			tags: nil,
+			path: "/issues/185",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // Issue209 operation middleware
-func (siw *ServerInterfaceWrapper) Issue209(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) Issue209(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "str" -------------
 	var str StringInPath
@@ -324,12 +493,29 @@ func (siw *ServerInterfaceWrapper) Issue209(w http.ResponseWriter, r *http.Reque
 		siw.Handler.Issue209(w, r, str)
 	})
 
+	handler = Issue209Context(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// Issue209Context is middleware setting the metadata of the Issue209
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func Issue209Context(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "Issue209",
+			tags: nil,
+			path: "/issues/209/${str}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // Issue30 operation middleware
-func (siw *ServerInterfaceWrapper) Issue30(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) Issue30(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "fallthrough" -------------
 	var pFallthrough string
@@ -346,12 +532,29 @@ func (siw *ServerInterfaceWrapper) Issue30(w http.ResponseWriter, r *http.Reques
 		siw.Handler.Issue30(w, r, pFallthrough)
 	})
 
+	handler = Issue30Context(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// Issue30Context is middleware setting the metadata of the Issue30
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func Issue30Context(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "Issue30",
+			tags: nil,
+			path: "/issues/30/{fallthrough}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetIssues375 operation middleware
-func (siw *ServerInterfaceWrapper) GetIssues375(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetIssues375(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	ctx = context.WithValue(ctx, AccessTokenScopes, []string{""})
 
@@ -359,12 +562,29 @@ func (siw *ServerInterfaceWrapper) GetIssues375(w http.ResponseWriter, r *http.R
 		siw.Handler.GetIssues375(w, r)
 	})
 
+	handler = GetIssues375Context(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetIssues375Context is middleware setting the metadata of the GetIssues375
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetIssues375Context(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "GetIssues375",
+			tags: nil,
+			path: "/issues/375",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // Issue41 operation middleware
-func (siw *ServerInterfaceWrapper) Issue41(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) Issue41(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "1param" -------------
 	var n1param N5startsWithNumber
@@ -381,12 +601,29 @@ func (siw *ServerInterfaceWrapper) Issue41(w http.ResponseWriter, r *http.Reques
 		siw.Handler.Issue41(w, r, n1param)
 	})
 
+	handler = Issue41Context(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// Issue41Context is middleware setting the metadata of the Issue41
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func Issue41Context(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "Issue41",
+			tags: nil,
+			path: "/issues/41/{1param}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // Issue9 operation middleware
-func (siw *ServerInterfaceWrapper) Issue9(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) Issue9(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	ctx = context.WithValue(ctx, AccessTokenScopes, []string{""})
 
@@ -405,9 +642,84 @@ func (siw *ServerInterfaceWrapper) Issue9(w http.ResponseWriter, r *http.Request
 		siw.Handler.Issue9(w, r, params)
 	})
 
+	handler = Issue9Context(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// Issue9Context is middleware setting the metadata of the Issue9
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func Issue9Context(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "Issue9",
+			tags: nil,
+			path: "/issues/9",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// operationContextKey is the context key of the operationMetadata set by the
+// <OperationID>Context middleware.
+type operationContextKey struct{}
+
+// operationMetadata describes the operation of a request.
+type operationMetadata struct {
+	id   string
+	tags []string
+	path string
+}
+
+// OperationIDFromContext returns the operationId of the operation of the
+// request of ctx, or "" outside of the operations of the server.
+func OperationIDFromContext(ctx context.Context) string {
+	op, _ := ctx.Value(operationContextKey{}).(operationMetadata)
+	return op.id
+}
+
+// OperationTagsFromContext returns the tags of the operation of the request
+// of ctx, which the caller must not modify.
+func OperationTagsFromContext(ctx context.Context) []string {
+	op, _ := ctx.Value(operationContextKey{}).(operationMetadata)
+	return op.tags
+}
+
+// OperationPathFromContext returns the path template of the operation of the
+// request of ctx in the spec, like /pets/{id}.
+func OperationPathFromContext(ctx context.Context) string {
+	op, _ := ctx.Value(operationContextKey{}).(operationMetadata)
+	return op.path
+}
+
+// errorHandlerContextKey is the context key of the ErrorHandlerFunc of the
+// server, for HandleError.
+type errorHandlerContextKey struct{}
+
+// HandleError writes err, which a handler failed with, with the
+// ErrorHandlerFunc of the server of r. Handlers pass an
+// *apierrors.APIError, like apierrors.ErrNotFound, to respond with its
+// status code; other errors are responded to with an HTTP/500.
+func HandleError(w http.ResponseWriter, r *http.Request, err error) {
+	if !errors.As(err, new(*apierrors.APIError)) {
+		err = apierrors.Wrap(http.StatusInternalServerError, err)
+	}
+	handle, ok := r.Context().Value(errorHandlerContextKey{}).(func(w http.ResponseWriter, r *http.Request, err error))
+	if !ok || handle == nil {
+		handle = defaultErrorHandler
+	}
+	handle(w, r, err)
+}
+
+// defaultErrorHandler writes the errors of the server as plain text, with
+// the status code of an *apierrors.APIError, or an HTTP/400 for the errors
+// binding the parameters of requests.
+func defaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	apierrors.Write(w, err, http.StatusBadRequest)
+}
+
 type UnescapedCookieParamError struct {
 	error
 }
@@ -432,19 +744,23 @@ type ServerOptions struct {
 	BaseRouter       chi.Router
 	Middlewares      map[string]func(http.Handler) http.Handler
 	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+	// Tag restricts the routes to the operations with this tag, none if
+	// empty.
+	Tag string
 }
 
 type ServerOption func(*ServerOptions)
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface, opts ...ServerOption) http.Handler {
+// Handler creates http.Handler with routing matching OpenAPI spec. The routes
+// are registered on a chi router of its own, unless another one is given
+// with WithRouter, so that the handler can be served as it is, by an
+// http.Server or httptest.NewServer.
+func Handler(si ServerInterface, opts ...ServerOption) http.Handler { //nolint:wsl,cyclop
 	options := &ServerOptions{
-		BaseURL:     "/",
-		BaseRouter:  chi.NewRouter(),
-		Middlewares: make(map[string]func(http.Handler) http.Handler),
-		ErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		},
+		BaseURL:          "/",
+		BaseRouter:       chi.NewRouter(),
+		Middlewares:      make(map[string]func(http.Handler) http.Handler),
+		ErrorHandlerFunc: defaultErrorHandler,
 	}
 
 	for _, f := range opts {
@@ -458,6 +774,10 @@ func Handler(si ServerInterface, opts ...ServerOption) http.Handler {
 		ErrorHandlerFunc: options.ErrorHandlerFunc,
 	}
 
+	if options.Tag != "" {
+		panic("goapi-gen: no operation is tagged " + options.Tag)
+	}
+
 	r.Route(options.BaseURL, func(r chi.Router) {
 		r.Get("/ensure-everything-is-referenced", wrapper.EnsureEverythingIsReferenced)
 		r.Get("/issues/127", wrapper.Issue127)
@@ -472,6 +792,25 @@ func Handler(si ServerInterface, opts ...ServerOption) http.Handler {
 	return r
 }
 
+// HandlerFromMux registers the routes of the OpenAPI spec on r, and returns r.
+func HandlerFromMux(r chi.Router, si ServerInterface, opts ...ServerOption) http.Handler {
+	return Handler(si, append([]ServerOption{WithRouter(r)}, opts...)...)
+}
+
+// HandlerFromMuxWithBaseURL registers the routes of the OpenAPI spec on r,
+// under baseURL, and returns r.
+func HandlerFromMuxWithBaseURL(r chi.Router, si ServerInterface, baseURL string, opts ...ServerOption) http.Handler {
+	return Handler(si, append([]ServerOption{WithRouter(r), WithServerBaseURL(baseURL)}, opts...)...)
+}
+
+// HandlerFromMuxForTag registers the routes of the operations of the OpenAPI
+// spec tagged with tag on r, under baseURL, and returns r. The routes of
+// different tags can be registered on the groups of a router, each with its
+// own middleware stack.
+func HandlerFromMuxForTag(r chi.Router, si ServerInterface, tag string, baseURL string, opts ...ServerOption) http.Handler {
+	return Handler(si, append([]ServerOption{WithRouter(r), WithServerBaseURL(baseURL), WithTag(tag)}, opts...)...)
+}
+
 func WithRouter(r chi.Router) ServerOption {
 	return func(s *ServerOptions) {
 		s.BaseRouter = r
@@ -484,6 +823,13 @@ func WithServerBaseURL(url string) ServerOption {
 	}
 }
 
+// WithTag restricts the routes to the operations tagged with tag.
+func WithTag(tag string) ServerOption {
+	return func(s *ServerOptions) {
+		s.Tag = tag
+	}
+}
+
 func WithMiddleware(key string, middleware func(http.Handler) http.Handler) ServerOption {
 	return func(s *ServerOptions) {
 		s.Middlewares[key] = middleware
@@ -1,15 +1,20 @@
 // Package server provides primitives to interact with the openapi HTTP API.
 //
 // Code generated by github.com/discord-gophers/goapi-gen version (devel) DO NOT EDIT.
+//
+//nolint:revive,godot
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/discord-gophers/goapi-gen/pkg/apierrors"
 	"github.com/discord-gophers/goapi-gen/pkg/runtime"
 	openapi_types "github.com/discord-gophers/goapi-gen/pkg/types"
 	"github.com/go-chi/chi/v5"
@@ -88,6 +93,17 @@ type SimpleResponse struct {
 	Name string `json:"name"`
 }
 
+// GetMonthPathParams defines parameters for GetMonth.
+type GetMonthPathParams struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+}
+
+// DownloadFilePathParams defines parameters for DownloadFile.
+type DownloadFilePathParams struct {
+	Name string `json:"name"`
+}
+
 // GetWithArgsParams defines parameters for GetWithArgs.
 type GetWithArgsParams struct {
 	// An optional query argument
@@ -100,12 +116,56 @@ type GetWithArgsParams struct {
 	HeaderArgument *int32 `json:"header_argument,omitempty"`
 }
 
+// GetWithReferencesPathParams defines parameters for GetWithReferences.
+type GetWithReferencesPathParams struct {
+	// A parameter in global path scope
+	GlobalArgument int64 `json:"global_argument"`
+
+	// Some argument
+	Argument Argument `json:"argument"`
+}
+
+// GetWithContentTypePathParams defines parameters for GetWithContentType.
+type GetWithContentTypePathParams struct {
+	// Get with a parameter and multiple output types
+	ContentType GetWithContentTypeParamsContentType `json:"content_type"`
+}
+
 // GetWithContentTypeParamsContentType defines parameters for GetWithContentType.
 type GetWithContentTypeParamsContentType string
 
+// Defines values for GetWithContentTypeParamsContentType.
+const (
+	GetWithContentTypeParamsContentTypeJSON GetWithContentTypeParamsContentType = "json"
+	GetWithContentTypeParamsContentTypeText GetWithContentTypeParamsContentType = "text"
+)
+
+func (v GetWithContentTypeParamsContentType) String() string {
+	return string(v)
+}
+
+// GetWithContentTypeParamsContentTypeValues returns all the values of GetWithContentTypeParamsContentType, in the order of their names.
+func GetWithContentTypeParamsContentTypeValues() []GetWithContentTypeParamsContentType {
+	return []GetWithContentTypeParamsContentType{
+		GetWithContentTypeParamsContentTypeJSON,
+		GetWithContentTypeParamsContentTypeText,
+	}
+}
+
+// GetReportPathParams defines parameters for GetReport.
+type GetReportPathParams struct {
+	ReportID int `json:"report-id"`
+}
+
 // CreateResourceJSONBody defines parameters for CreateResource.
 type CreateResourceJSONBody EveryTypeRequired
 
+// CreateResourcePathParams defines parameters for CreateResource.
+type CreateResourcePathParams struct {
+	// Some argument
+	Argument Argument `json:"argument"`
+}
+
 // CreateResource2JSONBody defines parameters for CreateResource2.
 type CreateResource2JSONBody Resource
 
@@ -115,12 +175,260 @@ type CreateResource2Params struct {
 	InlineQueryArgument *int `json:"inline_query_argument,omitempty"`
 }
 
+// CreateResource2PathParams defines parameters for CreateResource2.
+type CreateResource2PathParams struct {
+	// Some argument
+	InlineArgument int `json:"inline_argument"`
+}
+
 // UpdateResource3JSONBody defines parameters for UpdateResource3.
 type UpdateResource3JSONBody struct {
 	ID   *int    `json:"id,omitempty"`
 	Name *string `json:"name,omitempty"`
 }
 
+// UpdateResource3PathParams defines parameters for UpdateResource3.
+type UpdateResource3PathParams struct {
+	// Some argument
+	Fallthrough int `json:"fallthrough"`
+}
+
+// BindGetMonthPathParams binds the path parameters of GetMonth from rctx.
+func BindGetMonthPathParams(rctx *chi.Context) (*GetMonthPathParams, error) { //nolint:wsl,cyclop
+	var params GetMonthPathParams
+
+	// ------------- Path parameter "year" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "year", rctx.URLParam("year"), &params.Year); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter year: %w", err)
+	}
+
+	// ------------- Path parameter "month" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "month", rctx.URLParam("month"), &params.Month); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter month: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetMonthPathParamsFromRequest binds the path parameters of GetMonth from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetMonthPathParamsFromRequest(r *http.Request) (*GetMonthPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetMonthPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindDownloadFilePathParams binds the path parameters of DownloadFile from rctx.
+func BindDownloadFilePathParams(rctx *chi.Context) (*DownloadFilePathParams, error) { //nolint:wsl,cyclop
+	var params DownloadFilePathParams
+
+	// ------------- Path parameter "name" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "name", rctx.URLParam("name"), &params.Name); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter name: %w", err)
+	}
+
+	return &params, nil
+}
+
+// DownloadFilePathParamsFromRequest binds the path parameters of DownloadFile from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func DownloadFilePathParamsFromRequest(r *http.Request) (*DownloadFilePathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindDownloadFilePathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetWithReferencesPathParams binds the path parameters of GetWithReferences from rctx.
+func BindGetWithReferencesPathParams(rctx *chi.Context) (*GetWithReferencesPathParams, error) { //nolint:wsl,cyclop
+	var params GetWithReferencesPathParams
+
+	// ------------- Path parameter "global_argument" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "global_argument", rctx.URLParam("global_argument"), &params.GlobalArgument); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter global_argument: %w", err)
+	}
+
+	// ------------- Path parameter "argument" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "argument", rctx.URLParam("argument"), &params.Argument); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter argument: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetWithReferencesPathParamsFromRequest binds the path parameters of GetWithReferences from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetWithReferencesPathParamsFromRequest(r *http.Request) (*GetWithReferencesPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetWithReferencesPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetWithContentTypePathParams binds the path parameters of GetWithContentType from rctx.
+func BindGetWithContentTypePathParams(rctx *chi.Context) (*GetWithContentTypePathParams, error) { //nolint:wsl,cyclop
+	var params GetWithContentTypePathParams
+
+	// ------------- Path parameter "content_type" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "content_type", rctx.URLParam("content_type"), &params.ContentType); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter content_type: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetWithContentTypePathParamsFromRequest binds the path parameters of GetWithContentType from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetWithContentTypePathParamsFromRequest(r *http.Request) (*GetWithContentTypePathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetWithContentTypePathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindGetReportPathParams binds the path parameters of GetReport from rctx.
+func BindGetReportPathParams(rctx *chi.Context) (*GetReportPathParams, error) { //nolint:wsl,cyclop
+	var params GetReportPathParams
+
+	// ------------- Path parameter "report-id" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "report-id", rctx.URLParam("report-id"), &params.ReportID); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter report-id: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetReportPathParamsFromRequest binds the path parameters of GetReport from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func GetReportPathParamsFromRequest(r *http.Request) (*GetReportPathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindGetReportPathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindCreateResourcePathParams binds the path parameters of CreateResource from rctx.
+func BindCreateResourcePathParams(rctx *chi.Context) (*CreateResourcePathParams, error) { //nolint:wsl,cyclop
+	var params CreateResourcePathParams
+
+	// ------------- Path parameter "argument" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "argument", rctx.URLParam("argument"), &params.Argument); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter argument: %w", err)
+	}
+
+	return &params, nil
+}
+
+// CreateResourcePathParamsFromRequest binds the path parameters of CreateResource from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func CreateResourcePathParamsFromRequest(r *http.Request) (*CreateResourcePathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindCreateResourcePathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindCreateResource2PathParams binds the path parameters of CreateResource2 from rctx.
+func BindCreateResource2PathParams(rctx *chi.Context) (*CreateResource2PathParams, error) { //nolint:wsl,cyclop
+	var params CreateResource2PathParams
+
+	// ------------- Path parameter "inline_argument" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "inline_argument", rctx.URLParam("inline_argument"), &params.InlineArgument); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter inline_argument: %w", err)
+	}
+
+	return &params, nil
+}
+
+// CreateResource2PathParamsFromRequest binds the path parameters of CreateResource2 from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func CreateResource2PathParamsFromRequest(r *http.Request) (*CreateResource2PathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindCreateResource2PathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BindUpdateResource3PathParams binds the path parameters of UpdateResource3 from rctx.
+func BindUpdateResource3PathParams(rctx *chi.Context) (*UpdateResource3PathParams, error) { //nolint:wsl,cyclop
+	var params UpdateResource3PathParams
+
+	// ------------- Path parameter "fallthrough" -------------
+
+	if err := runtime.BindStyledParameter("simple", false, "fallthrough", rctx.URLParam("fallthrough"), &params.Fallthrough); err != nil {
+		return nil, fmt.Errorf("invalid format for parameter fallthrough: %w", err)
+	}
+
+	return &params, nil
+}
+
+// UpdateResource3PathParamsFromRequest binds the path parameters of UpdateResource3 from
+// the chi route context of r, and checks them against the constraints of their
+// schemas, like minimum or pattern.
+func UpdateResource3PathParamsFromRequest(r *http.Request) (*UpdateResource3PathParams, error) { //nolint:wsl,cyclop
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil, errors.New("no chi route context in the request")
+	}
+	params, err := BindUpdateResource3PathParams(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
 // CreateResourceJSONRequestBody defines body for CreateResource for application/json ContentType.
 type CreateResourceJSONRequestBody CreateResourceJSONBody
 
@@ -185,6 +493,18 @@ func (resp *Response) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	return e.Encode(resp.body)
 }
 
+// GetMonthJSON200Response is a constructor method for a GetMonth response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetMonthJSON200Response(body struct {
+	Name string `json:"name"`
+}) *Response {
+	return &Response{
+		body:        body,
+		statusCode:  200,
+		contentType: "application/json",
+	}
+}
+
 // GetEveryTypeOptionalJSON200Response is a constructor method for a GetEveryTypeOptional response.
 // A *Response is returned with the configured status code and content type from the spec.
 func GetEveryTypeOptionalJSON200Response(body EveryTypeOptional) *Response {
@@ -195,6 +515,18 @@ func GetEveryTypeOptionalJSON200Response(body EveryTypeOptional) *Response {
 	}
 }
 
+// DownloadFileJSON200Response is a constructor method for a DownloadFile response.
+// A *Response is returned with the configured status code and content type from the spec.
+func DownloadFileJSON200Response(body struct {
+	Name string `json:"name"`
+}) *Response {
+	return &Response{
+		body:        body,
+		statusCode:  200,
+		contentType: "application/json",
+	}
+}
+
 // GetSimpleJSON200Response is a constructor method for a GetSimple response.
 // A *Response is returned with the configured status code and content type from the spec.
 func GetSimpleJSON200Response(body SomeObject) *Response {
@@ -239,6 +571,18 @@ func GetWithContentTypeJSON200Response(body SomeObject) *Response {
 	}
 }
 
+// GetReportJSON200Response is a constructor method for a GetReport response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetReportJSON200Response(body struct {
+	Name string `json:"name"`
+}) *Response {
+	return &Response{
+		body:        body,
+		statusCode:  200,
+		contentType: "application/json",
+	}
+}
+
 // GetReservedKeywordJSON200Response is a constructor method for a GetReservedKeyword response.
 // A *Response is returned with the configured status code and content type from the spec.
 func GetReservedKeywordJSON200Response(body ReservedKeyword) *Response {
@@ -321,9 +665,15 @@ func PostWithTaggedMiddlewareJSON200Response(body struct {
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Two parameters in one segment
+	// (GET /dates/{year}-{month})
+	GetMonth(w http.ResponseWriter, r *http.Request, year int, month int)
 	// get every type optional
 	// (GET /every-type-optional)
 	GetEveryTypeOptional(w http.ResponseWriter, r *http.Request)
+	// A parameter followed by a colon and a literal, in one segment
+	// (GET /files/{name}:download)
+	DownloadFile(w http.ResponseWriter, r *http.Request, name string)
 	// Get resource via simple path
 	// (GET /get-simple)
 	GetSimple(w http.ResponseWriter, r *http.Request)
@@ -336,6 +686,9 @@ type ServerInterface interface {
 	// Get an object by ID
 	// (GET /get-with-type/{content_type})
 	GetWithContentType(w http.ResponseWriter, r *http.Request, contentType GetWithContentTypeParamsContentType)
+	// A hyphenated parameter followed by a dot and a literal
+	// (GET /reports/{report-id}.json)
+	GetReport(w http.ResponseWriter, r *http.Request, reportID int)
 	// get with reserved keyword
 	// (GET /reserved-keyword)
 	GetReservedKeyword(w http.ResponseWriter, r *http.Request)
@@ -367,31 +720,148 @@ type ServerInterfaceWrapper struct {
 	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
 }
 
+// GetMonth operation middleware
+func (siw *ServerInterfaceWrapper) GetMonth(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
+
+	// ------------- Path parameter "year" -------------
+	var year int
+
+	if err := runtime.BindStyledParameter("simple", false, "year", chi.URLParam(r, "year"), &year); err != nil {
+		err = fmt.Errorf("invalid format for parameter year: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err})
+		return
+	}
+
+	// ------------- Path parameter "month" -------------
+	var month int
+
+	if err := runtime.BindStyledParameter("simple", false, "month", chi.URLParam(r, "month"), &month); err != nil {
+		err = fmt.Errorf("invalid format for parameter month: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetMonth(w, r, year, month)
+	})
+
+	handler = GetMonthContext(handler).ServeHTTP
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetMonthContext is middleware setting the metadata of the GetMonth
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetMonthContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getMonth",
+			tags: nil,
+			path: "/dates/{year}-{month}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetEveryTypeOptional operation middleware
-func (siw *ServerInterfaceWrapper) GetEveryTypeOptional(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetEveryTypeOptional(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.GetEveryTypeOptional(w, r)
 	})
 
+	handler = GetEveryTypeOptionalContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetEveryTypeOptionalContext is middleware setting the metadata of the GetEveryTypeOptional
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetEveryTypeOptionalContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getEveryTypeOptional",
+			tags: nil,
+			path: "/every-type-optional",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// DownloadFile operation middleware
+func (siw *ServerInterfaceWrapper) DownloadFile(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
+
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	if err := runtime.BindStyledParameter("simple", false, "name", chi.URLParam(r, "name"), &name); err != nil {
+		err = fmt.Errorf("invalid format for parameter name: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DownloadFile(w, r, name)
+	})
+
+	handler = DownloadFileContext(handler).ServeHTTP
+
+	handler(w, r.WithContext(ctx))
+}
+
+// DownloadFileContext is middleware setting the metadata of the DownloadFile
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func DownloadFileContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "downloadFile",
+			tags: nil,
+			path: "/files/{name}:download",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetSimple operation middleware
-func (siw *ServerInterfaceWrapper) GetSimple(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetSimple(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.GetSimple(w, r)
 	})
 
+	handler = GetSimpleContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetSimpleContext is middleware setting the metadata of the GetSimple
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetSimpleContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getSimple",
+			tags: nil,
+			path: "/get-simple",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetWithArgs operation middleware
-func (siw *ServerInterfaceWrapper) GetWithArgs(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetWithArgs(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params GetWithArgsParams
@@ -438,12 +908,29 @@ func (siw *ServerInterfaceWrapper) GetWithArgs(w http.ResponseWriter, r *http.Re
 		siw.Handler.GetWithArgs(w, r, params)
 	})
 
+	handler = GetWithArgsContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetWithArgsContext is middleware setting the metadata of the GetWithArgs
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetWithArgsContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getWithArgs",
+			tags: nil,
+			path: "/get-with-args",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetWithReferences operation middleware
-func (siw *ServerInterfaceWrapper) GetWithReferences(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetWithReferences(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "global_argument" -------------
 	var globalArgument int64
@@ -467,12 +954,29 @@ func (siw *ServerInterfaceWrapper) GetWithReferences(w http.ResponseWriter, r *h
 		siw.Handler.GetWithReferences(w, r, globalArgument, argument)
 	})
 
+	handler = GetWithReferencesContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetWithReferencesContext is middleware setting the metadata of the GetWithReferences
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetWithReferencesContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getWithReferences",
+			tags: nil,
+			path: "/get-with-references/{global_argument}/{argument}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetWithContentType operation middleware
-func (siw *ServerInterfaceWrapper) GetWithContentType(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetWithContentType(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "content_type" -------------
 	var contentType GetWithContentTypeParamsContentType
@@ -487,23 +991,94 @@ func (siw *ServerInterfaceWrapper) GetWithContentType(w http.ResponseWriter, r *
 		siw.Handler.GetWithContentType(w, r, contentType)
 	})
 
+	handler = GetWithContentTypeContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetWithContentTypeContext is middleware setting the metadata of the GetWithContentType
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetWithContentTypeContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getWithContentType",
+			tags: nil,
+			path: "/get-with-type/{content_type}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetReport operation middleware
+func (siw *ServerInterfaceWrapper) GetReport(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
+
+	// ------------- Path parameter "report-id" -------------
+	var reportID int
+
+	if err := runtime.BindStyledParameter("simple", false, "report-id", chi.URLParam(r, "report-id"), &reportID); err != nil {
+		err = fmt.Errorf("invalid format for parameter report-id: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetReport(w, r, reportID)
+	})
+
+	handler = GetReportContext(handler).ServeHTTP
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetReportContext is middleware setting the metadata of the GetReport
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetReportContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getReport",
+			tags: nil,
+			path: "/reports/{report-id}.json",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetReservedKeyword operation middleware
-func (siw *ServerInterfaceWrapper) GetReservedKeyword(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetReservedKeyword(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.GetReservedKeyword(w, r)
 	})
 
+	handler = GetReservedKeywordContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetReservedKeywordContext is middleware setting the metadata of the GetReservedKeyword
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetReservedKeywordContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getReservedKeyword",
+			tags: nil,
+			path: "/reserved-keyword",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // CreateResource operation middleware
-func (siw *ServerInterfaceWrapper) CreateResource(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) CreateResource(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "argument" -------------
 	var argument Argument
@@ -518,12 +1093,29 @@ func (siw *ServerInterfaceWrapper) CreateResource(w http.ResponseWriter, r *http
 		siw.Handler.CreateResource(w, r, argument)
 	})
 
+	handler = CreateResourceContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// CreateResourceContext is middleware setting the metadata of the CreateResource
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func CreateResourceContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "createResource",
+			tags: nil,
+			path: "/resource/{argument}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // CreateResource2 operation middleware
-func (siw *ServerInterfaceWrapper) CreateResource2(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) CreateResource2(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "inline_argument" -------------
 	var inlineArgument int
@@ -549,12 +1141,29 @@ func (siw *ServerInterfaceWrapper) CreateResource2(w http.ResponseWriter, r *htt
 		siw.Handler.CreateResource2(w, r, inlineArgument, params)
 	})
 
+	handler = CreateResource2Context(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// CreateResource2Context is middleware setting the metadata of the CreateResource2
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func CreateResource2Context(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "createResource2",
+			tags: nil,
+			path: "/resource2/{inline_argument}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // UpdateResource3 operation middleware
-func (siw *ServerInterfaceWrapper) UpdateResource3(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) UpdateResource3(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	// ------------- Path parameter "fallthrough" -------------
 	var pFallthrough int
@@ -569,23 +1178,57 @@ func (siw *ServerInterfaceWrapper) UpdateResource3(w http.ResponseWriter, r *htt
 		siw.Handler.UpdateResource3(w, r, pFallthrough)
 	})
 
+	handler = UpdateResource3Context(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// UpdateResource3Context is middleware setting the metadata of the UpdateResource3
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func UpdateResource3Context(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "updateResource3",
+			tags: nil,
+			path: "/resource3/{fallthrough}",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetResponseWithReference operation middleware
-func (siw *ServerInterfaceWrapper) GetResponseWithReference(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetResponseWithReference(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.GetResponseWithReference(w, r)
 	})
 
+	handler = GetResponseWithReferenceContext(handler).ServeHTTP
+
 	handler(w, r.WithContext(ctx))
 }
 
+// GetResponseWithReferenceContext is middleware setting the metadata of the GetResponseWithReference
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetResponseWithReferenceContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getResponseWithReference",
+			tags: nil,
+			path: "/response-with-reference",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetWithTaggedMiddleware operation middleware
-func (siw *ServerInterfaceWrapper) GetWithTaggedMiddleware(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) GetWithTaggedMiddleware(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.GetWithTaggedMiddleware(w, r)
@@ -593,13 +1236,29 @@ func (siw *ServerInterfaceWrapper) GetWithTaggedMiddleware(w http.ResponseWriter
 
 	// Operation specific middleware
 	handler = siw.Middlewares["pathMiddleware"](handler).ServeHTTP
+	handler = GetWithTaggedMiddlewareContext(handler).ServeHTTP
 
 	handler(w, r.WithContext(ctx))
 }
 
+// GetWithTaggedMiddlewareContext is middleware setting the metadata of the GetWithTaggedMiddleware
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func GetWithTaggedMiddlewareContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "getWithTaggedMiddleware",
+			tags: nil,
+			path: "/with-tagged-middleware",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // PostWithTaggedMiddleware operation middleware
-func (siw *ServerInterfaceWrapper) PostWithTaggedMiddleware(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+func (siw *ServerInterfaceWrapper) PostWithTaggedMiddleware(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop
+	ctx := context.WithValue(r.Context(), errorHandlerContextKey{}, siw.ErrorHandlerFunc)
 
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.PostWithTaggedMiddleware(w, r)
@@ -608,10 +1267,84 @@ func (siw *ServerInterfaceWrapper) PostWithTaggedMiddleware(w http.ResponseWrite
 	// Operation specific middleware
 	handler = siw.Middlewares["pathMiddleware"](handler).ServeHTTP
 	handler = siw.Middlewares["operationMiddleware"](handler).ServeHTTP
+	handler = PostWithTaggedMiddlewareContext(handler).ServeHTTP
 
 	handler(w, r.WithContext(ctx))
 }
 
+// PostWithTaggedMiddlewareContext is middleware setting the metadata of the PostWithTaggedMiddleware
+// operation in the context of requests, for OperationIDFromContext,
+// OperationTagsFromContext and OperationPathFromContext. The server sets it
+// before the middleware of the operation.
+func PostWithTaggedMiddlewareContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), operationContextKey{}, operationMetadata{
+			id:   "postWithTaggedMiddleware",
+			tags: nil,
+			path: "/with-tagged-middleware",
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// operationContextKey is the context key of the operationMetadata set by the
+// <OperationID>Context middleware.
+type operationContextKey struct{}
+
+// operationMetadata describes the operation of a request.
+type operationMetadata struct {
+	id   string
+	tags []string
+	path string
+}
+
+// OperationIDFromContext returns the operationId of the operation of the
+// request of ctx, or "" outside of the operations of the server.
+func OperationIDFromContext(ctx context.Context) string {
+	op, _ := ctx.Value(operationContextKey{}).(operationMetadata)
+	return op.id
+}
+
+// OperationTagsFromContext returns the tags of the operation of the request
+// of ctx, which the caller must not modify.
+func OperationTagsFromContext(ctx context.Context) []string {
+	op, _ := ctx.Value(operationContextKey{}).(operationMetadata)
+	return op.tags
+}
+
+// OperationPathFromContext returns the path template of the operation of the
+// request of ctx in the spec, like /pets/{id}.
+func OperationPathFromContext(ctx context.Context) string {
+	op, _ := ctx.Value(operationContextKey{}).(operationMetadata)
+	return op.path
+}
+
+// errorHandlerContextKey is the context key of the ErrorHandlerFunc of the
+// server, for HandleError.
+type errorHandlerContextKey struct{}
+
+// HandleError writes err, which a handler failed with, with the
+// ErrorHandlerFunc of the server of r. Handlers pass an
+// *apierrors.APIError, like apierrors.ErrNotFound, to respond with its
+// status code; other errors are responded to with an HTTP/500.
+func HandleError(w http.ResponseWriter, r *http.Request, err error) {
+	if !errors.As(err, new(*apierrors.APIError)) {
+		err = apierrors.Wrap(http.StatusInternalServerError, err)
+	}
+	handle, ok := r.Context().Value(errorHandlerContextKey{}).(func(w http.ResponseWriter, r *http.Request, err error))
+	if !ok || handle == nil {
+		handle = defaultErrorHandler
+	}
+	handle(w, r, err)
+}
+
+// defaultErrorHandler writes the errors of the server as plain text, with
+// the status code of an *apierrors.APIError, or an HTTP/400 for the errors
+// binding the parameters of requests.
+func defaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	apierrors.Write(w, err, http.StatusBadRequest)
+}
+
 type UnescapedCookieParamError struct {
 	error
 }
@@ -636,19 +1369,23 @@ type ServerOptions struct {
 	BaseRouter       chi.Router
 	Middlewares      map[string]func(http.Handler) http.Handler
 	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+	// Tag restricts the routes to the operations with this tag, none if
+	// empty.
+	Tag string
 }
 
 type ServerOption func(*ServerOptions)
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface, opts ...ServerOption) http.Handler {
+// Handler creates http.Handler with routing matching OpenAPI spec. The routes
+// are registered on a chi router of its own, unless another one is given
+// with WithRouter, so that the handler can be served as it is, by an
+// http.Server or httptest.NewServer.
+func Handler(si ServerInterface, opts ...ServerOption) http.Handler { //nolint:wsl,cyclop
 	options := &ServerOptions{
-		BaseURL:     "/",
-		BaseRouter:  chi.NewRouter(),
-		Middlewares: make(map[string]func(http.Handler) http.Handler),
-		ErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		},
+		BaseURL:          "/",
+		BaseRouter:       chi.NewRouter(),
+		Middlewares:      make(map[string]func(http.Handler) http.Handler),
+		ErrorHandlerFunc: defaultErrorHandler,
 	}
 
 	for _, f := range opts {
@@ -669,12 +1406,19 @@ func Handler(si ServerInterface, opts ...ServerOption) http.Handler {
 		}
 	}
 
+	if options.Tag != "" {
+		panic("goapi-gen: no operation is tagged " + options.Tag)
+	}
+
 	r.Route(options.BaseURL, func(r chi.Router) {
+		r.Get("/dates/{year}-{month}", wrapper.GetMonth)
 		r.Get("/every-type-optional", wrapper.GetEveryTypeOptional)
+		r.Get("/files/{name}:download", wrapper.DownloadFile)
 		r.Get("/get-simple", wrapper.GetSimple)
 		r.Get("/get-with-args", wrapper.GetWithArgs)
 		r.Get("/get-with-references/{global_argument}/{argument}", wrapper.GetWithReferences)
 		r.Get("/get-with-type/{content_type}", wrapper.GetWithContentType)
+		r.Get("/reports/{report-id}.json", wrapper.GetReport)
 		r.Get("/reserved-keyword", wrapper.GetReservedKeyword)
 		r.Post("/resource/{argument}", wrapper.CreateResource)
 		r.Post("/resource2/{inline_argument}", wrapper.CreateResource2)
@@ -687,6 +1431,25 @@ func Handler(si ServerInterface, opts ...ServerOption) http.Handler {
 	return r
 }
 
+// HandlerFromMux registers the routes of the OpenAPI spec on r, and returns r.
+func HandlerFromMux(r chi.Router, si ServerInterface, opts ...ServerOption) http.Handler {
+	return Handler(si, append([]ServerOption{WithRouter(r)}, opts...)...)
+}
+
+// HandlerFromMuxWithBaseURL registers the routes of the OpenAPI spec on r,
+// under baseURL, and returns r.
+func HandlerFromMuxWithBaseURL(r chi.Router, si ServerInterface, baseURL string, opts ...ServerOption) http.Handler {
+	return Handler(si, append([]ServerOption{WithRouter(r), WithServerBaseURL(baseURL)}, opts...)...)
+}
+
+// HandlerFromMuxForTag registers the routes of the operations of the OpenAPI
+// spec tagged with tag on r, under baseURL, and returns r. The routes of
+// different tags can be registered on the groups of a router, each with its
+// own middleware stack.
+func HandlerFromMuxForTag(r chi.Router, si ServerInterface, tag string, baseURL string, opts ...ServerOption) http.Handler {
+	return Handler(si, append([]ServerOption{WithRouter(r), WithServerBaseURL(baseURL), WithTag(tag)}, opts...)...)
+}
+
 func WithRouter(r chi.Router) ServerOption {
 	return func(s *ServerOptions) {
 		s.BaseRouter = r
@@ -699,6 +1462,13 @@ func WithServerBaseURL(url string) ServerOption {
 	}
 }
 
+// WithTag restricts the routes to the operations tagged with tag.
+func WithTag(tag string) ServerOption {
+	return func(s *ServerOptions) {
+		s.Tag = tag
+	}
+}
+
 func WithMiddleware(key string, middleware func(http.Handler) http.Handler) ServerOption {
 	return func(s *ServerOptions) {
 		s.Middlewares[key] = middleware
@@ -24,9 +24,18 @@ var _ ServerInterface = &ServerInterfaceMock{}
 // 			CreateResource2Func: func(w http.ResponseWriter, r *http.Request, inlineArgument int, params CreateResource2Params)  {
 // 				panic("mock out the CreateResource2 method")
 // 			},
+// 			DownloadFileFunc: func(w http.ResponseWriter, r *http.Request, name string)  {
+// 				panic("mock out the DownloadFile method")
+// 			},
 // 			GetEveryTypeOptionalFunc: func(w http.ResponseWriter, r *http.Request)  {
 // 				panic("mock out the GetEveryTypeOptional method")
 // 			},
+// 			GetMonthFunc: func(w http.ResponseWriter, r *http.Request, year int, month int)  {
+// 				panic("mock out the GetMonth method")
+// 			},
+// 			GetReportFunc: func(w http.ResponseWriter, r *http.Request, reportID int)  {
+// 				panic("mock out the GetReport method")
+// 			},
 // 			GetReservedKeywordFunc: func(w http.ResponseWriter, r *http.Request)  {
 // 				panic("mock out the GetReservedKeyword method")
 // 			},
@@ -67,9 +76,18 @@ type ServerInterfaceMock struct {
 	// CreateResource2Func mocks the CreateResource2 method.
 	CreateResource2Func func(w http.ResponseWriter, r *http.Request, inlineArgument int, params CreateResource2Params)
 
+	// DownloadFileFunc mocks the DownloadFile method.
+	DownloadFileFunc func(w http.ResponseWriter, r *http.Request, name string)
+
 	// GetEveryTypeOptionalFunc mocks the GetEveryTypeOptional method.
 	GetEveryTypeOptionalFunc func(w http.ResponseWriter, r *http.Request)
 
+	// GetMonthFunc mocks the GetMonth method.
+	GetMonthFunc func(w http.ResponseWriter, r *http.Request, year int, month int)
+
+	// GetReportFunc mocks the GetReport method.
+	GetReportFunc func(w http.ResponseWriter, r *http.Request, reportID int)
+
 	// GetReservedKeywordFunc mocks the GetReservedKeyword method.
 	GetReservedKeywordFunc func(w http.ResponseWriter, r *http.Request)
 
@@ -119,6 +137,15 @@ type ServerInterfaceMock struct {
 			// Params is the params argument value.
 			Params CreateResource2Params
 		}
+		// DownloadFile holds details about calls to the DownloadFile method.
+		DownloadFile []struct {
+			// W is the w argument value.
+			W http.ResponseWriter
+			// R is the r argument value.
+			R *http.Request
+			// Name is the name argument value.
+			Name string
+		}
 		// GetEveryTypeOptional holds details about calls to the GetEveryTypeOptional method.
 		GetEveryTypeOptional []struct {
 			// W is the w argument value.
@@ -126,6 +153,26 @@ type ServerInterfaceMock struct {
 			// R is the r argument value.
 			R *http.Request
 		}
+		// GetMonth holds details about calls to the GetMonth method.
+		GetMonth []struct {
+			// W is the w argument value.
+			W http.ResponseWriter
+			// R is the r argument value.
+			R *http.Request
+			// Year is the year argument value.
+			Year int
+			// Month is the month argument value.
+			Month int
+		}
+		// GetReport holds details about calls to the GetReport method.
+		GetReport []struct {
+			// W is the w argument value.
+			W http.ResponseWriter
+			// R is the r argument value.
+			R *http.Request
+			// ReportID is the reportID argument value.
+			ReportID int
+		}
 		// GetReservedKeyword holds details about calls to the GetReservedKeyword method.
 		GetReservedKeyword []struct {
 			// W is the w argument value.
@@ -202,7 +249,10 @@ type ServerInterfaceMock struct {
 	}
 	lockCreateResource           sync.RWMutex
 	lockCreateResource2          sync.RWMutex
+	lockDownloadFile             sync.RWMutex
 	lockGetEveryTypeOptional     sync.RWMutex
+	lockGetMonth                 sync.RWMutex
+	lockGetReport                sync.RWMutex
 	lockGetReservedKeyword       sync.RWMutex
 	lockGetResponseWithReference sync.RWMutex
 	lockGetSimple                sync.RWMutex
@@ -236,6 +286,7 @@ func (mock *ServerInterfaceMock) CreateResource(w http.ResponseWriter, r *http.R
 
 // CreateResourceCalls gets all the calls that were made to CreateResource.
 // Check the length with:
+//
 //     len(mockedServerInterface.CreateResourceCalls())
 func (mock *ServerInterfaceMock) CreateResourceCalls() []struct {
 	W        http.ResponseWriter
@@ -277,6 +328,7 @@ func (mock *ServerInterfaceMock) CreateResource2(w http.ResponseWriter, r *http.
 
 // CreateResource2Calls gets all the calls that were made to CreateResource2.
 // Check the length with:
+//
 //     len(mockedServerInterface.CreateResource2Calls())
 func (mock *ServerInterfaceMock) CreateResource2Calls() []struct {
 	W              http.ResponseWriter
@@ -296,6 +348,46 @@ func (mock *ServerInterfaceMock) CreateResource2Calls() []struct {
 	return calls
 }
 
+// DownloadFile calls DownloadFileFunc.
+func (mock *ServerInterfaceMock) DownloadFile(w http.ResponseWriter, r *http.Request, name string) {
+	if mock.DownloadFileFunc == nil {
+		panic("ServerInterfaceMock.DownloadFileFunc: method is nil but ServerInterface.DownloadFile was just called")
+	}
+	callInfo := struct {
+		W    http.ResponseWriter
+		R    *http.Request
+		Name string
+	}{
+		W:    w,
+		R:    r,
+		Name: name,
+	}
+	mock.lockDownloadFile.Lock()
+	mock.calls.DownloadFile = append(mock.calls.DownloadFile, callInfo)
+	mock.lockDownloadFile.Unlock()
+	mock.DownloadFileFunc(w, r, name)
+}
+
+// DownloadFileCalls gets all the calls that were made to DownloadFile.
+// Check the length with:
+//
+//     len(mockedServerInterface.DownloadFileCalls())
+func (mock *ServerInterfaceMock) DownloadFileCalls() []struct {
+	W    http.ResponseWriter
+	R    *http.Request
+	Name string
+} {
+	var calls []struct {
+		W    http.ResponseWriter
+		R    *http.Request
+		Name string
+	}
+	mock.lockDownloadFile.RLock()
+	calls = mock.calls.DownloadFile
+	mock.lockDownloadFile.RUnlock()
+	return calls
+}
+
 // GetEveryTypeOptional calls GetEveryTypeOptionalFunc.
 func (mock *ServerInterfaceMock) GetEveryTypeOptional(w http.ResponseWriter, r *http.Request) {
 	if mock.GetEveryTypeOptionalFunc == nil {
@@ -316,6 +408,7 @@ func (mock *ServerInterfaceMock) GetEveryTypeOptional(w http.ResponseWriter, r *
 
 // GetEveryTypeOptionalCalls gets all the calls that were made to GetEveryTypeOptional.
 // Check the length with:
+//
 //     len(mockedServerInterface.GetEveryTypeOptionalCalls())
 func (mock *ServerInterfaceMock) GetEveryTypeOptionalCalls() []struct {
 	W http.ResponseWriter
@@ -331,6 +424,90 @@ func (mock *ServerInterfaceMock) GetEveryTypeOptionalCalls() []struct {
 	return calls
 }
 
+// GetMonth calls GetMonthFunc.
+func (mock *ServerInterfaceMock) GetMonth(w http.ResponseWriter, r *http.Request, year int, month int) {
+	if mock.GetMonthFunc == nil {
+		panic("ServerInterfaceMock.GetMonthFunc: method is nil but ServerInterface.GetMonth was just called")
+	}
+	callInfo := struct {
+		W     http.ResponseWriter
+		R     *http.Request
+		Year  int
+		Month int
+	}{
+		W:     w,
+		R:     r,
+		Year:  year,
+		Month: month,
+	}
+	mock.lockGetMonth.Lock()
+	mock.calls.GetMonth = append(mock.calls.GetMonth, callInfo)
+	mock.lockGetMonth.Unlock()
+	mock.GetMonthFunc(w, r, year, month)
+}
+
+// GetMonthCalls gets all the calls that were made to GetMonth.
+// Check the length with:
+//
+//     len(mockedServerInterface.GetMonthCalls())
+func (mock *ServerInterfaceMock) GetMonthCalls() []struct {
+	W     http.ResponseWriter
+	R     *http.Request
+	Year  int
+	Month int
+} {
+	var calls []struct {
+		W     http.ResponseWriter
+		R     *http.Request
+		Year  int
+		Month int
+	}
+	mock.lockGetMonth.RLock()
+	calls = mock.calls.GetMonth
+	mock.lockGetMonth.RUnlock()
+	return calls
+}
+
+// GetReport calls GetReportFunc.
+func (mock *ServerInterfaceMock) GetReport(w http.ResponseWriter, r *http.Request, reportID int) {
+	if mock.GetReportFunc == nil {
+		panic("ServerInterfaceMock.GetReportFunc: method is nil but ServerInterface.GetReport was just called")
+	}
+	callInfo := struct {
+		W        http.ResponseWriter
+		R        *http.Request
+		ReportID int
+	}{
+		W:        w,
+		R:        r,
+		ReportID: reportID,
+	}
+	mock.lockGetReport.Lock()
+	mock.calls.GetReport = append(mock.calls.GetReport, callInfo)
+	mock.lockGetReport.Unlock()
+	mock.GetReportFunc(w, r, reportID)
+}
+
+// GetReportCalls gets all the calls that were made to GetReport.
+// Check the length with:
+//
+//     len(mockedServerInterface.GetReportCalls())
+func (mock *ServerInterfaceMock) GetReportCalls() []struct {
+	W        http.ResponseWriter
+	R        *http.Request
+	ReportID int
+} {
+	var calls []struct {
+		W        http.ResponseWriter
+		R        *http.Request
+		ReportID int
+	}
+	mock.lockGetReport.RLock()
+	calls = mock.calls.GetReport
+	mock.lockGetReport.RUnlock()
+	return calls
+}
+
 // GetReservedKeyword calls GetReservedKeywordFunc.
 func (mock *ServerInterfaceMock) GetReservedKeyword(w http.ResponseWriter, r *http.Request) {
 	if mock.GetReservedKeywordFunc == nil {
@@ -351,6 +528,7 @@ func (mock *ServerInterfaceMock) GetReservedKeyword(w http.ResponseWriter, r *ht
 
 // GetReservedKeywordCalls gets all the calls that were made to GetReservedKeyword.
 // Check the length with:
+//
 //     len(mockedServerInterface.GetReservedKeywordCalls())
 func (mock *ServerInterfaceMock) GetReservedKeywordCalls() []struct {
 	W http.ResponseWriter
@@ -386,6 +564,7 @@ func (mock *ServerInterfaceMock) GetResponseWithReference(w http.ResponseWriter,
 
 // GetResponseWithReferenceCalls gets all the calls that were made to GetResponseWithReference.
 // Check the length with:
+//
 //     len(mockedServerInterface.GetResponseWithReferenceCalls())
 func (mock *ServerInterfaceMock) GetResponseWithReferenceCalls() []struct {
 	W http.ResponseWriter
@@ -421,6 +600,7 @@ func (mock *ServerInterfaceMock) GetSimple(w http.ResponseWriter, r *http.Reques
 
 // GetSimpleCalls gets all the calls that were made to GetSimple.
 // Check the length with:
+//
 //     len(mockedServerInterface.GetSimpleCalls())
 func (mock *ServerInterfaceMock) GetSimpleCalls() []struct {
 	W http.ResponseWriter
@@ -458,6 +638,7 @@ func (mock *ServerInterfaceMock) GetWithArgs(w http.ResponseWriter, r *http.Requ
 
 // GetWithArgsCalls gets all the calls that were made to GetWithArgs.
 // Check the length with:
+//
 //     len(mockedServerInterface.GetWithArgsCalls())
 func (mock *ServerInterfaceMock) GetWithArgsCalls() []struct {
 	W      http.ResponseWriter
@@ -497,6 +678,7 @@ func (mock *ServerInterfaceMock) GetWithContentType(w http.ResponseWriter, r *ht
 
 // GetWithContentTypeCalls gets all the calls that were made to GetWithContentType.
 // Check the length with:
+//
 //     len(mockedServerInterface.GetWithContentTypeCalls())
 func (mock *ServerInterfaceMock) GetWithContentTypeCalls() []struct {
 	W           http.ResponseWriter
@@ -538,6 +720,7 @@ func (mock *ServerInterfaceMock) GetWithReferences(w http.ResponseWriter, r *htt
 
 // GetWithReferencesCalls gets all the calls that were made to GetWithReferences.
 // Check the length with:
+//
 //     len(mockedServerInterface.GetWithReferencesCalls())
 func (mock *ServerInterfaceMock) GetWithReferencesCalls() []struct {
 	W              http.ResponseWriter
@@ -577,6 +760,7 @@ func (mock *ServerInterfaceMock) GetWithTaggedMiddleware(w http.ResponseWriter,
 
 // GetWithTaggedMiddlewareCalls gets all the calls that were made to GetWithTaggedMiddleware.
 // Check the length with:
+//
 //     len(mockedServerInterface.GetWithTaggedMiddlewareCalls())
 func (mock *ServerInterfaceMock) GetWithTaggedMiddlewareCalls() []struct {
 	W http.ResponseWriter
@@ -612,6 +796,7 @@ func (mock *ServerInterfaceMock) PostWithTaggedMiddleware(w http.ResponseWriter,
 
 // PostWithTaggedMiddlewareCalls gets all the calls that were made to PostWithTaggedMiddleware.
 // Check the length with:
+//
 //     len(mockedServerInterface.PostWithTaggedMiddlewareCalls())
 func (mock *ServerInterfaceMock) PostWithTaggedMiddlewareCalls() []struct {
 	W http.ResponseWriter
@@ -649,6 +834,7 @@ func (mock *ServerInterfaceMock) UpdateResource3(w http.ResponseWriter, r *http.
 
 // UpdateResource3Calls gets all the calls that were made to UpdateResource3.
 // Check the length with:
+//
 //     len(mockedServerInterface.UpdateResource3Calls())
 func (mock *ServerInterfaceMock) UpdateResource3Calls() []struct {
 	W            http.ResponseWriter
@@ -1,10 +1,14 @@
 package server
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/discord-gophers/goapi-gen/pkg/apierrors"
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -52,6 +56,49 @@ func TestErrorHandlerFunc(t *testing.T) {
 	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
 }
 
+func TestHandleError(t *testing.T) {
+	var handlerErr error
+	m := ServerInterfaceMock{}
+	m.GetSimpleFunc = func(w http.ResponseWriter, r *http.Request) {
+		HandleError(w, r, handlerErr)
+	}
+
+	serve := func(h http.Handler) (int, string) {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest("GET", "http://example.com/get-simple", nil))
+		body, _ := io.ReadAll(rr.Body)
+		return rr.Code, string(body)
+	}
+
+	h := Handler(&m, WithMiddlewares(noopMiddlewares))
+	handlerErr = apierrors.New(http.StatusNotFound, "no such thing")
+	code, body := serve(h)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, "no such thing\n", body)
+
+	handlerErr = apierrors.ErrConflict
+	code, body = serve(h)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, "Conflict\n", body)
+
+	// Other errors are internal, and aren't written.
+	handlerErr = errors.New("database is down")
+	code, body = serve(h)
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, "Internal Server Error\n", body)
+
+	// The errors go through the error handler of the server.
+	var handled error
+	h = Handler(&m, WithMiddlewares(noopMiddlewares), WithErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		handled = err
+		w.WriteHeader(apierrors.StatusCode(err, http.StatusBadRequest))
+	}))
+	code, _ = serve(h)
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.ErrorIs(t, handled, apierrors.ErrInternal)
+	assert.ErrorIs(t, handled, handlerErr)
+}
+
 func TestOmitMiddlewares(t *testing.T) {
 	defer func() {
 		// panics are the expected outcomes
@@ -113,3 +160,75 @@ func TestMiddlewareCalledWithOrder(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, []string{"first", "second"}, order)
 }
+
+func TestHandlerFromMuxWithBaseURL(t *testing.T) {
+	m := ServerInterfaceMock{}
+	m.DownloadFileFunc = func(w http.ResponseWriter, r *http.Request, name string) {
+		assert.Equal(t, "report.txt", name)
+	}
+	m.GetReportFunc = func(w http.ResponseWriter, r *http.Request, reportID int) {
+		assert.Equal(t, 42, reportID)
+	}
+	m.GetMonthFunc = func(w http.ResponseWriter, r *http.Request, year int, month int) {
+		assert.Equal(t, 2021, year)
+		assert.Equal(t, 7, month)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {})
+	h := HandlerFromMuxWithBaseURL(r, &m, "/api", WithMiddlewares(noopMiddlewares))
+
+	tests := []struct {
+		url        string
+		wantStatus int
+	}{
+		{"/api/files/report.txt:download", http.StatusOK},
+		{"/api/reports/42.json", http.StatusOK},
+		{"/api/dates/2021-7", http.StatusOK},
+		{"/health", http.StatusOK},
+		{"/files/report.txt:download", http.StatusNotFound},
+		{"/api/reports/42", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest("GET", "http://example.com"+tt.url, nil))
+		assert.Equal(t, tt.wantStatus, rr.Code, tt.url)
+	}
+
+	assert.Len(t, m.DownloadFileCalls(), 1)
+	assert.Len(t, m.GetReportCalls(), 1)
+	assert.Len(t, m.GetMonthCalls(), 1)
+}
+
+func TestOperationContext(t *testing.T) {
+	m := ServerInterfaceMock{}
+	m.PostWithTaggedMiddlewareFunc = func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "postWithTaggedMiddleware", OperationIDFromContext(r.Context()))
+	}
+
+	var id, path string
+	mw := map[string]func(http.Handler) http.Handler{
+		"pathMiddleware": func(h http.Handler) http.Handler { return h },
+		// The metadata is set before the middleware of the operation.
+		"operationMiddleware": func(h http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				id, path = OperationIDFromContext(r.Context()), OperationPathFromContext(r.Context())
+				h.ServeHTTP(w, r)
+			})
+		},
+	}
+
+	h := Handler(&m, WithMiddlewares(mw))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "http://example.com/with-tagged-middleware", nil))
+	assert.Len(t, m.PostWithTaggedMiddlewareCalls(), 1)
+	assert.Equal(t, "postWithTaggedMiddleware", id)
+	assert.Equal(t, "/with-tagged-middleware", path)
+
+	// The middleware can be used outside of the server as well.
+	GetSimpleContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id = OperationIDFromContext(r.Context())
+		assert.Nil(t, OperationTagsFromContext(r.Context()))
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://example.com/", nil))
+	assert.Equal(t, "getSimple", id)
+	assert.Empty(t, OperationIDFromContext(httptest.NewRequest("GET", "http://example.com/", nil).Context()))
+}
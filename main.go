@@ -18,11 +18,16 @@ import (
 	_ "embed"
 	"errors"
 	"fmt"
+	"go/token"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/discord-gophers/goapi-gen/pkg/codegen"
+	"github.com/discord-gophers/goapi-gen/pkg/config"
+	"github.com/discord-gophers/goapi-gen/pkg/loader"
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/kenshaw/snaker"
 	"github.com/urfave/cli/v2"
 )
@@ -30,39 +35,99 @@ import (
 var Version = "v0.0.1-alpha"
 
 const (
-	PackageKey        = "package"
-	GenerateKey       = "generate"
-	OutKey            = "out"
-	IncludeTagsKey    = "include-tags"
-	ExcludeTagsKey    = "exclude-tags"
-	TemplatesKey      = "templates"
-	ImportMappingKey  = "import-mapping"
-	ExcludeSchemasKey = "exclude-schemas"
-	AliasKey          = "alias"
-	InitialismsKey    = "initialisms"
-	ConfigKey         = "config"
+	PackageKey           = "package"
+	GenerateKey          = "generate"
+	OutKey               = "out"
+	OutputDirKey         = "output-dir"
+	FormatKey            = "format"
+	IncludeTagsKey       = "include-tags"
+	ExcludeTagsKey       = "exclude-tags"
+	TemplatesKey         = "templates"
+	ImportMappingKey     = "import-mapping"
+	ExcludeSchemasKey    = "exclude-schemas"
+	AliasKey             = "alias"
+	InitialismsKey       = "initialisms"
+	ConfigKey            = "config"
+	ContextFirstKey      = "context-first"
+	NoOmitEmptyKey       = "no-omitempty"
+	GenerateExamplesKey  = "generate-examples"
+	GenerateMockKey      = "generate-mock"
+	GenerateTestsKey     = "generate-tests"
+	GenerateFixtureKey   = "generate-fixture"
+	EmitProtoKey         = "emit-proto-annotations"
+	GenerateClientKey    = "generate-client"
+	ClientLinksKey       = "client-links"
+	GenerateCallbacksKey = "generate-callbacks"
+	StrictExtensionsKey  = "strict-extensions"
+	FrameworkKey         = "framework"
+	FunctionalOptionsKey = "functional-options"
+	NullableAsPointerKey = "nullable-as-pointer"
+	ValidatorTagsKey     = "validator-tags"
+	ProtoTagsKey         = "proto-tags"
+	YAMLTagsKey          = "yaml-tags"
+	SQLTypesKey          = "sql-types"
+	PgxTypesKey          = "pgx-types"
+	EnforceTimeoutsKey   = "enforce-timeouts"
+	ServeSpecKey         = "serve-spec"
+	SpecPathKey          = "spec-path"
+	NoLintKey            = "nolint"
+	VersionPrefixKey     = "version-prefix"
+	GoVersionKey         = "go-version"
+	DeepCopyKey          = "deep-copy"
+	SwaggestKey          = "swaggest"
+	HTTPTimeoutKey       = "http-timeout"
+	MaxRedirectsKey      = "max-redirects"
+	DryRunKey            = "dry-run"
+	SpecDirKey           = "spec-dir"
+	ValidateKey          = "validate"
 )
 
-func run(c *cli.Context, cfg *config) error {
-	if c.Args().Len() == 0 && (cfg.Package == "" || cfg.Package == "-") {
+func run(c *cli.Context, cfg *config.Config) error {
+	file, err := specFile(c, cfg.SpecDir)
+	if err != nil {
+		return err
+	}
+	if file == "" && (cfg.Package == "" || cfg.Package == "-") {
 		return errors.New("package required when reading from stdin")
 	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if cfg.Out != "" && cfg.OutputDir != "" {
+		return errors.New("only one of an output file and an output directory can be set")
+	}
+	if cfg.GenerateExamples && cfg.Out == "" && cfg.OutputDir == "" {
+		return errors.New("an output file is required to generate examples")
+	}
+	if cfg.GenerateMock && cfg.Out == "" && cfg.OutputDir == "" {
+		return errors.New("an output file is required to generate a mock server")
+	}
+	if cfg.GenerateTests && cfg.Out == "" && cfg.OutputDir == "" {
+		return errors.New("an output file is required to generate tests")
+	}
+	if cfg.GenerateFixture && cfg.Out == "" && cfg.OutputDir == "" {
+		return errors.New("an output file is required to generate a test fixture")
+	}
+	if cfg.EmitProtoAnnotations && cfg.Out == "" && cfg.OutputDir == "" {
+		return errors.New("an output file is required to emit proto annotations")
+	}
+	if cfg.DryRun && cfg.Out == "" && cfg.OutputDir == "" {
+		return errors.New("an output file is required for a dry run")
+	}
 
 	if cfg.Package == "" {
-		path := c.Args().First()
+		// The root file of a directory is named after the spec format, so
+		// the directory names the package.
+		path := file
+		if cfg.SpecDir != "" {
+			path, _ = filepath.Abs(cfg.SpecDir)
+		}
 		baseName := filepath.Base(path)
 		nameParts := strings.Split(baseName, ".")
 		cfg.Package = codegen.ToSnakeCase(nameParts[0])
 	}
-
-	var err error
-	in := os.Stdin
-	if file := c.Args().Get(0); file != "" {
-		in, err = os.Open(file)
-		if err != nil {
-			return fmt.Errorf("could not not open %s: %v", file, err)
-		}
-		defer in.Close()
+	if !token.IsIdentifier(cfg.Package) {
+		return fmt.Errorf("invalid package name %q, set one with --%s", cfg.Package, PackageKey)
 	}
 
 	templates, err := parseTemplateOverrides(cfg.Templates)
@@ -71,11 +136,34 @@ func run(c *cli.Context, cfg *config) error {
 	}
 
 	opts := codegen.Options{
-		IncludeTags:    cfg.IncludeTags,
-		ExcludeTags:    cfg.ExcludeTags,
-		ExcludeSchemas: cfg.ExcludeSchemas,
-		UserTemplates:  templates,
-		ImportMapping:  cfg.ImportMapping,
+		IncludeTags:         cfg.IncludeTags,
+		ExcludeTags:         cfg.ExcludeTags,
+		ExcludeSchemas:      cfg.ExcludeSchemas,
+		UserTemplates:       templates,
+		ImportMapping:       cfg.ImportMapping,
+		ContextFirst:        cfg.ContextFirst,
+		NoOmitEmpty:         cfg.NoOmitEmpty,
+		StrictExtensions:    cfg.StrictExtensions,
+		Framework:           cfg.Framework,
+		Format:              cfg.Format,
+		FunctionalOptions:   cfg.FunctionalOptions,
+		GenerateClient:      cfg.GenerateClient,
+		ClientLinks:         cfg.ClientLinks,
+		GenerateCallbacks:   cfg.GenerateCallbacks,
+		SkipNullablePointer: !*cfg.NullableAsPointer,
+		ValidatorTags:       cfg.ValidatorTags,
+		ProtoTags:           cfg.ProtoTags,
+		YAMLTags:            cfg.YAMLTags,
+		SQLTypes:            cfg.SQLTypes,
+		PgxTypes:            cfg.PgxTypes,
+		EnforceTimeouts:     cfg.EnforceTimeouts,
+		ServeSpec:           cfg.ServeSpec,
+		SpecPath:            cfg.SpecPath,
+		NoLint:              cfg.NoLint,
+		VersionPrefix:       cfg.VersionPrefix,
+		GoVersion:           cfg.GoVersion,
+		DeepCopy:            cfg.DeepCopy,
+		Swaggest:            cfg.Swaggest,
 	}
 
 	for _, tgt := range cfg.Generate {
@@ -95,10 +183,19 @@ func run(c *cli.Context, cfg *config) error {
 		}
 	}
 
-	swagger, err := parseSwagger(in)
+	swagger, err := loadSpec(file, cfg)
 	if err != nil {
 		return fmt.Errorf("could not load spec: %v", err)
 	}
+	if cfg.SpecDir != "" {
+		// The files of the directory make up a single package.
+		codegen.Bundle(swagger, cfg.ImportMapping)
+	}
+	if cfg.ValidateSpec {
+		if err := loader.Validate(c.Context, swagger); err != nil {
+			return err
+		}
+	}
 
 	// NOTE(hhhapz): This might need to be changed in the future.
 	// We might want to be more nitpicky about which minor versions we support,
@@ -119,43 +216,197 @@ func run(c *cli.Context, cfg *config) error {
 		}
 	}
 
-	code, err := codegen.Generate(swagger, cfg.Package, opts)
+	w := &fileWriter{dryRun: cfg.DryRun, out: os.Stdout}
+	if cfg.OutputDir != "" {
+		if err := writeFiles(w, swagger, cfg, opts); err != nil {
+			return err
+		}
+	} else {
+		code, err := codegen.Generate(swagger, cfg.Package, opts)
+		if err != nil {
+			return fmt.Errorf("could not generate code: %v", err)
+		}
+
+		if cfg.Out == "" {
+			if _, err := os.Stdout.WriteString(code); err != nil {
+				return fmt.Errorf("could not write code: %v", err)
+			}
+		} else if err := w.WriteFile(cfg.Out, []byte(code)); err != nil {
+			return fmt.Errorf("could not write code: %v", err)
+		}
+	}
+
+	if cfg.GenerateExamples {
+		if err := writeExamples(w, swagger, cfg, opts); err != nil {
+			return err
+		}
+	}
+	// The fixture serves the mock server.
+	if cfg.GenerateMock || cfg.GenerateFixture {
+		if err := writeMock(w, swagger, cfg, opts); err != nil {
+			return err
+		}
+	}
+	if cfg.GenerateFixture {
+		if err := writeFixture(w, cfg, opts); err != nil {
+			return err
+		}
+	}
+	if cfg.GenerateTests {
+		if err := writeValidatorTests(w, swagger, cfg, opts); err != nil {
+			return err
+		}
+	}
+	if cfg.EmitProtoAnnotations {
+		if err := writeProto(w, swagger, cfg, opts); err != nil {
+			return err
+		}
+	}
+
+	return w.Err()
+}
+
+// writeFiles writes the generated code to the output directory, with one
+// file per tag.
+func writeFiles(w *fileWriter, swagger *openapi3.T, cfg *config.Config, opts codegen.Options) error {
+	files, err := codegen.GenerateFiles(swagger, cfg.Package, opts)
 	if err != nil {
 		return fmt.Errorf("could not generate code: %v", err)
 	}
 
-	out := os.Stdout
-	if cfg.Out != "" {
-		out, err = os.Create(cfg.Out)
-		if err != nil {
-			return fmt.Errorf("could not open output file: %v", err)
+	if !w.dryRun {
+		if err := os.MkdirAll(cfg.OutputDir, 0o755); err != nil {
+			return fmt.Errorf("could not create output directory: %v", err)
 		}
-		defer out.Close()
 	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := w.WriteFile(filepath.Join(cfg.OutputDir, name), []byte(files[name])); err != nil {
+			return fmt.Errorf("could not write code: %v", err)
+		}
+	}
+
+	return nil
+}
 
-	_, err = out.WriteString(code)
+// writeExamples writes the example constructors next to the output file, as
+// out_examples_test.go, or to examples_test.go in the output directory.
+func writeExamples(w *fileWriter, swagger *openapi3.T, cfg *config.Config, opts codegen.Options) error {
+	code, err := codegen.GenerateExamples(swagger, cfg.Package, opts)
 	if err != nil {
-		return fmt.Errorf("could not write code: %v", err)
+		return fmt.Errorf("could not generate examples: %v", err)
+	}
+
+	name := strings.TrimSuffix(cfg.Out, ".go") + "_examples_test.go"
+	if cfg.OutputDir != "" {
+		name = filepath.Join(cfg.OutputDir, "examples_test.go")
+	}
+	if err := w.WriteFile(name, []byte(code)); err != nil {
+		return fmt.Errorf("could not write examples: %v", err)
+	}
+
+	return nil
+}
+
+// writeMock writes the mock server to mock_server_test.go, next to the output
+// file or in the output directory.
+func writeMock(w *fileWriter, swagger *openapi3.T, cfg *config.Config, opts codegen.Options) error {
+	code, err := codegen.GenerateMock(swagger, cfg.Package, opts)
+	if err != nil {
+		return fmt.Errorf("could not generate mock server: %v", err)
+	}
+
+	dir := filepath.Dir(cfg.Out)
+	if cfg.OutputDir != "" {
+		dir = cfg.OutputDir
+	}
+	if err := w.WriteFile(filepath.Join(dir, "mock_server_test.go"), []byte(code)); err != nil {
+		return fmt.Errorf("could not write mock server: %v", err)
+	}
+
+	return nil
+}
+
+// writeFixture writes the test fixture to fixture_test.go, next to the output
+// file or in the output directory.
+func writeFixture(w *fileWriter, cfg *config.Config, opts codegen.Options) error {
+	code, err := codegen.GenerateFixture(cfg.Package, opts)
+	if err != nil {
+		return fmt.Errorf("could not generate test fixture: %v", err)
+	}
+
+	dir := filepath.Dir(cfg.Out)
+	if cfg.OutputDir != "" {
+		dir = cfg.OutputDir
+	}
+	if err := w.WriteFile(filepath.Join(dir, "fixture_test.go"), []byte(code)); err != nil {
+		return fmt.Errorf("could not write test fixture: %v", err)
+	}
+
+	return nil
+}
+
+// writeValidatorTests writes the tests of the generated validators next to the
+// output file, as out_validators_test.go, or to validators_test.go in the
+// output directory.
+func writeValidatorTests(w *fileWriter, swagger *openapi3.T, cfg *config.Config, opts codegen.Options) error {
+	code, err := codegen.GenerateValidatorTests(swagger, cfg.Package, opts)
+	if err != nil {
+		return fmt.Errorf("could not generate tests: %v", err)
+	}
+
+	name := strings.TrimSuffix(cfg.Out, ".go") + "_validators_test.go"
+	if cfg.OutputDir != "" {
+		name = filepath.Join(cfg.OutputDir, "validators_test.go")
+	}
+	if err := w.WriteFile(name, []byte(code)); err != nil {
+		return fmt.Errorf("could not write tests: %v", err)
+	}
+
+	return nil
+}
+
+// writeProto writes the grpc-gateway bindings of the operations next to the
+// output file, as out.proto, or to the package name .proto in the output
+// directory.
+func writeProto(w *fileWriter, swagger *openapi3.T, cfg *config.Config, opts codegen.Options) error {
+	code, err := codegen.GenerateProto(swagger, cfg.Package, opts)
+	if err != nil {
+		return fmt.Errorf("could not generate proto annotations: %v", err)
+	}
+
+	name := strings.TrimSuffix(cfg.Out, ".go") + ".proto"
+	if cfg.OutputDir != "" {
+		name = filepath.Join(cfg.OutputDir, cfg.Package+".proto")
+	}
+	if err := w.WriteFile(name, []byte(code)); err != nil {
+		return fmt.Errorf("could not write proto annotations: %v", err)
 	}
 
 	return nil
 }
 
 func main() {
+	defaults := config.Default()
 	f := &flagConfig{
-		GenerateTargets: cli.NewStringSlice("types", "server", "spec"),
+		GenerateTargets: cli.NewStringSlice(defaults.Generate...),
 		IncludeTags:     &cli.StringSlice{},
 		ExcludeTags:     &cli.StringSlice{},
 		ImportMapping:   &cli.StringSlice{},
 		ExcludeSchemas:  &cli.StringSlice{},
 		Initialisms:     &cli.StringSlice{},
+		NoLint:          cli.NewStringSlice(defaults.NoLint...),
 	}
 	app := &cli.App{
 		Name: "goapi-gen",
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:        PackageKey,
-				Aliases:     []string{"p"},
+				Aliases:     []string{"p", "package-name"},
 				Usage:       "The package name for generated code.",
 				DefaultText: "swagger file name",
 				Destination: &f.PackageName,
@@ -163,7 +414,7 @@ func main() {
 			&cli.StringSliceFlag{
 				Name:        GenerateKey,
 				Aliases:     []string{"g"},
-				Value:       cli.NewStringSlice("types", "server", "spec"),
+				Value:       cli.NewStringSlice(defaults.Generate...),
 				Usage:       `List of generation options.`,
 				DefaultText: "types,server,spec",
 				Destination: f.GenerateTargets,
@@ -175,6 +426,17 @@ func main() {
 				DefaultText: "<stdout>",
 				Destination: &f.OutputFile,
 			},
+			&cli.StringFlag{
+				Name:        OutputDirKey,
+				Usage:       "Output directory, with one file per tag instead of a single output file",
+				Destination: &f.OutputDir,
+			},
+			&cli.StringFlag{
+				Name:        FormatKey,
+				Usage:       "How to format the generated code: goimports, gofmt, which only removes the unused imports, or none",
+				Value:       defaults.Format,
+				Destination: &f.Format,
+			},
 			&cli.StringSliceFlag{
 				Name:        IncludeTagsKey,
 				Aliases:     []string{"t"},
@@ -220,6 +482,173 @@ func main() {
 				Usage:       "Add custom initialisms (i.e ID, API, URI)",
 				Destination: f.Initialisms,
 			},
+			&cli.StringFlag{
+				Name:        FrameworkKey,
+				Usage:       "The framework to generate the server for, chi, echo or connect",
+				Value:       defaults.Framework,
+				Destination: &f.Framework,
+			},
+			&cli.BoolFlag{
+				Name:        ContextFirstKey,
+				Usage:       "Pass the request context as the first argument of server methods",
+				Destination: &f.ContextFirst,
+			},
+			&cli.BoolFlag{
+				Name:        NoOmitEmptyKey,
+				Usage:       "Never add omitempty to json tags, even for optional fields",
+				Destination: &f.NoOmitEmpty,
+			},
+			&cli.BoolFlag{
+				Name:        GenerateExamplesKey,
+				Usage:       "Generate a test file with constructors for the examples in the spec",
+				Destination: &f.GenerateExamples,
+			},
+			&cli.BoolFlag{
+				Name:        GenerateMockKey,
+				Usage:       "Generate a test file with a mock implementation of the server interface",
+				Destination: &f.GenerateMock,
+			},
+			&cli.BoolFlag{
+				Name:        GenerateTestsKey,
+				Usage:       "Generate a test file with table driven tests for the enum validators",
+				Destination: &f.GenerateTests,
+			},
+			&cli.BoolFlag{
+				Name:        GenerateFixtureKey,
+				Usage:       "Generate a test file with a fixture serving the mock server with httptest, implies --generate-mock",
+				Destination: &f.GenerateFixture,
+			},
+			&cli.BoolFlag{
+				Name:        EmitProtoKey,
+				Usage:       "Write a .proto file next to the output file, with the google.api.http annotations of the operations for grpc-gateway",
+				Destination: &f.EmitProto,
+			},
+			&cli.BoolFlag{
+				Name:        GenerateClientKey,
+				Usage:       "Generate a typed HTTP client for the operations in the spec",
+				Destination: &f.GenerateClient,
+			},
+			&cli.BoolFlag{
+				Name:        ClientLinksKey,
+				Usage:       "Generate functions following the links of the responses of the client, with --generate-client",
+				Destination: &f.ClientLinks,
+			},
+			&cli.BoolFlag{
+				Name:        GenerateCallbacksKey,
+				Usage:       "Generate clients delivering the callbacks of the operations in the spec, with retries",
+				Destination: &f.GenerateCallbacks,
+			},
+			&cli.BoolFlag{
+				Name:        StrictExtensionsKey,
+				Usage:       "Fail on x- extensions which aren't known to the generator",
+				Destination: &f.StrictExtensions,
+			},
+			&cli.BoolFlag{
+				Name:        FunctionalOptionsKey,
+				Usage:       "Generate constructors and functional options for struct types",
+				Destination: &f.FunctionalOptions,
+			},
+			&cli.BoolFlag{
+				Name:        NullableAsPointerKey,
+				Usage:       "Use pointers for nullable properties, even when they're required",
+				Value:       *defaults.NullableAsPointer,
+				Destination: &f.NullableAsPointer,
+			},
+			&cli.BoolFlag{
+				Name:        ValidatorTagsKey,
+				Usage:       "Add go-playground/validator tags for the constraints in the spec",
+				Destination: &f.ValidatorTags,
+			},
+			&cli.BoolFlag{
+				Name:        ProtoTagsKey,
+				Usage:       "Add protobuf tags to struct fields, numbered by their position",
+				Destination: &f.ProtoTags,
+			},
+			&cli.BoolFlag{
+				Name:        YAMLTagsKey,
+				Usage:       "Add gopkg.in/yaml.v3 tags to struct fields, and YAML methods to the types with custom JSON handling",
+				Destination: &f.YAMLTags,
+			},
+			&cli.BoolFlag{
+				Name:        SQLTypesKey,
+				Usage:       "Use types implementing sql.Scanner and driver.Valuer for uuid strings",
+				Destination: &f.SQLTypes,
+			},
+			&cli.BoolFlag{
+				Name:        PgxTypesKey,
+				Usage:       "Use a type implementing the pgx/v5 scanner and valuer interfaces for uuid strings",
+				Destination: &f.PgxTypes,
+			},
+			&cli.BoolFlag{
+				Name:        DeepCopyKey,
+				Usage:       "Generate Kubernetes-style DeepCopy and DeepCopyInto methods for the types",
+				Destination: &f.DeepCopy,
+			},
+			&cli.BoolFlag{
+				Name:        SwaggestKey,
+				Usage:       "Generate RegisterSchemas, registering the schemas of the types with a swaggest/openapi-go reflector",
+				Destination: &f.Swaggest,
+			},
+			&cli.BoolFlag{
+				Name:        EnforceTimeoutsKey,
+				Usage:       "Cancel the context of operations after their x-timeout, and respond with a 504",
+				Destination: &f.EnforceTimeouts,
+			},
+			&cli.BoolFlag{
+				Name:        ServeSpecKey,
+				Usage:       "Generate SpecHandler, serving the spec, and SwaggerUIHandler, serving a Swagger UI page for it",
+				Destination: &f.ServeSpec,
+			},
+			&cli.StringFlag{
+				Name:        SpecPathKey,
+				Usage:       "The path of the SpecPath constant, to register SpecHandler at",
+				Value:       defaults.SpecPath,
+				Destination: &f.SpecPath,
+			},
+			&cli.StringSliceFlag{
+				Name:        NoLintKey,
+				Value:       cli.NewStringSlice(defaults.NoLint...),
+				Usage:       `The linters to add //nolint directives for, "" for none`,
+				DefaultText: strings.Join(defaults.NoLint, ","),
+				Destination: f.NoLint,
+			},
+			&cli.StringFlag{
+				Name:        VersionPrefixKey,
+				Usage:       "Prepend a prefix, like V2, to the names of all the generated types and operations",
+				Destination: &f.VersionPrefix,
+			},
+			&cli.StringFlag{
+				Name:        GoVersionKey,
+				Usage:       "The Go version of the generated code, like 1.21; from 1.18, it uses generics and any",
+				Destination: &f.GoVersion,
+			},
+			&cli.DurationFlag{
+				Name:        HTTPTimeoutKey,
+				Usage:       "Timeout for fetching specs from http:// and https:// URLs",
+				Value:       defaults.HTTPTimeout,
+				Destination: &f.HTTPTimeout,
+			},
+			&cli.IntFlag{
+				Name:        MaxRedirectsKey,
+				Usage:       "Maximum number of redirects followed when fetching specs from URLs",
+				Value:       *defaults.MaxRedirects,
+				Destination: &f.MaxRedirects,
+			},
+			&cli.BoolFlag{
+				Name:        DryRunKey,
+				Usage:       "Print a diff of the files which would change instead of writing them, and fail if any would",
+				Destination: &f.DryRun,
+			},
+			&cli.StringFlag{
+				Name:        SpecDirKey,
+				Usage:       "Generate from a spec split into the files of a directory, with an openapi.yaml or openapi.json root file",
+				Destination: &f.SpecDir,
+			},
+			&cli.BoolFlag{
+				Name:        ValidateKey,
+				Usage:       "Validate the spec against the OpenAPI specification before generating, reporting every error",
+				Destination: &f.Validate,
+			},
 			&cli.StringFlag{
 				Name:        ConfigKey,
 				Aliases:     []string{"c"},
@@ -230,6 +659,7 @@ func main() {
 		EnableBashCompletion: true,
 		Version:              Version,
 		Usage:                "Generate Go code from OpenAPI specification YAML",
+		ArgsUsage:            "[spec, or - for stdin]",
 		Action: func(c *cli.Context) error {
 			cfg, err := parseConfig(c, f)
 			if err != nil {
@@ -247,6 +677,60 @@ func main() {
 					return nil
 				},
 			},
+			{
+				Name:      "bundle",
+				Usage:     "merge a spec split into several files into a single resolved file",
+				ArgsUsage: "[spec]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "out",
+						Aliases:     []string{"o"},
+						Usage:       "Output file, in JSON if it ends with .json",
+						DefaultText: "<stdout>",
+					},
+					&cli.StringFlag{
+						Name:  SpecDirKey,
+						Usage: "Directory of the spec, with an openapi.yaml or openapi.json root file",
+					},
+				},
+				Action: bundle,
+			},
+			{
+				Name:      "diff",
+				Usage:     "write a changelog of the operations, requests and responses which changed between two specs",
+				ArgsUsage: "old-spec new-spec",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Usage:   "Format of the changelog: text, json, or markdown for a pull request comment",
+						Value:   "text",
+					},
+					&cli.BoolFlag{
+						Name:  "breaking-only",
+						Usage: "Only write the breaking changes, and exit with code 1 if there are any",
+					},
+					&cli.StringFlag{
+						Name:        "out",
+						Aliases:     []string{"o"},
+						Usage:       "Output file",
+						DefaultText: "<stdout>",
+					},
+				},
+				Action: diff,
+			},
+			{
+				Name:      "init-config",
+				Usage:     "write a starter configuration file, with the default settings",
+				ArgsUsage: "[file]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "force",
+						Usage: "Overwrite the file if it exists",
+					},
+				},
+				Action: initConfig,
+			},
 			{
 				Name:   "docs",
 				Usage:  "generate docs",
@@ -1,58 +1,83 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"net/url"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/discord-gophers/goapi-gen/pkg/codegen"
+	"github.com/discord-gophers/goapi-gen/pkg/config"
+	specloader "github.com/discord-gophers/goapi-gen/pkg/loader"
 	"github.com/getkin/kin-openapi/openapi3"
+	specyaml "github.com/ghodss/yaml"
 	"github.com/urfave/cli/v2"
-	"gopkg.in/yaml.v3"
 )
 
 type flagConfig struct {
-	PackageName     string
-	GenerateTargets *cli.StringSlice
-	OutputFile      string
-	IncludeTags     *cli.StringSlice
-	ExcludeTags     *cli.StringSlice
-	TemplatesDir    string
-	ImportMapping   *cli.StringSlice
-	ExcludeSchemas  *cli.StringSlice
-	AliasTypes      bool
-	Initialisms     *cli.StringSlice
-}
-
-type config struct {
-	Package        string            `yaml:"package"`
-	Generate       []string          `yaml:"generate"`
-	Out            string            `yaml:"output"`
-	IncludeTags    []string          `yaml:"include-tags"`
-	ExcludeTags    []string          `yaml:"exclude-tags"`
-	Templates      string            `yaml:"templates"`
-	ImportMapping  map[string]string `yaml:"import-mapping"`
-	ExcludeSchemas []string          `yaml:"exclude-schemas"`
-	Alias          bool              `yaml:"alias"`
-	Initialisms    []string          `yaml:"initialisms"`
+	PackageName       string
+	GenerateTargets   *cli.StringSlice
+	OutputFile        string
+	OutputDir         string
+	Format            string
+	IncludeTags       *cli.StringSlice
+	ExcludeTags       *cli.StringSlice
+	TemplatesDir      string
+	ImportMapping     *cli.StringSlice
+	ExcludeSchemas    *cli.StringSlice
+	AliasTypes        bool
+	Initialisms       *cli.StringSlice
+	ContextFirst      bool
+	NoOmitEmpty       bool
+	GenerateExamples  bool
+	GenerateMock      bool
+	GenerateTests     bool
+	GenerateFixture   bool
+	EmitProto         bool
+	GenerateClient    bool
+	ClientLinks       bool
+	GenerateCallbacks bool
+	StrictExtensions  bool
+	Framework         string
+	FunctionalOptions bool
+	NullableAsPointer bool
+	ValidatorTags     bool
+	ProtoTags         bool
+	YAMLTags          bool
+	SQLTypes          bool
+	PgxTypes          bool
+	EnforceTimeouts   bool
+	ServeSpec         bool
+	SpecPath          string
+	NoLint            *cli.StringSlice
+	VersionPrefix     string
+	GoVersion         string
+	DeepCopy          bool
+	Swaggest          bool
+	HTTPTimeout       time.Duration
+	MaxRedirects      int
+	DryRun            bool
+	SpecDir           string
+	Validate          bool
 }
 
 // parseConfig parses the flags and configuration file (if provided). all
 // configuration entries via a file will be overridden via the cli flags.
-func parseConfig(c *cli.Context, f *flagConfig) (*config, error) {
-	cfg := config{}
+func parseConfig(c *cli.Context, f *flagConfig) (*config.Config, error) {
+	cfg := &config.Config{}
 
 	// Load the configuration file first.
-	if c.IsSet("config") {
-		f, err := os.Open(c.String("config"))
-		if err != nil {
-			return nil, fmt.Errorf("could not open configuration file: %v", err)
-		}
-		defer f.Close()
-
-		if err = yaml.NewDecoder(f).Decode(&cfg); err != nil {
-			return nil, fmt.Errorf("could not decode yaml configuration: %v", err)
+	if c.IsSet(ConfigKey) {
+		var err error
+		if cfg, err = config.Load(c.String(ConfigKey)); err != nil {
+			return nil, err
 		}
 	}
 
@@ -62,6 +87,12 @@ func parseConfig(c *cli.Context, f *flagConfig) (*config, error) {
 	if cfg.Out == "" || c.IsSet(OutKey) {
 		cfg.Out = f.OutputFile
 	}
+	if cfg.OutputDir == "" || c.IsSet(OutputDirKey) {
+		cfg.OutputDir = f.OutputDir
+	}
+	if cfg.Format == "" || c.IsSet(FormatKey) {
+		cfg.Format = f.Format
+	}
 	if cfg.Generate == nil || c.IsSet(GenerateKey) {
 		cfg.Generate = splitString(f.GenerateTargets, ',')
 	}
@@ -90,8 +121,104 @@ func parseConfig(c *cli.Context, f *flagConfig) (*config, error) {
 	if cfg.Initialisms == nil || c.IsSet(InitialismsKey) {
 		cfg.Initialisms = splitString(f.Initialisms, ',')
 	}
+	if c.IsSet(ContextFirstKey) {
+		cfg.ContextFirst = f.ContextFirst
+	}
+	if c.IsSet(NoOmitEmptyKey) {
+		cfg.NoOmitEmpty = f.NoOmitEmpty
+	}
+	if c.IsSet(GenerateExamplesKey) {
+		cfg.GenerateExamples = f.GenerateExamples
+	}
+	if c.IsSet(GenerateMockKey) {
+		cfg.GenerateMock = f.GenerateMock
+	}
+	if c.IsSet(GenerateTestsKey) {
+		cfg.GenerateTests = f.GenerateTests
+	}
+	if c.IsSet(GenerateFixtureKey) {
+		cfg.GenerateFixture = f.GenerateFixture
+	}
+	if c.IsSet(EmitProtoKey) {
+		cfg.EmitProtoAnnotations = f.EmitProto
+	}
+	if c.IsSet(GenerateClientKey) {
+		cfg.GenerateClient = f.GenerateClient
+	}
+	if c.IsSet(ClientLinksKey) {
+		cfg.ClientLinks = f.ClientLinks
+	}
+	if c.IsSet(GenerateCallbacksKey) {
+		cfg.GenerateCallbacks = f.GenerateCallbacks
+	}
+	if c.IsSet(StrictExtensionsKey) {
+		cfg.StrictExtensions = f.StrictExtensions
+	}
+	if cfg.Framework == "" || c.IsSet(FrameworkKey) {
+		cfg.Framework = f.Framework
+	}
+	if c.IsSet(FunctionalOptionsKey) {
+		cfg.FunctionalOptions = f.FunctionalOptions
+	}
+	if cfg.NullableAsPointer == nil || c.IsSet(NullableAsPointerKey) {
+		cfg.NullableAsPointer = &f.NullableAsPointer
+	}
+	if c.IsSet(ValidatorTagsKey) {
+		cfg.ValidatorTags = f.ValidatorTags
+	}
+	if c.IsSet(ProtoTagsKey) {
+		cfg.ProtoTags = f.ProtoTags
+	}
+	if c.IsSet(YAMLTagsKey) {
+		cfg.YAMLTags = f.YAMLTags
+	}
+	if c.IsSet(SQLTypesKey) {
+		cfg.SQLTypes = f.SQLTypes
+	}
+	if c.IsSet(PgxTypesKey) {
+		cfg.PgxTypes = f.PgxTypes
+	}
+	if c.IsSet(DeepCopyKey) {
+		cfg.DeepCopy = f.DeepCopy
+	}
+	if c.IsSet(SwaggestKey) {
+		cfg.Swaggest = f.Swaggest
+	}
+	if c.IsSet(ServeSpecKey) {
+		cfg.ServeSpec = f.ServeSpec
+	}
+	if cfg.SpecPath == "" || c.IsSet(SpecPathKey) {
+		cfg.SpecPath = f.SpecPath
+	}
+	if c.IsSet(EnforceTimeoutsKey) {
+		cfg.EnforceTimeouts = f.EnforceTimeouts
+	}
+	if cfg.NoLint == nil || c.IsSet(NoLintKey) {
+		cfg.NoLint = splitString(f.NoLint, ',')
+	}
+	if c.IsSet(VersionPrefixKey) {
+		cfg.VersionPrefix = f.VersionPrefix
+	}
+	if c.IsSet(GoVersionKey) {
+		cfg.GoVersion = f.GoVersion
+	}
+	if cfg.HTTPTimeout == 0 || c.IsSet(HTTPTimeoutKey) {
+		cfg.HTTPTimeout = f.HTTPTimeout
+	}
+	if cfg.MaxRedirects == nil || c.IsSet(MaxRedirectsKey) {
+		cfg.MaxRedirects = &f.MaxRedirects
+	}
+	if c.IsSet(DryRunKey) {
+		cfg.DryRun = f.DryRun
+	}
+	if cfg.SpecDir == "" || c.IsSet(SpecDirKey) {
+		cfg.SpecDir = f.SpecDir
+	}
+	if c.IsSet(ValidateKey) {
+		cfg.ValidateSpec = f.Validate
+	}
 
-	return &cfg, nil
+	return cfg, nil
 }
 
 func parseTemplateOverrides(templatesDir string) (map[string]string, error) {
@@ -134,6 +261,8 @@ func parseMappings(slice *cli.StringSlice) (map[string]string, error) {
 	return result, nil
 }
 
+// parseSwagger loads the spec read from in, such as stdin. Its relative
+// references are resolved from the working directory.
 func parseSwagger(in io.Reader) (swagger *openapi3.T, err error) {
 	loader := openapi3.NewLoader()
 	loader.IsExternalRefsAllowed = true
@@ -143,7 +272,62 @@ func parseSwagger(in io.Reader) (swagger *openapi3.T, err error) {
 		return nil, fmt.Errorf("could not read: %v", err)
 	}
 
-	return loader.LoadFromData(buf)
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("could not get working directory: %v", err)
+	}
+	// The references are resolved from the directory of the location.
+	return loadSwagger(loader, buf, &url.URL{Path: filepath.ToSlash(filepath.Join(wd, stdinSpec))})
+}
+
+// parseSwaggerFile loads the spec in the file at name. Its relative
+// references are resolved from the directory of the file, rather than the
+// working directory.
+func parseSwaggerFile(name string) (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+
+	buf, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %v", name, err)
+	}
+
+	return loadSwagger(loader, buf, &url.URL{Path: filepath.ToSlash(name)})
+}
+
+// loadSwagger loads the spec in data with loader, resolving its references
+// relative to location. The spec is parsed as JSON if it is valid JSON, and
+// as YAML otherwise. Broken local references are all reported at once,
+// before the loader fails on the first one. OpenAPI 3.1 specs are converted to
+// 3.0 first.
+func loadSwagger(loader *openapi3.Loader, data []byte, location *url.URL) (*openapi3.T, error) {
+	data, err := specloader.ConvertOpenAPI31(data)
+	if err != nil {
+		return nil, err
+	}
+
+	swagger := &openapi3.T{}
+	if json.Valid(data) {
+		err = json.Unmarshal(data, swagger)
+	} else {
+		err = specyaml.Unmarshal(data, swagger)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if errs := codegen.ValidateRefs(swagger); len(errs) != 0 {
+		msgs := make([]string, len(errs))
+		for i, err := range errs {
+			msgs[i] = "\t" + err.Error()
+		}
+		return nil, fmt.Errorf("%d broken references:\n%s", len(errs), strings.Join(msgs, "\n"))
+	}
+
+	if err := loader.ResolveRefsIn(swagger, location); err != nil {
+		return nil, err
+	}
+	return swagger, nil
 }
 
 // This function splits a string along the specifed separator, but it
@@ -183,3 +367,37 @@ func splitString(slice *cli.StringSlice, sep rune) []string {
 	}
 	return parts
 }
+
+// defaultConfigFile is the file written by init-config without an argument.
+const defaultConfigFile = "goapi-gen.yaml"
+
+// initConfig writes the starter configuration to the file given as argument,
+// refusing to overwrite an existing one unless --force is set.
+func initConfig(c *cli.Context) error {
+	name := defaultConfigFile
+	if c.Args().Present() {
+		name = c.Args().First()
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if c.Bool("force") {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(name, flags, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists, overwrite it with --force", name)
+	}
+	if err != nil {
+		return fmt.Errorf("could not create configuration file: %v", err)
+	}
+	if _, err := f.WriteString(config.Starter); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write configuration file: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("could not write configuration file: %v", err)
+	}
+
+	fmt.Printf("wrote %s, use it with --%s %s\n", name, ConfigKey, name)
+	return nil
+}
@@ -0,0 +1,108 @@
+// Package apierrors provides errors carrying an HTTP status code, which the
+// handlers of generated servers fail with to respond with that status: chi
+// handlers pass them to the generated HandleError, and echo handlers return
+// them. The sentinel errors, like ErrNotFound, match any *APIError of their
+// status with errors.Is:
+//
+//	err := apierrors.New(http.StatusNotFound, "no such pet")
+//	errors.Is(err, apierrors.ErrNotFound) // true
+package apierrors
+
+import (
+	"errors"
+	"net/http"
+)
+
+// APIError is an error responded to with an HTTP status code.
+type APIError struct {
+	// Status is the HTTP status code of the response.
+	Status int
+	// Message is the text of the response, the status text of Status if it
+	// is empty.
+	Message string
+	// Err is the cause of the error, which isn't written in the response.
+	Err error
+}
+
+// The sentinel errors of the common HTTP error statuses.
+var (
+	ErrBadRequest          = &APIError{Status: http.StatusBadRequest}
+	ErrUnauthorized        = &APIError{Status: http.StatusUnauthorized}
+	ErrForbidden           = &APIError{Status: http.StatusForbidden}
+	ErrNotFound            = &APIError{Status: http.StatusNotFound}
+	ErrMethodNotAllowed    = &APIError{Status: http.StatusMethodNotAllowed}
+	ErrConflict            = &APIError{Status: http.StatusConflict}
+	ErrGone                = &APIError{Status: http.StatusGone}
+	ErrPreconditionFailed  = &APIError{Status: http.StatusPreconditionFailed}
+	ErrUnprocessableEntity = &APIError{Status: http.StatusUnprocessableEntity}
+	ErrTooManyRequests     = &APIError{Status: http.StatusTooManyRequests}
+	ErrInternal            = &APIError{Status: http.StatusInternalServerError}
+	ErrNotImplemented      = &APIError{Status: http.StatusNotImplemented}
+	ErrServiceUnavailable  = &APIError{Status: http.StatusServiceUnavailable}
+	ErrGatewayTimeout      = &APIError{Status: http.StatusGatewayTimeout}
+)
+
+// New returns an error responded to with status and message.
+func New(status int, message string) *APIError {
+	return &APIError{Status: status, Message: message}
+}
+
+// Wrap returns an error responded to with status and its status text,
+// caused by err.
+func Wrap(status int, err error) *APIError {
+	return &APIError{Status: status, Err: err}
+}
+
+// Text returns the text of the response to e.
+func (e *APIError) Text() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return http.StatusText(e.Status)
+}
+
+func (e *APIError) Error() string {
+	if e.Err != nil {
+		return e.Text() + ": " + e.Err.Error()
+	}
+	return e.Text()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel error of the status of e, so
+// that errors.Is(apierrors.New(http.StatusNotFound, "no such pet"),
+// apierrors.ErrNotFound) is true.
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	return ok && t.Status == e.Status && t.Message == "" && t.Err == nil
+}
+
+// StatusCode returns the status code of the first *APIError in the chain of
+// err, or fallback if it has none.
+func StatusCode(err error, fallback int) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Status
+	}
+	return fallback
+}
+
+// Write writes err to w as a plain text response. An *APIError in the chain
+// of err is written with its status code and text. Other errors are written
+// with the fallback status code, and their text for client errors, as the
+// text of server errors may hold internal details.
+func Write(w http.ResponseWriter, err error, fallback int) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		http.Error(w, apiErr.Text(), apiErr.Status)
+		return
+	}
+	if fallback >= http.StatusInternalServerError {
+		http.Error(w, http.StatusText(fallback), fallback)
+		return
+	}
+	http.Error(w, err.Error(), fallback)
+}
@@ -0,0 +1,55 @@
+package apierrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	err := New(http.StatusNotFound, "no such pet")
+	assert.EqualError(t, err, "no such pet")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrConflict)
+	assert.ErrorIs(t, fmt.Errorf("finding pet: %w", err), ErrNotFound)
+
+	// Sentinels don't match errors of their status with another message.
+	assert.NotErrorIs(t, ErrNotFound, err)
+
+	cause := errors.New("duplicate key")
+	err = Wrap(http.StatusConflict, cause)
+	assert.EqualError(t, err, "Conflict: duplicate key")
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.ErrorIs(t, err, cause)
+
+	assert.Equal(t, http.StatusConflict, StatusCode(fmt.Errorf("adding pet: %w", err), http.StatusInternalServerError))
+	assert.Equal(t, http.StatusInternalServerError, StatusCode(cause, http.StatusInternalServerError))
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		fallback int
+		status   int
+		body     string
+	}{
+		{"message", New(http.StatusNotFound, "no such pet"), http.StatusBadRequest, http.StatusNotFound, "no such pet\n"},
+		{"sentinel", ErrTooManyRequests, http.StatusBadRequest, http.StatusTooManyRequests, "Too Many Requests\n"},
+		{"cause", Wrap(http.StatusServiceUnavailable, errors.New("database is down")), http.StatusBadRequest, http.StatusServiceUnavailable, "Service Unavailable\n"},
+		{"client error", errors.New("invalid id"), http.StatusBadRequest, http.StatusBadRequest, "invalid id\n"},
+		{"server error", errors.New("database is down"), http.StatusInternalServerError, http.StatusInternalServerError, "Internal Server Error\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Write(rec, tt.err, tt.fallback)
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, tt.body, rec.Body.String())
+		})
+	}
+}
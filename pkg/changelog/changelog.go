@@ -0,0 +1,522 @@
+// Package changelog compares two versions of an OpenAPI spec, and lists the
+// operations added and removed between them, as well as the changes to the
+// requests and responses of the operations they both have. Each change is
+// classified as breaking or not, from the point of view of the clients of the
+// old version of the spec.
+package changelog
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Kind is the kind of a Change.
+type Kind string
+
+const (
+	// OperationAdded is an operation of the new spec only.
+	OperationAdded Kind = "operation-added"
+	// OperationRemoved is an operation of the old spec only.
+	OperationRemoved Kind = "operation-removed"
+	// RequestChanged is a change to the parameters or the request body of an
+	// operation.
+	RequestChanged Kind = "request-changed"
+	// ResponseChanged is a change to the responses of an operation.
+	ResponseChanged Kind = "response-changed"
+)
+
+// Change is a difference between two versions of a spec.
+type Change struct {
+	Kind Kind `json:"kind"`
+	// Operation is the method and path of the operation, eg. GET /pets.
+	Operation   string `json:"operation"`
+	OperationID string `json:"operationId,omitempty"`
+	// Location is the part of the operation which changed, eg. the request
+	// body application/json, and the path within its schema, if any.
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+	// Breaking reports whether clients of the old spec may fail with the new
+	// one.
+	Breaking bool `json:"breaking"`
+}
+
+// Changelog is the list of changes between two versions of a spec, ordered
+// by the path and method of their operation.
+type Changelog struct {
+	Changes []Change `json:"changes"`
+}
+
+// HasBreaking returns if one of the changes of c is breaking.
+func (c *Changelog) HasBreaking() bool {
+	for _, ch := range c.Changes {
+		if ch.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// BreakingOnly returns the changelog of the breaking changes of c.
+func (c *Changelog) BreakingOnly() *Changelog {
+	breaking := &Changelog{Changes: []Change{}}
+	for _, ch := range c.Changes {
+		if ch.Breaking {
+			breaking.Changes = append(breaking.Changes, ch)
+		}
+	}
+	return breaking
+}
+
+// methods are the HTTP methods of operations, in the order they are
+// compared.
+var methods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "TRACE", "CONNECT"}
+
+// Compare returns the changes from the spec oldSpec to newSpec.
+//
+// A change to a request is breaking when the new spec may reject a request
+// the old one accepted, such as a new required property or a removed enum
+// value. A change to a response is breaking when the new spec allows a
+// response the old one didn't, such as a removed property or a new enum
+// value.
+func Compare(oldSpec, newSpec *openapi3.T) *Changelog {
+	d := &differ{changes: []Change{}}
+
+	paths := map[string]bool{}
+	for path := range oldSpec.Paths {
+		paths[path] = true
+	}
+	for path := range newSpec.Paths {
+		paths[path] = true
+	}
+	sortedPaths := make([]string, 0, len(paths))
+	for path := range paths {
+		sortedPaths = append(sortedPaths, path)
+	}
+	sort.Strings(sortedPaths)
+
+	for _, path := range sortedPaths {
+		oldItem, newItem := oldSpec.Paths[path], newSpec.Paths[path]
+		for _, method := range methods {
+			oldOp, newOp := operation(oldItem, method), operation(newItem, method)
+			d.op = method + " " + path
+			switch {
+			case oldOp == nil && newOp == nil:
+				continue
+			case oldOp == nil:
+				d.opID = newOp.OperationID
+				d.add(OperationAdded, "", "operation added", false)
+			case newOp == nil:
+				d.opID = oldOp.OperationID
+				d.add(OperationRemoved, "", "operation removed", true)
+			default:
+				d.opID = newOp.OperationID
+				d.operation(oldItem, oldOp, newItem, newOp)
+			}
+		}
+	}
+	return &Changelog{Changes: d.changes}
+}
+
+// operation returns the operation of item for method, or nil if it has none.
+func operation(item *openapi3.PathItem, method string) *openapi3.Operation {
+	if item == nil {
+		return nil
+	}
+	return item.GetOperation(method)
+}
+
+// direction tells whether a schema describes a request or a response, which
+// decides whether its changes are breaking.
+type direction int
+
+const (
+	request direction = iota
+	response
+)
+
+// differ holds the state of Compare.
+type differ struct {
+	changes []Change
+
+	// The operation being compared.
+	op, opID string
+	kind     Kind
+
+	// The pairs of schemas being compared, to stop at recursive schemas.
+	seen map[[2]*openapi3.Schema]bool
+}
+
+// add records a change of the operation being compared.
+func (d *differ) add(kind Kind, location, message string, breaking bool) {
+	d.changes = append(d.changes, Change{
+		Kind:        kind,
+		Operation:   d.op,
+		OperationID: d.opID,
+		Location:    location,
+		Message:     message,
+		Breaking:    breaking,
+	})
+}
+
+// operation compares the requests and responses of an operation of both
+// specs, with the parameters of their path items.
+func (d *differ) operation(oldItem *openapi3.PathItem, oldOp *openapi3.Operation, newItem *openapi3.PathItem, newOp *openapi3.Operation) {
+	d.seen = map[[2]*openapi3.Schema]bool{}
+
+	d.kind = RequestChanged
+	d.parameters(parameters(oldItem, oldOp), parameters(newItem, newOp))
+	d.requestBody(oldOp.RequestBody, newOp.RequestBody)
+
+	d.kind = ResponseChanged
+	d.responses(oldOp.Responses, newOp.Responses)
+}
+
+// parameters returns the parameters of op, with those of its path item which
+// it doesn't override, by their location and name, eg. query limit.
+func parameters(item *openapi3.PathItem, op *openapi3.Operation) map[string]*openapi3.Parameter {
+	params := map[string]*openapi3.Parameter{}
+	for _, list := range []openapi3.Parameters{item.Parameters, op.Parameters} {
+		for _, ref := range list {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			params[ref.Value.In+" "+ref.Value.Name] = ref.Value
+		}
+	}
+	return params
+}
+
+// parameters compares the parameters of an operation.
+func (d *differ) parameters(oldParams, newParams map[string]*openapi3.Parameter) {
+	for _, key := range unionKeys(oldParams, newParams) {
+		oldParam, newParam := oldParams[key], newParams[key]
+		location := "parameter " + key
+		switch {
+		case oldParam == nil && newParam.Required:
+			d.add(d.kind, location, "required parameter added", true)
+		case oldParam == nil:
+			d.add(d.kind, location, "optional parameter added", false)
+		case newParam == nil:
+			d.add(d.kind, location, "parameter removed", true)
+		default:
+			if !oldParam.Required && newParam.Required {
+				d.add(d.kind, location, "parameter became required", true)
+			} else if oldParam.Required && !newParam.Required {
+				d.add(d.kind, location, "parameter became optional", false)
+			}
+			d.schema(request, location, "", oldParam.Schema, newParam.Schema)
+		}
+	}
+}
+
+// requestBody compares the request bodies of an operation.
+func (d *differ) requestBody(oldRef, newRef *openapi3.RequestBodyRef) {
+	var oldBody, newBody *openapi3.RequestBody
+	if oldRef != nil {
+		oldBody = oldRef.Value
+	}
+	if newRef != nil {
+		newBody = newRef.Value
+	}
+
+	switch {
+	case oldBody == nil && newBody == nil:
+		return
+	case oldBody == nil:
+		if newBody.Required {
+			d.add(d.kind, "request body", "required request body added", true)
+		} else {
+			d.add(d.kind, "request body", "optional request body added", false)
+		}
+		return
+	case newBody == nil:
+		d.add(d.kind, "request body", "request body removed", true)
+		return
+	}
+
+	if !oldBody.Required && newBody.Required {
+		d.add(d.kind, "request body", "request body became required", true)
+	} else if oldBody.Required && !newBody.Required {
+		d.add(d.kind, "request body", "request body became optional", false)
+	}
+	d.content(request, "request body", oldBody.Content, newBody.Content)
+}
+
+// responses compares the responses of an operation, by status code.
+func (d *differ) responses(oldResponses, newResponses openapi3.Responses) {
+	for _, status := range unionKeys(oldResponses, newResponses) {
+		oldRef, newRef := oldResponses[status], newResponses[status]
+		location := "response " + status
+		switch {
+		case oldRef == nil || oldRef.Value == nil:
+			d.add(d.kind, location, "response added", false)
+		case newRef == nil || newRef.Value == nil:
+			d.add(d.kind, location, "response removed", true)
+		default:
+			d.content(response, location, oldRef.Value.Content, newRef.Value.Content)
+		}
+	}
+}
+
+// content compares the media types of a request body or a response. Media
+// types are breaking to remove from either, as clients may only support
+// those.
+func (d *differ) content(dir direction, location string, oldContent, newContent openapi3.Content) {
+	for _, mediaType := range unionKeys(oldContent, newContent) {
+		oldMT, newMT := oldContent[mediaType], newContent[mediaType]
+		mtLocation := location + " " + mediaType
+		switch {
+		case oldMT == nil:
+			d.add(d.kind, mtLocation, "media type added", false)
+		case newMT == nil:
+			d.add(d.kind, mtLocation, "media type removed", true)
+		default:
+			d.schema(dir, mtLocation, "", oldMT.Schema, newMT.Schema)
+		}
+	}
+}
+
+// schema compares the schemas of a request or a response, at path within the
+// schema of location.
+func (d *differ) schema(dir direction, location, path string, oldRef, newRef *openapi3.SchemaRef) {
+	var oldSchema, newSchema *openapi3.Schema
+	if oldRef != nil {
+		oldSchema = oldRef.Value
+	}
+	if newRef != nil {
+		newSchema = newRef.Value
+	}
+	if oldSchema == nil || newSchema == nil {
+		// A missing schema allows anything, so adding one constrains the
+		// values, and removing one relaxes them.
+		if oldSchema != newSchema {
+			message := "schema added"
+			if newSchema == nil {
+				message = "schema removed"
+			}
+			d.change(dir, location, path, message, (newSchema == nil) == (dir == response))
+		}
+		return
+	}
+
+	pair := [2]*openapi3.Schema{oldSchema, newSchema}
+	if d.seen[pair] {
+		return
+	}
+	d.seen[pair] = true
+
+	if oldSchema.Type != newSchema.Type {
+		d.change(dir, location, path, fmt.Sprintf("type changed from %s to %s", typeName(oldSchema.Type), typeName(newSchema.Type)), true)
+		return
+	}
+	if oldSchema.Format != newSchema.Format {
+		d.change(dir, location, path, fmt.Sprintf("format changed from %s to %s", typeName(oldSchema.Format), typeName(newSchema.Format)), true)
+	}
+	if oldSchema.Nullable != newSchema.Nullable {
+		if newSchema.Nullable {
+			d.change(dir, location, path, "became nullable", dir == response)
+		} else {
+			d.change(dir, location, path, "became non-nullable", dir == request)
+		}
+	}
+	d.enum(dir, location, path, oldSchema.Enum, newSchema.Enum)
+	d.constraints(dir, location, path, oldSchema, newSchema)
+	d.properties(dir, location, path, oldSchema, newSchema)
+
+	d.schema(dir, location, path+"[]", oldSchema.Items, newSchema.Items)
+	if oldSchema.AdditionalProperties != nil && newSchema.AdditionalProperties != nil {
+		d.schema(dir, location, path+"[*]", oldSchema.AdditionalProperties, newSchema.AdditionalProperties)
+	}
+	for _, composition := range []struct {
+		name     string
+		old, new openapi3.SchemaRefs
+	}{
+		{"allOf", oldSchema.AllOf, newSchema.AllOf},
+		{"anyOf", oldSchema.AnyOf, newSchema.AnyOf},
+		{"oneOf", oldSchema.OneOf, newSchema.OneOf},
+	} {
+		if len(composition.old) != len(composition.new) {
+			d.change(dir, location, path, fmt.Sprintf("%s changed from %d to %d schemas", composition.name, len(composition.old), len(composition.new)), true)
+			continue
+		}
+		for i := range composition.old {
+			d.schema(dir, location, fmt.Sprintf("%s(%s %d)", path, composition.name, i), composition.old[i], composition.new[i])
+		}
+	}
+}
+
+// change records a change of the schema at path within the schema of
+// location.
+func (d *differ) change(dir direction, location, path, message string, breaking bool) {
+	if path != "" {
+		location += " " + path
+	}
+	d.add(d.kind, location, message, breaking)
+}
+
+// enum compares the enum values of a schema. Removing a value may reject a
+// request, and adding one may surprise a client with a response.
+func (d *differ) enum(dir direction, location, path string, oldEnum, newEnum []interface{}) {
+	if len(oldEnum) == 0 || len(newEnum) == 0 {
+		if len(oldEnum) != 0 {
+			d.change(dir, location, path, "enum removed", dir == response)
+		} else if len(newEnum) != 0 {
+			d.change(dir, location, path, "enum added", dir == request)
+		}
+		return
+	}
+	for _, v := range oldEnum {
+		if !containsValue(newEnum, v) {
+			d.change(dir, location, path, fmt.Sprintf("enum value %v removed", v), dir == request)
+		}
+	}
+	for _, v := range newEnum {
+		if !containsValue(oldEnum, v) {
+			d.change(dir, location, path, fmt.Sprintf("enum value %v added", v), dir == response)
+		}
+	}
+}
+
+// constraints compares the bounds and the pattern of a schema. Tightening
+// them may reject a request, and loosening them may surprise a client with a
+// response.
+func (d *differ) constraints(dir direction, location, path string, oldSchema, newSchema *openapi3.Schema) {
+	tightened := func(name string, from, to interface{}) {
+		d.change(dir, location, path, fmt.Sprintf("%s tightened from %v to %v", name, from, to), dir == request)
+	}
+	loosened := func(name string, from, to interface{}) {
+		d.change(dir, location, path, fmt.Sprintf("%s loosened from %v to %v", name, from, to), dir == response)
+	}
+
+	lowerBound := func(name string, from, to float64) {
+		if to > from {
+			tightened(name, from, to)
+		} else if to < from {
+			loosened(name, from, to)
+		}
+	}
+	lowerBound("minLength", float64(oldSchema.MinLength), float64(newSchema.MinLength))
+	lowerBound("minItems", float64(oldSchema.MinItems), float64(newSchema.MinItems))
+	if oldSchema.Min != nil || newSchema.Min != nil {
+		lowerBound("minimum", bound(oldSchema.Min, false), bound(newSchema.Min, false))
+	}
+
+	upperBound := func(name string, from, to float64) {
+		if to < from {
+			tightened(name, from, to)
+		} else if to > from {
+			loosened(name, from, to)
+		}
+	}
+	if oldSchema.MaxLength != nil || newSchema.MaxLength != nil {
+		upperBound("maxLength", uintBound(oldSchema.MaxLength), uintBound(newSchema.MaxLength))
+	}
+	if oldSchema.MaxItems != nil || newSchema.MaxItems != nil {
+		upperBound("maxItems", uintBound(oldSchema.MaxItems), uintBound(newSchema.MaxItems))
+	}
+	if oldSchema.Max != nil || newSchema.Max != nil {
+		upperBound("maximum", bound(oldSchema.Max, true), bound(newSchema.Max, true))
+	}
+
+	if oldSchema.Pattern != newSchema.Pattern {
+		d.change(dir, location, path, fmt.Sprintf("pattern changed from %q to %q", oldSchema.Pattern, newSchema.Pattern), true)
+	}
+}
+
+// properties compares the properties of an object schema.
+func (d *differ) properties(dir direction, location, path string, oldSchema, newSchema *openapi3.Schema) {
+	oldRequired, newRequired := stringSet(oldSchema.Required), stringSet(newSchema.Required)
+	// Clients may send properties the schema doesn't have, which are
+	// only rejected if it disallows additional ones.
+	rejectsAdditional := newSchema.AdditionalPropertiesAllowed != nil && !*newSchema.AdditionalPropertiesAllowed
+
+	for _, name := range unionKeys(oldSchema.Properties, newSchema.Properties) {
+		oldProp, newProp := oldSchema.Properties[name], newSchema.Properties[name]
+		propPath := path + "." + name
+		switch {
+		case oldProp == nil && newRequired[name]:
+			d.change(dir, location, propPath, "required property added", dir == request)
+		case oldProp == nil:
+			d.change(dir, location, propPath, "optional property added", false)
+		case newProp == nil:
+			d.change(dir, location, propPath, "property removed", dir == response || rejectsAdditional)
+		default:
+			if !oldRequired[name] && newRequired[name] {
+				d.change(dir, location, propPath, "property became required", dir == request)
+			} else if oldRequired[name] && !newRequired[name] {
+				d.change(dir, location, propPath, "property became optional", dir == response)
+			}
+			d.schema(dir, location, propPath, oldProp, newProp)
+		}
+	}
+}
+
+// typeName returns t, or "none" if it's empty.
+func typeName(t string) string {
+	if t == "" {
+		return "none"
+	}
+	return t
+}
+
+// bound returns the value of a minimum or maximum, or the infinity it is
+// when it's unset.
+func bound(v *float64, upper bool) float64 {
+	switch {
+	case v != nil:
+		return *v
+	case upper:
+		return math.Inf(1)
+	default:
+		return math.Inf(-1)
+	}
+}
+
+// uintBound returns the value of a maxLength or maxItems, or infinity when
+// it's unset.
+func uintBound(v *uint64) float64 {
+	if v == nil {
+		return math.Inf(1)
+	}
+	return float64(*v)
+}
+
+// containsValue returns if values has v.
+func containsValue(values []interface{}, v interface{}) bool {
+	for _, value := range values {
+		if reflect.DeepEqual(value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// stringSet returns the set of the strings of s.
+func stringSet(s []string) map[string]bool {
+	set := make(map[string]bool, len(s))
+	for _, v := range s {
+		set[v] = true
+	}
+	return set
+}
+
+// unionKeys returns the sorted keys of the maps a and b, which have the same
+// type of keys.
+func unionKeys(a, b interface{}) []string {
+	keys := map[string]bool{}
+	for _, m := range []interface{}{a, b} {
+		for _, k := range reflect.ValueOf(m).MapKeys() {
+			keys[k.String()] = true
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
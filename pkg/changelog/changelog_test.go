@@ -0,0 +1,189 @@
+package changelog
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const oldSpec = `openapi: 3.0.1
+info: {title: t, version: "1"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - {name: limit, in: query, schema: {type: integer, maximum: 100}}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: '#/components/schemas/Pet'}
+    delete:
+      operationId: deletePets
+      responses:
+        '204': {description: gone}
+    post:
+      operationId: addPet
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        '201': {description: created}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        kind: {type: string, enum: [cat, dog]}
+        tag: {type: string}
+        parent: {$ref: '#/components/schemas/Pet'}
+`
+
+const newSpec = `openapi: 3.0.1
+info: {title: t, version: "2"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - {name: limit, in: query, schema: {type: integer, maximum: 50}}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: '#/components/schemas/Pet'}
+    post:
+      operationId: addPet
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        '201': {description: created}
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      responses:
+        '200': {description: ok}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name, age]
+      properties:
+        name: {type: string}
+        kind: {type: string, enum: [cat, dog, bird]}
+        age: {type: integer}
+        parent: {$ref: '#/components/schemas/Pet'}
+`
+
+func loadSpecs(t *testing.T) (*openapi3.T, *openapi3.T) {
+	oldSwagger, err := openapi3.NewLoader().LoadFromData([]byte(oldSpec))
+	require.NoError(t, err)
+	newSwagger, err := openapi3.NewLoader().LoadFromData([]byte(newSpec))
+	require.NoError(t, err)
+	return oldSwagger, newSwagger
+}
+
+func TestCompare(t *testing.T) {
+	oldSwagger, newSwagger := loadSpecs(t)
+
+	log := Compare(oldSwagger, newSwagger)
+	assert.Equal(t, []Change{
+		{RequestChanged, "GET /pets", "listPets", "parameter query limit", "maximum tightened from 100 to 50", true},
+		{ResponseChanged, "GET /pets", "listPets", "response 200 application/json [].age", "required property added", false},
+		{ResponseChanged, "GET /pets", "listPets", "response 200 application/json [].kind", "enum value bird added", true},
+		{ResponseChanged, "GET /pets", "listPets", "response 200 application/json [].tag", "property removed", true},
+		{RequestChanged, "POST /pets", "addPet", "request body application/json .age", "required property added", true},
+		{RequestChanged, "POST /pets", "addPet", "request body application/json .kind", "enum value bird added", false},
+		{RequestChanged, "POST /pets", "addPet", "request body application/json .tag", "property removed", false},
+		{OperationRemoved, "DELETE /pets", "deletePets", "", "operation removed", true},
+		{OperationAdded, "GET /pets/{id}", "getPet", "", "operation added", false},
+	}, log.Changes)
+	assert.True(t, log.HasBreaking())
+
+	breaking := log.BreakingOnly()
+	assert.Len(t, breaking.Changes, 5)
+	assert.True(t, breaking.HasBreaking())
+
+	same := Compare(oldSwagger, oldSwagger)
+	assert.Empty(t, same.Changes)
+	assert.False(t, same.HasBreaking())
+}
+
+func TestWrite(t *testing.T) {
+	oldSwagger, newSwagger := loadSpecs(t)
+	log := Compare(oldSwagger, newSwagger)
+
+	var text bytes.Buffer
+	require.NoError(t, log.WriteText(&text))
+	assert.Equal(t, `Added operations:
+  GET /pets/{id} (getPet)
+
+Removed operations:
+  DELETE /pets (deletePets) [breaking]
+
+Changed requests:
+  GET /pets (listPets)
+    parameter query limit: maximum tightened from 100 to 50 [breaking]
+  POST /pets (addPet)
+    request body application/json .age: required property added [breaking]
+    request body application/json .kind: enum value bird added
+    request body application/json .tag: property removed
+
+Changed responses:
+  GET /pets (listPets)
+    response 200 application/json [].age: required property added
+    response 200 application/json [].kind: enum value bird added [breaking]
+    response 200 application/json [].tag: property removed [breaking]
+
+9 changes, 5 breaking.
+`, text.String())
+
+	var md bytes.Buffer
+	require.NoError(t, log.BreakingOnly().WriteMarkdown(&md))
+	assert.Equal(t, "## API changelog\n\n"+
+		"> [!CAUTION]\n> 5 breaking changes\n\n"+
+		"### Removed operations\n\n"+
+		"- :warning: **Breaking:** `DELETE /pets` (deletePets)\n\n"+
+		"### Changed requests\n\n"+
+		"- `GET /pets` (listPets)\n"+
+		"  - :warning: **Breaking:** `parameter query limit`: maximum tightened from 100 to 50\n"+
+		"- `POST /pets` (addPet)\n"+
+		"  - :warning: **Breaking:** `request body application/json .age`: required property added\n\n"+
+		"### Changed responses\n\n"+
+		"- `GET /pets` (listPets)\n"+
+		"  - :warning: **Breaking:** `response 200 application/json [].kind`: enum value bird added\n"+
+		"  - :warning: **Breaking:** `response 200 application/json [].tag`: property removed\n\n"+
+		"5 changes, 5 breaking.\n", md.String())
+
+	var js bytes.Buffer
+	require.NoError(t, log.WriteJSON(&js))
+	var decoded struct {
+		Breaking bool
+		Changes  []Change
+	}
+	require.NoError(t, json.Unmarshal(js.Bytes(), &decoded))
+	assert.True(t, decoded.Breaking)
+	assert.Equal(t, log.Changes, decoded.Changes)
+
+	text.Reset()
+	require.NoError(t, Compare(oldSwagger, oldSwagger).WriteText(&text))
+	assert.Equal(t, "No changes.\n", text.String())
+}
@@ -0,0 +1,166 @@
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// sections are the kinds of changes, in the order they are written, with
+// their titles.
+var sections = []struct {
+	kind  Kind
+	title string
+}{
+	{OperationAdded, "Added operations"},
+	{OperationRemoved, "Removed operations"},
+	{RequestChanged, "Changed requests"},
+	{ResponseChanged, "Changed responses"},
+}
+
+// WriteText writes c as plain text, with a section for each kind of changes,
+// and the breaking ones tagged with [breaking].
+func (c *Changelog) WriteText(w io.Writer) error {
+	var b strings.Builder
+	for _, section := range sections {
+		changes := c.ofKind(section.kind)
+		if len(changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "%s:\n", section.title)
+		for _, op := range byOperation(changes) {
+			if section.kind == OperationAdded || section.kind == OperationRemoved {
+				fmt.Fprintf(&b, "  %s%s\n", operationName(op[0]), textTag(op[0]))
+				continue
+			}
+			fmt.Fprintf(&b, "  %s\n", operationName(op[0]))
+			for _, ch := range op {
+				fmt.Fprintf(&b, "    %s: %s%s\n", ch.Location, ch.Message, textTag(ch))
+			}
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(c.summary() + "\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes c as a JSON object, with the list of its changes and
+// whether one of them is breaking.
+func (c *Changelog) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Breaking bool     `json:"breaking"`
+		Changes  []Change `json:"changes"`
+	}{c.HasBreaking(), c.Changes})
+}
+
+// WriteMarkdown writes c as GitHub flavored Markdown, for a comment on a pull
+// request: the breaking changes are called out in a caution alert, and
+// tagged in the lists of changes.
+func (c *Changelog) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("## API changelog\n\n")
+
+	if breaking := len(c.BreakingOnly().Changes); breaking != 0 {
+		fmt.Fprintf(&b, "> [!CAUTION]\n> %s\n\n", plural(breaking, "breaking change"))
+	}
+	for _, section := range sections {
+		changes := c.ofKind(section.kind)
+		if len(changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "### %s\n\n", section.title)
+		for _, op := range byOperation(changes) {
+			if section.kind == OperationAdded || section.kind == OperationRemoved {
+				fmt.Fprintf(&b, "- %s%s\n", markdownTag(op[0]), markdownOperation(op[0]))
+				continue
+			}
+			fmt.Fprintf(&b, "- %s\n", markdownOperation(op[0]))
+			for _, ch := range op {
+				fmt.Fprintf(&b, "  - %s`%s`: %s\n", markdownTag(ch), ch.Location, ch.Message)
+			}
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(c.summary() + "\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ofKind returns the changes of c of kind.
+func (c *Changelog) ofKind(kind Kind) []Change {
+	var changes []Change
+	for _, ch := range c.Changes {
+		if ch.Kind == kind {
+			changes = append(changes, ch)
+		}
+	}
+	return changes
+}
+
+// summary returns the number of changes of c, and of breaking ones.
+func (c *Changelog) summary() string {
+	if len(c.Changes) == 0 {
+		return "No changes."
+	}
+	return fmt.Sprintf("%s, %d breaking.", plural(len(c.Changes), "change"), len(c.BreakingOnly().Changes))
+}
+
+// byOperation groups changes, which are ordered by operation, by their
+// operation.
+func byOperation(changes []Change) [][]Change {
+	var groups [][]Change
+	for i, ch := range changes {
+		if i == 0 || ch.Operation != changes[i-1].Operation {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], ch)
+	}
+	return groups
+}
+
+// operationName returns the method and path of the operation of ch, with its
+// operationId if it has one.
+func operationName(ch Change) string {
+	if ch.OperationID == "" {
+		return ch.Operation
+	}
+	return ch.Operation + " (" + ch.OperationID + ")"
+}
+
+// markdownOperation is operationName, in Markdown.
+func markdownOperation(ch Change) string {
+	if ch.OperationID == "" {
+		return "`" + ch.Operation + "`"
+	}
+	return "`" + ch.Operation + "` (" + ch.OperationID + ")"
+}
+
+// textTag returns the tag of a breaking change in plain text.
+func textTag(ch Change) string {
+	if ch.Breaking {
+		return " [breaking]"
+	}
+	return ""
+}
+
+// markdownTag returns the tag of a breaking change in Markdown.
+func markdownTag(ch Change) string {
+	if ch.Breaking {
+		return ":warning: **Breaking:** "
+	}
+	return ""
+}
+
+// plural returns n and noun, in the plural if n isn't 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
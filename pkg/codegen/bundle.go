@@ -0,0 +1,221 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Bundle moves the parts of swagger which were loaded from other files into
+// swagger itself, so that it can be written as a single document, and its
+// types generated in a single package. Its references have to be resolved
+// already. References to the files of importMapping are kept, since their
+// types are generated in other packages.
+//
+// Schemas from other files become component schemas, named after the schema
+// they were in the other file, or after the file when it holds a single
+// schema, so that they keep their type names. Other components, such as
+// parameters or responses, are inlined where they are referenced.
+func Bundle(swagger *openapi3.T, importMapping map[string]string) {
+	if swagger.Components.Schemas == nil {
+		swagger.Components.Schemas = openapi3.Schemas{}
+	}
+	b := bundler{
+		importMapping: importMapping,
+		schemas:       swagger.Components.Schemas,
+		names:         map[*openapi3.Schema]string{},
+		visited:       map[*openapi3.Schema]bool{},
+	}
+
+	for _, p := range swagger.Paths {
+		p.Ref = ""
+	}
+	for name, s := range swagger.Components.Schemas {
+		// A component which is another file is that file.
+		if !strings.HasPrefix(s.Ref, "#") && !b.isMapped(s.Ref) {
+			s.Ref = ""
+		}
+		if s.Value != nil {
+			b.names[s.Value] = name
+		}
+	}
+
+	// The schemas are all named before their references change, so that the
+	// same schema loaded twice is still equal.
+	_ = walkSwagger(swagger, b.findRef)
+
+	added := openapi3.Schemas{}
+	refs := make([]string, len(b.refs))
+	for i, sref := range b.refs {
+		if strings.HasPrefix(sref.Ref, "#/components/schemas/") {
+			// Local references of other files may point to schemas of that
+			// file, which the spec doesn't have.
+			name := strings.TrimPrefix(sref.Ref, "#/components/schemas/")
+			if _, ok := b.schemas[name]; !ok {
+				if _, ok := added[name]; !ok {
+					added[name] = &openapi3.SchemaRef{Value: sref.Value}
+					b.names[sref.Value] = name
+				}
+			}
+			refs[i] = sref.Ref
+			continue
+		}
+		refs[i] = "#/components/schemas/" + b.addSchema(added, externalSchemaName(sref.Ref), sref.Value)
+	}
+	for i, sref := range b.refs {
+		sref.Ref = refs[i]
+	}
+
+	for name, s := range added {
+		swagger.Components.Schemas[name] = s
+	}
+}
+
+// bundler holds the state of Bundle.
+type bundler struct {
+	importMapping map[string]string
+	schemas       openapi3.Schemas // The component schemas of the spec
+	names         map[*openapi3.Schema]string
+	visited       map[*openapi3.Schema]bool
+	refs          []*openapi3.SchemaRef // The schema references to bundle
+}
+
+// isMapped returns if ref is to a file of the import mapping.
+func (b *bundler) isMapped(ref string) bool {
+	file := strings.SplitN(ref, "#", 2)[0]
+	_, ok := b.importMapping[file]
+	return file != "" && ok
+}
+
+// findRef inlines ref when it was loaded from another file and isn't a schema,
+// or collects it to be bundled when it's a schema, and returns whether its
+// value has to be walked.
+func (b *bundler) findRef(ref RefWrapper) (bool, error) {
+	if ref.Ref == "" {
+		return true, nil
+	}
+	if b.isMapped(ref.Ref) {
+		return false, nil
+	}
+
+	sref, ok := ref.SourceRef.(*openapi3.SchemaRef)
+	if !ok {
+		if strings.HasPrefix(ref.Ref, "#") {
+			// The component itself is walked with the other components.
+			return false, nil
+		}
+		clearRef(ref.SourceRef)
+		return true, nil
+	}
+
+	if sref.Value == nil {
+		return false, nil
+	}
+	if strings.HasPrefix(ref.Ref, "#") {
+		name := strings.TrimPrefix(ref.Ref, "#/components/schemas/")
+		if _, ok := b.schemas[name]; ok || strings.Contains(name, "/") {
+			return false, nil
+		}
+	}
+	b.refs = append(b.refs, sref)
+
+	if b.visited[sref.Value] {
+		return false, nil
+	}
+	b.visited[sref.Value] = true
+	return true, nil
+}
+
+// addSchema adds the schema s from another file to added, and returns its
+// name. It is name, unless another schema already has it, or one with the
+// same Go type name, or s was already added under another one.
+func (b *bundler) addSchema(added openapi3.Schemas, name string, s *openapi3.Schema) string {
+	if name, ok := b.names[s]; ok {
+		return name
+	}
+	unique := name
+	for i := 2; ; i++ {
+		existing, sref := b.lookup(added, unique)
+		if sref == nil {
+			added[unique] = &openapi3.SchemaRef{Value: s}
+			b.names[s] = unique
+			return unique
+		}
+		if sameSchema(sref.Value, s) {
+			b.names[s] = existing
+			return existing
+		}
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+}
+
+// lookup returns the component schema, of the spec or added, with the same
+// Go type as name, and its name.
+func (b *bundler) lookup(added openapi3.Schemas, name string) (string, *openapi3.SchemaRef) {
+	typeName := SchemaNameToTypeName(name)
+	for _, schemas := range []openapi3.Schemas{b.schemas, added} {
+		for existing, sref := range schemas {
+			if SchemaNameToTypeName(existing) == typeName {
+				return existing, sref
+			}
+		}
+	}
+	return "", nil
+}
+
+// sameSchema returns if a and b are the same schema, which may have been
+// loaded twice from the same file.
+func sameSchema(a, b *openapi3.Schema) bool {
+	if a == b {
+		return true
+	}
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	jb, err := json.Marshal(b)
+	return err == nil && bytes.Equal(ja, jb)
+}
+
+// externalSchemaName returns the name of the schema referenced by ref, like
+// "schemas.yaml#/components/schemas/Pet" or "pet.yaml": the last part of the
+// JSON pointer, or the name of the file without its extension.
+func externalSchemaName(ref string) string {
+	file, pointer := ref, ""
+	if i := strings.Index(ref, "#"); i != -1 {
+		file, pointer = ref[:i], ref[i+1:]
+	}
+	if pointer = strings.Trim(pointer, "/"); pointer != "" {
+		parts := strings.Split(pointer, "/")
+		return strings.NewReplacer("~1", "/", "~0", "~").Replace(parts[len(parts)-1])
+	}
+	base := path.Base(file)
+	return strings.TrimSuffix(base, path.Ext(base))
+}
+
+// clearRef removes the reference of a ref other than a schema, which inlines
+// its resolved value.
+func clearRef(ref interface{}) {
+	switch r := ref.(type) {
+	case *openapi3.ParameterRef:
+		r.Ref = ""
+	case *openapi3.RequestBodyRef:
+		r.Ref = ""
+	case *openapi3.ResponseRef:
+		r.Ref = ""
+	case *openapi3.CallbackRef:
+		r.Ref = ""
+	case *openapi3.HeaderRef:
+		r.Ref = ""
+	case *openapi3.SecuritySchemeRef:
+		r.Ref = ""
+	case *openapi3.LinkRef:
+		r.Ref = ""
+	case *openapi3.ExampleRef:
+		r.Ref = ""
+	}
+}
@@ -0,0 +1,146 @@
+package codegen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range bundleTestFiles {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+	}
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	swagger, err := loader.LoadFromFile(filepath.Join(dir, "openapi.yaml"))
+	require.NoError(t, err)
+
+	Bundle(swagger, map[string]string{"shared.yaml": "example.com/shared"})
+
+	// The schemas of other files are components, loaded once.
+	assert.ElementsMatch(t, []string{"Error", "Pet", "owner", "tag"}, SortedSchemaKeys(swagger.Components.Schemas))
+
+	pets := swagger.Paths["/pets"].Get
+	assert.Equal(t, "#/components/schemas/Pet", pets.Responses["200"].Value.Content.Get("application/json").Schema.Value.Items.Ref)
+	assert.Equal(t, "#/components/schemas/Error", pets.Responses["default"].Value.Content.Get("application/json").Schema.Ref)
+	assert.Equal(t, "#/components/schemas/owner", swagger.Components.Schemas["Pet"].Value.Properties["owner"].Ref)
+
+	// Other components are inlined.
+	owners := swagger.Paths["/owners"].Get
+	assert.Empty(t, owners.Parameters[0].Ref)
+	assert.Equal(t, "limit", owners.Parameters[0].Value.Name)
+	assert.Equal(t, "#/components/schemas/owner", owners.Responses["200"].Value.Content.Get("application/json").Schema.Value.Items.Ref)
+
+	// Mapped files are generated in other packages.
+	assert.Equal(t, "shared.yaml#/components/schemas/Shared", owners.Responses["200"].Value.Headers["X-Shared"].Value.Schema.Ref)
+
+	// The local references of the bundle all point into it.
+	data, err := json.Marshal(swagger)
+	require.NoError(t, err)
+	bundled := &openapi3.T{}
+	require.NoError(t, json.Unmarshal(data, bundled))
+	assert.Empty(t, ValidateRefs(bundled))
+	assert.Equal(t, "#/components/schemas/tag", bundled.Components.Schemas["owner"].Value.Properties["tags"].Value.Items.Ref)
+}
+
+var bundleTestFiles = map[string]string{
+	"openapi.yaml": `
+openapi: 3.0.1
+info:
+  title: bundle
+  version: 1.0.0
+paths:
+  /pets:
+    $ref: paths/pets.yaml
+  /owners:
+    get:
+      parameters:
+        - $ref: 'parameters.yaml#/limit'
+      responses:
+        '200':
+          description: ok
+          headers:
+            X-Shared:
+              schema:
+                $ref: 'shared.yaml#/components/schemas/Shared'
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: schemas/owner.yaml
+components:
+  schemas:
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+    Pet:
+      $ref: schemas/pet.yaml
+`,
+	"parameters.yaml": `
+limit:
+  name: limit
+  in: query
+  schema:
+    type: integer
+`,
+	"shared.yaml": `
+openapi: 3.0.1
+info:
+  title: shared
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Shared:
+      type: string
+`,
+	"paths/pets.yaml": `
+get:
+  responses:
+    '200':
+      description: ok
+      content:
+        application/json:
+          schema:
+            type: array
+            items:
+              $ref: ../schemas/pet.yaml
+    default:
+      description: error
+      content:
+        application/json:
+          schema:
+            $ref: '../openapi.yaml#/components/schemas/Error'
+`,
+	"schemas/pet.yaml": `
+type: object
+properties:
+  name:
+    type: string
+  owner:
+    $ref: owner.yaml
+`,
+	"schemas/owner.yaml": `
+type: object
+properties:
+  tags:
+    type: array
+    items:
+      $ref: tag.yaml
+`,
+	"schemas/tag.yaml": `
+type: string
+`,
+}
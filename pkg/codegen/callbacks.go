@@ -0,0 +1,130 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// CallbackDefinition describes an operation of a callback, a request which
+// the API sends to a URL given in a request of one of its operations. Its
+// OperationID is the name of the operation, followed by the name of the
+// callback and Callback, which its payload types and client are named by.
+type CallbackDefinition struct {
+	OperationDefinition
+
+	Name       string // The name of the callback in the spec
+	Expression string // The runtime expression of the URL of the callback, like {$request.body#/callbackUrl}
+	Operation  string // The OperationID of the operation defining the callback
+	Statuses   []int  // The success status codes of the responses to the callback, any 2xx if empty
+}
+
+// CallbackDefinitions returns the operations of the callbacks of ops, by
+// operation and by callback name. The client delivering a callback is named
+// after the HTTP method as well if the callback has several operations.
+func CallbackDefinitions(ops []OperationDefinition) ([]CallbackDefinition, error) {
+	var definitions []CallbackDefinition
+	seen := map[string]bool{}
+	for _, op := range ops {
+		names := make([]string, 0, len(op.Spec.Callbacks))
+		for name := range op.Spec.Callbacks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			ref := op.Spec.Callbacks[name]
+			if ref == nil || ref.Value == nil {
+				return nil, fmt.Errorf("callback %s of %s has no operations", name, op.OperationID)
+			}
+			callback := *ref.Value
+
+			expressions := make([]string, 0, len(callback))
+			count := 0
+			for expression, pathItem := range callback {
+				expressions = append(expressions, expression)
+				count += len(pathItem.Operations())
+			}
+			sort.Strings(expressions)
+
+			for _, expression := range expressions {
+				cbOps := callback[expression].Operations()
+				for _, method := range SortedOperationsKeys(cbOps) {
+					cbOp := cbOps[method]
+					methodName := ToCamelCase(name)
+					if count > 1 {
+						methodName += ToCamelCase(strings.ToLower(method))
+					}
+					if methodName == "" {
+						return nil, fmt.Errorf("invalid callback name %q of %s", name, op.OperationID)
+					}
+					operationID := versioned(op.OperationID + methodName + "Callback")
+					if seen[operationID] {
+						return nil, fmt.Errorf("callback %s of %s has several URL expressions with method %s", name, op.OperationID, method)
+					}
+					seen[operationID] = true
+
+					bodyDefinitions, typeDefinitions, err := GenerateBodyDefinitions(operationID, cbOp.RequestBody)
+					if err != nil {
+						return nil, fmt.Errorf("error generating body definitions of callback %s of %s: %w", name, op.OperationID, err)
+					}
+
+					cb := CallbackDefinition{
+						OperationDefinition: OperationDefinition{
+							OperationID:     operationID,
+							TypeDefinitions: typeDefinitions,
+							Bodies:          bodyDefinitions,
+							Summary:         cbOp.Summary,
+							Method:          method,
+							Path:            expression,
+							Spec:            cbOp,
+						},
+						Name:       name,
+						Expression: expression,
+						Operation:  op.OperationID,
+					}
+					if cbOp.RequestBody != nil {
+						cb.BodyRequired = cbOp.RequestBody.Value.Required
+					}
+					for code := range cbOp.Responses {
+						status, err := strconv.Atoi(code)
+						if err == nil && status >= 200 && status < 300 {
+							cb.Statuses = append(cb.Statuses, status)
+						}
+					}
+					sort.Ints(cb.Statuses)
+					definitions = append(definitions, cb)
+				}
+			}
+		}
+	}
+	return definitions, nil
+}
+
+// Payload returns the default body of c, which Deliver sends, or nil if it
+// has none.
+func (c CallbackDefinition) Payload() *RequestBodyDefinition {
+	for _, body := range c.Bodies {
+		if body.Default {
+			return &body
+		}
+	}
+	return nil
+}
+
+// StatusList returns the success status codes of c, separated by commas.
+func (c CallbackDefinition) StatusList() string {
+	statuses := make([]string, len(c.Statuses))
+	for i, status := range c.Statuses {
+		statuses[i] = strconv.Itoa(status)
+	}
+	return strings.Join(statuses, ", ")
+}
+
+// GenerateCallbacks generates the clients delivering callbacks, with their
+// CallbackOptions.
+func GenerateCallbacks(t *template.Template, callbacks []CallbackDefinition) (string, error) {
+	return GenerateTemplates([]string{"callbacks.tmpl"}, t, callbacks)
+}
@@ -26,7 +26,6 @@ import (
 	"text/template"
 
 	"github.com/getkin/kin-openapi/openapi3"
-	"golang.org/x/tools/imports"
 
 	"github.com/discord-gophers/goapi-gen/pkg/codegen/templates"
 )
@@ -35,17 +34,40 @@ import (
 //
 // Most callers to this package will use Generate.
 type Options struct {
-	GenerateServer bool              // GenerateChiServer specifies whether to generate chi server boilerplate
-	GenerateTypes  bool              // GenerateTypes specifies whether to generate type definitions
-	EmbedSpec      bool              // Whether to embed the swagger spec in the generated code
-	SkipFmt        bool              // Whether to skip go imports on the generated code
-	SkipPrune      bool              // Whether to skip pruning unused components on the generated code
-	AliasTypes     bool              // Whether to alias types if possible
-	IncludeTags    []string          // Only include operations that have one of these tags. Ignored when empty.
-	ExcludeTags    []string          // Exclude operations that have one of these tags. Ignored when empty.
-	UserTemplates  map[string]string // Override built-in templates from user-provided files
-	ImportMapping  map[string]string // ImportMapping specifies the golang package path for each external reference
-	ExcludeSchemas []string          // Exclude from generation schemas with given names. Ignored when empty.
+	GenerateServer      bool              // GenerateChiServer specifies whether to generate chi server boilerplate
+	GenerateTypes       bool              // GenerateTypes specifies whether to generate type definitions
+	GenerateClient      bool              // GenerateClient specifies whether to generate a typed HTTP client
+	ClientLinks         bool              // Whether to generate the functions following the links of the responses of the client
+	GenerateCallbacks   bool              // Whether to generate the clients delivering the callbacks of the operations
+	EmbedSpec           bool              // Whether to embed the swagger spec in the generated code
+	SkipFmt             bool              // Whether to skip go imports on the generated code
+	Format              string            // How to format the generated code, goimports, gofmt or none. Defaults to goimports.
+	SkipPrune           bool              // Whether to skip pruning unused components on the generated code
+	AliasTypes          bool              // Whether to alias types if possible
+	IncludeTags         []string          // Only include operations that have one of these tags. Ignored when empty.
+	ExcludeTags         []string          // Exclude operations that have one of these tags. Ignored when empty.
+	UserTemplates       map[string]string // Override built-in templates from user-provided files
+	ImportMapping       map[string]string // ImportMapping specifies the golang package path for each external reference
+	ExcludeSchemas      []string          // Exclude from generation schemas with given names. Ignored when empty.
+	ContextFirst        bool              // Whether to pass the request context as the first argument of server methods
+	NoOmitEmpty         bool              // Whether to leave out omitempty from all json tags
+	StrictExtensions    bool              // Whether to fail on unknown x- extensions
+	Framework           string            // The framework to generate the server for, chi, echo or connect. Defaults to chi.
+	FunctionalOptions   bool              // Whether to generate constructors and functional options for struct types
+	SkipNullablePointer bool              // Whether to leave required nullable properties as values, instead of pointers
+	ValidatorTags       bool              // Whether to add go-playground/validator tags for schema constraints
+	ProtoTags           bool              // Whether to add protobuf tags, numbering fields by their position
+	YAMLTags            bool              // Whether to add yaml tags, and YAML methods to the types with custom JSON handling
+	SQLTypes            bool              // Whether to use types implementing sql.Scanner and driver.Valuer for string formats
+	PgxTypes            bool              // Whether to use PgxUUID, implementing the pgx/v5 scanner and valuer interfaces, for uuid strings
+	EnforceTimeouts     bool              // Whether to cancel the context of operations after their x-timeout, and respond with a 504
+	ServeSpec           bool              // Whether to generate the handlers serving the spec and a Swagger UI page for it
+	SpecPath            string            // The path to register the spec handler at. Defaults to /openapi.json.
+	NoLint              []string          // The linters to add //nolint directives for, eg. revive or wsl
+	VersionPrefix       string            // The prefix of the names of all the generated types and operations, eg. V2
+	GoVersion           string            // The Go version of the generated code, eg. 1.21. Generics and any are used from 1.18.
+	DeepCopy            bool              // Whether to generate Kubernetes-style DeepCopy and DeepCopyInto methods for the types
+	Swaggest            bool              // Whether to generate RegisterSchemas, registering the schemas of the types with swaggest/openapi-go
 }
 
 // goImport represents a go package to be imported in the generated code
@@ -76,6 +98,14 @@ func (im importMap) GoImports() []string {
 
 var importMapping importMap
 
+// goTypeImports holds the packages of fully qualified x-go-type extensions,
+// keyed by package path.
+var goTypeImports = importMap{}
+
+// globalOptions holds the options of the current generation, for the parts of
+// the generator which don't have them passed along explicitly.
+var globalOptions Options
+
 func constructImportMapping(input map[string]string) importMap {
 	var (
 		pathToName = map[string]string{}
@@ -104,41 +134,41 @@ func constructImportMapping(input map[string]string) importMap {
 // Generate uses the Go templating engine to generate all of our server wrappers from
 // the descriptions we've built up above from the schema objects.
 func Generate(swagger *openapi3.T, packageName string, opts Options) (string, error) {
-	importMapping = constructImportMapping(opts.ImportMapping)
-
-	filterOperationsByTag(swagger, opts)
-	if !opts.SkipPrune {
-		pruneUnusedComponents(swagger)
+	t, err := prepare(swagger, opts)
+	if err != nil {
+		return "", err
 	}
 
-	// This creates the golang templates text package
-	TemplateFunctions["opts"] = func() Options { return opts }
-	t := template.New("goapi-gen").Funcs(TemplateFunctions)
-	// This parses all of our own template files into the template object
-	// above
-	t, err := templates.Parse(t)
+	ops, err := OperationDefinitions(swagger)
 	if err != nil {
-		return "", fmt.Errorf("error parsing goapi-gen templates: %w", err)
+		return "", fmt.Errorf("error creating operation definitions: %w", err)
 	}
 
-	// Override built-in templates with user-provided versions
-	for _, tpl := range t.Templates() {
-		if _, ok := opts.UserTemplates[tpl.Name()]; ok {
-			utpl := t.New(tpl.Name())
-			if _, err := utpl.Parse(opts.UserTemplates[tpl.Name()]); err != nil {
-				return "", fmt.Errorf("error parsing user-provided template %q: %w", tpl.Name(), err)
-			}
-		}
+	webhooks, err := WebhookDefinitions(swagger)
+	if err != nil {
+		return "", fmt.Errorf("error creating webhook definitions: %w", err)
 	}
 
-	ops, err := OperationDefinitions(swagger)
-	if err != nil {
-		return "", fmt.Errorf("error creating operation definitions: %w", err)
+	var callbacks []CallbackDefinition
+	if opts.GenerateCallbacks {
+		callbacks, err = CallbackDefinitions(ops)
+		if err != nil {
+			return "", fmt.Errorf("error creating callback definitions: %w", err)
+		}
 	}
 
 	var typeDefinitions, constantDefinitions string
 	if opts.GenerateTypes {
-		typeDefinitions, err = GenerateTypeDefinitions(t, swagger, ops, opts.ExcludeSchemas)
+		// The payloads of webhooks and callbacks are typed like the bodies of
+		// operations.
+		typeOps := append([]OperationDefinition{}, ops...)
+		for _, webhook := range webhooks {
+			typeOps = append(typeOps, webhook.OperationDefinition)
+		}
+		for _, callback := range callbacks {
+			typeOps = append(typeOps, callback.OperationDefinition)
+		}
+		typeDefinitions, err = GenerateTypeDefinitions(t, swagger, typeOps, opts.ExcludeSchemas)
 		if err != nil {
 			return "", fmt.Errorf("error generating type definitions: %w", err)
 		}
@@ -152,10 +182,49 @@ func Generate(swagger *openapi3.T, packageName string, opts Options) (string, er
 
 	var serverOut string
 	if opts.GenerateServer {
-		serverOut, err = GenerateChiServer(t, ops)
+		switch opts.Framework {
+		case "", "chi":
+			serverOut, err = GenerateChiServer(t, ops)
+		case "echo":
+			serverOut, err = GenerateEchoServer(t, ops)
+		case "connect":
+			serverOut, err = GenerateConnectServer(t, ops)
+		default:
+			return "", fmt.Errorf("unknown framework: %s", opts.Framework)
+		}
 		if err != nil {
 			return "", fmt.Errorf("error generating Go handlers for Paths: %w", err)
 		}
+		if len(webhooks) != 0 {
+			webhookOut, err := GenerateWebhookServer(t, webhooks)
+			if err != nil {
+				return "", fmt.Errorf("error generating webhook server: %w", err)
+			}
+			serverOut += webhookOut
+		}
+	}
+
+	var clientOut string
+	if opts.GenerateClient {
+		clientOut, err = GenerateClient(t, ops)
+		if err != nil {
+			return "", fmt.Errorf("error generating client: %w", err)
+		}
+		if opts.ClientLinks {
+			links, err := GenerateClientLinks(t, ops)
+			if err != nil {
+				return "", fmt.Errorf("error generating client links: %w", err)
+			}
+			clientOut += links
+		}
+	}
+
+	var callbacksOut string
+	if len(callbacks) != 0 {
+		callbacksOut, err = GenerateCallbacks(t, callbacks)
+		if err != nil {
+			return "", fmt.Errorf("error generating callbacks: %w", err)
+		}
 	}
 
 	var inlinedSpec string
@@ -166,10 +235,31 @@ func Generate(swagger *openapi3.T, packageName string, opts Options) (string, er
 		}
 	}
 
+	var specHandlers string
+	if opts.ServeSpec {
+		specHandlers, err = GenerateSpecHandlers(t, opts)
+		if err != nil {
+			return "", fmt.Errorf("error generating spec handlers: %w", err)
+		}
+	}
+
 	var buf bytes.Buffer
 	w := bufio.NewWriter(&buf)
 
 	externalImports := importMapping.GoImports()
+
+	// The packages of x-go-type extensions may already be imported by the
+	// imports template, and importing them twice doesn't compile.
+	builtinImports, err := GenerateImports(t, nil, packageName)
+	if err != nil {
+		return "", fmt.Errorf("error generating imports: %w", err)
+	}
+	for _, goTypeImport := range goTypeImports.GoImports() {
+		if !strings.Contains(builtinImports, goTypeImport) {
+			externalImports = append(externalImports, goTypeImport)
+		}
+	}
+
 	importsOut, err := GenerateImports(t, externalImports, packageName)
 	if err != nil {
 		return "", fmt.Errorf("error generating imports: %w", err)
@@ -197,6 +287,18 @@ func Generate(swagger *openapi3.T, packageName string, opts Options) (string, er
 		}
 	}
 
+	if opts.GenerateClient {
+		_, err = w.WriteString(clientOut)
+		if err != nil {
+			return "", fmt.Errorf("error writing client: %w", err)
+		}
+	}
+
+	_, err = w.WriteString(callbacksOut)
+	if err != nil {
+		return "", fmt.Errorf("error writing callbacks: %w", err)
+	}
+
 	if opts.EmbedSpec {
 		_, err = w.WriteString(inlinedSpec)
 		if err != nil {
@@ -204,6 +306,13 @@ func Generate(swagger *openapi3.T, packageName string, opts Options) (string, er
 		}
 	}
 
+	if opts.ServeSpec {
+		_, err = w.WriteString(specHandlers)
+		if err != nil {
+			return "", fmt.Errorf("error writing spec handlers: %w", err)
+		}
+	}
+
 	err = w.Flush()
 	if err != nil {
 		return "", fmt.Errorf("error flushing output buffer: %w", err)
@@ -211,45 +320,87 @@ func Generate(swagger *openapi3.T, packageName string, opts Options) (string, er
 
 	// remove any byte-order-marks which break Go-Code
 	goCode := SanitizeCode(buf.String())
+	if usesGenerics() {
+		goCode = useAny(goCode)
+	}
 
 	// The generation code produces unindented horrors. Use the Go Imports
 	// to make it all pretty.
-	if opts.SkipFmt {
-		return goCode, nil
-	}
+	return formatCode(packageName+".go", goCode, opts)
+}
+
+// prepare sets the global state of the generation of swagger with opts, for
+// every entry point generating a file: it checks opts and the extensions of
+// swagger, filters its operations by tag, prunes its unused components, finds
+// the Go names of its schemas, and parses the templates. swagger is nil for
+// the files which don't depend on the spec.
+func prepare(swagger *openapi3.T, opts Options) (*template.Template, error) {
+	globalOptions = opts
+	importMapping = constructImportMapping(opts.ImportMapping)
+	goTypeImports = importMap{}
 
-	outBytes, err := imports.Process(packageName+".go", []byte(goCode), nil)
+	minor, err := goMinorVersion(opts.GoVersion)
 	if err != nil {
-		return "", fmt.Errorf("error formatting Go code: %w", err)
+		return nil, err
+	}
+	if opts.Framework == "connect" && opts.GoVersion != "" && minor < 18 {
+		return nil, fmt.Errorf("the connect framework uses generics, which Go %s doesn't have", opts.GoVersion)
 	}
-	return string(outBytes), nil
-}
 
-// GenerateTypeDefinitions produces the type definitions in ops and executes
-// the template.
-func GenerateTypeDefinitions(t *template.Template, swagger *openapi3.T, ops []OperationDefinition, excludeSchemas []string) (string, error) {
-	schemaTypes, err := GenerateTypesForSchemas(t, swagger.Components.Schemas, excludeSchemas)
-	if err != nil {
-		return "", fmt.Errorf("error generating Go types for component schemas: %w", err)
+	if swagger != nil {
+		if opts.StrictExtensions {
+			if err := checkExtensions(swagger); err != nil {
+				return nil, err
+			}
+		}
+
+		filterOperationsByTag(swagger, opts)
+		if !opts.SkipPrune {
+			pruneUnusedComponents(swagger)
+		}
+		names, err := findGoNames(swagger)
+		if err != nil {
+			return nil, err
+		}
+		goNames = names
+		readWriteTypes = findReadWriteTypes(swagger, opts.ExcludeSchemas)
 	}
 
-	paramTypes, err := GenerateTypesForParameters(t, swagger.Components.Parameters)
+	return parseTemplates(opts)
+}
+
+// parseTemplates parses the built-in templates, overridden by the templates
+// in opts.UserTemplates.
+func parseTemplates(opts Options) (*template.Template, error) {
+	// This creates the golang templates text package
+	TemplateFunctions["opts"] = func() Options { return opts }
+	t := template.New("goapi-gen").Funcs(TemplateFunctions)
+	// This parses all of our own template files into the template object
+	// above
+	t, err := templates.Parse(t)
 	if err != nil {
-		return "", fmt.Errorf("error generating Go types for component parameters: %w", err)
+		return nil, fmt.Errorf("error parsing goapi-gen templates: %w", err)
 	}
-	allTypes := append(schemaTypes, paramTypes...)
 
-	responseTypes, err := GenerateTypesForResponses(t, swagger.Components.Responses)
-	if err != nil {
-		return "", fmt.Errorf("error generating Go types for component responses: %w", err)
+	// Override built-in templates with user-provided versions
+	for _, tpl := range t.Templates() {
+		if _, ok := opts.UserTemplates[tpl.Name()]; ok {
+			utpl := t.New(tpl.Name())
+			if _, err := utpl.Parse(opts.UserTemplates[tpl.Name()]); err != nil {
+				return nil, fmt.Errorf("error parsing user-provided template %q: %w", tpl.Name(), err)
+			}
+		}
 	}
-	allTypes = append(allTypes, responseTypes...)
+	return t, nil
+}
 
-	bodyTypes, err := GenerateTypesForRequestBodies(t, swagger.Components.RequestBodies)
+// GenerateTypeDefinitions produces the type definitions in ops and executes
+// the template.
+func GenerateTypeDefinitions(t *template.Template, swagger *openapi3.T, ops []OperationDefinition, excludeSchemas []string) (string, error) {
+	allTypes, err := componentTypeDefinitions(t, swagger, excludeSchemas)
 	if err != nil {
-		return "", fmt.Errorf("error generating Go types for component request bodies: %w", err)
+		return "", err
 	}
-	allTypes = append(allTypes, bodyTypes...)
 
 	paramTypesOut, err := GenerateTypesForOperations(t, ops)
 	if err != nil {
@@ -276,10 +427,117 @@ func GenerateTypeDefinitions(t *template.Template, swagger *openapi3.T, ops []Op
 		return "", fmt.Errorf("error generating allOf boilerplate: %w", err)
 	}
 
-	typeDefinitions := enumsOut + typesOut + enumTypesOut + paramTypesOut + allOfBoilerplate
+	allOfMarshalers, err := GenerateAllOfBoilerplate(t, allTypes)
+	if err != nil {
+		return "", fmt.Errorf("error generating allOf JSON handling: %w", err)
+	}
+
+	unionBoilerplate, err := GenerateUnionBoilerplate(t, allTypes)
+	if err != nil {
+		return "", fmt.Errorf("error generating union boilerplate: %w", err)
+	}
+
+	structTypes := allTypes
+	for _, op := range ops {
+		structTypes = append(structTypes, op.TypeDefinitions...)
+		for _, body := range op.Bodies {
+			if body.CustomType() {
+				structTypes = append(structTypes, *body.TypeDef(op.OperationID))
+			}
+		}
+	}
+
+	validators, err := GenerateValidators(t, structTypes)
+	if err != nil {
+		return "", fmt.Errorf("error generating x-go-validate validators: %w", err)
+	}
+
+	typeDefinitions := enumsOut + typesOut + enumTypesOut + paramTypesOut + allOfBoilerplate + allOfMarshalers + unionBoilerplate + validators
+
+	if globalOptions.YAMLTags {
+		yamlBoilerplate, err := GenerateYAMLBoilerplate(t, allTypes)
+		if err != nil {
+			return "", fmt.Errorf("error generating YAML handling: %w", err)
+		}
+		typeDefinitions += yamlBoilerplate
+	}
+
+	if globalOptions.FunctionalOptions {
+		functionalOptions, err := GenerateFunctionalOptions(t, structTypes)
+		if err != nil {
+			return "", fmt.Errorf("error generating functional options: %w", err)
+		}
+		typeDefinitions += functionalOptions
+	}
+
+	if globalOptions.DeepCopy {
+		copyTypes := allTypes
+		for _, op := range ops {
+			copyTypes = append(copyTypes, op.TypeDefinitions...)
+			for _, body := range op.Bodies {
+				copyTypes = append(copyTypes, *body.TypeDef(op.OperationID))
+			}
+		}
+		deepCopy, err := GenerateDeepCopy(t, copyTypes)
+		if err != nil {
+			return "", fmt.Errorf("error generating deep copy methods: %w", err)
+		}
+		typeDefinitions += deepCopy
+	}
+
+	if globalOptions.Swaggest {
+		swaggest, err := GenerateSwaggest(t, swagger, allTypes)
+		if err != nil {
+			return "", fmt.Errorf("error generating swaggest schemas: %w", err)
+		}
+		typeDefinitions += swaggest
+	}
+
+	if globalOptions.PgxTypes {
+		pgx, err := GenerateTemplates([]string{"pgx.tmpl"}, t, nil)
+		if err != nil {
+			return "", fmt.Errorf("error generating pgx types: %w", err)
+		}
+		typeDefinitions += pgx
+	}
+
+	if usesGenerics() {
+		generics, err := GenerateTemplates([]string{"generics.tmpl"}, t, nil)
+		if err != nil {
+			return "", fmt.Errorf("error generating generic helpers: %w", err)
+		}
+		typeDefinitions += generics
+	}
 	return typeDefinitions, nil
 }
 
+// componentTypeDefinitions returns the type definitions for everything under
+// the components section of swagger.
+func componentTypeDefinitions(t *template.Template, swagger *openapi3.T, excludeSchemas []string) ([]TypeDefinition, error) {
+	schemaTypes, err := GenerateTypesForSchemas(t, swagger.Components.Schemas, excludeSchemas)
+	if err != nil {
+		return nil, fmt.Errorf("error generating Go types for component schemas: %w", err)
+	}
+
+	paramTypes, err := GenerateTypesForParameters(t, swagger.Components.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("error generating Go types for component parameters: %w", err)
+	}
+	allTypes := append(schemaTypes, paramTypes...)
+
+	responseTypes, err := GenerateTypesForResponses(t, swagger.Components.Responses)
+	if err != nil {
+		return nil, fmt.Errorf("error generating Go types for component responses: %w", err)
+	}
+	allTypes = append(allTypes, responseTypes...)
+
+	bodyTypes, err := GenerateTypesForRequestBodies(t, swagger.Components.RequestBodies)
+	if err != nil {
+		return nil, fmt.Errorf("error generating Go types for component request bodies: %w", err)
+	}
+	return append(allTypes, bodyTypes...), nil
+}
+
 // GenerateConstants creates operation ids, context keys, paths, etc. to be
 // exported as constants
 func GenerateConstants(t *template.Template, ops []OperationDefinition) (string, error) {
@@ -322,16 +580,25 @@ func GenerateTypesForSchemas(t *template.Template, schemas map[string]*openapi3.
 		}
 		schemaRef := schemas[schemaName]
 
-		goSchema, err := GenerateGoSchema(schemaRef, []string{schemaName})
+		// Inline types are named after the x-go-name of the schema too.
+		path := []string{schemaName}
+		if name, ok := goNames[schemaName]; ok {
+			path = []string{name}
+		}
+		goSchema, err := GenerateGoSchema(schemaRef, path)
 		if err != nil {
 			return nil, fmt.Errorf("error converting Schema %s to Go type: %w", schemaName, err)
 		}
 
-		types = append(types, TypeDefinition{
+		td := TypeDefinition{
 			JSONName: schemaName,
-			TypeName: SchemaNameToTypeName(schemaName),
+			TypeName: componentTypeName(schemaName),
 			Schema:   goSchema,
-		})
+		}
+		types = append(types, td)
+		if readWriteTypes[td.TypeName] {
+			types = append(types, readWriteVariants(td)...)
+		}
 
 		types = append(types, goSchema.AdditionalTypeDefs()...)
 	}
@@ -353,7 +620,7 @@ func GenerateTypesForParameters(t *template.Template, params map[string]*openapi
 		typeDef := TypeDefinition{
 			JSONName: paramName,
 			Schema:   goType,
-			TypeName: SchemaNameToTypeName(paramName),
+			TypeName: versioned(SchemaNameToTypeName(paramName)),
 		}
 
 		if paramOrRef.Ref != "" {
@@ -362,7 +629,7 @@ func GenerateTypesForParameters(t *template.Template, params map[string]*openapi
 			if err != nil {
 				return nil, fmt.Errorf("error generating Go type for (%s) in parameter %s: %w", paramOrRef.Ref, paramName, err)
 			}
-			typeDef.TypeName = SchemaNameToTypeName(refType)
+			typeDef.TypeName = versioned(SchemaNameToTypeName(unversioned(refType)))
 		}
 
 		types = append(types, typeDef)
@@ -388,11 +655,14 @@ func GenerateTypesForResponses(t *template.Template, responses openapi3.Response
 			if err != nil {
 				return nil, fmt.Errorf("error generating Go type for schema in response %s: %w", responseName, err)
 			}
+			if variant, ok := readWriteVariant(jsonResponse.Schema, "Response"); ok {
+				goType.GoType = variant
+			}
 
 			typeDef := TypeDefinition{
 				JSONName: responseName,
 				Schema:   goType,
-				TypeName: SchemaNameToTypeName(responseName),
+				TypeName: versioned(SchemaNameToTypeName(responseName)),
 			}
 
 			if responseOrRef.Ref != "" {
@@ -401,7 +671,7 @@ func GenerateTypesForResponses(t *template.Template, responses openapi3.Response
 				if err != nil {
 					return nil, fmt.Errorf("error generating Go type for (%s) in parameter %s: %w", responseOrRef.Ref, responseName, err)
 				}
-				typeDef.TypeName = SchemaNameToTypeName(refType)
+				typeDef.TypeName = versioned(SchemaNameToTypeName(unversioned(refType)))
 			}
 			types = append(types, typeDef)
 		}
@@ -426,11 +696,14 @@ func GenerateTypesForRequestBodies(t *template.Template, bodies map[string]*open
 			if err != nil {
 				return nil, fmt.Errorf("error generating Go type for schema in body %s: %w", bodyName, err)
 			}
+			if variant, ok := readWriteVariant(jsonBody.Schema, "Request"); ok {
+				goType.GoType = variant
+			}
 
 			typeDef := TypeDefinition{
 				JSONName: bodyName,
 				Schema:   goType,
-				TypeName: SchemaNameToTypeName(bodyName),
+				TypeName: versioned(SchemaNameToTypeName(bodyName)),
 			}
 
 			if bodyOrRef.Ref != "" {
@@ -439,7 +712,7 @@ func GenerateTypesForRequestBodies(t *template.Template, bodies map[string]*open
 				if err != nil {
 					return nil, fmt.Errorf("error generating Go type for (%s) in body %s: %w", bodyOrRef.Ref, bodyName, err)
 				}
-				typeDef.TypeName = SchemaNameToTypeName(refType)
+				typeDef.TypeName = versioned(SchemaNameToTypeName(unversioned(refType)))
 			}
 			types = append(types, typeDef)
 		}
@@ -536,18 +809,7 @@ func GenerateEnums(t *template.Template, types []TypeDefinition) (string, error)
 
 // GenerateImports creates import statements and the package definition.
 func GenerateImports(t *template.Template, externalImports []string, packageName string) (string, error) {
-	// Read build version for incorporating into generated files
-	var modulePath string
-	var moduleVersion string
-	if bi, ok := debug.ReadBuildInfo(); ok {
-		modulePath = bi.Main.Path
-		moduleVersion = bi.Main.Version
-	} else {
-		// Unit tests have ok=false, so we'll just use "unknown" for the
-		// version if we can't read this.
-		modulePath = "unknown module path"
-		moduleVersion = "unknown version"
-	}
+	modulePath, moduleVersion := buildVersion()
 
 	context := struct {
 		ExternalImports []string
@@ -564,6 +826,16 @@ func GenerateImports(t *template.Template, externalImports []string, packageName
 	return GenerateTemplates([]string{"imports.tmpl"}, t, context)
 }
 
+// buildVersion reads the build version for incorporating into generated files.
+func buildVersion() (modulePath, moduleVersion string) {
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		return bi.Main.Path, bi.Main.Version
+	}
+	// Unit tests have ok=false, so we'll just use "unknown" for the
+	// version if we can't read this.
+	return "unknown module path", "unknown version"
+}
+
 // GenerateAdditionalPropertyBoilerplate creates any glue code for interfacing
 // with additional properties and JSON marshaling.
 func GenerateAdditionalPropertyBoilerplate(t *template.Template, typeDefs []TypeDefinition) (string, error) {
@@ -592,6 +864,168 @@ func GenerateAdditionalPropertyBoilerplate(t *template.Template, typeDefs []Type
 	return GenerateTemplates([]string{"additional-properties.tmpl"}, t, context)
 }
 
+// GenerateValidators generates the Validate method of every type in typeDefs
+// with properties which have an x-go-validate function.
+func GenerateValidators(t *template.Template, typeDefs []TypeDefinition) (string, error) {
+	var filteredTypes []TypeDefinition
+
+	m := map[string]bool{}
+
+	for _, t := range typeDefs {
+		if found := m[t.TypeName]; found {
+			continue
+		}
+
+		m[t.TypeName] = true
+
+		if t.Schema.RefType == "" && t.Schema.HasValidators() {
+			filteredTypes = append(filteredTypes, t)
+		}
+	}
+
+	context := struct {
+		Types []TypeDefinition
+	}{
+		Types: filteredTypes,
+	}
+
+	return GenerateTemplates([]string{"validate.tmpl"}, t, context)
+}
+
+// GenerateYAMLBoilerplate generates the YAML handling of the types in
+// typeDefs which the yaml package can't handle by their struct tags: enum
+// types, whose value is unexported, and the types with JSON methods of their
+// own, for additional properties, allOf compositions and unions, which read
+// and write YAML through their JSON methods.
+func GenerateYAMLBoilerplate(t *template.Template, typeDefs []TypeDefinition) (string, error) {
+	var enumTypes, jsonTypes []TypeDefinition
+
+	m := map[string]bool{}
+
+	for _, t := range typeDefs {
+		if found := m[t.TypeName]; found {
+			continue
+		}
+
+		m[t.TypeName] = true
+
+		switch {
+		case len(t.Schema.EnumValues) > 0:
+			enumTypes = append(enumTypes, t)
+		case t.Schema.RefType != "":
+		case t.Schema.HasAdditionalProperties || t.Schema.EmbedsAllOf() || t.Schema.Discriminator != nil:
+			jsonTypes = append(jsonTypes, t)
+		}
+	}
+
+	context := struct {
+		EnumTypes []TypeDefinition
+		JSONTypes []TypeDefinition
+	}{
+		EnumTypes: enumTypes,
+		JSONTypes: jsonTypes,
+	}
+
+	return GenerateTemplates([]string{"yaml.tmpl"}, t, context)
+}
+
+// GenerateAllOfBoilerplate generates the JSON handling for every allOf
+// composition in typeDefs which embeds referenced types. Embedded types may
+// have JSON methods of their own, which the merged struct would otherwise
+// promote, and fields sharing a JSON name would be dropped. Types with
+// additional properties already have their own JSON handling.
+func GenerateAllOfBoilerplate(t *template.Template, typeDefs []TypeDefinition) (string, error) {
+	var filteredTypes []TypeDefinition
+
+	m := map[string]bool{}
+
+	for _, t := range typeDefs {
+		if found := m[t.TypeName]; found {
+			continue
+		}
+
+		m[t.TypeName] = true
+
+		if t.Schema.EmbedsAllOf() && t.Schema.RefType == "" && !t.Schema.HasAdditionalProperties {
+			filteredTypes = append(filteredTypes, t)
+		}
+	}
+
+	context := struct {
+		Types []TypeDefinition
+	}{
+		Types: filteredTypes,
+	}
+
+	return GenerateTemplates([]string{"allof.tmpl"}, t, context)
+}
+
+// GenerateUnionBoilerplate generates the variant interface and JSON handling
+// for every discriminated union in typeDefs.
+func GenerateUnionBoilerplate(t *template.Template, typeDefs []TypeDefinition) (string, error) {
+	var filteredTypes []TypeDefinition
+
+	m := map[string]bool{}
+
+	for _, t := range typeDefs {
+		if found := m[t.TypeName]; found {
+			continue
+		}
+
+		m[t.TypeName] = true
+
+		if t.Schema.Discriminator != nil && t.Schema.RefType == "" {
+			filteredTypes = append(filteredTypes, t)
+		}
+	}
+
+	context := struct {
+		Types []TypeDefinition
+	}{
+		Types: filteredTypes,
+	}
+
+	return GenerateTemplates([]string{"union.tmpl"}, t, context)
+}
+
+// GenerateFunctionalOptions generates a constructor and functional options
+// for every struct type in typeDefs that declares its own properties. Types
+// whose constructor name would collide with another type are skipped.
+func GenerateFunctionalOptions(t *template.Template, typeDefs []TypeDefinition) (string, error) {
+	var filteredTypes []TypeDefinition
+
+	typeNames := map[string]bool{}
+	for _, t := range typeDefs {
+		typeNames[t.TypeName] = true
+	}
+
+	m := map[string]bool{}
+
+	for _, t := range typeDefs {
+		if found := m[t.TypeName]; found {
+			continue
+		}
+
+		m[t.TypeName] = true
+
+		if typeNames["New"+t.TypeName] {
+			continue
+		}
+
+		if t.Schema.RefType == "" && t.Schema.ArrayType == nil && len(t.Schema.Properties) > 0 {
+			filteredTypes = append(filteredTypes, t)
+		}
+	}
+
+	context := struct {
+		Types []TypeDefinition
+	}{
+		Types: filteredTypes,
+	}
+
+	return GenerateTemplates([]string{"functional-options.tmpl"}, t, context)
+}
+
 // SanitizeCode runs sanitizers across the generated Go code to ensure the
 // generated code will be able to compile.
 func SanitizeCode(goCode string) string {
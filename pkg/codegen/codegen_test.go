@@ -1,7 +1,14 @@
 package codegen
 
 import (
+	"encoding/json"
+	"go/ast"
 	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
 	"testing"
 	"text/template"
 
@@ -41,99 +48,2618 @@ func TestExamplePetStoreCodeGeneration(t *testing.T) {
 	// Check that the property comments were generated
 	assert.Contains(t, code, "// Unique id of the pet")
 
-	// Check that the summary comment contains newlines
+	// Check that the summary comment contains newlines, followed by the
+	// description
 	assert.Contains(t, code, `// Deletes a pet by ID
+	//
+	// deletes a single pet based on the ID supplied
 	// (DELETE /pets/{id})
 `)
 
+	// Check that long descriptions are wrapped
+	assert.Contains(t, code, `// Returns all pets from the system that the user has access to
+	// Nam sed condimentum est. Maecenas tempor sagittis sapien, nec rhoncus sem
+	// sagittis sit amet.`)
+
 	// Make sure the generated code is valid:
 	linter := new(lint.Linter)
 	problems, err := linter.Lint("test.gen.go", []byte(code))
 	assert.NoError(t, err)
-	assert.Len(t, problems, 0)
+	assert.Len(t, problems, 0)
+}
+
+func TestExamplePetStoreCodeGenerationWithContextFirst(t *testing.T) {
+	// Input vars for code generation:
+	packageName := "api"
+	opts := Options{
+		GenerateServer: true,
+		GenerateTypes:  true,
+		ContextFirst:   true,
+	}
+
+	// Get a spec from the example PetStore definition:
+	swagger, err := examplePetstore.GetSwagger()
+	assert.NoError(t, err)
+
+	// Run our code generation:
+	code, err := Generate(swagger, packageName, opts)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, code)
+
+	// Check that we have valid (formattable) code:
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// Check that the context is passed first, both in the interface and the
+	// handler shim:
+	assert.Contains(t, code, "DeletePet(ctx context.Context, w http.ResponseWriter, r *http.Request, id int64)")
+	assert.Contains(t, code, "siw.Handler.DeletePet(r.Context(), w, r, id)")
+}
+
+func TestExamplePetStoreCodeGenerationWithEcho(t *testing.T) {
+	packageName := "api"
+	opts := Options{
+		GenerateServer: true,
+		GenerateTypes:  true,
+		Framework:      "echo",
+	}
+
+	swagger, err := examplePetstore.GetSwagger()
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, packageName, opts)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, code)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// Check that the server is generated for echo, instead of chi:
+	assert.Contains(t, code, `"github.com/labstack/echo/v4"`)
+	assert.Contains(t, code, "DeletePet(ctx echo.Context, id int64) error")
+	assert.Contains(t, code, "FindPets(ctx echo.Context, params FindPetsParams) error")
+	assert.Contains(t, code, `router.DELETE(options.BaseURL+"/pets/:id", wrapper.DeletePet)`)
+	assert.NotContains(t, code, "func Handler(si ServerInterface")
+	// The *apierrors.APIError of handlers are converted to echo errors:
+	assert.Contains(t, code, "return echoError(w.Handler.DeletePet(ctx, id))")
+
+	// Unknown frameworks are rejected:
+	opts.Framework = "gin"
+	_, err = Generate(swagger, packageName, opts)
+	assert.EqualError(t, err, "unknown framework: gin")
+}
+
+func TestExamplePetStoreCodeGenerationWithNoOmitEmpty(t *testing.T) {
+	packageName := "api"
+	opts := Options{
+		GenerateTypes: true,
+		NoOmitEmpty:   true,
+	}
+
+	swagger, err := examplePetstore.GetSwagger()
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, packageName, opts)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, code)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// Optional fields keep their pointer types, but lose omitempty:
+	assert.Contains(t, code, "Tag *string `json:\"tag\"`")
+	assert.Contains(t, code, "Limit *int32 `json:\"limit\"`")
+	assert.NotContains(t, code, "omitempty")
+}
+
+func TestExamplePetStoreCodeGenerationWithFunctionalOptions(t *testing.T) {
+	packageName := "api"
+	opts := Options{
+		GenerateTypes:     true,
+		FunctionalOptions: true,
+	}
+
+	swagger, err := examplePetstore.GetSwagger()
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, packageName, opts)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, code)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// Required fields are positional, optional fields are options:
+	assert.Contains(t, code, "func NewNewPet(name string, opts ...NewPetOption) *NewPet {")
+	assert.Contains(t, code, "func WithNewPetTag(val string) NewPetOption {")
+	assert.Contains(t, code, "v.Tag = &val")
+	assert.Contains(t, code, "func NewFindPetsParams(opts ...FindPetsParamsOption) *FindPetsParams {")
+	assert.Contains(t, code, "func WithFindPetsParamsLimit(val int32) FindPetsParamsOption {")
+
+	// NewPet is already a type, so Pet doesn't get a constructor:
+	assert.NotContains(t, code, "type PetOption func(*Pet)")
+}
+
+func TestFunctionalOptionsArgNames(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: functional options
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Thing:
+      type: object
+      required: [opts, v, name]
+      properties:
+        opts:
+          type: string
+        v:
+          type: string
+        name:
+          type: string
+        val:
+          type: string
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true, FunctionalOptions: true})
+	assert.NoError(t, err)
+
+	// The properties named like the variables of the constructor don't
+	// collide with them.
+	assert.Contains(t, code, "func NewThing(name string, opts_ string, v_ string, opts ...ThingOption) *Thing {")
+	assert.Contains(t, code, "Opts: opts_,")
+	typeCheck(t, code)
+}
+
+func TestExamplePetStoreCodeGenerationWithClient(t *testing.T) {
+	packageName := "api"
+	opts := Options{
+		GenerateTypes:  true,
+		GenerateClient: true,
+	}
+
+	swagger, err := examplePetstore.GetSwagger()
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, packageName, opts)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, code)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "func NewClient(server string, opts ...ClientOption) *Client {")
+	assert.Contains(t, code, "func (c *Client) FindPets(ctx context.Context, params FindPetsParams) (*FindPetsResponse, error) {")
+	assert.Contains(t, code, "func (c *Client) AddPet(ctx context.Context, body AddPetJSONRequestBody) (*AddPetResponse, error) {")
+	assert.Contains(t, code, "func (c *Client) DeletePet(ctx context.Context, id int64) (*DeletePetResponse, error) {")
+	assert.Contains(t, code, `operationPath = strings.Replace(operationPath, "{id}", pathParam0, 1)`)
+
+	// JSON responses are decoded by status code:
+	assert.Contains(t, code, "JSON201      *Pet")
+	assert.Contains(t, code, "case rsp.StatusCode == 201:")
+	assert.Contains(t, code, "response.JSONDefault = &dest")
+}
+
+func TestClientBinaryResponses(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: files
+  version: 1.0.0
+paths:
+  /files/{name}:
+    get:
+      operationId: downloadFile
+      parameters:
+        - name: name
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: the file
+          content:
+            application/octet-stream:
+              schema:
+                type: string
+                format: binary
+            application/pdf: {}
+        '404':
+          description: missing
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Error'
+  /export:
+    get:
+      operationId: exportCSV
+      responses:
+        '200':
+          description: csv
+          content:
+            text/csv:
+              schema:
+                type: string
+                format: binary
+  /error:
+    get:
+      operationId: getError
+      responses:
+        '200':
+          description: json
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Error'
+components:
+  schemas:
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateClient: true})
+	assert.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// Binary responses are streamed, JSON ones are still decoded.
+	assert.Contains(t, code, "return &DownloadFileResponse{BodyReader: rsp.Body, HTTPResponse: rsp}, nil")
+	assert.Contains(t, code, "response.JSON404 = &dest")
+	assert.Contains(t, code, "return &ExportCSVResponse{BodyReader: rsp.Body, HTTPResponse: rsp}, nil")
+
+	// Operations without binary responses are left as they are.
+	assert.NotContains(t, code, "GetErrorResponse{BodyReader")
+	assert.Regexp(t, `type GetErrorResponse struct {\s+Body +\[\]byte\s+HTTPResponse`, code)
+}
+
+func TestClientLinks(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: orders
+  version: 1.0.0
+paths:
+  /users/{userId}/orders:
+    get:
+      operationId: getUserOrders
+      parameters:
+        - name: userId
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: status
+          in: query
+          schema:
+            type: string
+      responses:
+        '200':
+          description: the orders
+    post:
+      operationId: createOrder
+      parameters:
+        - name: userId
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        '201':
+          description: the order
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: string
+                  userId:
+                    type: integer
+          links:
+            userOrders:
+              operationId: getUserOrders
+              description: The orders of the user.
+              parameters:
+                userId: $response.body#/userId
+                query.status: pending
+            order:
+              operationRef: '#/paths/~1orders~1{orderId}/get'
+              parameters:
+                orderId: $response.body#/id
+            pets:
+              operationId: listPets
+  /orders/{orderId}:
+    get:
+      operationId: getOrder
+      parameters:
+        - name: orderId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: the order
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateClient: true, ClientLinks: true})
+	assert.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "func GetUserOrdersLinkFromCreateOrderResponse(resp CreateOrderResponse) (GetUserOrdersPathParams, GetUserOrdersParams, error) {")
+	assert.Contains(t, code, "// The orders of the user.")
+	assert.Contains(t, code, "if !(resp.StatusCode() == 201) {")
+	assert.Contains(t, code, `runtime.LinkValue("$response.body#/userId", "/users/{userId}/orders", resp.HTTPResponse, resp.Body)`)
+	assert.Contains(t, code, "runtime.BindLinkValue(value, &pathParams.UserID)")
+	assert.Contains(t, code, `value := json.RawMessage("\"pending\"")`)
+	assert.Contains(t, code, "runtime.BindLinkValue(value, &params.Status)")
+
+	// Links by operationRef are followed too, and links to operations which
+	// aren't generated are left out.
+	assert.Contains(t, code, "func GetOrderLinkFromCreateOrderResponse(resp CreateOrderResponse) (GetOrderPathParams, error) {")
+	assert.NotContains(t, code, "ListPetsLinkFrom")
+
+	// Links are only generated with ClientLinks.
+	code, err = Generate(swagger, "api", Options{GenerateTypes: true, GenerateClient: true})
+	assert.NoError(t, err)
+	assert.NotContains(t, code, "LinkFrom")
+
+	swagger.Paths["/users/{userId}/orders"].Post.Responses["201"].Value.Links["order"].Value.Parameters["orderId"] = "$request.body#/id"
+	_, err = Generate(swagger, "api", Options{GenerateTypes: true, GenerateClient: true, ClientLinks: true})
+	assert.ErrorContains(t, err, "unsupported runtime expression $request.body#/id")
+}
+
+func TestGenerateRequestBindMethods(t *testing.T) {
+	packageName := "api"
+	opts := Options{
+		GenerateTypes: true,
+	}
+
+	// Get the sample spec:
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testOpenAPIDefinition))
+	assert.NoError(t, err)
+
+	// Run our code generation:
+	code, err := Generate(swagger, packageName, opts)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, code)
+
+	// Check that we have valid (formattable) code:
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// Check that we have a package:
+	assert.Contains(t, code, "package api")
+
+	// func (AddPetJSONRequestBody) Bind(*http.Request) error {
+
+	// Check for expected request binders:
+	assert.Contains(t, code, "func (CreateLiveCatJSONRequestBody) Bind(*http.Request) error {")
+
+	// Check for unbindable request types:
+	assert.NotContains(t, code, "func (CreateCatJSONRequestBody) Bind(*http.Request) error {")
+}
+
+func TestPathParamsFromRequest(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: files
+  version: 1.0.0
+paths:
+  /users/{id}/files/{name}/{ratio}:
+    get:
+      operationId: getFile
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+            minimum: 1
+            maximum: 100
+            exclusiveMaximum: true
+        - name: name
+          in: path
+          required: true
+          schema:
+            type: string
+            minLength: 2
+            maxLength: 8
+            pattern: '^[a-z"]+$'
+        - name: ratio
+          in: path
+          required: true
+          schema:
+            type: number
+            minimum: 0.5
+      responses:
+        '204':
+          description: ok
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateServer: true})
+	assert.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "func GetFilePathParamsFromRequest(r *http.Request) (*GetFilePathParams, error) {")
+	assert.Contains(t, code, "params, err := BindGetFilePathParams(rctx)")
+	assert.Contains(t, code, "if params.ID < 1 {")
+	assert.Contains(t, code, "if params.ID >= 100 {")
+	assert.Contains(t, code, `errors.New("invalid value for parameter id: must be less than 100")`)
+	assert.Contains(t, code, "if utf8.RuneCountInString(params.Name) > 8 {")
+	assert.Contains(t, code, `var getFileNamePattern = regexp.MustCompile("^[a-z\"]+$")`)
+	assert.Contains(t, code, "if !getFileNamePattern.MatchString(params.Name) {")
+
+	// Bounds which aren't constants of the type are compared as floats.
+	assert.Contains(t, code, "if float64(params.Ratio) < 0.5 {")
+}
+
+func TestExamplePetStoreCodeGenerationWithUserTemplates(t *testing.T) {
+	userTemplates := map[string]string{"typedef.tmpl": "//blah"}
+
+	// Input vars for code generation:
+	packageName := "api"
+	opts := Options{
+		GenerateTypes: true,
+		UserTemplates: userTemplates,
+	}
+
+	// Get a spec from the example PetStore definition:
+	swagger, err := examplePetstore.GetSwagger()
+	assert.NoError(t, err)
+
+	// Run our code generation:
+	code, err := Generate(swagger, packageName, opts)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, code)
+
+	// Check that we have valid (formattable) code:
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// Check that we have a package:
+	assert.Contains(t, code, "package api")
+
+	// Check that the built-in template has been overridden
+	assert.Contains(t, code, "//blah")
+}
+
+func TestExampleOpenAPICodeGeneration(t *testing.T) {
+	// Input vars for code generation:
+	packageName := "testswagger"
+	opts := Options{
+		GenerateTypes: true,
+		EmbedSpec:     true,
+	}
+
+	// Get a spec from the test definition in this file:
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testOpenAPIDefinition))
+	assert.NoError(t, err)
+
+	// Run our code generation:
+	code, err := Generate(swagger, packageName, opts)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, code)
+
+	// Check that we have valid (formattable) code:
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+}
+
+func TestGoTypeExtensionImports(t *testing.T) {
+	packageName := "api"
+	opts := Options{
+		GenerateTypes: true,
+		SkipPrune:     true,
+	}
+
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: x-go-type
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Price:
+      type: string
+      x-go-type: github.com/shopspring/decimal.Decimal
+    Order:
+      type: object
+      required: [total]
+      properties:
+        total:
+          type: number
+          x-go-type: github.com/cockroachdb/apd/v3.Decimal
+        node:
+          x-go-type: gopkg.in/yaml.v3.Node
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, packageName, opts)
+	assert.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// Named schemas and inline properties both use the shortened type, and
+	// import its package exactly once:
+	assert.Contains(t, code, "type Price decimal.Decimal")
+	assert.Regexp(t, "Total +apd.Decimal", code)
+	assert.Regexp(t, `Node +\*yaml.Node`, code)
+	assert.Contains(t, code, `"github.com/shopspring/decimal"`)
+	assert.Contains(t, code, `"github.com/cockroachdb/apd/v3"`)
+	assert.Equal(t, 1, strings.Count(code, `"gopkg.in/yaml.v3"`))
+}
+
+func TestGoValidateExtension(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: x-go-validate
+  version: 1.0.0
+paths:
+  /cards:
+    post:
+      operationId: addCard
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [number]
+              properties:
+                number:
+                  type: string
+                  x-go-validate: github.com/acme/cards/v2.ValidateLuhn
+      responses:
+        '204':
+          description: added
+components:
+  schemas:
+    Card:
+      type: object
+      required: [number]
+      properties:
+        number:
+          type: string
+          x-go-validate: github.com/acme/cards/v2.ValidateLuhn
+        nickname:
+          type: string
+          x-go-validate: validateNickname
+        holder:
+          type: string
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, `"github.com/acme/cards/v2"`)
+	assert.Contains(t, code, `func (a Card) Validate() error {
+	if a.Nickname != nil {
+		if err := validateNickname(*a.Nickname); err != nil {
+			return fmt.Errorf("nickname: %w", err)
+		}
+	}
+	if err := cards.ValidateLuhn(a.Number); err != nil {
+		return fmt.Errorf("number: %w", err)
+	}
+	return nil
+}`)
+	assert.Contains(t, code, "func (a AddCardJSONBody) Validate() error {")
+	assert.NotContains(t, code, "func (a Pet) Validate() error")
+	assert.NotContains(t, code, "a.Holder")
+	assert.Equal(t, 2, strings.Count(code, ") Validate() error"))
+
+	swagger.Components.Schemas["Pet"].Value.Properties["name"].Value.Extensions["x-go-validate"] = json.RawMessage(`"[]cards.Validate"`)
+	_, err = Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	assert.EqualError(t, err, `error generating type definitions: error generating Go types for component schemas: error converting Schema Pet to Go type: property 'name': invalid value for "x-go-validate": "[]cards.Validate" is not a function`)
+}
+
+func TestDiscriminatedUnions(t *testing.T) {
+	packageName := "api"
+	opts := Options{
+		GenerateTypes: true,
+		SkipPrune:     true,
+	}
+
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: unions
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Cat:
+      type: object
+      properties:
+        petType: {type: string}
+    Dog:
+      type: object
+      properties:
+        petType: {type: string}
+    Pet:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+        - $ref: '#/components/schemas/Dog'
+      discriminator:
+        propertyName: petType
+        mapping:
+          cat: '#/components/schemas/Cat'
+          kitten: Cat
+    Owner:
+      type: object
+      properties:
+        pet:
+          anyOf:
+            - $ref: '#/components/schemas/Cat'
+            - $ref: '#/components/schemas/Dog'
+          discriminator:
+            propertyName: petType
+    Anything:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+        - type: string
+      discriminator:
+        propertyName: petType
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, packageName, opts)
+	assert.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Regexp(t, `type Pet struct {\s+Value PetVariant\s+}`, code)
+	assert.Contains(t, code, "func (Cat) isPet() {}")
+	assert.Contains(t, code, "func (u *Pet) UnmarshalJSON(b []byte) error {")
+	assert.Contains(t, code, `case "cat", "kitten":`)
+	assert.Contains(t, code, `case "Dog":`)
+
+	// Inline unions get their own type:
+	assert.Regexp(t, `Pet +\*OwnerPet`, code)
+	assert.Contains(t, code, "func (Dog) isOwnerPet() {}")
+
+	// Variants which aren't references can't implement the interface:
+	assert.Contains(t, code, "type Anything interface{}")
+}
+
+func TestUnionRequestBodies(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: union bodies
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      operationId: addPet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              oneOf:
+                - $ref: '#/components/schemas/Cat'
+                - $ref: '#/components/schemas/Dog'
+              discriminator:
+                propertyName: petType
+      responses:
+        204: {description: added}
+    put:
+      operationId: replacePet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        204: {description: replaced}
+components:
+  schemas:
+    Cat:
+      type: object
+      properties:
+        petType: {type: string}
+    Dog:
+      type: object
+      properties:
+        petType: {type: string}
+    Pet:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+        - $ref: '#/components/schemas/Dog'
+      discriminator:
+        propertyName: petType
+    Animal:
+      $ref: '#/components/schemas/Pet'
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	assert.NoError(t, err)
+	typeCheck(t, code)
+
+	// A defined type wouldn't have the JSON methods of the union:
+	assert.Contains(t, code, "type AddPetJSONRequestBody = AddPetJSONBody")
+	assert.Contains(t, code, "type ReplacePetJSONBody = Pet")
+	assert.Contains(t, code, "type ReplacePetJSONRequestBody = ReplacePetJSONBody")
+	assert.Contains(t, code, "type Animal = Pet")
+	assert.Contains(t, code, "func (u *AddPetJSONBody) UnmarshalJSON(b []byte) error {")
+
+	// The union implements render.Binder, instead of every body aliasing it:
+	assert.Contains(t, code, "func (AddPetJSONBody) Bind(*http.Request) error {")
+	assert.Contains(t, code, "func (Pet) Bind(*http.Request) error {")
+	assert.NotContains(t, code, "func (ReplacePetJSONRequestBody) Bind(")
+
+	// Nor do the aliases get methods of their own:
+	code, err = Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true, DeepCopy: true})
+	assert.NoError(t, err)
+	typeCheck(t, code)
+	assert.NotContains(t, code, "func (in *Animal) DeepCopy")
+}
+
+func TestValidatorTags(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: validator tags
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Thing:
+      type: object
+      required: [id, name]
+      properties:
+        id:
+          type: integer
+          minimum: 1
+        score:
+          type: number
+          maximum: 1
+          exclusiveMaximum: true
+        name:
+          type: string
+          maxLength: 64
+          pattern: '^[a-z]+(,[a-z]+)*$'
+        tags:
+          type: array
+          items:
+            type: string
+          minItems: 1
+          uniqueItems: true
+        createdAt:
+          type: string
+          format: date-time
+          minLength: 1
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	assert.NoError(t, err)
+	assert.NotContains(t, code, "validate:")
+
+	code, err = Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true, ValidatorTags: true})
+	assert.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "`json:\"id\" validate:\"min=1\"`")
+	assert.Contains(t, code, "`json:\"score,omitempty\" validate:\"omitempty,lt=1\"`")
+	assert.Contains(t, code, "`json:\"name\" validate:\"max=64,regexp=^[a-z]+(0x2C[a-z]+)*$\"`")
+	assert.Contains(t, code, "`json:\"tags,omitempty\" validate:\"omitempty,min=1,unique\"`")
+	assert.Contains(t, code, "`json:\"createdAt,omitempty\"`")
+}
+
+func TestProtoTags(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: proto tags
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Thing:
+      type: object
+      required: [id]
+      properties:
+        id:
+          type: integer
+          format: int64
+        name:
+          type: string
+        score:
+          type: number
+        scores:
+          type: array
+          items:
+            type: integer
+        tags:
+          type: array
+          items:
+            type: string
+        valid:
+          type: boolean
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	assert.NoError(t, err)
+	assert.NotContains(t, code, "protobuf:")
+
+	code, err = Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true, ProtoTags: true})
+	assert.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// Fields are numbered in the order they're generated.
+	assert.Contains(t, code, "`json:\"id\" protobuf:\"varint,1,opt,name=id,proto3\"`")
+	assert.Contains(t, code, "`json:\"name,omitempty\" protobuf:\"bytes,2,opt,name=name,proto3\"`")
+	assert.Contains(t, code, "`json:\"score,omitempty\" protobuf:\"fixed32,3,opt,name=score,proto3\"`")
+	assert.Contains(t, code, "`json:\"scores,omitempty\" protobuf:\"varint,4,rep,name=scores,packed,proto3\"`")
+	assert.Contains(t, code, "`json:\"tags,omitempty\" protobuf:\"bytes,5,rep,name=tags,proto3\"`")
+	assert.Contains(t, code, "`json:\"valid,omitempty\" protobuf:\"varint,6,opt,name=valid,proto3\"`")
+}
+
+func TestYAMLTags(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: yaml-tags
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Kind:
+      type: string
+      enum: [cat, dog]
+    Pet:
+      type: object
+      required: [pet_name]
+      properties:
+        pet_name:
+          type: string
+        kind:
+          $ref: '#/components/schemas/Kind'
+      additionalProperties:
+        type: string
+    Plain:
+      type: object
+      properties:
+        id:
+          type: integer
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	assert.NoError(t, err)
+	assert.NotContains(t, code, "yaml")
+
+	code, err = Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true, YAMLTags: true})
+	assert.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// Fields have the same names and omitempty as in JSON.
+	assert.Contains(t, code, "`json:\"pet_name\" yaml:\"pet_name\"`")
+	assert.Contains(t, code, "`json:\"kind,omitempty\" yaml:\"kind,omitempty\"`")
+	assert.Contains(t, code, "`json:\"id,omitempty\" yaml:\"id,omitempty\"`")
+	assert.Contains(t, code, "AdditionalProperties map[string]string `json:\"-\" yaml:\"-\"`")
+
+	// Enums and types with custom JSON handling get YAML methods, others
+	// don't need them.
+	assert.Contains(t, code, "func (t Kind) MarshalYAML() (interface{}, error) {")
+	assert.Contains(t, code, "func (t *Kind) UnmarshalYAML(node *yaml.Node) error {")
+	assert.Contains(t, code, "func (a Pet) MarshalYAML() (interface{}, error) {")
+	assert.Contains(t, code, "func (a *Pet) UnmarshalYAML(node *yaml.Node) error {")
+	assert.NotContains(t, code, "func (a Plain) MarshalYAML")
+}
+
+func TestServeSpec(t *testing.T) {
+	swagger, err := examplePetstore.GetSwagger()
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateServer: true})
+	assert.NoError(t, err)
+	assert.NotContains(t, code, "SpecHandler")
+
+	code, err = Generate(swagger, "api", Options{GenerateServer: true, ServeSpec: true})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, `const SpecPath = "/openapi.json"`)
+	assert.Contains(t, code, "func SpecHandler(spec []byte) http.Handler {")
+	assert.Contains(t, code, "func SwaggerUIHandler(specURL string) http.Handler {")
+	assert.Contains(t, code, `"crypto/sha256"`)
+	// Only embedded specs have a handler of their own.
+	assert.NotContains(t, code, "EmbeddedSpecHandler")
+
+	code, err = Generate(swagger, "api", Options{GenerateServer: true, EmbedSpec: true, ServeSpec: true, SpecPath: "/api/spec.json"})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, `const SpecPath = "/api/spec.json"`)
+	assert.Contains(t, code, "func EmbeddedSpecHandler() http.Handler {")
+}
+
+func TestSQLTypes(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: sql types
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Thing:
+      type: object
+      required: [id]
+      properties:
+        id:
+          type: string
+          format: uuid
+        born:
+          type: string
+          format: date
+        updated:
+          type: string
+          format: date-time
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	assert.NoError(t, err)
+	assert.Regexp(t, "ID +string", code)
+
+	code, err = Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true, SQLTypes: true})
+	assert.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Regexp(t, "ID +openapi_types.UUID", code)
+	assert.Regexp(t, `Born +\*openapi_types.Date`, code)
+	assert.Regexp(t, `Updated +\*time.Time`, code)
+}
+
+func TestPgxTypes(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: pgx types
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Thing:
+      type: object
+      required: [id]
+      properties:
+        id:
+          type: string
+          format: uuid
+        owner:
+          type: string
+          format: uuid
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	assert.NoError(t, err)
+	assert.NotContains(t, code, "pgtype")
+	assert.NotContains(t, code, "PgxUUID")
+
+	// pgx types take precedence over the database/sql ones.
+	for _, formatter := range []string{"goimports", "gofmt"} {
+		code, err = Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true, SQLTypes: true, PgxTypes: true, Format: formatter})
+		assert.NoError(t, err)
+
+		_, err = format.Source([]byte(code))
+		assert.NoError(t, err)
+
+		assert.Contains(t, code, `"github.com/jackc/pgx/v5/pgtype"`)
+		assert.Regexp(t, "ID +PgxUUID", code)
+		assert.Regexp(t, `Owner +\*PgxUUID`, code)
+		assert.Contains(t, code, "type PgxUUID string")
+		assert.Contains(t, code, "func (u *PgxUUID) ScanUUID(v pgtype.UUID) error {")
+		assert.Contains(t, code, "func (u PgxUUID) UUIDValue() (pgtype.UUID, error) {")
+		assert.Contains(t, code, "func (u *PgxUUID) ScanText(v pgtype.Text) error {")
+		assert.Contains(t, code, "func (u PgxUUID) TextValue() (pgtype.Text, error) {")
+		assert.NotContains(t, code, "openapi_types")
+	}
+}
+
+func TestAllOfMarshalers(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: allOf
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Base:
+      type: object
+      properties:
+        id:
+          type: string
+    Extra:
+      type: object
+      properties:
+        tag:
+          type: string
+      additionalProperties: true
+    Both:
+      allOf:
+        - $ref: '#/components/schemas/Base'
+        - $ref: '#/components/schemas/Extra'
+        - type: object
+          required: [own]
+          properties:
+            own:
+              type: boolean
+    Described:
+      allOf:
+        - $ref: '#/components/schemas/Base'
+        - description: Only a description.
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	assert.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// The promoted methods of Extra would hide the other fields of Both.
+	assert.Contains(t, code, "func (a Both) MarshalJSON() ([]byte, error) {")
+	assert.Contains(t, code, "func (a *Both) UnmarshalJSON(b []byte) error {")
+	assert.Contains(t, code, "part, err = json.Marshal(a.Extra)")
+	assert.Contains(t, code, `object["own"], err = json.Marshal(a.Own)`)
+
+	// A single embedded type already promotes the right methods.
+	assert.NotContains(t, code, "func (a Described) MarshalJSON")
+	assert.NotContains(t, code, "// Embedded fields due to inline allOf schema\n}")
+}
+
+func TestAllOfCycle(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: allOf
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    A:
+      allOf:
+        - $ref: '#/components/schemas/B'
+    B:
+      allOf:
+        - $ref: '#/components/schemas/A'
+`))
+	assert.NoError(t, err)
+
+	_, err = Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	assert.ErrorContains(t, err, "circular allOf reference")
+}
+
+func TestGoNameExtension(t *testing.T) {
+	spec := `
+openapi: 3.0.1
+info:
+  title: x-go-name
+  version: 1.0.0
+paths:
+  /v2/users/{id}:
+    patch:
+      operationId: patchUser
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/V2UsersIdPatchRequest'
+      responses:
+        '204':
+          description: patched
+  /v2/users:
+    post:
+      operationId: createUser
+      requestBody:
+        content:
+          application/json:
+            schema:
+              x-go-name: NewUser
+              type: object
+              properties:
+                name:
+                  type: string
+      responses:
+        '204':
+          description: created
+components:
+  schemas:
+    V2UsersIdPatchRequest:
+      x-go-name: UpdateUserRequest
+      type: object
+      properties:
+        user_id:
+          type: string
+          x-go-name: UserID
+        manager:
+          $ref: '#/components/schemas/V2UsersIdPatchRequest'
+        status:
+          type: string
+          enum: [active]
+`
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateServer: true})
+	assert.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "type UpdateUserRequest struct {")
+	assert.NotContains(t, code, "V2UsersIdPatchRequest struct")
+	assert.Regexp(t, "UserID +\\*string +`json:\"user_id,omitempty\"`", code)
+	// References use the name of the type, not of the field.
+	assert.Regexp(t, "Manager +\\*UpdateUserRequest +`json:\"manager,omitempty\"`", code)
+	assert.Contains(t, code, "UpdateUserRequestStatusActive")
+	assert.Contains(t, code, "type PatchUserJSONBody UpdateUserRequest")
+	assert.Contains(t, code, "type NewUser struct {")
+	assert.Contains(t, code, "type CreateUserJSONRequestBody NewUser")
+
+	for name, value := range map[string]string{
+		"not an identifier": "Update-User",
+		"unexported":        "updateUser",
+		"collision":         "Other",
+	} {
+		t.Run(name, func(t *testing.T) {
+			invalid := strings.Replace(spec, "x-go-name: UpdateUserRequest", "x-go-name: "+value, 1) + `
+    Other:
+      type: object
+      properties:
+        manager:
+          $ref: '#/components/schemas/V2UsersIdPatchRequest'
+`
+			swagger, err := openapi3.NewLoader().LoadFromData([]byte(invalid))
+			assert.NoError(t, err)
+
+			_, err = Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestGoEmbeddedExtension(t *testing.T) {
+	spec := `
+openapi: 3.0.1
+info:
+  title: x-go-embedded
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: the pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Audit:
+      x-go-embedded: true
+      type: object
+      properties:
+        createdAt:
+          type: string
+          format: date-time
+        updatedAt:
+          type: string
+          format: date-time
+    Owner:
+      type: object
+      properties:
+        ownerId:
+          type: string
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        audit:
+          $ref: '#/components/schemas/Audit'
+        owner:
+          x-go-embedded: true
+          allOf:
+            - $ref: '#/components/schemas/Owner'
+        vet:
+          $ref: '#/components/schemas/Owner'
+`
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true})
+	assert.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// The properties keep their JSON name, while their fields are promoted.
+	assert.Regexp(t, "\n\\s+Audit +`json:\"audit,omitempty\"`", code)
+	assert.Regexp(t, "\n\\s+Owner +`json:\"owner,omitempty\"`", code)
+	assert.Regexp(t, "Vet +\\*Owner +`json:\"vet,omitempty\"`", code)
+
+	for name, schema := range map[string]string{
+		"not an object": `
+    Id:
+      x-go-embedded: true
+      type: string
+    Pet:
+      properties:
+        id:
+          $ref: '#/components/schemas/Id'`,
+		"map": `
+    Labels:
+      x-go-embedded: true
+      type: object
+      additionalProperties:
+        type: string
+    Pet:
+      properties:
+        labels:
+          $ref: '#/components/schemas/Labels'`,
+		"no reference": `
+    Pet:
+      properties:
+        audit:
+          x-go-embedded: true
+          type: object
+          properties:
+            createdAt:
+              type: string`,
+		"embedded twice": `
+    Audit:
+      x-go-embedded: true
+      properties:
+        createdAt:
+          type: string
+    Pet:
+      properties:
+        created:
+          $ref: '#/components/schemas/Audit'
+        updated:
+          $ref: '#/components/schemas/Audit'`,
+	} {
+		swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: x-go-embedded
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: the pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:` + schema))
+		assert.NoError(t, err, name)
+		_, err = Generate(swagger, "api", Options{GenerateTypes: true})
+		assert.Error(t, err, name)
+	}
+}
+
+func TestSkipNullablePointer(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: nullable
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Thing:
+      type: object
+      required: [required]
+      properties:
+        required:
+          type: string
+          nullable: true
+        optional:
+          type: string
+          nullable: true
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	assert.NoError(t, err)
+	assert.Regexp(t, "Required +\\*string +`json:\"required\"`", code)
+	assert.Regexp(t, "Optional +\\*string +`json:\"optional\"`", code)
+
+	code, err = Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true, SkipNullablePointer: true})
+	assert.NoError(t, err)
+	assert.Regexp(t, "Required +string +`json:\"required\"`", code)
+	assert.Regexp(t, "Optional +\\*string +`json:\"optional\"`", code)
+}
+
+func TestAdditionalProperties(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: additional
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Typed:
+      type: object
+      properties:
+        name:
+          type: string
+      additionalProperties:
+        type: string
+    Any:
+      type: object
+      additionalProperties: true
+    Closed:
+      type: object
+      properties:
+        name:
+          type: string
+      additionalProperties: false
+    Empty:
+      type: object
+      additionalProperties: false
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Regexp(t, "type Typed struct {\\n\\tName +\\*string +`json:\"name,omitempty\"`\\n\\tAdditionalProperties map\\[string\\]string `json:\"-\"`", code)
+	assert.Contains(t, code, "func (a Typed) Get(fieldName string) (value string, found bool)")
+	assert.Contains(t, code, "type Any struct {\n\tAdditionalProperties map[string]interface{} `json:\"-\"`\n}")
+	assert.Contains(t, code, "func (a *Any) UnmarshalJSON(b []byte) error")
+
+	assert.Contains(t, code, "// Closed defines model for Closed.\n//\n// Additional properties are rejected by the spec, and dropped when unmarshaling.\ntype Closed struct {\n\tName *string `json:\"name,omitempty\"`\n}")
+	assert.NotContains(t, code, "func (a *Closed) UnmarshalJSON")
+	assert.Contains(t, code, "// Additional properties are rejected by the spec, and dropped when unmarshaling.\ntype Empty struct{}")
+}
+
+func TestDeprecated(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: deprecated
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets
+      description: Use searchPets instead.
+      deprecated: true
+      parameters:
+        - name: legacy
+          in: query
+          deprecated: true
+          schema:
+            type: string
+      responses:
+        '204':
+          description: No content
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        nickname:
+          type: string
+          deprecated: true
+        oldName:
+          type: string
+          description: Replaced by name.
+          deprecated: true
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateServer: true, GenerateClient: true, SkipPrune: true})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "// Deprecated: see OpenAPI spec\n\tNickname *string")
+	assert.Contains(t, code, "// Deprecated: Replaced by name.\n\tOldName *string")
+	assert.Contains(t, code, "// Deprecated: see OpenAPI spec\n\tLegacy *string")
+	assert.Contains(t, code, "// (GET /pets)\n\t//\n\t// Deprecated: Use searchPets instead.\n\tListPets(")
+	assert.Contains(t, code, "// (GET /pets)\n//\n// Deprecated: Use searchPets instead.\nfunc (c *Client) ListPets(")
+}
+
+func TestExternalDocs(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: external docs
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets
+      externalDocs:
+        description: Listing pets
+        url: https://example.com/docs/pets
+      responses:
+        '200':
+          description: The pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      type: object
+      description: A pet.
+      externalDocs:
+        url: https://example.com/docs/pet
+      properties:
+        name:
+          type: string
+    Kind:
+      type: string
+      enum: [dog, cat]
+      externalDocs:
+        description: The kinds of pets
+        url: https://example.com/docs/kinds
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateServer: true, GenerateClient: true, SkipPrune: true})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "// A pet.\n//\n// See: https://example.com/docs/pet\ntype Pet struct {")
+	assert.Contains(t, code, "// Kind defines model for Kind.\n//\n// See: The kinds of pets https://example.com/docs/kinds\ntype Kind struct {")
+	assert.Contains(t, code, "// (GET /pets)\n\t//\n\t// See: Listing pets https://example.com/docs/pets\n\tListPets(")
+	assert.Contains(t, code, "// (GET /pets)\n//\n// See: Listing pets https://example.com/docs/pets\nfunc (c *Client) ListPets(")
+}
+
+func TestExcludeSchemas(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: exclude schemas
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      operationId: addPet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        '204':
+          description: No content
+          headers:
+            X-Created:
+              schema:
+                $ref: '#/components/schemas/Timestamp'
+        '400':
+          description: Bad request
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/ErrorBody'
+components:
+  schemas:
+    Timestamp:
+      type: object
+      x-go-type: time.Time
+      properties:
+        seconds:
+          type: integer
+    ErrorBody:
+      type: object
+      properties:
+        message:
+          type: string
+    Pet:
+      type: object
+      required: [born]
+      properties:
+        born:
+          $ref: '#/components/schemas/Timestamp'
+        lastError:
+          $ref: '#/components/schemas/ErrorBody'
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateServer: true, ExcludeSchemas: []string{"Timestamp", "ErrorBody"}})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.NotContains(t, code, "type Timestamp ")
+	assert.NotContains(t, code, "type ErrorBody ")
+	// References use the x-go-type of excluded schemas, or interface{}.
+	assert.Contains(t, code, "Born time.Time `json:\"born\"`")
+	assert.Contains(t, code, `	// The ErrorBody schema is excluded from generation, set its x-go-type to use a
+	// Go type for it.
+	LastError *interface{} `)
+	assert.Contains(t, code, "func AddPetJSON400Response(body interface{}) *Response {")
+}
+
+func TestHandlerForTag(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: tags
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [public, pets]
+      responses:
+        '204':
+          description: No content
+  /admin/pets/{id}:
+    delete:
+      operationId: deletePet
+      tags: [admin]
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '204':
+          description: No content
+  /health:
+    get:
+      operationId: health
+      responses:
+        '204':
+          description: No content
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateServer: true})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "func HandlerFromMuxForTag(r chi.Router, si ServerInterface, tag string, baseURL string, opts ...ServerOption) http.Handler {")
+	assert.Contains(t, code, `		switch options.Tag {
+		case "admin":
+			r.Delete(prefix+"/admin/pets/{id}", wrapper.DeletePet)
+		case "pets":
+			r.Get(prefix+"/pets", wrapper.ListPets)
+		case "public":
+			r.Get(prefix+"/pets", wrapper.ListPets)
+		default:
+			panic("goapi-gen: no operation is tagged " + options.Tag)
+		}`)
+	// Untagged operations are only registered along with the others.
+	assert.Equal(t, 1, strings.Count(code, "wrapper.Health)"))
+}
+
+func TestConnectFramework(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: connect
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      operationId: addPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+      responses:
+        '204':
+          description: No content
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '204':
+          description: No content
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateServer: true, Framework: "connect"})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, `"connectrpc.com/connect"`)
+	// The REST server is generated as well, for the Connect handlers to
+	// serve the requests through it.
+	assert.Contains(t, code, "func Handler(si ServerInterface, opts ...ServerOption) http.Handler {")
+	assert.Contains(t, code, "func NewConnectHandler(rest http.Handler, service string, opts ...connect.HandlerOption) (string, http.Handler) {")
+	assert.Contains(t, code, `connectUnary[AddPetJSONRequestBody](rest, "POST", "/pets"),`)
+	assert.Contains(t, code, `connectUnary[struct{}](rest, "GET", "/pets/{id}"),`)
+	assert.Contains(t, code, `mux.Handle("/"+service+"/GetPet", NewGetPetConnectHandler(rest, service, opts...))`)
+
+	_, err = Generate(swagger, "api", Options{GenerateServer: true, Framework: "connect", GoVersion: "1.17"})
+	assert.EqualError(t, err, "the connect framework uses generics, which Go 1.17 doesn't have")
+}
+
+func TestFormat(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: format
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        born:
+          type: string
+          format: date-time
+`))
+	assert.NoError(t, err)
+
+	goimports, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	assert.NoError(t, err)
+	assert.Contains(t, goimports, "\t\"time\"\n")
+	assert.NotContains(t, goimports, "\"net/http\"")
+
+	// gofmt removes the unused imports as well.
+	gofmt, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true, Format: "gofmt"})
+	assert.NoError(t, err)
+	assert.Equal(t, goimports, gofmt)
+
+	none, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true, Format: "none"})
+	assert.NoError(t, err)
+	assert.Contains(t, none, "\"net/http\"")
+	_, err = format.Source([]byte(none))
+	assert.NoError(t, err)
+
+	_, err = Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true, Format: "black"})
+	assert.EqualError(t, err, `unknown format "black"`)
+}
+
+func TestAssumedPackageName(t *testing.T) {
+	for path, name := range map[string]string{
+		"net/http":                          "http",
+		"github.com/go-chi/chi/v5":          "chi",
+		"gopkg.in/yaml.v3":                  "yaml",
+		"github.com/go-openapi/jsonpointer": "jsonpointer",
+		"github.com/labstack/go-echo":       "echo",
+	} {
+		assert.Equal(t, name, assumedPackageName(path), path)
+	}
+}
+
+func TestCallbacks(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: callbacks
+  version: 1.0.0
+paths:
+  /subscriptions:
+    post:
+      operationId: subscribe
+      callbacks:
+        onEvent:
+          '{$request.body#/callbackUrl}':
+            post:
+              requestBody:
+                required: true
+                content:
+                  application/json:
+                    schema:
+                      $ref: '#/components/schemas/Event'
+              responses:
+                '202':
+                  description: Accepted
+                '204':
+                  description: Received
+            get:
+              responses:
+                '200':
+                  description: ok
+      responses:
+        '201':
+          description: Subscribed
+components:
+  schemas:
+    Event:
+      type: object
+      properties:
+        id:
+          type: string
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateCallbacks: true})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "type CallbackOptions struct {")
+	// The callback has several methods, which its clients are named after.
+	assert.Contains(t, code, "type SubscribeOnEventPostCallbackJSONRequestBody SubscribeOnEventPostCallbackJSONBody")
+	assert.Contains(t, code, "func (c *SubscribeOnEventPostCallback) Deliver(ctx context.Context, callbackURL string, payload SubscribeOnEventPostCallbackJSONRequestBody) error {")
+	assert.Contains(t, code, `return deliverCallback(ctx, c.Options, "POST", callbackURL, "application/json", body, []int{202, 204})`)
+	assert.Contains(t, code, "func (c *SubscribeOnEventGetCallback) Deliver(ctx context.Context, callbackURL string) error {")
+	assert.Contains(t, code, `return deliverCallback(ctx, c.Options, "GET", callbackURL, "", nil, []int{200})`)
+
+	// Callbacks are only generated with GenerateCallbacks.
+	code, err = Generate(swagger, "api", Options{GenerateTypes: true})
+	assert.NoError(t, err)
+	assert.NotContains(t, code, "Callback")
+}
+
+func TestOperationContext(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: metadata
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    get:
+      operationId: get-pet
+      tags: [pets, "public"]
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '204':
+          description: No content
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateServer: true})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "func GetPetContext(next http.Handler) http.Handler {")
+	// The operationId is the one of the spec, rather than its Go name.
+	assert.Contains(t, code, `			id:   "get-pet",
+			tags: []string{"pets", "public"},
+			path: "/pets/{id}",`)
+	assert.Contains(t, code, "handler = GetPetContext(handler).ServeHTTP")
+}
+
+func TestMiddlewareFuncs(t *testing.T) {
+	spec := `
+openapi: 3.0.1
+info:
+  title: middleware
+  version: 1.0.0
+paths:
+  /pets:
+    x-go-middleware: [Auth]
+    get:
+      operationId: listPets
+      x-go-middleware: [RateLimit]
+      responses:
+        '204':
+          description: No content
+    post:
+      operationId: addPet
+      responses:
+        '204':
+          description: No content
+`
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateServer: true})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// The functions are checked once, and path middleware wraps the others.
+	assert.Equal(t, 1, strings.Count(code, "_ func(http.Handler) http.Handler = Auth\n"))
+	assert.Contains(t, code, "_ func(http.Handler) http.Handler = RateLimit\n")
+	assert.Contains(t, code, "handler = Auth(RateLimit(handler)).ServeHTTP")
+	assert.Contains(t, code, "handler = Auth(handler).ServeHTTP")
+
+	code, err = Generate(swagger, "api", Options{GenerateServer: true, Framework: "echo"})
+	assert.NoError(t, err)
+	assert.Contains(t, code, `router.GET(options.BaseURL+"/pets", wrapper.ListPets, echo.WrapMiddleware(Auth), echo.WrapMiddleware(RateLimit))`)
+
+	swagger, err = openapi3.NewLoader().LoadFromData([]byte(strings.Replace(spec, "[RateLimit]", "[rate-limit]", 1)))
+	assert.NoError(t, err)
+	_, err = Generate(swagger, "api", Options{GenerateServer: true})
+	assert.ErrorContains(t, err, `invalid value for "x-go-middleware": "rate-limit" is not a Go identifier`)
+}
+
+func TestEnforceTimeouts(t *testing.T) {
+	spec := `
+openapi: 3.0.1
+info:
+  title: timeouts
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      x-timeout: 1m30s
+      responses:
+        '204':
+          description: No content
+    post:
+      operationId: addPet
+      responses:
+        '204':
+          description: No content
+`
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateServer: true})
+	assert.NoError(t, err)
+	assert.NotContains(t, code, "timeoutHandler")
+
+	code, err = Generate(swagger, "api", Options{GenerateServer: true, EnforceTimeouts: true})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+	assert.Contains(t, code, "handler = timeoutHandler(90*time.Second, handler)")
+	assert.Contains(t, code, "handler = timeoutHandler(siw.Timeout, handler)")
+	assert.Contains(t, code, "func WithDefaultTimeout(timeout time.Duration) ServerOption {")
+	assert.Contains(t, code, "http.StatusGatewayTimeout")
+
+	code, err = Generate(swagger, "api", Options{GenerateServer: true, EnforceTimeouts: true, Framework: "echo"})
+	assert.NoError(t, err)
+	assert.Contains(t, code, `router.GET(options.BaseURL+"/pets", wrapper.ListPets, timeoutMiddleware(90*time.Second))`)
+	assert.Contains(t, code, `router.POST(options.BaseURL+"/pets", wrapper.AddPet, timeoutMiddleware(options.Timeout))`)
+
+	for _, timeout := range []string{"soon", "-1s"} {
+		swagger, err = openapi3.NewLoader().LoadFromData([]byte(strings.Replace(spec, "1m30s", timeout, 1)))
+		assert.NoError(t, err)
+		_, err = Generate(swagger, "api", Options{GenerateServer: true})
+		assert.ErrorContains(t, err, `invalid value for "x-timeout"`)
+	}
+}
+
+func TestNoLint(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: nolint
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '204':
+          description: No content
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateServer: true, GenerateClient: true})
+	assert.NoError(t, err)
+	assert.NotContains(t, code, "nolint")
+
+	opts := Options{GenerateTypes: true, GenerateServer: true, GenerateClient: true, NoLint: []string{"revive", "wsl", "godot", "cyclop", ""}}
+	code, err = Generate(swagger, "api", opts)
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+	assert.Contains(t, code, "DO NOT EDIT.\n//\n//nolint:revive,godot\npackage api\n")
+	assert.Contains(t, code, "func (siw *ServerInterfaceWrapper) GetPet(w http.ResponseWriter, r *http.Request) { //nolint:wsl,cyclop\n")
+	assert.Contains(t, code, "func Handler(si ServerInterface, opts ...ServerOption) http.Handler { //nolint:wsl,cyclop\n")
+	assert.Contains(t, code, "func BindGetPetPathParams(rctx *chi.Context) (*GetPetPathParams, error) { //nolint:wsl,cyclop\n")
+	assert.Regexp(t, `func \(c \*Client\) GetPet\(.*\) { //nolint:wsl,cyclop\n`, code)
+
+	opts.Framework = "echo"
+	code, err = Generate(swagger, "api", opts)
+	assert.NoError(t, err)
+	assert.Contains(t, code, "func (w *ServerInterfaceWrapper) GetPet(ctx echo.Context) error { //nolint:wsl,cyclop\n")
+	assert.Contains(t, code, "func RegisterHandlers(router EchoRouter, si ServerInterface, opts ...ServerOption) { //nolint:wsl,cyclop\n")
+
+	code, err = GenerateMock(swagger, "api", Options{NoLint: []string{"godot"}})
+	assert.NoError(t, err)
+	assert.Contains(t, code, "DO NOT EDIT.\n//\n//nolint:godot\npackage api\n")
+}
+
+func TestWebhooks(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: webhooks
+  version: 1.0.0
+paths: {}
+x-webhooks:
+  newPet:
+    post:
+      summary: A pet was added.
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        '200':
+          description: Received
+  ping:
+    post:
+      responses:
+        '200':
+          description: Received
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateServer: true})
+	assert.NoError(t, err)
+	// Pet is only used by the webhook, and isn't pruned.
+	assert.Contains(t, code, "type Pet struct")
+	assert.Contains(t, code, "type NewPetWebhookJSONRequestBody NewPetWebhookJSONBody")
+	assert.Contains(t, code, `type WebhookServer interface {
+	// A pet was added.
+	// (POST webhook newPet)
+	NewPet(w http.ResponseWriter, r *http.Request, body NewPetWebhookJSONRequestBody)
+
+	// (POST webhook ping)
+	Ping(w http.ResponseWriter, r *http.Request)
+}`)
+	assert.Contains(t, code, "func ServeWebhook(ws WebhookServer) http.Handler {")
+	assert.Contains(t, code, `		case "newPet":
+			switch r.Method {
+			case "POST":
+				var body NewPetWebhookJSONRequestBody`)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// Without webhooks, there is no WebhookServer.
+	delete(swagger.Extensions, "x-webhooks")
+	code, err = Generate(swagger, "api", Options{GenerateTypes: true, GenerateServer: true})
+	assert.NoError(t, err)
+	assert.NotContains(t, code, "WebhookServer")
 }
 
-func TestGenerateRequestBindMethods(t *testing.T) {
-	packageName := "api"
-	opts := Options{
-		GenerateTypes: true,
+func TestDeepCopy(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: deep copy
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      operationId: addPet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        '204':
+          description: No content
+components:
+  schemas:
+    Owner:
+      type: object
+      properties:
+        name:
+          type: string
+    Pet:
+      type: object
+      properties:
+        tags:
+          type: array
+          items:
+            type: string
+        owner:
+          $ref: '#/components/schemas/Owner'
+        vets:
+          type: array
+          items:
+            $ref: '#/components/schemas/Owner'
+        extra: {}
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true})
+	assert.NoError(t, err)
+	assert.NotContains(t, code, "DeepCopy")
+
+	code, err = Generate(swagger, "api", Options{GenerateTypes: true, DeepCopy: true})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, `func (in *Pet) DeepCopyInto(out *Pet) {
+	*out = *in
+	if in.Extra != nil {
+		out.Extra = new(interface{})
+		in, out := in.Extra, out.Extra
+		*out = runtime.DeepCopyJSONValue(*in)
+	}
+	if in.Owner != nil {
+		out.Owner = new(Owner)
+		in, out := in.Owner, out.Owner
+		in.DeepCopyInto(out)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Vets != nil {
+		in, out := &in.Vets, &out.Vets
+		*out = make([]Owner, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
+}`)
+	assert.Contains(t, code, `func (in *Pet) DeepCopy() *Pet {
+	if in == nil {
+		return nil
+	}
+	out := new(Pet)
+	in.DeepCopyInto(out)
+	return out
+}`)
+	// The methods of the underlying type of defined types aren't promoted.
+	assert.Contains(t, code, `func (in *AddPetJSONBody) DeepCopyInto(out *AddPetJSONBody) {
+	*out = *in
+	(*Pet)(in).DeepCopyInto((*Pet)(out))
+}`)
+	assert.Contains(t, code, "func (in *AddPetJSONRequestBody) DeepCopy() *AddPetJSONRequestBody {")
 
-	// Get the sample spec:
-	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testOpenAPIDefinition))
+	// Aliases can't have methods, and are copied as their type.
+	code, err = Generate(swagger, "api", Options{GenerateTypes: true, DeepCopy: true, AliasTypes: true})
 	assert.NoError(t, err)
+	assert.Contains(t, code, "type AddPetJSONRequestBody = AddPetJSONBody")
+	assert.NotContains(t, code, "func (in *AddPetJSONRequestBody)")
+}
 
-	// Run our code generation:
-	code, err := Generate(swagger, packageName, opts)
+func TestEntryPointsPrepare(t *testing.T) {
+	const spec = `
+openapi: 3.0.1
+info:
+  title: entry points
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      x-typo: true
+      responses:
+        '204':
+          description: No content
+components:
+  schemas:
+    Unused:
+      type: string
+`
+	entryPoints := map[string]func(*openapi3.T, string, Options) (string, error){
+		"Generate":               Generate,
+		"GenerateMock":           GenerateMock,
+		"GenerateExamples":       GenerateExamples,
+		"GenerateValidatorTests": GenerateValidatorTests,
+		"GenerateProto":          GenerateProto,
+	}
+	for name, generate := range entryPoints {
+		t.Run(name, func(t *testing.T) {
+			swagger, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+			assert.NoError(t, err)
+
+			_, err = generate(swagger, "api", Options{GenerateTypes: true, StrictExtensions: true})
+			assert.ErrorContains(t, err, "x-typo at #/paths/~1pets/get")
+
+			_, err = generate(swagger, "api", Options{GenerateTypes: true})
+			assert.NoError(t, err)
+			assert.NotContains(t, swagger.Components.Schemas, "Unused")
+		})
+	}
+
+	_, err := GenerateFixture("api", Options{Framework: "connect", GoVersion: "1.17"})
+	assert.ErrorContains(t, err, "the connect framework uses generics")
+}
+
+func TestDeepCopyUnions(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: deep copy unions
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Cat:
+      type: object
+      properties:
+        toys:
+          type: array
+          items:
+            type: string
+    Dog:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+    Pet:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+        - $ref: '#/components/schemas/Dog'
+      discriminator:
+        propertyName: petType
+`))
 	assert.NoError(t, err)
-	assert.NotEmpty(t, code)
 
-	// Check that we have valid (formattable) code:
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true, DeepCopy: true})
+	assert.NoError(t, err)
+	typeCheck(t, code)
+
+	// The variant is copied with its own methods, rather than shared:
+	assert.Contains(t, code, `func (in *Pet) DeepCopyInto(out *Pet) {
+	*out = *in
+	switch v := in.Value.(type) {
+	case Cat:
+		copied := v
+		v.DeepCopyInto(&copied)
+		out.Value = copied
+	case Dog:
+		copied := v
+		v.DeepCopyInto(&copied)
+		out.Value = copied
+	}
+}`)
+}
+
+func TestSwaggest(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: swaggest
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        '200':
+          description: The pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Anything: {}
+    Owner:
+      type: object
+      properties:
+        name:
+          type: string
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        owner:
+          $ref: '#/components/schemas/Owner'
+        carer:
+          $ref: '#/components/schemas/Person'
+        extra:
+          $ref: '#/components/schemas/Anything'
+    Person:
+      $ref: '#/components/schemas/Owner'
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true})
+	assert.NoError(t, err)
+	assert.NotContains(t, code, "swaggest")
+
+	code, err = Generate(swagger, "api", Options{GenerateTypes: true, Swaggest: true})
+	assert.NoError(t, err)
 	_, err = format.Source([]byte(code))
 	assert.NoError(t, err)
 
-	// Check that we have a package:
-	assert.Contains(t, code, "package api")
+	assert.Contains(t, code, `swaggest "github.com/swaggest/openapi-go/openapi3"`)
+	assert.Contains(t, code, `"Person":   "{\"$ref\":\"#/components/schemas/Owner\"}",`)
+	assert.Contains(t, code, `func RegisterSchemas(r *swaggest.Reflector) error {`)
+	assert.Contains(t, code, `func (Pet) JSONSchemaBytes() ([]byte, error) {
+	return []byte(swaggestSchemas["Pet"]), nil
+}`)
+	assert.Contains(t, code, "func (Person) JSONSchemaBytes() ([]byte, error) {")
+	// Interface types can't have methods, and are reflected.
+	assert.Contains(t, code, `"Anything": "{}",`)
+	assert.NotContains(t, code, "func (Anything) JSONSchemaBytes")
+}
 
-	// func (AddPetJSONRequestBody) Bind(*http.Request) error {
+func TestVersionPrefix(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: version prefix
+  version: 2.0.0
+paths:
+  /users:
+    get:
+      operationId: findUsers
+      parameters:
+        - name: status
+          in: query
+          schema:
+            type: string
+            enum: [active, banned]
+      responses:
+        '200':
+          description: the users
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/User'
+    post:
+      operationId: createUser
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+      responses:
+        '201':
+          description: the user
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        name:
+          type: string
+`))
+	assert.NoError(t, err)
 
-	// Check for expected request binders:
-	assert.Contains(t, code, "func (CreateLiveCatJSONRequestBody) Bind(*http.Request) error {")
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateServer: true, GenerateClient: true, VersionPrefix: "V2"})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
 
-	// Check for unbindable request types:
-	assert.NotContains(t, code, "func (CreateCatJSONRequestBody) Bind(*http.Request) error {")
+	assert.Contains(t, code, "type V2User struct {")
+	assert.Contains(t, code, "type V2FindUsersParams struct {")
+	assert.Contains(t, code, "type V2FindUsersParamsStatus string")
+	assert.Contains(t, code, `V2FindUsersParamsStatusActive V2FindUsersParamsStatus = "active"`)
+	assert.Contains(t, code, "type V2CreateUserJSONBody struct {")
+	assert.Contains(t, code, "type V2CreateUserJSONRequestBody V2CreateUserJSONBody")
+	assert.Contains(t, code, "func V2FindUsersJSON200Response(body []V2User) *Response {")
+	assert.Contains(t, code, "V2FindUsers(w http.ResponseWriter, r *http.Request, params V2FindUsersParams)\n")
+	// The wire format is unaffected.
+	assert.Regexp(t, "Name +\\*string +`json:\"name,omitempty\"`", code)
+	assert.NotContains(t, code, `json:"V2`)
 }
 
-func TestExamplePetStoreCodeGenerationWithUserTemplates(t *testing.T) {
-	userTemplates := map[string]string{"typedef.tmpl": "//blah"}
+func TestOperationEnumConstants(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: enums
+  version: 1.0.0
+paths:
+  /orders:
+    get:
+      operationId: listOrders
+      parameters:
+        - name: sort
+          in: query
+          schema:
+            type: string
+            enum: [asc, desc, 'say "hi"']
+        - name: X-Level
+          in: header
+          schema:
+            type: integer
+            enum: [1, 2]
+        - name: since
+          in: query
+          schema:
+            type: string
+            format: date
+            enum: [2020-01-01]
+      responses:
+        '204':
+          description: No content
+`))
+	assert.NoError(t, err)
 
-	// Input vars for code generation:
-	packageName := "api"
-	opts := Options{
-		GenerateTypes: true,
-		UserTemplates: userTemplates,
-	}
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
 
-	// Get a spec from the example PetStore definition:
-	swagger, err := examplePetstore.GetSwagger()
+	assert.Regexp(t, `ListOrdersParamsSortAsc +ListOrdersParamsSort = "asc"`, code)
+	assert.Regexp(t, `ListOrdersParamsSortDesc +ListOrdersParamsSort = "desc"`, code)
+	assert.Contains(t, code, `ListOrdersParamsSort = "say \"hi\""`)
+	assert.Regexp(t, `ListOrdersParamsXLevelN1 +ListOrdersParamsXLevel = 1`, code)
+	assert.NotContains(t, code, "Defines values for ListOrdersParamsSince")
+
+	assert.Contains(t, code, "func (v ListOrdersParamsSort) String() string {\n\treturn string(v)\n}")
+	assert.Contains(t, code, "func ListOrdersParamsXLevelValues() []ListOrdersParamsXLevel {\n\treturn []ListOrdersParamsXLevel{\n\t\tListOrdersParamsXLevelN1,\n\t\tListOrdersParamsXLevelN2,\n\t}\n}")
+	assert.NotContains(t, code, "ListOrdersParamsSinceValues")
+}
+
+func TestMultipartFormParams(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: multipart
+  version: 1.0.0
+paths:
+  /upload:
+    post:
+      operationId: uploadFile
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              required: [file]
+              properties:
+                file:
+                  type: string
+                  format: binary
+                attachments:
+                  type: array
+                  items:
+                    type: string
+                    format: binary
+                count:
+                  type: integer
+                labels:
+                  type: array
+                  items:
+                    type: string
+      responses:
+        '204':
+          description: uploaded
+`))
 	assert.NoError(t, err)
 
-	// Run our code generation:
-	code, err := Generate(swagger, packageName, opts)
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true})
 	assert.NoError(t, err)
-	assert.NotEmpty(t, code)
 
-	// Check that we have valid (formattable) code:
 	_, err = format.Source([]byte(code))
 	assert.NoError(t, err)
 
-	// Check that we have a package:
-	assert.Contains(t, code, "package api")
+	assert.Contains(t, code, "type UploadFileFormParams struct {")
+	assert.Regexp(t, "File +\\[\\]\\*multipart.FileHeader +`json:\"file\"`", code)
+	assert.Regexp(t, "Attachments +\\[\\]\\*multipart.FileHeader +`json:\"attachments,omitempty\"`", code)
+	assert.Regexp(t, "Count +\\*int +`json:\"count,omitempty\"`", code)
+	assert.Contains(t, code, "func BindUploadFileFormParams(r *http.Request) (*UploadFileFormParams, error) {")
+	assert.Contains(t, code, "r.ParseMultipartForm(32 << 20)")
+	assert.Contains(t, code, `return nil, fmt.Errorf("form field file is required, but not found")`)
+	assert.Contains(t, code, "params.Labels = append(params.Labels, item)")
+}
 
-	// Check that the built-in template has been overridden
-	assert.Contains(t, code, "//blah")
+func TestURLEncodedFormParams(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: urlencoded
+  version: 1.0.0
+paths:
+  /login:
+    post:
+      operationId: login
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              required: [user]
+              properties:
+                user:
+                  type: string
+                  maxLength: 32
+                remember:
+                  type: boolean
+                scopes:
+                  type: array
+                  items:
+                    type: string
+      responses:
+        '204':
+          description: logged in
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, ValidatorTags: true})
+	assert.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "type LoginFormParams struct {")
+	assert.Regexp(t, "User +string +`json:\"user\" validate:\"max=32\"`", code)
+	assert.Regexp(t, "Remember +\\*bool +`json:\"remember,omitempty\"`", code)
+	assert.Contains(t, code, "// application/x-www-form-urlencoded body of r.")
+	assert.Contains(t, code, "form := r.PostForm")
+	assert.NotContains(t, code, "ParseMultipartForm")
+	assert.Contains(t, code, `return nil, fmt.Errorf("form field user is required, but not found")`)
+	assert.Contains(t, code, "params.Scopes = append(params.Scopes, item)")
 }
 
-func TestExampleOpenAPICodeGeneration(t *testing.T) {
-	// Input vars for code generation:
-	packageName := "testswagger"
+func TestReadWriteOnlyVariants(t *testing.T) {
+	packageName := "api"
 	opts := Options{
 		GenerateTypes: true,
-		EmbedSpec:     true,
 	}
 
-	// Get a spec from the test definition in this file:
-	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testOpenAPIDefinition))
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: readOnly and writeOnly
+  version: 1.0.0
+paths:
+  /users:
+    post:
+      operationId: createUser
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/User'
+      responses:
+        '201':
+          description: created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+    get:
+      operationId: listUsers
+      responses:
+        '200':
+          description: users
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/User'
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, name]
+      properties:
+        id:
+          type: integer
+          readOnly: true
+        name:
+          type: string
+        password:
+          type: string
+          writeOnly: true
+`))
 	assert.NoError(t, err)
 
-	// Run our code generation:
 	code, err := Generate(swagger, packageName, opts)
 	assert.NoError(t, err)
-	assert.NotEmpty(t, code)
 
-	// Check that we have valid (formattable) code:
 	_, err = format.Source([]byte(code))
 	assert.NoError(t, err)
+
+	// The schema itself is unchanged:
+	assert.Regexp(t, `type User struct {\s+ID +int +\S+\s+Name +string +\S+\s+Password +\*string +\S+\s+}`, code)
+
+	// Request bodies leave out readOnly properties:
+	assert.Regexp(t, `type UserRequest struct {\s+Name +string +\S+\s+Password +\*string +\S+\s+}`, code)
+	assert.Contains(t, code, "type CreateUserJSONBody UserRequest")
+
+	// And responses leave out writeOnly properties:
+	assert.Regexp(t, `type UserResponse struct {\s+ID +int +\S+\s+Name +string +\S+\s+}`, code)
+	assert.Contains(t, code, "func CreateUserJSON201Response(body UserResponse) *Response {")
+	assert.Contains(t, code, "func ListUsersJSON200Response(body []UserResponse) *Response {")
 }
 
 const testOpenAPIDefinition = `
@@ -378,6 +2904,9 @@ func (t *MyType) FromValue(value string) error {
     }
     return fmt.Errorf("unknown enum value: %v", value)
 }
+func (t MyType) String() string {
+    return t.value
+}
 `,
 		},
 		{
@@ -420,6 +2949,9 @@ func (t *MyType) FromValue(value int64) error {
     }
     return fmt.Errorf("unknown enum value: %v", value)
 }
+func (t MyType) String() string {
+    return fmt.Sprint(t.value)
+}
 `,
 		},
 	}
@@ -439,3 +2971,16 @@ func (t *MyType) FromValue(value int64) error {
 		})
 	}
 }
+
+// typeCheck fails t if code, a generated file, doesn't compile.
+func typeCheck(t *testing.T, code string) {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "api.gen.go", code, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = conf.Check("api", fset, []*ast.File{file}, nil)
+	assert.NoError(t, err)
+}
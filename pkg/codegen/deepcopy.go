@@ -0,0 +1,320 @@
+package codegen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"text/template"
+)
+
+// DeepCopyDefinition describes the DeepCopyInto and DeepCopy methods of a
+// generated type.
+type DeepCopyDefinition struct {
+	TypeName string
+	Body     string // The statements of DeepCopyInto copying what *out = *in doesn't
+}
+
+// GenerateDeepCopy generates DeepCopyInto and DeepCopy methods for typeDefs,
+// following the conventions of the Kubernetes deepcopy-gen. Maps, slices and
+// pointers are copied recursively, and so are the types of typeDefs, with
+// their own methods. Aliases, and pointer and interface types, which can't
+// have methods, are copied as the type they are declared as. The variant of a
+// union is copied by a type switch over its variants, and free-form values
+// with runtime.DeepCopyJSONValue, while the types of other packages are
+// shared by the copies.
+func GenerateDeepCopy(t *template.Template, typeDefs []TypeDefinition) (string, error) {
+	c := deepCopier{copiers: map[string]bool{}, aliases: map[string]ast.Expr{}, unions: map[string][]DiscriminatorVariant{}}
+
+	var defined []TypeDefinition
+	decls := map[string]string{}
+	exprs := map[string]ast.Expr{}
+	for _, td := range typeDefs {
+		if _, ok := decls[td.TypeName]; ok {
+			continue
+		}
+		decl := td.Schema.TypeDecl()
+		expr, err := parser.ParseExpr(decl)
+		if err != nil {
+			return "", fmt.Errorf("error parsing type %s: %w", td.TypeName, err)
+		}
+		decls[td.TypeName], exprs[td.TypeName] = decl, expr
+		defined = append(defined, td)
+		if d := td.Schema.Discriminator; d != nil {
+			c.unions[d.Interface] = d.Variants
+		}
+	}
+
+	var names []string
+	for _, td := range defined {
+		expr := exprs[td.TypeName]
+		if td.MustAlias() || globalOptions.AliasTypes && td.CanAlias() || !hasMethods(expr, exprs) {
+			c.aliases[td.TypeName] = expr
+			continue
+		}
+		c.copiers[td.TypeName] = true
+		names = append(names, td.TypeName)
+	}
+
+	defs := make([]DeepCopyDefinition, 0, len(names))
+	for _, name := range names {
+		body, err := c.body(decls[name])
+		if err != nil {
+			return "", fmt.Errorf("error generating deep copy of %s: %w", name, err)
+		}
+		defs = append(defs, DeepCopyDefinition{TypeName: name, Body: strings.TrimSuffix(body, "\n")})
+	}
+	return GenerateTemplates([]string{"deepcopy.tmpl"}, t, defs)
+}
+
+// hasMethods returns whether a type declared as expr can have methods, which
+// pointer and interface types can't. exprs holds the declarations of the
+// other generated types.
+func hasMethods(expr ast.Expr, exprs map[string]ast.Expr) bool {
+	for i := 0; i <= len(exprs); i++ {
+		switch e := expr.(type) {
+		case *ast.InterfaceType, *ast.StarExpr:
+			return false
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.Ident:
+			decl, ok := exprs[e.Name]
+			if !ok {
+				return true
+			}
+			expr = decl
+		default:
+			return true
+		}
+	}
+	return true
+}
+
+// deepCopier generates the statements deep copying the values of Go types.
+type deepCopier struct {
+	copiers map[string]bool                   // The types with DeepCopyInto methods
+	aliases map[string]ast.Expr               // The types of aliases, and of pointer and interface types, which have none
+	unions  map[string][]DiscriminatorVariant // The variants of the interfaces of unions
+	src     string                            // The source of the type being copied
+	fset    *token.FileSet
+}
+
+// body returns the statements of the DeepCopyInto method of a type declared
+// as decl.
+func (c *deepCopier) body(decl string) (string, error) {
+	c.src, c.fset = decl, token.NewFileSet()
+	expr, err := parser.ParseExprFrom(c.fset, "", decl, 0)
+	if err != nil {
+		return "", err
+	}
+	for {
+		id, ok := expr.(*ast.Ident)
+		if !ok {
+			break
+		}
+		if alias, ok := c.aliases[id.Name]; ok {
+			expr = alias
+			continue
+		}
+		if c.copiers[id.Name] {
+			// The methods of the underlying type aren't promoted.
+			return fmt.Sprintf("(*%s)(in).DeepCopyInto((*%s)(out))\n", id.Name, id.Name), nil
+		}
+		break
+	}
+	return c.copy(expr, "in", "out"), nil
+}
+
+// copy returns the statements deep copying *in into *out, where in and out
+// are pointers to values of type expr, and *out is already a shallow copy of
+// *in. It returns nothing if the shallow copy is a deep one.
+func (c *deepCopier) copy(expr ast.Expr, in, out string) string {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return c.copy(e.X, in, out)
+
+	case *ast.Ident:
+		if alias, ok := c.aliases[e.Name]; ok {
+			return c.copy(alias, in, out)
+		}
+		if c.copiers[e.Name] {
+			return fmt.Sprintf("%s.DeepCopyInto(%s)\n", receiver(in), out)
+		}
+		if variants, ok := c.unions[e.Name]; ok {
+			return c.copyUnion(variants, in, out)
+		}
+		return ""
+
+	case *ast.InterfaceType:
+		if e.Methods != nil && len(e.Methods.List) != 0 {
+			return ""
+		}
+		return fmt.Sprintf("%s = runtime.DeepCopyJSONValue(%s)\n", deref(out), deref(in))
+
+	case *ast.SelectorExpr:
+		if pkg, ok := e.X.(*ast.Ident); ok && pkg.Name == "json" && e.Sel.Name == "RawMessage" {
+			return c.copySlice(ast.NewIdent("byte"), "json.RawMessage", in, out)
+		}
+		return ""
+
+	case *ast.StarExpr:
+		var b strings.Builder
+		fmt.Fprintf(&b, "if %s != nil {\n", deref(in))
+		fmt.Fprintf(&b, "%s = new(%s)\n", deref(out), c.text(e.X))
+		fmt.Fprintf(&b, "in, out := %s, %s\n", deref(in), deref(out))
+		if body := c.copy(e.X, "in", "out"); c.copies(e.X) {
+			b.WriteString(body)
+		} else {
+			b.WriteString("*out = *in\n")
+			b.WriteString(body)
+		}
+		b.WriteString("}\n")
+		return b.String()
+
+	case *ast.ArrayType:
+		if e.Len != nil {
+			elem := c.copy(e.Elt, "&(*in)[i]", "&(*out)[i]")
+			if elem == "" {
+				return ""
+			}
+			return fmt.Sprintf("{\n%sfor i := range *in {\n%s}\n}\n", rebind(in, out), elem)
+		}
+		return c.copySlice(e.Elt, c.text(e), in, out)
+
+	case *ast.MapType:
+		var b strings.Builder
+		fmt.Fprintf(&b, "if %s != nil {\n", deref(in))
+		b.WriteString(rebind(in, out))
+		fmt.Fprintf(&b, "*out = make(%s, len(*in))\n", c.text(e))
+		b.WriteString("for key, val := range *in {\n")
+		if elem := c.copy(e.Value, "&val", "&copied"); elem == "" {
+			b.WriteString("(*out)[key] = val\n")
+		} else {
+			b.WriteString("copied := val\n")
+			b.WriteString(elem)
+			b.WriteString("(*out)[key] = copied\n")
+		}
+		b.WriteString("}\n}\n")
+		return b.String()
+
+	case *ast.StructType:
+		var b strings.Builder
+		for _, field := range e.Fields.List {
+			names := field.Names
+			if len(names) == 0 {
+				names = []*ast.Ident{embeddedName(field.Type)}
+			}
+			for _, name := range names {
+				if name == nil {
+					continue
+				}
+				b.WriteString(c.copy(field.Type, "&"+receiver(in)+"."+name.Name, "&"+receiver(out)+"."+name.Name))
+			}
+		}
+		return b.String()
+
+	default:
+		return ""
+	}
+}
+
+// copySlice returns the statements deep copying *in into *out, slices of
+// type typ with elements of type elem.
+func (c *deepCopier) copySlice(elem ast.Expr, typ, in, out string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "if %s != nil {\n", deref(in))
+	b.WriteString(rebind(in, out))
+	fmt.Fprintf(&b, "*out = make(%s, len(*in))\n", typ)
+	if body := c.copy(elem, "&(*in)[i]", "&(*out)[i]"); !c.copies(elem) {
+		b.WriteString("copy(*out, *in)\n")
+		if body != "" {
+			fmt.Fprintf(&b, "for i := range *in {\n%s}\n", body)
+		}
+	} else {
+		fmt.Fprintf(&b, "for i := range *in {\n%s}\n", body)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// copyUnion returns the statements deep copying *in into *out, the interface
+// of a union with variants. Variants which a shallow copy deep copies are
+// left out of the type switch.
+func (c *deepCopier) copyUnion(variants []DiscriminatorVariant, in, out string) string {
+	var cases strings.Builder
+	for _, v := range variants {
+		body := c.copy(ast.NewIdent(v.GoType), "&v", "&copied")
+		if body == "" {
+			continue
+		}
+		fmt.Fprintf(&cases, "case %s:\ncopied := v\n%s%s = copied\n", v.GoType, body, deref(out))
+	}
+	if cases.Len() == 0 {
+		return ""
+	}
+	return fmt.Sprintf("switch v := %s.(type) {\n%s}\n", deref(in), cases.String())
+}
+
+// copies returns whether the copy of expr replaces the whole value, as the
+// DeepCopyInto methods and runtime.DeepCopyJSONValue do.
+func (c *deepCopier) copies(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return c.copies(e.X)
+	case *ast.InterfaceType:
+		return e.Methods == nil || len(e.Methods.List) == 0
+	case *ast.Ident:
+		if alias, ok := c.aliases[e.Name]; ok {
+			return c.copies(alias)
+		}
+		return c.copiers[e.Name]
+	default:
+		return false
+	}
+}
+
+// text returns the source of expr.
+func (c *deepCopier) text(expr ast.Expr) string {
+	return c.src[c.fset.Position(expr.Pos()).Offset:c.fset.Position(expr.End()).Offset]
+}
+
+// deref returns the value the pointer p points to.
+func deref(p string) string {
+	if strings.HasPrefix(p, "&") {
+		return p[1:]
+	}
+	return "*" + p
+}
+
+// receiver returns the value the pointer p points to, or p if it is a
+// variable, to call methods and select fields on.
+func receiver(p string) string {
+	if strings.HasPrefix(p, "&") {
+		return p[1:]
+	}
+	return p
+}
+
+// rebind returns the statement shadowing in and out with the pointers in and
+// out, unless they are already named so.
+func rebind(in, out string) string {
+	if in == "in" && out == "out" {
+		return ""
+	}
+	return fmt.Sprintf("in, out := %s, %s\n", in, out)
+}
+
+// embeddedName returns the name of the field embedding the type expr.
+func embeddedName(expr ast.Expr) *ast.Ident {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e
+	case *ast.StarExpr:
+		return embeddedName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,189 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ExampleDefinition describes a generated type which can be populated from
+// the examples in the spec.
+type ExampleDefinition struct {
+	TypeName string // The Go type the example decodes into
+	JSON     string // The example, encoded as JSON
+}
+
+// JSONLiteral returns the example as a Go string literal, preferring a raw
+// string when the example allows for one.
+func (e ExampleDefinition) JSONLiteral() string {
+	if strings.Contains(e.JSON, "`") {
+		return strconv.Quote(e.JSON)
+	}
+	return "`" + e.JSON + "`"
+}
+
+// GenerateExamples generates a test file for packageName, holding a
+// {TypeName}Example function for every schema and request body which has
+// examples in the spec. The functions return the examples decoded into the
+// types generated by Generate, for use as test fixtures.
+func GenerateExamples(swagger *openapi3.T, packageName string, opts Options) (string, error) {
+	t, err := prepare(swagger, opts)
+	if err != nil {
+		return "", err
+	}
+
+	ops, err := OperationDefinitions(swagger)
+	if err != nil {
+		return "", fmt.Errorf("error creating operation definitions: %w", err)
+	}
+
+	examples, err := ExampleDefinitions(swagger, ops, opts.ExcludeSchemas)
+	if err != nil {
+		return "", fmt.Errorf("error creating example definitions: %w", err)
+	}
+
+	modulePath, moduleVersion := buildVersion()
+	context := struct {
+		Examples    []ExampleDefinition
+		PackageName string
+		ModuleName  string
+		Version     string
+	}{
+		Examples:    examples,
+		PackageName: packageName,
+		ModuleName:  modulePath,
+		Version:     moduleVersion,
+	}
+
+	goCode, err := GenerateTemplates([]string{"examples.tmpl"}, t, context)
+	if err != nil {
+		return "", fmt.Errorf("error generating examples: %w", err)
+	}
+	goCode = SanitizeCode(goCode)
+	if usesGenerics() {
+		goCode = useAny(goCode)
+	}
+
+	return formatCode(packageName+"_test.go", goCode, opts)
+}
+
+// ExampleDefinitions collects the examples of the component schemas and the
+// JSON request bodies of ops. Schemas without an example of their own are
+// assembled from the examples of their properties or items, when they have
+// any.
+func ExampleDefinitions(swagger *openapi3.T, ops []OperationDefinition, excludeSchemas []string) ([]ExampleDefinition, error) {
+	var examples []ExampleDefinition
+	add := func(typeName string, example interface{}) error {
+		buf, err := json.Marshal(example)
+		if err != nil {
+			return fmt.Errorf("error marshaling example for %s: %w", typeName, err)
+		}
+		examples = append(examples, ExampleDefinition{TypeName: typeName, JSON: string(buf)})
+		return nil
+	}
+
+	for _, schemaName := range SortedSchemaKeys(swagger.Components.Schemas) {
+		if StringInArray(schemaName, excludeSchemas) {
+			continue
+		}
+		example, ok := schemaExample(swagger.Components.Schemas[schemaName], map[*openapi3.Schema]bool{})
+		if !ok {
+			continue
+		}
+		if err := add(componentTypeName(schemaName), example); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, op := range ops {
+		if op.Spec.RequestBody == nil || op.Spec.RequestBody.Value == nil {
+			continue
+		}
+		for _, body := range op.Bodies {
+			example, ok := mediaTypeExample(op.Spec.RequestBody.Value.Content[body.ContentType])
+			if !ok {
+				continue
+			}
+			if err := add(body.TypeDef(op.OperationID).TypeName, example); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return examples, nil
+}
+
+// mediaTypeExample returns the example of mt, the first of its named examples,
+// or the example of its schema, in that order.
+func mediaTypeExample(mt *openapi3.MediaType) (interface{}, bool) {
+	if mt == nil {
+		return nil, false
+	}
+	if mt.Example != nil {
+		return mt.Example, true
+	}
+
+	names := make([]string, 0, len(mt.Examples))
+	for name := range mt.Examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ex := mt.Examples[name]; ex != nil && ex.Value != nil && ex.Value.Value != nil {
+			return ex.Value.Value, true
+		}
+	}
+
+	return schemaExample(mt.Schema, map[*openapi3.Schema]bool{})
+}
+
+// schemaExample returns the example of sref. Without one, objects are built
+// from the examples of their properties, and arrays hold the example of
+// their items. seen guards against recursive schemas.
+func schemaExample(sref *openapi3.SchemaRef, seen map[*openapi3.Schema]bool) (interface{}, bool) {
+	if sref == nil || sref.Value == nil {
+		return nil, false
+	}
+	schema := sref.Value
+	if schema.Example != nil {
+		return schema.Example, true
+	}
+	if seen[schema] {
+		return nil, false
+	}
+	seen[schema] = true
+	defer delete(seen, schema)
+
+	if len(schema.AllOf) > 0 {
+		merged := map[string]interface{}{}
+		for _, s := range schema.AllOf {
+			example, _ := schemaExample(s, seen)
+			if obj, ok := example.(map[string]interface{}); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		return merged, len(merged) > 0
+	}
+
+	if schema.Type == "array" {
+		example, ok := schemaExample(schema.Items, seen)
+		if !ok {
+			return nil, false
+		}
+		return []interface{}{example}, true
+	}
+
+	obj := map[string]interface{}{}
+	for name, p := range schema.Properties {
+		if example, ok := schemaExample(p, seen); ok {
+			obj[name] = example
+		}
+	}
+	return obj, len(obj) > 0
+}
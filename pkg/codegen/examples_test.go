@@ -0,0 +1,87 @@
+package codegen
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+const testExamplesDefinition = `
+openapi: 3.0.1
+info:
+  title: Examples
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      operationId: addPet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/NewPet'
+            examples:
+              fido:
+                value:
+                  name: Fido
+      responses:
+        '204':
+          description: Created
+components:
+  schemas:
+    NewPet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+          example: Rex
+        tag:
+          type: string
+    Pet:
+      allOf:
+        - $ref: '#/components/schemas/NewPet'
+        - properties:
+            id:
+              type: integer
+              example: 7
+    Pets:
+      type: array
+      items:
+        $ref: '#/components/schemas/Pet'
+    Error:
+      properties:
+        message:
+          type: string
+`
+
+func TestGenerateExamples(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testExamplesDefinition))
+	assert.NoError(t, err)
+
+	code, err := GenerateExamples(swagger, "api", Options{SkipPrune: true})
+	assert.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// Property examples are combined into objects, allOfs and arrays:
+	assert.Contains(t, code, "func NewPetExample() NewPet {")
+	assert.Contains(t, code, "`{\"name\":\"Rex\"}`")
+	assert.Contains(t, code, "`{\"id\":7,\"name\":\"Rex\"}`")
+	assert.Contains(t, code, "`[{\"id\":7,\"name\":\"Rex\"}]`")
+
+	// Request bodies prefer the examples of their media type:
+	assert.Contains(t, code, "func AddPetJSONRequestBodyExample() AddPetJSONRequestBody {")
+	assert.Contains(t, code, "`{\"name\":\"Fido\"}`")
+
+	// Schemas without any examples are left out:
+	assert.NotContains(t, code, "ErrorExample")
+}
+
+func TestExampleDefinitionJSONLiteral(t *testing.T) {
+	assert.Equal(t, "`\"plain\"`", ExampleDefinition{JSON: `"plain"`}.JSONLiteral())
+	assert.Equal(t, "\"\\\"`tick`\\\"\"", ExampleDefinition{JSON: "\"`tick`\""}.JSONLiteral())
+}
@@ -3,6 +3,14 @@ package codegen
 import (
 	"encoding/json"
 	"fmt"
+	"go/token"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
 )
 
 const (
@@ -10,6 +18,13 @@ const (
 	extPropOmitEmpty = "x-omitempty"
 	extPropExtraTags = "x-go-extra-tags"
 	extMiddlewares   = "x-go-middlewares"
+	extMiddleware    = "x-go-middleware"
+	extPropGoName    = "x-go-name"
+	extTimeout       = "x-timeout"
+	extGoValidate    = "x-go-validate"
+	extGoEmbedded    = "x-go-embedded"
+	extRateLimit     = "x-rate-limit" // read by pkg/middleware/ratelimit
+	extWebhooks      = "x-webhooks"
 )
 
 func extTypeName(extPropValue interface{}) (string, error) {
@@ -25,6 +40,37 @@ func extTypeName(extPropValue interface{}) (string, error) {
 	return name, nil
 }
 
+var majorVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// extGoType resolves the value of x-go-type to the type used in the generated
+// code. Fully qualified types such as "github.com/shopspring/decimal.Decimal"
+// are shortened to "decimal.Decimal", and the package they need is returned
+// as well. Any other type name is used verbatim.
+func extGoType(typeName string) (string, *goImport) {
+	qualified := strings.TrimLeft(typeName, "*[]")
+	modifiers := typeName[:len(typeName)-len(qualified)]
+
+	slash := strings.LastIndex(qualified, "/")
+	dot := strings.LastIndex(qualified, ".")
+	if slash < 0 || dot < slash {
+		return typeName, nil
+	}
+	pkgPath, name := qualified[:dot], qualified[dot+1:]
+
+	// Derive the package name the same way the go tool would guess it, eg.
+	// "gopkg.in/yaml.v3" is yaml and "github.com/jackc/pgx/v4" is pgx.
+	pkgName := path.Base(pkgPath)
+	if majorVersionSuffix.MatchString(pkgName) && path.Dir(pkgPath) != "." {
+		pkgName = path.Base(path.Dir(pkgPath))
+	}
+	if i := strings.IndexByte(pkgName, '.'); i > 0 {
+		pkgName = pkgName[:i]
+	}
+	pkgName = SanitizeGoIdentity(strings.TrimPrefix(pkgName, "go-"))
+
+	return modifiers + pkgName + "." + name, &goImport{Path: pkgPath}
+}
+
 func extParseOmitEmpty(extPropValue interface{}) (bool, error) {
 	raw, ok := extPropValue.(json.RawMessage)
 	if !ok {
@@ -39,6 +85,20 @@ func extParseOmitEmpty(extPropValue interface{}) (bool, error) {
 	return omitEmpty, nil
 }
 
+func extParseEmbedded(extPropValue interface{}) (bool, error) {
+	raw, ok := extPropValue.(json.RawMessage)
+	if !ok {
+		return false, fmt.Errorf("failed to convert type: %T", extPropValue)
+	}
+
+	var embedded bool
+	if err := json.Unmarshal(raw, &embedded); err != nil {
+		return false, fmt.Errorf("failed to unmarshal json: %w", err)
+	}
+
+	return embedded, nil
+}
+
 func extExtraTags(extPropValue interface{}) (map[string]string, error) {
 	raw, ok := extPropValue.(json.RawMessage)
 	if !ok {
@@ -62,3 +122,258 @@ func extParseMiddlewares(extPropValue interface{}) ([]string, error) {
 	}
 	return middlewares, nil
 }
+
+// extParseMiddlewareFuncs parses the names of the middleware functions of
+// x-go-middleware, which have to be Go identifiers of the generated package.
+func extParseMiddlewareFuncs(extPropValue interface{}) ([]string, error) {
+	funcs, err := extParseMiddlewares(extPropValue)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range funcs {
+		if !token.IsIdentifier(f) {
+			return nil, fmt.Errorf("%q is not a Go identifier", f)
+		}
+	}
+	return funcs, nil
+}
+
+// extParseTimeout parses the duration of x-timeout, such as "5s", which has
+// to be positive.
+func extParseTimeout(extPropValue interface{}) (time.Duration, error) {
+	raw, ok := extPropValue.(json.RawMessage)
+	if !ok {
+		return 0, fmt.Errorf("failed to convert type: %T", extPropValue)
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal json: %w", err)
+	}
+	timeout, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("%s is not positive", s)
+	}
+	return timeout, nil
+}
+
+// extParseGoValidate parses the function of x-go-validate, which is resolved
+// like x-go-type: "github.com/acme/cards.ValidateLuhn" is called as
+// cards.ValidateLuhn, and its package is returned to be imported.
+func extParseGoValidate(extPropValue interface{}) (string, *goImport, error) {
+	name, err := extTypeName(extPropValue)
+	if err != nil {
+		return "", nil, err
+	}
+	fn, imp := extGoType(name)
+	parts := strings.Split(fn, ".")
+	for _, part := range parts {
+		if len(parts) > 2 || !token.IsIdentifier(part) {
+			return "", nil, fmt.Errorf("%q is not a function", name)
+		}
+	}
+	return fn, imp, nil
+}
+
+// knownExtensions lists the extensions understood by the generator, anything
+// else is reported by checkExtensions.
+var knownExtensions = map[string]bool{
+	extPropGoType:    true,
+	extPropOmitEmpty: true,
+	extPropExtraTags: true,
+	extMiddlewares:   true,
+	extMiddleware:    true,
+	extPropGoName:    true,
+	extTimeout:       true,
+	extGoValidate:    true,
+	extGoEmbedded:    true,
+	extRateLimit:     true,
+	extWebhooks:      true,
+}
+
+// checkExtensions returns an error listing every unknown extension in
+// swagger, along with the location it appeared at.
+func checkExtensions(swagger *openapi3.T) error {
+	c := extensionChecker{seen: map[*openapi3.Schema]bool{}}
+	c.walk(swagger)
+	if len(c.unknown) == 0 {
+		return nil
+	}
+	return fmt.Errorf("unknown extensions:\n%s", strings.Join(c.unknown, "\n"))
+}
+
+// extensionChecker collects the unknown extensions found while walking a spec.
+type extensionChecker struct {
+	unknown []string
+	seen    map[*openapi3.Schema]bool
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func (c *extensionChecker) check(path string, props openapi3.ExtensionProps) {
+	names := make([]string, 0, len(props.Extensions))
+	for name := range props.Extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !knownExtensions[name] {
+			c.unknown = append(c.unknown, fmt.Sprintf("%s at %s", name, path))
+		}
+	}
+}
+
+func (c *extensionChecker) walk(swagger *openapi3.T) {
+	c.check("#", swagger.ExtensionProps)
+	if swagger.Info != nil {
+		c.check("#/info", swagger.Info.ExtensionProps)
+	}
+
+	for _, pathName := range SortedPathsKeys(swagger.Paths) {
+		pathItem := swagger.Paths[pathName]
+		path := "#/paths/" + jsonPointerEscaper.Replace(pathName)
+		c.check(path, pathItem.ExtensionProps)
+		for i, param := range pathItem.Parameters {
+			c.parameter(fmt.Sprintf("%s/parameters/%d", path, i), param)
+		}
+
+		ops := pathItem.Operations()
+		for _, method := range SortedOperationsKeys(ops) {
+			c.operation(path+"/"+strings.ToLower(method), ops[method])
+		}
+	}
+
+	webhooks, _ := loadWebhooks(swagger)
+	webhookNames := make([]string, 0, len(webhooks))
+	for name := range webhooks {
+		webhookNames = append(webhookNames, name)
+	}
+	sort.Strings(webhookNames)
+	for _, name := range webhookNames {
+		path := "#/x-webhooks/" + jsonPointerEscaper.Replace(name)
+		ops := webhooks[name].Operations()
+		for _, method := range SortedOperationsKeys(ops) {
+			c.operation(path+"/"+strings.ToLower(method), ops[method])
+		}
+	}
+
+	components := swagger.Components
+	c.check("#/components", components.ExtensionProps)
+	for _, name := range SortedSchemaKeys(components.Schemas) {
+		c.schema("#/components/schemas/"+name, components.Schemas[name])
+	}
+	for _, name := range SortedParameterKeys(components.Parameters) {
+		c.parameter("#/components/parameters/"+name, components.Parameters[name])
+	}
+	for _, name := range SortedRequestBodyKeys(components.RequestBodies) {
+		c.requestBody("#/components/requestBodies/"+name, components.RequestBodies[name])
+	}
+	for _, name := range SortedResponsesKeys(components.Responses) {
+		c.response("#/components/responses/"+name, components.Responses[name])
+	}
+	c.headers("#/components/headers", components.Headers)
+
+	schemeNames := make([]string, 0, len(components.SecuritySchemes))
+	for name := range components.SecuritySchemes {
+		schemeNames = append(schemeNames, name)
+	}
+	sort.Strings(schemeNames)
+	for _, name := range schemeNames {
+		if scheme := components.SecuritySchemes[name]; scheme.Ref == "" && scheme.Value != nil {
+			c.check("#/components/securitySchemes/"+name, scheme.Value.ExtensionProps)
+		}
+	}
+}
+
+func (c *extensionChecker) operation(path string, op *openapi3.Operation) {
+	c.check(path, op.ExtensionProps)
+	for i, param := range op.Parameters {
+		c.parameter(fmt.Sprintf("%s/parameters/%d", path, i), param)
+	}
+	c.requestBody(path+"/requestBody", op.RequestBody)
+	for _, code := range SortedResponsesKeys(op.Responses) {
+		c.response(path+"/responses/"+code, op.Responses[code])
+	}
+}
+
+func (c *extensionChecker) parameter(path string, ref *openapi3.ParameterRef) {
+	// References are checked where they're defined.
+	if ref == nil || ref.Ref != "" || ref.Value == nil {
+		return
+	}
+	c.check(path, ref.Value.ExtensionProps)
+	c.schema(path+"/schema", ref.Value.Schema)
+	c.content(path+"/content", ref.Value.Content)
+}
+
+func (c *extensionChecker) requestBody(path string, ref *openapi3.RequestBodyRef) {
+	if ref == nil || ref.Ref != "" || ref.Value == nil {
+		return
+	}
+	c.check(path, ref.Value.ExtensionProps)
+	c.content(path+"/content", ref.Value.Content)
+}
+
+func (c *extensionChecker) response(path string, ref *openapi3.ResponseRef) {
+	if ref == nil || ref.Ref != "" || ref.Value == nil {
+		return
+	}
+	c.check(path, ref.Value.ExtensionProps)
+	c.headers(path+"/headers", ref.Value.Headers)
+	c.content(path+"/content", ref.Value.Content)
+}
+
+func (c *extensionChecker) headers(path string, headers openapi3.Headers) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		ref := headers[name]
+		if ref == nil || ref.Ref != "" || ref.Value == nil {
+			continue
+		}
+		headerPath := path + "/" + jsonPointerEscaper.Replace(name)
+		c.check(headerPath, ref.Value.ExtensionProps)
+		c.schema(headerPath+"/schema", ref.Value.Schema)
+	}
+}
+
+func (c *extensionChecker) content(path string, content openapi3.Content) {
+	for _, contentType := range SortedContentKeys(content) {
+		mt := content[contentType]
+		mtPath := path + "/" + jsonPointerEscaper.Replace(contentType)
+		c.check(mtPath, mt.ExtensionProps)
+		c.schema(mtPath+"/schema", mt.Schema)
+	}
+}
+
+func (c *extensionChecker) schema(path string, ref *openapi3.SchemaRef) {
+	if ref == nil || ref.Ref != "" || ref.Value == nil || c.seen[ref.Value] {
+		return
+	}
+	schema := ref.Value
+	c.seen[schema] = true
+
+	c.check(path, schema.ExtensionProps)
+	for _, name := range SortedSchemaKeys(schema.Properties) {
+		c.schema(path+"/properties/"+jsonPointerEscaper.Replace(name), schema.Properties[name])
+	}
+	c.schema(path+"/items", schema.Items)
+	c.schema(path+"/additionalProperties", schema.AdditionalProperties)
+	c.schema(path+"/not", schema.Not)
+	for i, s := range schema.AllOf {
+		c.schema(fmt.Sprintf("%s/allOf/%d", path, i), s)
+	}
+	for i, s := range schema.AnyOf {
+		c.schema(fmt.Sprintf("%s/anyOf/%d", path, i), s)
+	}
+	for i, s := range schema.OneOf {
+		c.schema(fmt.Sprintf("%s/oneOf/%d", path, i), s)
+	}
+}
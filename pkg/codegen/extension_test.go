@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -48,3 +49,106 @@ func Test_extTypeName(t *testing.T) {
 		})
 	}
 }
+
+func Test_extGoType(t *testing.T) {
+	tests := []struct {
+		name       string
+		typeName   string
+		want       string
+		wantImport *goImport
+	}{
+		{
+			name:     "builtin",
+			typeName: "uint64",
+			want:     "uint64",
+		},
+		{
+			name:     "unqualified",
+			typeName: "decimal.Decimal",
+			want:     "decimal.Decimal",
+		},
+		{
+			name:       "qualified",
+			typeName:   "github.com/shopspring/decimal.Decimal",
+			want:       "decimal.Decimal",
+			wantImport: &goImport{Path: "github.com/shopspring/decimal"},
+		},
+		{
+			name:       "qualified pointer",
+			typeName:   "*github.com/shopspring/decimal.Decimal",
+			want:       "*decimal.Decimal",
+			wantImport: &goImport{Path: "github.com/shopspring/decimal"},
+		},
+		{
+			name:       "major version",
+			typeName:   "github.com/cockroachdb/apd/v3.Decimal",
+			want:       "apd.Decimal",
+			wantImport: &goImport{Path: "github.com/cockroachdb/apd/v3"},
+		},
+		{
+			name:       "gopkg.in",
+			typeName:   "[]gopkg.in/yaml.v3.Node",
+			want:       "[]yaml.Node",
+			wantImport: &goImport{Path: "gopkg.in/yaml.v3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotImport := extGoType(tt.typeName)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantImport, gotImport)
+		})
+	}
+}
+
+func TestCheckExtensions(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: Extensions
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    x-go-middlewares: [auth]
+    get:
+      operationId: getPet
+      x-typo: true
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+            x-go-tpye: uuid.UUID
+      responses:
+        '200':
+          description: A pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      properties:
+        name:
+          type: string
+          x-omitempty: false
+        tag:
+          type: string
+          x-omitmepty: false
+`))
+	assert.NoError(t, err)
+
+	err = checkExtensions(swagger)
+	assert.EqualError(t, err, `unknown extensions:
+x-typo at #/paths/~1pets~1{id}/get
+x-go-tpye at #/paths/~1pets~1{id}/get/parameters/0/schema
+x-omitmepty at #/components/schemas/Pet/properties/tag`)
+
+	// Known extensions alone pass the check:
+	delete(swagger.Paths["/pets/{id}"].Get.Extensions, "x-typo")
+	delete(swagger.Paths["/pets/{id}"].Get.Parameters[0].Value.Schema.Value.Extensions, "x-go-tpye")
+	delete(swagger.Components.Schemas["Pet"].Value.Properties["tag"].Value.Extensions, "x-omitmepty")
+	assert.NoError(t, checkExtensions(swagger))
+}
@@ -43,4 +43,96 @@ func TestFilterOperationsByTag(t *testing.T) {
 		assert.NotEmpty(t, code)
 		assert.NotContains(t, code, `"/cat"`)
 	})
+
+	t.Run("prune excluded types", func(t *testing.T) {
+		opts := Options{
+			GenerateTypes:  true,
+			GenerateServer: true,
+			ExcludeTags:    []string{"internal"},
+		}
+
+		swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: tags
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      tags: [users]
+      responses:
+        '200':
+          description: users
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/UserList'
+    delete:
+      operationId: purgeUsers
+      tags: [internal]
+      parameters:
+        - $ref: '#/components/parameters/Reason'
+      responses:
+        '200':
+          description: report
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/PurgeReport'
+  /internal/stats:
+    get:
+      operationId: getStats
+      tags: [internal]
+      responses:
+        '200':
+          description: stats
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Stats'
+components:
+  parameters:
+    Reason:
+      name: reason
+      in: query
+      schema:
+        type: string
+  schemas:
+    User:
+      type: object
+      properties:
+        name:
+          type: string
+    UserList:
+      type: array
+      items:
+        $ref: '#/components/schemas/User'
+    PurgeReport:
+      type: object
+      properties:
+        users:
+          $ref: '#/components/schemas/UserList'
+    Stats:
+      type: object
+      properties:
+        count:
+          type: integer
+`))
+		assert.NoError(t, err)
+
+		code, err := Generate(swagger, packageName, opts)
+		assert.NoError(t, err)
+		assert.Contains(t, code, "ListUsers(w http.ResponseWriter, r *http.Request)\n")
+		assert.Contains(t, code, `r.Get("/users", wrapper.ListUsers)`)
+		assert.NotContains(t, code, "PurgeUsers")
+		assert.NotContains(t, code, "GetStats")
+
+		// Types used by included operations are kept, the others are pruned.
+		assert.Contains(t, code, "type UserList []User")
+		assert.Contains(t, code, "type User struct")
+		assert.NotContains(t, code, "PurgeReport")
+		assert.NotContains(t, code, "Stats")
+		assert.NotContains(t, code, "Reason")
+	})
 }
@@ -0,0 +1,123 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/imports"
+)
+
+// formatCode formats the Go code of the file named name as set by
+// opts.Format, goimports by default, or not at all with opts.SkipFmt.
+func formatCode(name, code string, opts Options) (string, error) {
+	formatter := opts.Format
+	if opts.SkipFmt {
+		formatter = "none"
+	}
+
+	switch formatter {
+	case "", "goimports":
+		out, err := imports.Process(name, []byte(code), nil)
+		if err != nil {
+			return "", fmt.Errorf("error formatting Go code: %w", err)
+		}
+		return string(out), nil
+	case "gofmt":
+		out, err := gofmt(name, code)
+		if err != nil {
+			return "", fmt.Errorf("error formatting Go code: %w", err)
+		}
+		return out, nil
+	case "none":
+		return code, nil
+	default:
+		return "", fmt.Errorf("unknown format %q", formatter)
+	}
+}
+
+// gofmt formats code like gofmt, once the imports it doesn't use are
+// removed. The templates import every package the generated code may use,
+// so, unlike with goimports, no import is ever added.
+func gofmt(name, code string) (string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, name, code, parser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+
+	// Deleting an import removes it from file.Imports.
+	for _, spec := range append([]*ast.ImportSpec(nil), file.Imports...) {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return "", err
+		}
+		importName := assumedPackageName(path)
+		if spec.Name != nil {
+			importName = spec.Name.Name
+		}
+		if importName == "_" || importName == "." || usesPackage(file, importName) {
+			continue
+		}
+		if spec.Name != nil {
+			astutil.DeleteNamedImport(fset, file, spec.Name.Name, path)
+		} else {
+			astutil.DeleteImport(fset, file, path)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return "", err
+	}
+	// Format again, to remove the blank lines left by the deleted imports.
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// usesPackage returns whether the code of file refers to the package
+// imported as name, by a selector of one of its unresolved identifiers.
+func usesPackage(file *ast.File, name string) bool {
+	used := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Name == name && id.Obj == nil {
+				used = true
+			}
+		}
+		return !used
+	})
+	return used
+}
+
+// majorVersion matches the major version suffixes of import paths, like v5
+// in github.com/go-chi/chi/v5, and .v3 in gopkg.in/yaml.v3.
+var majorVersion = regexp.MustCompile(`^v[0-9]+$|\.v[0-9]+$`)
+
+// assumedPackageName returns the name of the package imported at path, by the
+// conventions goimports assumes when it doesn't load the package.
+func assumedPackageName(path string) string {
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && majorVersion.MatchString(name) && !strings.Contains(name, ".") {
+		name = parts[len(parts)-2]
+	}
+	name = majorVersion.ReplaceAllString(name, "")
+	name = strings.TrimPrefix(name, "go-")
+	return strings.Map(func(r rune) rune {
+		if r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+			return r
+		}
+		return -1
+	}, name)
+}
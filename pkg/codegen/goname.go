@@ -0,0 +1,86 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// goNames holds the Go type names of the component schemas which override
+// them with x-go-name, keyed by schema name.
+var goNames = map[string]string{}
+
+// extGoName returns the x-go-name of props, if it has one. The name must be
+// an exported Go identifier, since it names types and fields.
+func extGoName(props openapi3.ExtensionProps) (string, error) {
+	ext, ok := props.Extensions[extPropGoName]
+	if !ok {
+		return "", nil
+	}
+	raw, ok := ext.(json.RawMessage)
+	if !ok {
+		return "", fmt.Errorf("failed to convert type: %T", ext)
+	}
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		return "", fmt.Errorf("invalid value for %q: %w", extPropGoName, err)
+	}
+	if !token.IsIdentifier(name) || !token.IsExported(name) {
+		return "", fmt.Errorf("invalid value for %q: %q is not an exported Go identifier", extPropGoName, name)
+	}
+	return name, nil
+}
+
+// findGoNames returns the Go type names set with x-go-name on the component
+// schemas of swagger. Names which collide with the type of another schema are
+// an error.
+func findGoNames(swagger *openapi3.T) (map[string]string, error) {
+	names := map[string]string{}
+	owners := map[string]string{}
+	for _, schemaName := range SortedSchemaKeys(swagger.Components.Schemas) {
+		owners[SchemaNameToTypeName(schemaName)] = schemaName
+	}
+
+	for _, schemaName := range SortedSchemaKeys(swagger.Components.Schemas) {
+		sref := swagger.Components.Schemas[schemaName]
+		if sref.Ref != "" || sref.Value == nil {
+			continue
+		}
+		name, err := extGoName(sref.Value.ExtensionProps)
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %w", schemaName, err)
+		}
+		if name == "" {
+			continue
+		}
+		if owner, ok := owners[name]; ok && owner != schemaName {
+			return nil, fmt.Errorf("schema %s: %s %q collides with schema %s", schemaName, extPropGoName, name, owner)
+		}
+		owners[name] = schemaName
+		names[schemaName] = name
+	}
+	return names, nil
+}
+
+// componentTypeName returns the Go type name of the component schema
+// schemaName, which is its x-go-name when it has one.
+func componentTypeName(schemaName string) string {
+	if name, ok := goNames[schemaName]; ok {
+		return versioned(name)
+	}
+	return versioned(SchemaNameToTypeName(schemaName))
+}
+
+// versioned returns the generated Go name typeName with the VersionPrefix of
+// the options prepended.
+func versioned(typeName string) string {
+	return globalOptions.VersionPrefix + typeName
+}
+
+// unversioned returns typeName without the VersionPrefix of the options.
+func unversioned(typeName string) string {
+	return strings.TrimPrefix(typeName, globalOptions.VersionPrefix)
+}
@@ -0,0 +1,72 @@
+package codegen
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// goMinorVersion returns the minor version of the Go version v, like 1.21
+// or go1.21.3, or 0 if v is empty.
+func goMinorVersion(v string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+	parts := strings.Split(strings.TrimPrefix(v, "go"), ".")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] != "1" {
+		return 0, fmt.Errorf("invalid Go version %q, expected one like 1.21", v)
+	}
+	for _, part := range parts[1:] {
+		if _, err := strconv.ParseUint(part, 10, 32); err != nil {
+			return 0, fmt.Errorf("invalid Go version %q, expected one like 1.21", v)
+		}
+	}
+	minor, _ := strconv.Atoi(parts[1])
+	return minor, nil
+}
+
+// usesGenerics returns whether the generated code targets Go 1.18 or later,
+// and so may use generics and any. An invalid GoVersion, which Generate
+// rejects, doesn't.
+func usesGenerics() bool {
+	minor, err := goMinorVersion(globalOptions.GoVersion)
+	return err == nil && minor >= 18
+}
+
+// useAny replaces the empty interfaces of the Go code code with any, leaving
+// the rest of it, including comments and strings, untouched.
+func useAny(code string) string {
+	src := []byte(code)
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, src, nil, 0)
+
+	var b strings.Builder
+	last := 0
+	ifacePos := -1
+	expect := token.INTERFACE
+	for {
+		pos, tok, _ := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		switch {
+		case tok == token.INTERFACE:
+			ifacePos, expect = file.Offset(pos), token.LBRACE
+		case tok == expect && tok == token.LBRACE:
+			expect = token.RBRACE
+		case tok == expect && tok == token.RBRACE:
+			b.WriteString(code[last:ifacePos])
+			b.WriteString("any")
+			last = file.Offset(pos) + 1
+			expect = token.INTERFACE
+		default:
+			expect = token.INTERFACE
+		}
+	}
+	b.WriteString(code[last:])
+	return b.String()
+}
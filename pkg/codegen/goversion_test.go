@@ -0,0 +1,101 @@
+package codegen
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoMinorVersion(t *testing.T) {
+	for v, want := range map[string]int{"": 0, "1.17": 17, "1.21": 21, "go1.21.3": 21} {
+		minor, err := goMinorVersion(v)
+		assert.NoError(t, err, v)
+		assert.Equal(t, want, minor, v)
+	}
+	for _, v := range []string{"2.0", "1", "1.x", "1.21.3.4", "latest"} {
+		_, err := goMinorVersion(v)
+		assert.Error(t, err, v)
+	}
+}
+
+func TestUseAny(t *testing.T) {
+	code := `package api
+
+// Value holds an interface{}.
+type Value struct {
+	Any  interface{}
+	Map  map[string]interface {
+	}
+	Name interface{ Name() string }
+}
+
+const doc = "interface{}"
+`
+	assert.Equal(t, `package api
+
+// Value holds an interface{}.
+type Value struct {
+	Any  any
+	Map  map[string]any
+	Name interface{ Name() string }
+}
+
+const doc = "interface{}"
+`, useAny(code))
+}
+
+func TestGoVersion(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: go version
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        '200':
+          description: the pets
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        tag:
+          type: string
+        extra: {}
+`))
+	assert.NoError(t, err)
+
+	opts := Options{GenerateTypes: true, GenerateServer: true}
+	code, err := Generate(swagger, "api", opts)
+	assert.NoError(t, err)
+	assert.Contains(t, code, "body        interface{}")
+	assert.NotContains(t, code, "func Ptr")
+
+	opts.GoVersion = "1.21"
+	code, err = Generate(swagger, "api", opts)
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+	assert.NotContains(t, code, "interface{}")
+	assert.Contains(t, code, "body        any")
+	assert.Regexp(t, "Extra +\\*any +`json:\"extra,omitempty\"`", code)
+	assert.Contains(t, code, "func Ptr[T any](v T) *T {")
+
+	opts.GoVersion = "1.16"
+	code, err = Generate(swagger, "api", opts)
+	assert.NoError(t, err)
+	assert.Contains(t, code, "interface{}")
+
+	opts.GoVersion = "2"
+	_, err = Generate(swagger, "api", opts)
+	assert.ErrorContains(t, err, `invalid Go version "2"`)
+}
@@ -24,6 +24,23 @@ import (
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
+// GenerateSpecHandlers generates the handlers serving a spec, registered at
+// opts.SpecPath, and a Swagger UI page for it. With opts.EmbedSpec, the
+// embedded spec has a handler as well.
+func GenerateSpecHandlers(t *template.Template, opts Options) (string, error) {
+	specPath := opts.SpecPath
+	if specPath == "" {
+		specPath = "/openapi.json"
+	}
+	return GenerateTemplates([]string{"spec-handler.tmpl"}, t, struct {
+		SpecPath  string
+		EmbedSpec bool
+	}{
+		SpecPath:  specPath,
+		EmbedSpec: opts.EmbedSpec,
+	})
+}
+
 // GenerateInlinedSpec generates a gzipped, base64 encoded JSON representation
 // of the swagger definition, which is embedded inside the generated code.
 func GenerateInlinedSpec(t *template.Template, importMapping importMap, swagger *openapi3.T) (string, error) {
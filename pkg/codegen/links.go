@@ -0,0 +1,234 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// LinkDefinition describes a link of a response of an operation, generated
+// as a function returning the parameters of the linked operation, evaluated
+// against the response of the generated client.
+type LinkDefinition struct {
+	Name         string // The name of the link in the response
+	ResponseName string // The status code of the response, like 201 or 2XX, or default
+	Description  string
+	Source       *OperationDefinition // The operation of the response
+	Target       *OperationDefinition // The linked operation
+	Params       []LinkParamDefinition
+}
+
+// FuncName returns the name of the generated function, like
+// GetUserLinkFromCreateUserResponse.
+func (l LinkDefinition) FuncName() string {
+	return l.Target.OperationID + "LinkFrom" + l.Source.OperationID + "Response"
+}
+
+// DescriptionAsComment returns the description of l as a paragraph of the
+// comment of its function, or nothing if it has none.
+func (l LinkDefinition) DescriptionAsComment() string {
+	if strings.TrimSpace(l.Description) == "" {
+		return ""
+	}
+	return "//\n" + StringToGoComment(l.Description)
+}
+
+// StatusCondition returns the condition on the status code of resp matching
+// the response of l, or nothing for a default response.
+func (l LinkDefinition) StatusCondition() string {
+	switch strings.ToUpper(l.ResponseName) {
+	case "DEFAULT":
+		return ""
+	case "1XX", "2XX", "3XX", "4XX", "5XX":
+		return fmt.Sprintf("resp.StatusCode()/100 == %s", l.ResponseName[:1])
+	default:
+		return "resp.StatusCode() == " + l.ResponseName
+	}
+}
+
+// LinkParamDefinition describes a parameter of the linked operation of a
+// link, set to the value of a runtime expression, or a constant.
+type LinkParamDefinition struct {
+	Param      ParameterDefinition // The parameter of the linked operation
+	Expression string              // The runtime expression of the value, like $response.body#/id
+	Value      string              // The value as JSON, if it isn't a runtime expression
+}
+
+// InPath returns whether the parameter is a path parameter of the linked
+// operation, set in its PathParams rather than its Params.
+func (p LinkParamDefinition) InPath() bool {
+	return p.Param.In == "path"
+}
+
+// linkExpressions are the prefixes of the runtime expressions which
+// runtime.LinkValue can evaluate against a response.
+var linkExpressions = []string{
+	"$request.path.",
+	"$request.query.",
+	"$request.header.",
+	"$response.header.",
+	"$response.body#",
+}
+
+// DescribeLinks returns the links of the responses of ops to other
+// operations of ops, sorted by operation, response and name. Links to
+// operations filtered out of ops are left out.
+func DescribeLinks(ops []OperationDefinition) ([]LinkDefinition, error) {
+	var links []LinkDefinition
+	funcNames := map[string]string{}
+	for i := range ops {
+		source := &ops[i]
+		for _, responseName := range SortedResponsesKeys(source.Spec.Responses) {
+			response := source.Spec.Responses[responseName].Value
+			if response == nil {
+				continue
+			}
+			names := make([]string, 0, len(response.Links))
+			for name := range response.Links {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				ref := response.Links[name]
+				if ref == nil || ref.Value == nil {
+					continue
+				}
+				where := fmt.Sprintf("link %s of the %s response of %s", name, responseName, source.OperationID)
+
+				target, err := linkTarget(ops, ref.Value.OperationID, ref.Value.OperationRef)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", where, err)
+				}
+				if target == nil {
+					continue
+				}
+				link := LinkDefinition{
+					Name:         name,
+					ResponseName: responseName,
+					Description:  ref.Value.Description,
+					Source:       source,
+					Target:       target,
+				}
+				if other, ok := funcNames[link.FuncName()]; ok {
+					return nil, fmt.Errorf("%s: %s already links to %s", where, other, target.OperationID)
+				}
+				funcNames[link.FuncName()] = where
+
+				paramNames := make([]string, 0, len(ref.Value.Parameters))
+				for paramName := range ref.Value.Parameters {
+					paramNames = append(paramNames, paramName)
+				}
+				sort.Strings(paramNames)
+				for _, paramName := range paramNames {
+					param, err := describeLinkParam(target, paramName, ref.Value.Parameters[paramName])
+					if err != nil {
+						return nil, fmt.Errorf("%s: parameter %s: %w", where, paramName, err)
+					}
+					link.Params = append(link.Params, param)
+				}
+				links = append(links, link)
+			}
+		}
+	}
+	return links, nil
+}
+
+// linkTarget returns the operation of ops a link refers to by operationID
+// or operationRef, or nil if it was filtered out. Only local operationRefs,
+// like #/paths/~1users~1{id}/get, are supported.
+func linkTarget(ops []OperationDefinition, operationID, operationRef string) (*OperationDefinition, error) {
+	if operationID != "" {
+		for i := range ops {
+			if ops[i].OperationID == versioned(ToCamelCase(operationID)) {
+				return &ops[i], nil
+			}
+		}
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(operationRef, "#/paths/") {
+		return nil, fmt.Errorf("unsupported operationRef %s, only local ones are", operationRef)
+	}
+	ref := strings.TrimPrefix(operationRef, "#/paths/")
+	i := strings.LastIndexByte(ref, '/')
+	if i == -1 {
+		return nil, fmt.Errorf("invalid operationRef %s", operationRef)
+	}
+	path := strings.NewReplacer("~1", "/", "~0", "~").Replace(ref[:i])
+	for j := range ops {
+		if ops[j].Path == path && strings.EqualFold(ops[j].Method, ref[i+1:]) {
+			return &ops[j], nil
+		}
+	}
+	return nil, nil
+}
+
+// describeLinkParam describes the parameter name of target, which may be
+// qualified by its location like path.id, set by a link to value.
+func describeLinkParam(target *OperationDefinition, name string, value interface{}) (LinkParamDefinition, error) {
+	var in string
+	if i := strings.IndexByte(name, '.'); i != -1 {
+		switch name[:i] {
+		case "path", "query", "header", "cookie":
+			in, name = name[:i], name[i+1:]
+		}
+	}
+	var param *ParameterDefinition
+	for _, p := range target.AllParams() {
+		if p.ParamName == name && (in == "" || p.In == in) {
+			p := p
+			param = &p
+			break
+		}
+	}
+	if param == nil {
+		return LinkParamDefinition{}, fmt.Errorf("no such parameter in %s", target.OperationID)
+	}
+
+	if expr, ok := value.(string); ok && strings.HasPrefix(expr, "$") {
+		if !isLinkExpression(expr) {
+			return LinkParamDefinition{}, fmt.Errorf("unsupported runtime expression %s", expr)
+		}
+		return LinkParamDefinition{Param: *param, Expression: expr}, nil
+	}
+	if s, ok := value.(string); ok && strings.Contains(s, "{$") {
+		return LinkParamDefinition{}, fmt.Errorf("unsupported embedded runtime expression in %q", s)
+	}
+	constant, err := json.Marshal(value)
+	if err != nil {
+		return LinkParamDefinition{}, err
+	}
+	return LinkParamDefinition{Param: *param, Value: string(constant)}, nil
+}
+
+// isLinkExpression returns whether expr is a runtime expression supported by
+// runtime.LinkValue.
+func isLinkExpression(expr string) bool {
+	switch expr {
+	case "$url", "$method", "$statusCode", "$response.body":
+		return true
+	}
+	for _, prefix := range linkExpressions {
+		if strings.HasPrefix(expr, prefix) && len(expr) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateClientLinks generates a function for each link of the responses
+// of ops, returning the parameters of the linked operation for a response of
+// the generated client.
+func GenerateClientLinks(t *template.Template, ops []OperationDefinition) (string, error) {
+	links, err := DescribeLinks(ops)
+	if err != nil {
+		return "", err
+	}
+	if len(links) == 0 {
+		return "", nil
+	}
+	return GenerateTemplates([]string{"links.tmpl"}, t, links)
+}
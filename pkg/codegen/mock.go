@@ -0,0 +1,65 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// GenerateMock generates a test file for packageName, holding a MockServer
+// which implements the ServerInterface generated by Generate with a function
+// per operation. Operations without a function respond with 501 Not
+// Implemented, so that the mock can be served with httptest.NewServer.
+func GenerateMock(swagger *openapi3.T, packageName string, opts Options) (string, error) {
+	t, err := prepare(swagger, opts)
+	if err != nil {
+		return "", err
+	}
+
+	ops, err := OperationDefinitions(swagger)
+	if err != nil {
+		return "", fmt.Errorf("error creating operation definitions: %w", err)
+	}
+
+	return generateTestFile("mock.tmpl", "mock server", t, ops, packageName, opts)
+}
+
+// GenerateFixture generates a test file for packageName, holding a
+// TestFixture which serves the MockServer generated by GenerateMock with
+// httptest.NewServer, for end to end tests. It doesn't depend on the spec.
+func GenerateFixture(packageName string, opts Options) (string, error) {
+	t, err := prepare(nil, opts)
+	if err != nil {
+		return "", err
+	}
+	return generateTestFile("fixture.tmpl", "test fixture", t, nil, packageName, opts)
+}
+
+// generateTestFile executes the template name of a test file for packageName,
+// generating what.
+func generateTestFile(name, what string, t *template.Template, ops []OperationDefinition, packageName string, opts Options) (string, error) {
+	modulePath, moduleVersion := buildVersion()
+	context := struct {
+		Operations  []OperationDefinition
+		PackageName string
+		ModuleName  string
+		Version     string
+	}{
+		Operations:  ops,
+		PackageName: packageName,
+		ModuleName:  modulePath,
+		Version:     moduleVersion,
+	}
+
+	goCode, err := GenerateTemplates([]string{name}, t, context)
+	if err != nil {
+		return "", fmt.Errorf("error generating %s: %w", what, err)
+	}
+	goCode = SanitizeCode(goCode)
+	if usesGenerics() {
+		goCode = useAny(goCode)
+	}
+
+	return formatCode(packageName+"_test.go", goCode, opts)
+}
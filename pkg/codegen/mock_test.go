@@ -0,0 +1,56 @@
+package codegen
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateMock(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testOpenAPIDefinition))
+	assert.NoError(t, err)
+
+	code, err := GenerateMock(swagger, "api", Options{})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "var _ ServerInterface = (*MockServer)(nil)")
+	assert.Contains(t, code, "GetTestByNameFunc func(w http.ResponseWriter, r *http.Request, name string, params GetTestByNameParams)")
+	assert.Contains(t, code, "func (m *MockServer) GetTestByName(w http.ResponseWriter, r *http.Request, name string, params GetTestByNameParams) {")
+	assert.Contains(t, code, "m.GetTestByNameFunc(w, r, name, params)")
+	assert.Contains(t, code, "http.Error(w, http.StatusText(http.StatusNotImplemented), http.StatusNotImplemented)")
+
+	swagger, err = openapi3.NewLoader().LoadFromData([]byte(testOpenAPIDefinition))
+	assert.NoError(t, err)
+
+	code, err = GenerateMock(swagger, "api", Options{Framework: "echo"})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "GetTestByNameFunc func(ctx echo.Context, name string, params GetTestByNameParams) error")
+	assert.Contains(t, code, "return echo.NewHTTPError(http.StatusNotImplemented)")
+}
+
+func TestGenerateFixture(t *testing.T) {
+	code, err := GenerateFixture("api", Options{})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "type TestFixture struct {\n\tMockServer\n")
+	assert.Contains(t, code, "func (f *TestFixture) Start(t testing.TB) *url.URL {")
+	assert.Contains(t, code, "f.server = httptest.NewServer(Handler(&f.MockServer, f.Options...))")
+	assert.Contains(t, code, "t.Cleanup(f.server.Close)")
+
+	code, err = GenerateFixture("api", Options{Framework: "echo"})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "RegisterHandlers(e, &f.MockServer, f.Options...)")
+	assert.Contains(t, code, "f.server = httptest.NewServer(e)")
+}
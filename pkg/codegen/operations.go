@@ -18,8 +18,11 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 	"unicode"
 
 	"github.com/kenshaw/snaker"
@@ -46,7 +49,7 @@ func (pd ParameterDefinition) TypeDef() string {
 // JSONTag returns the tag for pd. It includes omitempty if it is
 // required.
 func (pd *ParameterDefinition) JSONTag() string {
-	if pd.Required {
+	if pd.Required || globalOptions.NoOmitEmpty {
 		return fmt.Sprintf("`json:\"%s\"`", pd.ParamName)
 	}
 	return fmt.Sprintf("`json:\"%s,omitempty\"`", pd.ParamName)
@@ -209,7 +212,8 @@ func DescribeSecurityDefinition(srs openapi3.SecurityRequirements) []SecurityDef
 
 // OperationDefinition represents an Operation.
 type OperationDefinition struct {
-	OperationID string // The operation_id description from Swagger, used to generate function names
+	OperationID     string // The operation_id description from Swagger, used to generate function names
+	SpecOperationID string // The operationId of the operation in the spec, as it is, empty if it has none
 
 	PathParams          []ParameterDefinition // Parameters in the path, eg, /path/:param
 	HeaderParams        []ParameterDefinition // Parameters in HTTP headers
@@ -219,10 +223,14 @@ type OperationDefinition struct {
 	SecurityDefinitions []SecurityDefinition  // These are the security providers
 	BodyRequired        bool
 	Bodies              []RequestBodyDefinition // The list of bodies for which to generate handlers.
+	FormParams          []FormParamDefinition   // Fields of a form request body
+	FormContentType     string                  // multipart/form-data or application/x-www-form-urlencoded, with FormParams
 	Summary             string                  // Summary string from Swagger, used to generate a comment
 	Method              string                  // GET, POST, DELETE, etc.
 	Path                string                  // The Swagger path for the operation, like /resource/{id}
 	Middlewares         []string                // Sent as part of x-go-middlewares.
+	MiddlewareFuncs     []string                // Sent as part of x-go-middleware.
+	Timeout             time.Duration           // The x-timeout of the operation, zero if it has none.
 	Spec                *openapi3.Operation
 }
 
@@ -250,17 +258,49 @@ func (o *OperationDefinition) RequiresParamObject() bool {
 	return len(o.Params()) > 0
 }
 
-// SummaryAsComment returns the summary as a multiline comment for o.
+// SummaryAsComment returns the summary as a multiline comment for o,
+// followed by the description. The description of deprecated operations is
+// left to DeprecatedAsComment.
 func (o *OperationDefinition) SummaryAsComment() string {
-	if o.Summary == "" {
+	summary := StringToGoComment(o.Summary)
+	description := strings.TrimSpace(o.Spec.Description)
+	if o.Spec.Deprecated || description == "" || description == strings.TrimSpace(o.Summary) {
+		return summary
+	}
+	if summary == "" {
+		return StringToGoComment(description)
+	}
+	return summary + "\n//\n" + StringToGoComment(description)
+}
+
+// ExternalDocsAsComment returns the See paragraph of the comment for o,
+// linking its external documentation, or nothing if it has none.
+func (o *OperationDefinition) ExternalDocsAsComment() string {
+	see := ExternalDocsToGoComment(o.Spec.ExternalDocs)
+	if see == "" {
+		return ""
+	}
+	return "//\n" + see
+}
+
+// DeprecatedAsComment returns the Deprecated paragraph of the comment for o,
+// or nothing if o isn't deprecated.
+func (o *OperationDefinition) DeprecatedAsComment() string {
+	if !o.Spec.Deprecated {
 		return ""
 	}
-	trimmed := strings.TrimSuffix(o.Summary, "\n")
-	parts := strings.Split(trimmed, "\n")
-	for i, p := range parts {
-		parts[i] = "// " + p
+	return "//\n" + DeprecatedToGoComment(o.Spec.Description)
+}
+
+// ConnectBodyType returns the type of the body of the Connect requests of o,
+// the type of its default JSON body, or struct{} if it has none.
+func (o *OperationDefinition) ConnectBodyType() string {
+	for _, body := range o.Bodies {
+		if body.Default && body.NameTag == "JSON" {
+			return body.TypeDef(o.OperationID).TypeName
+		}
 	}
-	return strings.Join(parts, "\n")
+	return "struct{}"
 }
 
 // GetResponseTypeDefinitions produces a list of type definitions for a given
@@ -309,13 +349,23 @@ func (o *OperationDefinition) GetResponseTypeDefinitions() ([]ResponseTypeDefini
 						ResponseName:    responseName,
 						ContentTypeName: contentTypeName,
 					}
-					if IsGoTypeReference(contentType.Schema.Ref) {
+					// The references to excluded schemas are resolved by
+					// GenerateGoSchema already.
+					_, excluded := excludedSchemaName(contentType.Schema.Ref)
+					if IsGoTypeReference(contentType.Schema.Ref) && !excluded {
 						refType, err := RefPathToGoType(contentType.Schema.Ref)
 						if err != nil {
 							return nil, fmt.Errorf("error dereferencing response Ref: %w", err)
 						}
 						td.Schema.RefType = refType
 					}
+					if variant, ok := readWriteVariant(contentType.Schema, "Response"); ok {
+						if td.Schema.IsRef() {
+							td.Schema.RefType = variant
+						} else {
+							td.Schema.GoType = variant
+						}
+					}
 					tds = append(tds, td)
 				}
 			}
@@ -399,6 +449,14 @@ func OperationDefinitions(swagger *openapi3.T) ([]OperationDefinition, error) {
 				return nil, fmt.Errorf("invalid value for %q: %w", extMiddlewares, err)
 			}
 		}
+		var pathMiddlewareFuncs []string
+		if extension, ok := pathItem.Extensions[extMiddleware]; ok {
+			var err error
+			pathMiddlewareFuncs, err = extParseMiddlewareFuncs(extension)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for %q: %w", extMiddleware, err)
+			}
+		}
 
 		// Each path can have a number of operations, POST, GET, OPTIONS, etc.
 		pathOps := pathItem.Operations()
@@ -409,6 +467,7 @@ func OperationDefinitions(swagger *openapi3.T) ([]OperationDefinition, error) {
 			}
 
 			// We rely on OperationID to generate function names, it's required
+			specOperationID := op.OperationID
 			op.OperationID = ToCamelCase(op.OperationID)
 			if op.OperationID == "" {
 				op.OperationID, err = generateDefaultOperationID(opName, requestPath)
@@ -446,26 +505,52 @@ func OperationDefinitions(swagger *openapi3.T) ([]OperationDefinition, error) {
 				}
 				middlewares = append(middlewares, opMiddlewares...)
 			}
+			middlewareFuncs := pathMiddlewareFuncs
+			if extension, ok := op.Extensions[extMiddleware]; ok {
+				opMiddlewareFuncs, err := extParseMiddlewareFuncs(extension)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value for %q: %w", extMiddleware, err)
+				}
+				middlewareFuncs = append(append([]string{}, middlewareFuncs...), opMiddlewareFuncs...)
+			}
+
+			var timeout time.Duration
+			if extension, ok := op.Extensions[extTimeout]; ok {
+				if timeout, err = extParseTimeout(extension); err != nil {
+					return nil, fmt.Errorf("invalid value for %q: %w", extTimeout, err)
+				}
+			}
 
 			bodyDefinitions, typeDefinitions, err := GenerateBodyDefinitions(op.OperationID, op.RequestBody)
 			if err != nil {
 				return nil, fmt.Errorf("error generating body definitions: %w", err)
 			}
 
+			formParams, err := DescribeFormParams(op.OperationID, op.RequestBody)
+			if err != nil {
+				return nil, fmt.Errorf("error describing form parameters for %s/%s: %w", opName, requestPath, err)
+			}
+			formContentType, _ := formContent(op.RequestBody)
+
 			opDef := OperationDefinition{
-				PathParams:   pathParams,
-				HeaderParams: FilterParameterDefinitionByType(allParams, "header"),
-				QueryParams:  FilterParameterDefinitionByType(allParams, "query"),
-				CookieParams: FilterParameterDefinitionByType(allParams, "cookie"),
-				OperationID:  ToCamelCase(op.OperationID),
+				PathParams:      pathParams,
+				HeaderParams:    FilterParameterDefinitionByType(allParams, "header"),
+				QueryParams:     FilterParameterDefinitionByType(allParams, "query"),
+				CookieParams:    FilterParameterDefinitionByType(allParams, "cookie"),
+				OperationID:     versioned(ToCamelCase(op.OperationID)),
+				SpecOperationID: specOperationID,
 				// Replace newlines in summary.
 				Summary:         op.Summary,
 				Method:          opName,
 				Path:            requestPath,
 				Spec:            op,
 				Bodies:          bodyDefinitions,
+				FormParams:      formParams,
+				FormContentType: formContentType,
 				TypeDefinitions: typeDefinitions,
 				Middlewares:     middlewares,
+				MiddlewareFuncs: middlewareFuncs,
+				Timeout:         timeout,
 			}
 
 			// check for overrides of SecurityDefinitions.
@@ -538,10 +623,22 @@ func GenerateBodyDefinitions(operationID string, bodyOrRef *openapi3.RequestBody
 		}
 
 		bodyTypeName := operationID + tag + "Body"
+		if content.Schema != nil && content.Schema.Ref == "" && content.Schema.Value != nil {
+			name, err := extGoName(content.Schema.Value.ExtensionProps)
+			if err != nil {
+				return nil, nil, fmt.Errorf("request body: %w", err)
+			}
+			if name != "" {
+				bodyTypeName = name
+			}
+		}
 		bodySchema, err := GenerateGoSchema(content.Schema, []string{bodyTypeName})
 		if err != nil {
 			return nil, nil, fmt.Errorf("error generating request body definition: %w", err)
 		}
+		if variant, ok := readWriteVariant(content.Schema, "Request"); ok {
+			bodySchema.GoType = variant
+		}
 
 		// If the body is a pre-defined type
 		if IsGoTypeReference(bodyOrRef.Ref) {
@@ -558,12 +655,12 @@ func GenerateBodyDefinitions(operationID string, bodyOrRef *openapi3.RequestBody
 		// that we have an easy to use type for marshaling.
 		if bodySchema.RefType == "" {
 			td := TypeDefinition{
-				TypeName: bodyTypeName,
+				TypeName: versioned(bodyTypeName),
 				Schema:   bodySchema,
 			}
 			typeDefinitions = append(typeDefinitions, td)
 			// The body schema now is a reference to a type
-			bodySchema.RefType = bodyTypeName
+			bodySchema.RefType = td.TypeName
 		}
 
 		bd := RequestBodyDefinition{
@@ -578,6 +675,77 @@ func GenerateBodyDefinitions(operationID string, bodyOrRef *openapi3.RequestBody
 	return bodyDefinitions, typeDefinitions, nil
 }
 
+// FormParamDefinition describes a field of a multipart/form-data or
+// application/x-www-form-urlencoded request body.
+type FormParamDefinition struct {
+	Property
+	File bool // Whether the field holds uploaded files, only in multipart bodies
+	JSON bool // Whether the field is an object, sent as JSON
+}
+
+// DescribeFormParams returns the fields of the multipart/form-data or, if it
+// has none, application/x-www-form-urlencoded request body of bodyOrRef. Fields
+// of files, or arrays of files, of multipart bodies are typed as
+// []*multipart.FileHeader.
+func DescribeFormParams(operationID string, bodyOrRef *openapi3.RequestBodyRef) ([]FormParamDefinition, error) {
+	contentType, content := formContent(bodyOrRef)
+	if content == nil {
+		return nil, nil
+	}
+	schema := content.Schema.Value
+
+	var params []FormParamDefinition
+	for _, name := range SortedSchemaKeys(schema.Properties) {
+		sref := schema.Properties[name]
+		param := FormParamDefinition{
+			Property: Property{
+				Description:    sref.Value.Description,
+				JSONFieldName:  name,
+				Required:       StringInArray(name, schema.Required),
+				Nullable:       sref.Value.Nullable,
+				Deprecated:     sref.Value.Deprecated,
+				ExtensionProps: &sref.Value.ExtensionProps,
+			},
+			JSON: sref.Value.Type == "object",
+		}
+
+		file := isFileSchema(sref.Value) || (sref.Value.Type == "array" && sref.Value.Items != nil && isFileSchema(sref.Value.Items.Value))
+		if file && contentType == "multipart/form-data" {
+			param.File = true
+			param.Schema = Schema{GoType: "[]*multipart.FileHeader", SkipOptionalPointer: true}
+		} else {
+			propSchema, err := GenerateGoSchema(sref, []string{operationID + "FormParams", name})
+			if err != nil {
+				return nil, fmt.Errorf("error generating type for form field %s: %w", name, err)
+			}
+			param.Schema = propSchema
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+// formContent returns the form media type of the request body of bodyOrRef
+// and its content, preferring multipart/form-data, or "" and nil if it has
+// none with a schema.
+func formContent(bodyOrRef *openapi3.RequestBodyRef) (string, *openapi3.MediaType) {
+	if bodyOrRef == nil || bodyOrRef.Value == nil {
+		return "", nil
+	}
+	for _, contentType := range []string{"multipart/form-data", "application/x-www-form-urlencoded"} {
+		content := bodyOrRef.Value.Content.Get(contentType)
+		if content != nil && content.Schema != nil && content.Schema.Value != nil {
+			return contentType, content
+		}
+	}
+	return "", nil
+}
+
+// isFileSchema returns if schema describes the contents of a file.
+func isFileSchema(schema *openapi3.Schema) bool {
+	return schema != nil && schema.Type == "string" && schema.Format == "binary"
+}
+
 // GenerateTypeDefsForOperation returns the type definitions for op.
 func GenerateTypeDefsForOperation(op OperationDefinition) []TypeDefinition {
 	var typeDefs []TypeDefinition
@@ -585,6 +753,12 @@ func GenerateTypeDefsForOperation(op OperationDefinition) []TypeDefinition {
 	if len(op.Params()) != 0 {
 		typeDefs = append(typeDefs, GenerateParamsTypes(op)...)
 	}
+	if len(op.PathParams) != 0 {
+		typeDefs = append(typeDefs, GeneratePathParamsTypes(op))
+	}
+	if len(op.FormParams) != 0 {
+		typeDefs = append(typeDefs, GenerateFormParamsTypes(op))
+	}
 
 	// Now, go through all the additional types we need to declare.
 	for _, param := range op.AllParams() {
@@ -594,6 +768,10 @@ func GenerateTypeDefsForOperation(op OperationDefinition) []TypeDefinition {
 	for _, body := range op.Bodies {
 		typeDefs = append(typeDefs, body.Schema.AdditionalTypeDefs()...)
 	}
+
+	for _, param := range op.FormParams {
+		typeDefs = append(typeDefs, param.Schema.AdditionalTypeDefs()...)
+	}
 	return typeDefs
 }
 
@@ -624,6 +802,7 @@ func GenerateParamsTypes(op OperationDefinition) []TypeDefinition {
 			Description:    param.Spec.Description,
 			JSONFieldName:  param.ParamName,
 			Required:       param.Required,
+			Deprecated:     param.Spec.Deprecated,
 			Schema:         pSchema,
 			ExtensionProps: &param.Spec.ExtensionProps,
 		}
@@ -640,12 +819,123 @@ func GenerateParamsTypes(op OperationDefinition) []TypeDefinition {
 	return append(typeDefs, td)
 }
 
+// GeneratePathParamsTypes defines the schema for a path parameters definition
+// object, which holds the typed path parameters of an operation.
+func GeneratePathParamsTypes(op OperationDefinition) TypeDefinition {
+	s := Schema{}
+	for _, param := range op.PathParams {
+		prop := Property{
+			Description:    param.Spec.Description,
+			JSONFieldName:  param.ParamName,
+			Required:       true,
+			Deprecated:     param.Spec.Deprecated,
+			Schema:         param.Schema,
+			ExtensionProps: &param.Spec.ExtensionProps,
+		}
+		s.Properties = append(s.Properties, prop)
+	}
+	s.GoType = GenStructFromSchema(s)
+
+	return TypeDefinition{
+		TypeName: op.OperationID + "PathParams",
+		Schema:   s,
+	}
+}
+
+// ParamConstraint is a check of a constraint of the schema of a path
+// parameter, generated for the parameters bound by the path params helpers.
+type ParamConstraint struct {
+	Check      string // The Go condition failing the constraint, like params.ID < 1
+	Error      string // The error of values failing the constraint
+	Pattern    string // The pattern of a pattern constraint
+	PatternVar string // The variable holding the compiled Pattern
+}
+
+// PathParamConstraints returns the checks of the minimum, maximum,
+// minLength, maxLength and pattern constraints of the path parameters of o.
+// Enums aren't included, since their generated types already reject unknown
+// values, and neither are the parameters of referenced or custom types.
+func (o *OperationDefinition) PathParamConstraints() []ParamConstraint {
+	var constraints []ParamConstraint
+	for _, param := range o.PathParams {
+		schema := param.Schema.OAPISchema
+		if schema == nil || param.Schema.IsRef() || !param.IsStyled() {
+			continue
+		}
+		if _, ok := schema.Extensions[extPropGoType]; ok {
+			continue
+		}
+		field := "params." + param.GoName()
+		fail := func(check, format string, args ...interface{}) {
+			constraints = append(constraints, ParamConstraint{
+				Check: check,
+				Error: fmt.Sprintf("invalid value for parameter %s: ", param.ParamName) + fmt.Sprintf(format, args...),
+			})
+		}
+		// bound fails values for which op holds, or exclusiveOp if the bound
+		// is exclusive. Bounds which aren't constants of the type of the
+		// parameter are compared as floats.
+		bound := func(v *float64, exclusive bool, op, msg, exclusiveOp, exclusiveMsg string) {
+			if v == nil {
+				return
+			}
+			value := strconv.FormatFloat(*v, 'f', -1, 64)
+			x := field
+			if *v != math.Trunc(*v) || (*v < 0 && strings.HasPrefix(param.Schema.GoType, "uint")) {
+				x = "float64(" + field + ")"
+			}
+			if exclusive {
+				op, msg = exclusiveOp, exclusiveMsg
+			}
+			fail(x+" "+op+" "+value, "must be %s %s", msg, value)
+		}
+
+		switch param.Schema.GoType {
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+			bound(schema.Min, schema.ExclusiveMin, "<", "at least", "<=", "greater than")
+			bound(schema.Max, schema.ExclusiveMax, ">", "at most", ">=", "less than")
+		case "string":
+			if len(schema.Enum) != 0 {
+				continue
+			}
+			if schema.MinLength != 0 {
+				fail(fmt.Sprintf("utf8.RuneCountInString(%s) < %d", field, schema.MinLength), "must be at least %d characters long", schema.MinLength)
+			}
+			if schema.MaxLength != nil {
+				fail(fmt.Sprintf("utf8.RuneCountInString(%s) > %d", field, *schema.MaxLength), "must be at most %d characters long", *schema.MaxLength)
+			}
+			if schema.Pattern != "" {
+				patternVar := strings.ToLower(o.OperationID[:1]) + o.OperationID[1:] + param.GoName() + "Pattern"
+				fail(fmt.Sprintf("!%s.MatchString(%s)", patternVar, field), "must match %s", schema.Pattern)
+				constraints[len(constraints)-1].Pattern = schema.Pattern
+				constraints[len(constraints)-1].PatternVar = patternVar
+			}
+		}
+	}
+	return constraints
+}
+
+// GenerateFormParamsTypes defines the schema for a form parameters definition
+// object, which holds the fields of the form request body of an operation.
+func GenerateFormParamsTypes(op OperationDefinition) TypeDefinition {
+	s := Schema{}
+	for _, param := range op.FormParams {
+		s.Properties = append(s.Properties, param.Property)
+	}
+	s.GoType = GenStructFromSchema(s)
+
+	return TypeDefinition{
+		TypeName: op.OperationID + "FormParams",
+		Schema:   s,
+	}
+}
+
 // GenerateTypesForOperations prooduces code all types used by ops.
 func GenerateTypesForOperations(t *template.Template, ops []OperationDefinition) (string, error) {
 	var buf bytes.Buffer
 	w := bufio.NewWriter(&buf)
 
-	addTypes, err := GenerateTemplates([]string{"param-types.tmpl", "request-bodies.tmpl", "response-bodies.tmpl"}, t, ops)
+	addTypes, err := GenerateTemplates([]string{"param-types.tmpl", "path-params.tmpl", "form-params.tmpl", "request-bodies.tmpl", "response-bodies.tmpl"}, t, ops)
 	if err != nil {
 		return "", fmt.Errorf("error generating type boilerplate for operations: %w", err)
 	}
@@ -672,6 +962,15 @@ func GenerateTypesForOperations(t *template.Template, ops []OperationDefinition)
 		return "", fmt.Errorf("error generating additional properties boilerplate for operations: %w", err)
 	}
 
+	unions, err := GenerateUnionBoilerplate(t, td)
+	if err != nil {
+		return "", fmt.Errorf("error generating union boilerplate for operations: %w", err)
+	}
+
+	if _, err := w.WriteString(unions); err != nil {
+		return "", fmt.Errorf("error writing union boilerplate to buffer: %w", err)
+	}
+
 	if err = w.Flush(); err != nil {
 		return "", fmt.Errorf("error flushing output buffer for server interface: %w", err)
 	}
@@ -684,6 +983,24 @@ func GenerateChiServer(t *template.Template, operations []OperationDefinition) (
 	return GenerateTemplates([]string{"interface.tmpl", "middleware.tmpl", "handler.tmpl"}, t, operations)
 }
 
+// GenerateEchoServer generates the Echo server boilerplate, in place of the
+// one generated by GenerateChiServer.
+func GenerateEchoServer(t *template.Template, operations []OperationDefinition) (string, error) {
+	return GenerateTemplates([]string{"echo-interface.tmpl", "echo-wrappers.tmpl", "echo-register.tmpl"}, t, operations)
+}
+
+// GenerateConnectServer generates the chi server boilerplate, along with the
+// Connect handlers serving the operations through it.
+func GenerateConnectServer(t *template.Template, operations []OperationDefinition) (string, error) {
+	return GenerateTemplates([]string{"interface.tmpl", "middleware.tmpl", "handler.tmpl", "connect.tmpl"}, t, operations)
+}
+
+// GenerateClient generates a typed HTTP client with a method for each
+// operation in ops.
+func GenerateClient(t *template.Template, operations []OperationDefinition) (string, error) {
+	return GenerateTemplates([]string{"client.tmpl"}, t, operations)
+}
+
 // GenerateTemplates generates templates
 func GenerateTemplates(templates []string, t *template.Template, ops interface{}) (string, error) {
 	var generatedTemplates []string
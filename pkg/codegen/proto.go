@@ -0,0 +1,169 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// protoMethods are the HTTP methods with a pattern of their own in a
+// google.api.http rule. The others are custom patterns.
+var protoMethods = map[string]bool{
+	"GET":    true,
+	"PUT":    true,
+	"POST":   true,
+	"DELETE": true,
+	"PATCH":  true,
+}
+
+// ProtoRPC describes the rpc of an operation, with the google.api.http
+// rule binding it to the path and method of the operation.
+type ProtoRPC struct {
+	Name    string       // The name of the rpc, the operation ID
+	Method  string       // The HTTP method of the operation
+	Path    string       // The path template of the rule, with the field names of the path parameters
+	Body    string       // The field of the request body, if the operation has one
+	Fields  []ProtoField // The fields of the request message
+	Comment string       // The summary of the operation, as a comment
+}
+
+// Pattern returns the pattern field of the rule, like get, or custom for the
+// methods without a pattern of their own.
+func (rpc ProtoRPC) Pattern() string {
+	if protoMethods[rpc.Method] {
+		return strings.ToLower(rpc.Method)
+	}
+	return "custom"
+}
+
+// ProtoField is a field of the request message of an rpc.
+type ProtoField struct {
+	Name   string // The name of the field, in snake case
+	Type   string // The type of the field, like int64 or repeated string
+	Number int    // The field number
+}
+
+// GenerateProto generates a .proto file for packageName, holding a service
+// with an rpc per operation, annotated with the google.api.http rule binding
+// it to the operation, for grpc-gateway. The request messages have a field
+// per path and query parameter, and a google.protobuf.Value for the request
+// body, and the rpcs return a google.protobuf.Value, which any JSON response
+// is.
+func GenerateProto(swagger *openapi3.T, packageName string, opts Options) (string, error) {
+	t, err := prepare(swagger, opts)
+	if err != nil {
+		return "", err
+	}
+
+	ops, err := OperationDefinitions(swagger)
+	if err != nil {
+		return "", fmt.Errorf("error creating operation definitions: %w", err)
+	}
+	rpcs := make([]ProtoRPC, 0, len(ops))
+	for _, op := range ops {
+		rpc, err := describeProtoRPC(op)
+		if err != nil {
+			return "", fmt.Errorf("error describing rpc for %s: %w", op.OperationID, err)
+		}
+		rpcs = append(rpcs, rpc)
+	}
+
+	modulePath, moduleVersion := buildVersion()
+	context := struct {
+		RPCs        []ProtoRPC
+		PackageName string
+		Service     string
+		ModuleName  string
+		Version     string
+	}{
+		RPCs:        rpcs,
+		PackageName: packageName,
+		Service:     ToCamelCase(packageName) + "Service",
+		ModuleName:  modulePath,
+		Version:     moduleVersion,
+	}
+	return GenerateTemplates([]string{"proto.tmpl"}, t, context)
+}
+
+// describeProtoRPC describes the rpc of op. Header and cookie parameters
+// aren't bound by grpc-gateway, and are left out of the request message.
+func describeProtoRPC(op OperationDefinition) (ProtoRPC, error) {
+	rpc := ProtoRPC{
+		Name:    op.OperationID,
+		Method:  op.Method,
+		Path:    op.Path,
+		Comment: protoComment(op.Summary),
+	}
+
+	seen := map[string]string{}
+	addField := func(name, typ string) error {
+		field := ToSnakeCase(name)
+		if other, ok := seen[field]; ok {
+			return fmt.Errorf("%s and %s are both the field %s", other, name, field)
+		}
+		seen[field] = name
+		rpc.Fields = append(rpc.Fields, ProtoField{Name: field, Type: typ, Number: len(rpc.Fields) + 1})
+		return nil
+	}
+
+	params := append(append([]ParameterDefinition{}, op.PathParams...), op.QueryParams...)
+	for _, param := range params {
+		if err := addField(param.ParamName, protoType(param.Spec.Schema)); err != nil {
+			return ProtoRPC{}, err
+		}
+		if param.In == "path" {
+			rpc.Path = strings.Replace(rpc.Path, "{"+param.ParamName+"}", "{"+ToSnakeCase(param.ParamName)+"}", 1)
+		}
+	}
+	if op.Spec.RequestBody != nil {
+		if err := addField("body", "google.protobuf.Value"); err != nil {
+			return ProtoRPC{}, err
+		}
+		rpc.Body = "body"
+	}
+	return rpc, nil
+}
+
+// protoType returns the protobuf type of a parameter of schema: a scalar
+// type, a repeated scalar type for arrays, or a google.protobuf.Value.
+func protoType(schema *openapi3.SchemaRef) string {
+	if schema == nil || schema.Value == nil {
+		return "google.protobuf.Value"
+	}
+	s := schema.Value
+	switch s.Type {
+	case "integer":
+		if s.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if s.Format == "float" {
+			return "float"
+		}
+		return "double"
+	case "boolean":
+		return "bool"
+	case "string":
+		return "string"
+	case "array":
+		if item := protoType(s.Items); item != "google.protobuf.Value" {
+			return "repeated " + item
+		}
+	}
+	return "google.protobuf.Value"
+}
+
+// protoComment returns s as a proto comment, indented for an rpc.
+func protoComment(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("  // "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
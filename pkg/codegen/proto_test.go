@@ -0,0 +1,108 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateProto(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: pets
+  version: 1.0.0
+paths:
+  /owners/{ownerId}/pets:
+    get:
+      operationId: listPets
+      summary: Lists the pets of an owner
+      parameters:
+        - name: ownerId
+          in: path
+          required: true
+          schema:
+            type: string
+        - name: tags
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            format: int32
+        - name: X-Request-ID
+          in: header
+          schema:
+            type: string
+      responses:
+        '200':
+          description: the pets
+    post:
+      operationId: addPet
+      parameters:
+        - name: ownerId
+          in: path
+          required: true
+          schema:
+            type: string
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+      responses:
+        '201':
+          description: the pet
+    head:
+      operationId: countPets
+      parameters:
+        - name: ownerId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '204':
+          description: the count
+`))
+	assert.NoError(t, err)
+
+	code, err := GenerateProto(swagger, "pets", Options{})
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, `syntax = "proto3";`)
+	assert.Contains(t, code, "package pets;")
+	assert.Contains(t, code, `import "google/api/annotations.proto";`)
+	assert.Contains(t, code, "service PetsService {")
+
+	assert.Contains(t, code, `  // Lists the pets of an owner
+  rpc ListPets(ListPetsRequest) returns (google.protobuf.Value) {
+    option (google.api.http) = {
+      get: "/owners/{owner_id}/pets"
+    };
+  }`)
+	assert.Contains(t, code, `    option (google.api.http) = {
+      post: "/owners/{owner_id}/pets"
+      body: "body"
+    };`)
+	assert.Contains(t, code, `      custom: {
+        kind: "HEAD"
+        path: "/owners/{owner_id}/pets"
+      }`)
+
+	// Header parameters aren't bound by grpc-gateway.
+	assert.Contains(t, code, `message ListPetsRequest {
+  string owner_id = 1;
+  repeated string tags = 2;
+  int32 limit = 3;
+}`)
+	assert.Contains(t, code, `message AddPetRequest {
+  string owner_id = 1;
+  google.protobuf.Value body = 2;
+}`)
+}
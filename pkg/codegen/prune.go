@@ -36,6 +36,17 @@ func walkSwagger(swagger *openapi3.T, doFn func(RefWrapper) (bool, error)) error
 		}
 	}
 
+	// The webhooks which can't be loaded fail the generation later on.
+	webhooks, _ := loadWebhooks(swagger)
+	for _, p := range webhooks {
+		for _, param := range p.Parameters {
+			walkParameterRef(param, doFn)
+		}
+		for _, op := range p.Operations() {
+			walkOperation(op, doFn)
+		}
+	}
+
 	walkComponents(&swagger.Components, doFn)
 
 	return nil
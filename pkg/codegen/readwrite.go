@@ -0,0 +1,109 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// readWriteTypes holds the names of the component schemas which get Request
+// and Response variants, because they have readOnly or writeOnly properties.
+var readWriteTypes = map[string]bool{}
+
+// findReadWriteTypes returns the component schemas of swagger which have
+// readOnly or writeOnly properties. Schemas whose variants would collide with
+// another schema, and excluded schemas, are left out.
+func findReadWriteTypes(swagger *openapi3.T, excludeSchemas []string) map[string]bool {
+	types := map[string]bool{}
+	if swagger.Components.Schemas == nil {
+		return types
+	}
+
+	typeNames := map[string]bool{}
+	for schemaName := range swagger.Components.Schemas {
+		typeNames[componentTypeName(schemaName)] = true
+	}
+
+	for schemaName, sref := range swagger.Components.Schemas {
+		if sref.Ref != "" || StringInArray(schemaName, excludeSchemas) || !hasReadWriteProperties(sref.Value) {
+			continue
+		}
+		typeName := componentTypeName(schemaName)
+		if typeNames[typeName+"Request"] || typeNames[typeName+"Response"] {
+			continue
+		}
+		types[typeName] = true
+	}
+	return types
+}
+
+// hasReadWriteProperties returns if schema is a plain object with readOnly
+// or writeOnly properties.
+func hasReadWriteProperties(schema *openapi3.Schema) bool {
+	if schema == nil || schema.AllOf != nil || schema.OneOf != nil || schema.AnyOf != nil {
+		return false
+	}
+	if _, ok := schema.Extensions[extPropGoType]; ok {
+		return false
+	}
+	for _, p := range schema.Properties {
+		if p.Value != nil && (p.Value.ReadOnly || p.Value.WriteOnly) {
+			return true
+		}
+	}
+	return false
+}
+
+// readWriteVariants returns the Request and Response variants of the
+// component schema td. Request variants leave out readOnly properties, and
+// Response variants leave out writeOnly properties.
+func readWriteVariants(td TypeDefinition) []TypeDefinition {
+	request, response := td.Schema, td.Schema
+	request.Properties, response.Properties = nil, nil
+	for _, p := range td.Schema.Properties {
+		spec := td.Schema.OAPISchema.Properties[p.JSONFieldName].Value
+		if !spec.ReadOnly {
+			request.Properties = append(request.Properties, p)
+		}
+		if !spec.WriteOnly {
+			response.Properties = append(response.Properties, p)
+		}
+	}
+	request.GoType = GenStructFromSchema(request)
+	response.GoType = GenStructFromSchema(response)
+
+	request.Description = fmt.Sprintf("// %sRequest is %s without its readOnly properties.", td.TypeName, td.TypeName)
+	response.Description = fmt.Sprintf("// %sResponse is %s without its writeOnly properties.", td.TypeName, td.TypeName)
+
+	return []TypeDefinition{
+		{TypeName: td.TypeName + "Request", JSONName: td.JSONName, Schema: request},
+		{TypeName: td.TypeName + "Response", JSONName: td.JSONName, Schema: response},
+	}
+}
+
+// readWriteVariant returns the type to use for sref in a request or response,
+// where suffix is either "Request" or "Response". It returns false if sref
+// doesn't reference a schema with variants, directly or as array items.
+func readWriteVariant(sref *openapi3.SchemaRef, suffix string) (string, bool) {
+	if sref == nil {
+		return "", false
+	}
+	if sref.Ref == "" {
+		if sref.Value != nil && sref.Value.Type == "array" {
+			if goType, ok := readWriteVariant(sref.Value.Items, suffix); ok {
+				return "[]" + goType, true
+			}
+		}
+		return "", false
+	}
+
+	if !strings.HasPrefix(sref.Ref, "#/components/schemas/") {
+		return "", false
+	}
+	refType, err := RefPathToGoType(sref.Ref)
+	if err != nil || !readWriteTypes[refType] {
+		return "", false
+	}
+	return refType + suffix, true
+}
@@ -0,0 +1,79 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ValidateRefs returns an error for every local $ref of swagger, like
+// "#/components/schemas/Pet", which doesn't point to a component. It works on
+// specs whose references haven't been resolved yet, so that all the broken
+// references can be reported at once. References to other files aren't
+// checked.
+func ValidateRefs(swagger *openapi3.T) []error {
+	if swagger == nil {
+		return nil
+	}
+
+	broken := map[string]bool{}
+	_ = walkSwagger(swagger, func(ref RefWrapper) (bool, error) {
+		if ref.Ref == "" {
+			return true, nil
+		}
+		if strings.HasPrefix(ref.Ref, "#") && !componentExists(&swagger.Components, ref.Ref) {
+			broken[ref.Ref] = true
+		}
+		// The component itself is walked with the other components.
+		return false, nil
+	})
+
+	refs := make([]string, 0, len(broken))
+	for ref := range broken {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	errs := make([]error, 0, len(refs))
+	for _, ref := range refs {
+		errs = append(errs, fmt.Errorf("unresolved $ref %q", ref))
+	}
+	return errs
+}
+
+// componentExists returns if the local ref, like "#/components/schemas/Pet",
+// points to one of components. Refs into a component, like
+// "#/components/schemas/Pet/properties/id", only need the component to exist.
+func componentExists(components *openapi3.Components, ref string) bool {
+	parts := strings.Split(ref, "/")
+	if len(parts) < 4 || parts[0] != "#" || parts[1] != "components" {
+		return false
+	}
+	// Names are escaped as in JSON pointers.
+	name := strings.NewReplacer("~1", "/", "~0", "~").Replace(parts[3])
+
+	var ok bool
+	switch parts[2] {
+	case "schemas":
+		_, ok = components.Schemas[name]
+	case "parameters":
+		_, ok = components.Parameters[name]
+	case "headers":
+		_, ok = components.Headers[name]
+	case "requestBodies":
+		_, ok = components.RequestBodies[name]
+	case "responses":
+		_, ok = components.Responses[name]
+	case "securitySchemes":
+		_, ok = components.SecuritySchemes[name]
+	case "examples":
+		_, ok = components.Examples[name]
+	case "links":
+		_, ok = components.Links[name]
+	case "callbacks":
+		_, ok = components.Callbacks[name]
+	}
+	return ok
+}
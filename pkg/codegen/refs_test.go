@@ -0,0 +1,85 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRefs(t *testing.T) {
+	t.Run("unresolved", func(t *testing.T) {
+		swagger := &openapi3.T{}
+		require.NoError(t, yaml.Unmarshal([]byte(brokenRefsTestFixture), swagger))
+
+		var msgs []string
+		for _, err := range ValidateRefs(swagger) {
+			msgs = append(msgs, err.Error())
+		}
+		assert.Equal(t, []string{
+			`unresolved $ref "#/components/headers/Rate"`,
+			`unresolved $ref "#/components/parameters/Limit"`,
+			`unresolved $ref "#/components/requestBodies/NewPet"`,
+			`unresolved $ref "#/components/responses/NotFound"`,
+			`unresolved $ref "#/components/schemas/Owner"`,
+			`unresolved $ref "#/components/schemas/Tag"`,
+		}, msgs)
+	})
+
+	t.Run("resolved", func(t *testing.T) {
+		swagger, err := openapi3.NewLoader().LoadFromData([]byte(pruneSpecTestFixture))
+		require.NoError(t, err)
+		assert.Empty(t, ValidateRefs(swagger))
+	})
+}
+
+const brokenRefsTestFixture = `
+openapi: 3.0.1
+info:
+  title: Broken refs
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/Limit'
+      responses:
+        200:
+          description: The pets
+          headers:
+            X-Rate-Limit:
+              $ref: '#/components/headers/Rate'
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+        404:
+          $ref: '#/components/responses/NotFound'
+    post:
+      requestBody:
+        $ref: '#/components/requestBodies/NewPet'
+      responses:
+        201:
+          description: Created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet/properties/owner'
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        owner:
+          $ref: '#/components/schemas/Owner'
+        tags:
+          type: array
+          items:
+            $ref: '#/components/schemas/Tag'
+        external:
+          $ref: 'other.yaml#/components/schemas/External'
+`
@@ -3,9 +3,12 @@ package codegen
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/kenshaw/snaker"
 )
 
 // Schema represents an OpenAPI type definition.
@@ -25,12 +28,58 @@ type Schema struct {
 	SkipOptionalPointer bool // Some types don't need a * in front when they're optional
 	Bindable            bool // Indicates whether this type can implement render.Binder
 
-	Description string // The description of the element
+	Discriminator *Discriminator // For a oneOf/anyOf with a discriminator, the union variants
+	UnionRef      bool           // Whether the schema is a reference to a union, whose type has its own JSON methods
+
+	AllOfParts []AllOfPart // For an allOf, the merged schemas, in order
+
+	Description  string // The description of the element
+	ExternalDocs string // The See paragraph of the comment of the element, linking its external documentation
 
 	// The original OpenAPIv3 Schema.
 	OAPISchema *openapi3.Schema
 }
 
+// AllOfPart is one of the schemas merged by an allOf. Referenced schemas are
+// embedded in the merged struct, while the properties of inline schemas become
+// its own fields.
+type AllOfPart struct {
+	Embedded   string     // The name of the embedded field, for a reference
+	Properties []Property // The fields of an inline schema
+}
+
+// EmbedsAllOf returns if s is the struct of an allOf which merges several
+// schemas, at least one of them embedded. A single embedded type already
+// has its JSON methods promoted.
+func (s Schema) EmbedsAllOf() bool {
+	if len(s.AllOfParts) < 2 {
+		return false
+	}
+	for _, part := range s.AllOfParts {
+		if part.Embedded != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// RejectsAdditionalProperties returns if s is an object whose schema
+// disallows additional properties.
+func (s Schema) RejectsAdditionalProperties() bool {
+	return s.OAPISchema != nil && SchemaRejectsAdditionalProperties(s.OAPISchema)
+}
+
+// HasValidators returns if one of the properties of s has an x-go-validate
+// function.
+func (s Schema) HasValidators() bool {
+	for _, p := range s.Properties {
+		if p.Validator != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // IsRef returns if s references another type.
 func (s Schema) IsRef() bool {
 	return s.RefType != ""
@@ -45,6 +94,27 @@ func (s Schema) TypeDecl() string {
 	return s.GoType
 }
 
+// EnumConstants returns the Go literals of the enum values of s, by the name
+// of their constant. It returns nothing if s isn't an enum of a type which
+// can be a constant, such as time.Time.
+func (s Schema) EnumConstants() map[string]string {
+	if len(s.EnumValues) == 0 {
+		return nil
+	}
+	switch s.GoType {
+	case "string":
+		consts := make(map[string]string, len(s.EnumValues))
+		for name, value := range s.EnumValues {
+			consts[name] = strconv.Quote(value)
+		}
+		return consts
+	case "int", "int32", "int64", "float32", "float64", "bool":
+		return s.EnumValues
+	default:
+		return nil
+	}
+}
+
 // MergeProperty adds p to the properties of s. p must not already be a property
 // in the schema.
 func (s *Schema) MergeProperty(p Property) error {
@@ -69,6 +139,21 @@ func (s Schema) AdditionalTypeDefs() []TypeDefinition {
 	return result
 }
 
+// Discriminator represents a oneOf/anyOf union, where the variant is chosen
+// by the value of a property.
+type Discriminator struct {
+	Interface    string                 // The name of the interface implemented by all variants
+	PropertyName string                 // The JSON name of the discriminating property
+	Variants     []DiscriminatorVariant // The variants of the union
+}
+
+// DiscriminatorVariant is a single type of a union, and the discriminator
+// values which select it.
+type DiscriminatorVariant struct {
+	GoType string
+	Values []string
+}
+
 // Property represents an OpenAPI property.
 type Property struct {
 	Description    string
@@ -76,18 +161,49 @@ type Property struct {
 	Schema         Schema
 	Required       bool
 	Nullable       bool
+	Deprecated     bool
+	GoName         string // The x-go-name of the property, if any
+	Validator      string // The x-go-validate function of the property, if any
+	Embedded       bool   // Whether the referenced struct of the property is embedded, with x-go-embedded
 	ExtensionProps *openapi3.ExtensionProps
 }
 
-// GoFieldName returns the Go name of p.
+// GoFieldName returns the Go name of p. The name of an embedded field is the
+// name of its type.
 func (p Property) GoFieldName() string {
+	if p.Embedded {
+		return p.Schema.GoType[strings.LastIndex(p.Schema.GoType, ".")+1:]
+	}
+	if p.GoName != "" {
+		return p.GoName
+	}
 	return SchemaNameToTypeName(p.JSONFieldName)
 }
 
+// GoVariableName returns a safe version of p's GoFieldName to be used as a
+// variable or parameter name.
+func (p Property) GoVariableName() string {
+	name := snaker.ForceLowerCamelIdentifier(p.GoFieldName())
+	if IsGoKeyword(name) {
+		name = "p" + UppercaseFirstCharacter(name)
+	}
+	if unicode.IsNumber([]rune(name)[0]) {
+		name = "n" + name
+	}
+	return name
+}
+
+// Pointer returns if p is declared as a pointer to its schema type. Optional
+// and nullable properties are pointers, unless nullable pointers are skipped.
+// Embedded properties never are.
+func (p Property) Pointer() bool {
+	return !p.Embedded && !p.Schema.SkipOptionalPointer && (!p.Required || (p.Nullable && !globalOptions.SkipNullablePointer))
+}
+
 // GoTypeDef returns the go type of p.
 func (p Property) GoTypeDef() string {
 	typeDef := p.Schema.TypeDecl()
-	if !p.Schema.SkipOptionalPointer && (!p.Required || p.Nullable) {
+	if p.Pointer() {
 		typeDef = "*" + typeDef
 	}
 	return typeDef
@@ -147,6 +263,14 @@ func (t *TypeDefinition) CanAlias() bool {
 	return t.Schema.IsRef() || (t.Schema.ArrayType != nil && t.Schema.ArrayType.IsRef())
 }
 
+// MustAlias returns whether t has to be an alias of the type it is declared
+// as, whichever the AliasTypes option: a defined type doesn't have the
+// methods of its underlying type, and the JSON methods of a union type are
+// the only way to decode its variants.
+func (t *TypeDefinition) MustAlias() bool {
+	return t.Schema.UnionRef || (t.Schema.Discriminator != nil && t.Schema.IsRef())
+}
+
 // PropertiesEqual returns if a and b can be considered to be the same.
 // a and b are the same if they have the same field name, same type, and are
 // both required (or not).
@@ -154,6 +278,51 @@ func PropertiesEqual(a, b Property) bool {
 	return a.JSONFieldName == b.JSONFieldName && a.Schema.TypeDecl() == b.Schema.TypeDecl() && a.Required == b.Required
 }
 
+// excludedSchemaName returns the name of the component schema ref refers to,
+// if it is excluded from generation by the ExcludeSchemas of the options.
+func excludedSchemaName(ref string) (string, bool) {
+	name := strings.TrimPrefix(ref, "#/components/schemas/")
+	if name == ref || !StringInArray(name, globalOptions.ExcludeSchemas) {
+		return "", false
+	}
+	return name, true
+}
+
+// excludedSchema returns the schema of the references to the component
+// schema name, which is excluded from generation. They are of the type of
+// its x-go-type, or interface{} if it has none.
+func excludedSchema(name string, schema *openapi3.Schema) (Schema, error) {
+	extension, ok := schema.Extensions[extPropGoType]
+	if !ok {
+		return Schema{
+			GoType:      "interface{}",
+			Description: StringToGoComment(excludedSchemaComment(name)),
+			OAPISchema:  schema,
+		}, nil
+	}
+
+	typeName, err := extTypeName(extension)
+	if err != nil {
+		return Schema{}, fmt.Errorf("invalid value for %q of schema %s: %w", extPropGoType, name, err)
+	}
+	goType, imp := extGoType(typeName)
+	if imp != nil {
+		goTypeImports[imp.Path] = *imp
+	}
+	return Schema{
+		GoType:       goType,
+		Description:  StringToGoComment(schema.Description),
+		ExternalDocs: ExternalDocsToGoComment(schema.ExternalDocs),
+		Bindable:     true,
+	}, nil
+}
+
+// excludedSchemaComment returns the comment of the values of the component
+// schema name, which is excluded from generation without an x-go-type.
+func excludedSchemaComment(name string) string {
+	return fmt.Sprintf("The %s schema is excluded from generation, set its x-go-type to use a Go type for it.", name)
+}
+
 // GenerateGoSchema generates the schema for sref.
 // If it cannot properly resolve the type of sref, it returns
 // map[string]interface{} or interface{}.
@@ -170,35 +339,72 @@ func GenerateGoSchema(sref *openapi3.SchemaRef, path []string) (Schema, error) {
 	// If Ref is set on the SchemaRef, it means that this type is actually a reference to
 	// another type. We're not de-referencing, so simply use the referenced type.
 	if IsGoTypeReference(sref.Ref) {
+		if name, ok := excludedSchemaName(sref.Ref); ok {
+			return excludedSchema(name, schema)
+		}
+
 		// Convert the reference path to Go type
 		refType, err := RefPathToGoType(sref.Ref)
 		if err != nil {
 			return Schema{}, fmt.Errorf("error turning reference (%s) into a Go type: %s",
 				sref.Ref, err)
 		}
-		return Schema{
-			GoType:      refType,
-			Description: StringToGoComment(schema.Description),
-			Bindable:    true,
-		}, nil
+		out := Schema{
+			GoType:       refType,
+			Description:  StringToGoComment(schema.Description),
+			ExternalDocs: ExternalDocsToGoComment(schema.ExternalDocs),
+			Bindable:     true,
+		}
+		if schema != nil && (schema.AnyOf != nil || schema.OneOf != nil) {
+			discriminator, err := unionDiscriminator(schema, path)
+			if err != nil {
+				return Schema{}, fmt.Errorf("error generating union: %w", err)
+			}
+			out.UnionRef = discriminator != nil
+		}
+		return out, nil
 	}
 
 	outSchema := Schema{
-		Description: StringToGoComment(schema.Description),
-		OAPISchema:  schema,
-		Bindable:    true,
+		Description:  StringToGoComment(schema.Description),
+		ExternalDocs: ExternalDocsToGoComment(schema.ExternalDocs),
+		OAPISchema:   schema,
+		Bindable:     true,
 	}
 
-	// FIXME(hhhapz): We can probably support this in a meaningful way.
-	// We can't support this in any meaningful way
 	if schema.AnyOf != nil || schema.OneOf != nil {
-		outSchema.GoType = "interface{}"
-		outSchema.Bindable = false
+		discriminator, err := unionDiscriminator(schema, path)
+		if err != nil {
+			return Schema{}, fmt.Errorf("error generating union: %w", err)
+		}
+		// Without a discriminator, there's no way to tell the variants apart.
+		if discriminator == nil {
+			outSchema.GoType = "interface{}"
+			outSchema.Bindable = false
+			return outSchema, nil
+		}
+
+		outSchema.Discriminator = discriminator
+		outSchema.GoType = fmt.Sprintf("struct {\n    Value %s\n}", discriminator.Interface)
+		if len(path) > 1 { // handle additional type only on non-toplevel types
+			typeName := versioned(SchemaNameToTypeName(PathToTypeName(append([]string(nil), path...))))
+			typeDef := TypeDefinition{
+				TypeName: typeName,
+				JSONName: strings.Join(path, "."),
+				Schema:   outSchema,
+			}
+			outSchema.AdditionalTypes = append(outSchema.AdditionalTypes, typeDef)
+			outSchema.RefType = typeName
+		}
 		return outSchema, nil
 	}
 
 	if schema.AllOf != nil {
-		mergedSchema, err := MergeSchemas(schema.AllOf, path)
+		if allOfCycle(schema, schema, map[*openapi3.Schema]bool{}) {
+			return Schema{}, errors.New("circular allOf reference")
+		}
+		allOf := typedAllOf(schema.AllOf)
+		mergedSchema, err := MergeSchemas(allOf, path)
 		if err != nil {
 			return Schema{}, fmt.Errorf("error merging schemas: %w", err)
 		}
@@ -212,7 +418,11 @@ func GenerateGoSchema(sref *openapi3.SchemaRef, path []string) (Schema, error) {
 		if err != nil {
 			return outSchema, fmt.Errorf("invalid value for %q: %w", extPropGoType, err)
 		}
-		outSchema.GoType = typeName
+		goType, imp := extGoType(typeName)
+		if imp != nil {
+			goTypeImports[imp.Path] = *imp
+		}
+		outSchema.GoType = goType
 		return outSchema, nil
 	}
 
@@ -225,7 +435,10 @@ func GenerateGoSchema(sref *openapi3.SchemaRef, path []string) (Schema, error) {
 		if len(schema.Properties) == 0 && !SchemaHasAdditionalProperties(schema) {
 			// If the object has no properties or additional properties, we
 			// have some special cases for its type.
-			if t == "object" {
+			if SchemaRejectsAdditionalProperties(schema) {
+				// We have an object which can't have any properties.
+				outType = "struct{}"
+			} else if t == "object" {
 				// We have an object with no properties. This is a generic object
 				// expressed as a map.
 				outType = "map[string]interface{}"
@@ -238,6 +451,7 @@ func GenerateGoSchema(sref *openapi3.SchemaRef, path []string) (Schema, error) {
 			outSchema.GoType = outType
 		} else {
 			// We've got an object with some properties.
+			embeddedTypes := map[string]string{}
 			for _, pName := range SortedSchemaKeys(schema.Properties) {
 				p := schema.Properties[pName]
 				propertyPath := append(path, pName)
@@ -253,7 +467,7 @@ func GenerateGoSchema(sref *openapi3.SchemaRef, path []string) (Schema, error) {
 					// but are not a pre-defined type, we need to define a type
 					// for them, which will be based on the field names we followed
 					// to get to the type.
-					typeName := PathToTypeName(propertyPath)
+					typeName := versioned(PathToTypeName(propertyPath))
 
 					typeDef := TypeDefinition{
 						TypeName: typeName,
@@ -268,12 +482,54 @@ func GenerateGoSchema(sref *openapi3.SchemaRef, path []string) (Schema, error) {
 				if p.Value != nil {
 					description = p.Value.Description
 				}
+				if name, ok := excludedSchemaName(p.Ref); ok && pSchema.GoType == "interface{}" {
+					description = strings.TrimSpace(description + "\n\n" + excludedSchemaComment(name))
+				}
+				// The x-go-name of a reference names the referenced type.
+				var goName string
+				if p.Ref == "" {
+					if goName, err = extGoName(p.Value.ExtensionProps); err != nil {
+						return Schema{}, fmt.Errorf("property '%s': %w", pName, err)
+					}
+				}
+				var validator string
+				if ext, ok := p.Value.Extensions[extGoValidate]; ok {
+					fn, imp, err := extParseGoValidate(ext)
+					if err != nil {
+						return Schema{}, fmt.Errorf("property '%s': invalid value for %q: %w", pName, extGoValidate, err)
+					}
+					if imp != nil {
+						goTypeImports[imp.Path] = *imp
+					}
+					validator = fn
+				}
+				embeddedRef, err := propertyEmbeddedRef(p)
+				if err != nil {
+					return Schema{}, fmt.Errorf("property '%s': %w", pName, err)
+				}
+				if embeddedRef != nil {
+					if embeddedRef != p {
+						// The allOf of the property is its referenced type.
+						if pSchema, err = GenerateGoSchema(embeddedRef, propertyPath); err != nil {
+							return Schema{}, fmt.Errorf("error generating Go schema for property '%s': %w", pName, err)
+						}
+					}
+					name := pSchema.GoType[strings.LastIndex(pSchema.GoType, ".")+1:]
+					if other, ok := embeddedTypes[name]; ok {
+						return Schema{}, fmt.Errorf("properties '%s' and '%s' both embed %s", other, pName, name)
+					}
+					embeddedTypes[name] = pName
+				}
 				prop := Property{
 					JSONFieldName:  pName,
 					Schema:         pSchema,
 					Required:       required,
 					Description:    description,
 					Nullable:       p.Value.Nullable,
+					Deprecated:     p.Value.Deprecated,
+					GoName:         goName,
+					Validator:      validator,
+					Embedded:       embeddedRef != nil,
 					ExtensionProps: &p.Value.ExtensionProps,
 				}
 				outSchema.Properties = append(outSchema.Properties, prop)
@@ -313,10 +569,10 @@ func GenerateGoSchema(sref *openapi3.SchemaRef, path []string) (Schema, error) {
 			} else {
 				constNamePath = append(path, k)
 			}
-			outSchema.EnumValues[SchemaNameToTypeName(PathToTypeName(constNamePath))] = v
+			outSchema.EnumValues[versioned(SchemaNameToTypeName(PathToTypeName(constNamePath)))] = v
 		}
 		if len(path) > 1 { // handle additional type only on non-toplevel types
-			typeName := SchemaNameToTypeName(PathToTypeName(path))
+			typeName := versioned(SchemaNameToTypeName(PathToTypeName(path)))
 			typeDef := TypeDefinition{
 				TypeName: typeName,
 				JSONName: strings.Join(path, "."),
@@ -335,6 +591,62 @@ func GenerateGoSchema(sref *openapi3.SchemaRef, path []string) (Schema, error) {
 	return outSchema, nil
 }
 
+// unionDiscriminator returns the discriminator of the oneOf/anyOf schema at
+// path. It returns nil if schema has no discriminator, or if any of its
+// variants isn't a reference to a type generated in this package, since the
+// variants have to implement the union interface.
+func unionDiscriminator(schema *openapi3.Schema, path []string) (*Discriminator, error) {
+	if schema.Discriminator == nil || schema.Discriminator.PropertyName == "" {
+		return nil, nil
+	}
+
+	refs := schema.OneOf
+	if refs == nil {
+		refs = schema.AnyOf
+	}
+
+	typeName := versioned(SchemaNameToTypeName(PathToTypeName(append([]string(nil), path...))))
+	d := Discriminator{
+		Interface:    typeName + "Variant",
+		PropertyName: schema.Discriminator.PropertyName,
+	}
+
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		if ref.Ref == "" || !IsGoTypeReference(ref.Ref) {
+			return nil, nil
+		}
+		goType, err := RefPathToGoType(ref.Ref)
+		if err != nil {
+			return nil, fmt.Errorf("error turning reference (%s) into a Go type: %w", ref.Ref, err)
+		}
+		if strings.Contains(goType, ".") || seen[goType] {
+			return nil, nil
+		}
+		seen[goType] = true
+
+		// The discriminator values default to the name of the referenced
+		// schema, unless the mapping says otherwise.
+		name := ref.Ref[strings.LastIndex(ref.Ref, "/")+1:]
+		var values []string
+		for _, value := range SortedStringKeys(schema.Discriminator.Mapping) {
+			mapped := schema.Discriminator.Mapping[value]
+			if mapped == ref.Ref || mapped == name {
+				values = append(values, value)
+			}
+		}
+		if len(values) == 0 {
+			values = []string{name}
+		}
+
+		d.Variants = append(d.Variants, DiscriminatorVariant{
+			GoType: goType,
+			Values: values,
+		})
+	}
+	return &d, nil
+}
+
 // resolveType resolves primitive  type or array for schema
 func resolveType(schema *openapi3.Schema, path []string, outSchema *Schema) error {
 	f := schema.Format
@@ -408,6 +720,14 @@ func resolveType(schema *openapi3.Schema, path []string, outSchema *Schema) erro
 			outSchema.GoType = "openapi_types.Date"
 		case "date-time":
 			outSchema.GoType = "time.Time"
+		case "uuid":
+			if globalOptions.PgxTypes {
+				outSchema.GoType = "PgxUUID"
+			} else if globalOptions.SQLTypes {
+				outSchema.GoType = "openapi_types.UUID"
+			} else {
+				outSchema.GoType = "string"
+			}
 		case "json":
 			outSchema.GoType = "json.RawMessage"
 			outSchema.SkipOptionalPointer = true
@@ -443,16 +763,24 @@ func GenFieldsFromProperties(props []Property) []string {
 	var fields []string
 	for i, p := range props {
 		field := ""
+		comment := StringToGoComment(p.Description)
+		if p.Deprecated {
+			comment = DeprecatedToGoComment(p.Description)
+		}
 		// Add a comment to a field in case we have one, otherwise skip.
-		if p.Description != "" {
+		if comment != "" {
 			// Separate the comment from a previous-defined, unrelated field.
 			// Make sure the actual field is separated by a newline.
 			if i != 0 {
 				field += "\n"
 			}
-			field += fmt.Sprintf("%s\n", StringToGoComment(p.Description))
+			field += fmt.Sprintf("%s\n", comment)
+		}
+		if p.Embedded {
+			field += fmt.Sprintf("    %s", p.GoTypeDef())
+		} else {
+			field += fmt.Sprintf("    %s %s", p.GoFieldName(), p.GoTypeDef())
 		}
-		field += fmt.Sprintf("    %s %s", p.GoFieldName(), p.GoTypeDef())
 
 		// Support x-omitempty
 		omitEmpty := true
@@ -461,6 +789,9 @@ func GenFieldsFromProperties(props []Property) []string {
 				omitEmpty = extOmitEmpty
 			}
 		}
+		if globalOptions.NoOmitEmpty {
+			omitEmpty = false
+		}
 
 		fieldTags := make(map[string]string)
 
@@ -469,6 +800,17 @@ func GenFieldsFromProperties(props []Property) []string {
 		} else {
 			fieldTags["json"] = p.JSONFieldName + ",omitempty"
 		}
+		if globalOptions.ValidatorTags {
+			if tag := validateTag(p); tag != "" {
+				fieldTags["validate"] = tag
+			}
+		}
+		if globalOptions.ProtoTags {
+			fieldTags["protobuf"] = protoTag(i+1, p)
+		}
+		if globalOptions.YAMLTags {
+			fieldTags["yaml"] = fieldTags["json"]
+		}
 		if extension, ok := p.ExtensionProps.Extensions[extPropExtraTags]; ok {
 			if tags, err := extExtraTags(extension); err == nil {
 				keys := SortedStringKeys(tags)
@@ -489,6 +831,153 @@ func GenFieldsFromProperties(props []Property) []string {
 	return fields
 }
 
+// propertyEmbeddedRef returns the reference to the schema whose struct the
+// property p embeds with x-go-embedded, or nil if it isn't embedded. Since
+// the extensions next to a $ref are dropped, x-go-embedded is either set on
+// the referenced schema, to embed it in every object referencing it, or on a
+// property which is an allOf of the one reference. The referenced schema has
+// to be an object, generated as a struct.
+func propertyEmbeddedRef(p *openapi3.SchemaRef) (*openapi3.SchemaRef, error) {
+	if p.Value == nil {
+		return nil, nil
+	}
+	ext, ok := p.Value.Extensions[extGoEmbedded]
+	if !ok {
+		return nil, nil
+	}
+	embedded, err := extParseEmbedded(ext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %q: %w", extGoEmbedded, err)
+	}
+	if !embedded {
+		return nil, nil
+	}
+
+	ref := p
+	if p.Ref == "" {
+		typed := typedAllOf(p.Value.AllOf)
+		if len(typed) != 1 || typed[0].Ref == "" {
+			return nil, fmt.Errorf("%s needs a $ref, or an allOf of one $ref", extGoEmbedded)
+		}
+		ref = typed[0]
+	}
+	if !IsGoTypeReference(ref.Ref) || !isStructSchema(ref.Value) {
+		return nil, fmt.Errorf("%s of %s: only object schemas can be embedded", extGoEmbedded, ref.Ref)
+	}
+	return ref, nil
+}
+
+// isStructSchema returns whether s is an object schema generated as a
+// struct, rather than a map or another type.
+func isStructSchema(s *openapi3.Schema) bool {
+	if s == nil || SchemaHasAdditionalProperties(s) {
+		return false
+	}
+	if _, ok := s.Extensions[extPropGoType]; ok {
+		return false
+	}
+	return s.Type == "object" || s.Type == "" && (len(s.Properties) > 0 || len(s.AllOf) > 0)
+}
+
+// validatorEscaper escapes the characters with a special meaning in
+// go-playground/validator tags, and then in struct tags.
+var validatorEscaper = strings.NewReplacer(
+	",", "0x2C",
+	"|", "0x7C",
+	`\`, `\\`,
+	`"`, `\"`,
+)
+
+// protoTag returns the protobuf tag of p, the fieldNum-th field of its struct.
+// The wire type is derived from the Go type of p; types which aren't numbers
+// or booleans, such as strings or other structs, are encoded as bytes.
+func protoTag(fieldNum int, p Property) string {
+	label, schema := "opt", p.Schema
+	if schema.ArrayType != nil {
+		label, schema = "rep", *schema.ArrayType
+	}
+
+	wireType := "bytes"
+	switch schema.GoType {
+	case "int", "int32", "int64", "bool":
+		wireType = "varint"
+	case "float32":
+		wireType = "fixed32"
+	case "float64":
+		wireType = "fixed64"
+	}
+
+	tag := fmt.Sprintf("%s,%d,%s,name=%s", wireType, fieldNum, label, p.JSONFieldName)
+	if label == "rep" && wireType != "bytes" {
+		tag += ",packed"
+	}
+	return tag + ",proto3"
+}
+
+// validateTag returns the go-playground/validator tag for the constraints of
+// p, or "" if it has none. Enums aren't included, since their generated types
+// already reject unknown values.
+func validateTag(p Property) string {
+	schema := p.Schema.OAPISchema
+	if schema == nil || p.Schema.IsRef() {
+		return ""
+	}
+	// Custom types, like dates or x-go-type, may not support the rules.
+	if _, ok := schema.Extensions[extPropGoType]; ok {
+		return ""
+	}
+
+	var rules []string
+	bound := func(inclusive, exclusive string, v *float64, exclusiveBound bool) {
+		if v == nil {
+			return
+		}
+		rule := inclusive
+		if exclusiveBound {
+			rule = exclusive
+		}
+		rules = append(rules, rule+"="+strconv.FormatFloat(*v, 'f', -1, 64))
+	}
+	length := func(rule string, v *uint64) {
+		if v != nil {
+			rules = append(rules, fmt.Sprintf("%s=%d", rule, *v))
+		}
+	}
+
+	switch schema.Type {
+	case "integer", "number":
+		bound("min", "gt", schema.Min, schema.ExclusiveMin)
+		bound("max", "lt", schema.Max, schema.ExclusiveMax)
+	case "string":
+		if p.Schema.GoType != "string" {
+			break
+		}
+		if schema.MinLength != 0 {
+			length("min", &schema.MinLength)
+		}
+		length("max", schema.MaxLength)
+		if schema.Pattern != "" && !strings.Contains(schema.Pattern, "`") {
+			rules = append(rules, "regexp="+validatorEscaper.Replace(schema.Pattern))
+		}
+	case "array":
+		if schema.MinItems != 0 {
+			length("min", &schema.MinItems)
+		}
+		length("max", schema.MaxItems)
+		if schema.UniqueItems {
+			rules = append(rules, "unique")
+		}
+	}
+
+	if len(rules) == 0 {
+		return ""
+	}
+	if p.Pointer() || !p.Required {
+		rules = append([]string{"omitempty"}, rules...)
+	}
+	return strings.Join(rules, ",")
+}
+
 // GenStructFromSchema creates a struct definition from the given Schema.
 // If the schema has additional properties, it is defined as a map[string]Type.
 func GenStructFromSchema(schema Schema) string {
@@ -503,13 +992,22 @@ func GenStructFromSchema(schema Schema) string {
 			addPropsType = schema.AdditionalPropertiesType.RefType
 		}
 
-		objectParts = append(objectParts,
-			fmt.Sprintf("AdditionalProperties map[string]%s `json:\"-\"`", addPropsType))
+		objectParts = append(objectParts, additionalPropertiesField(addPropsType))
 	}
 	objectParts = append(objectParts, "}")
 	return strings.Join(objectParts, "\n")
 }
 
+// additionalPropertiesField returns the field holding the additional
+// properties of a struct, of type map[string]addPropsType. It is skipped by
+// the JSON and YAML packages, as it is handled by the generated methods.
+func additionalPropertiesField(addPropsType string) string {
+	if globalOptions.YAMLTags {
+		return fmt.Sprintf("AdditionalProperties map[string]%s `json:\"-\" yaml:\"-\"`", addPropsType)
+	}
+	return fmt.Sprintf("AdditionalProperties map[string]%s `json:\"-\"`", addPropsType)
+}
+
 // MergeSchemas merges all the fields in the schemas supplied together.
 func MergeSchemas(allOf []*openapi3.SchemaRef, path []string) (Schema, error) {
 	var outSchema Schema
@@ -531,6 +1029,13 @@ func MergeSchemas(allOf []*openapi3.SchemaRef, path []string) (Schema, error) {
 		}
 		schema.RefType = refType
 
+		if refType != "" {
+			embedded := refType[strings.LastIndex(refType, ".")+1:]
+			outSchema.AllOfParts = append(outSchema.AllOfParts, AllOfPart{Embedded: embedded})
+		} else {
+			outSchema.AllOfParts = append(outSchema.AllOfParts, AllOfPart{Properties: schema.Properties})
+		}
+
 		for _, p := range schema.Properties {
 			err = outSchema.MergeProperty(p)
 			if err != nil {
@@ -563,6 +1068,48 @@ func MergeSchemas(allOf []*openapi3.SchemaRef, path []string) (Schema, error) {
 	return outSchema, nil
 }
 
+// typedAllOf returns the schemas of allOf which describe a type. Schemas which
+// only annotate the others, for example with a description, are left out.
+func typedAllOf(allOf []*openapi3.SchemaRef) []*openapi3.SchemaRef {
+	var typed []*openapi3.SchemaRef
+	for _, schemaOrRef := range allOf {
+		s := schemaOrRef.Value
+		if schemaOrRef.Ref != "" || s == nil {
+			typed = append(typed, schemaOrRef)
+			continue
+		}
+		_, hasGoType := s.Extensions[extPropGoType]
+		if s.Type != "" || len(s.Properties) != 0 || s.Items != nil || len(s.Enum) != 0 ||
+			len(s.AllOf) != 0 || len(s.AnyOf) != 0 || len(s.OneOf) != 0 ||
+			s.AdditionalProperties != nil || s.AdditionalPropertiesAllowed != nil || hasGoType {
+			typed = append(typed, schemaOrRef)
+		}
+	}
+	return typed
+}
+
+// allOfCycle returns if the allOf schemas of s, or the ones they merge in
+// turn, lead back to target. seen holds the schemas already walked.
+func allOfCycle(target, s *openapi3.Schema, seen map[*openapi3.Schema]bool) bool {
+	for _, sref := range s.AllOf {
+		v := sref.Value
+		if v == nil {
+			continue
+		}
+		if v == target {
+			return true
+		}
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		if allOfCycle(target, v, seen) {
+			return true
+		}
+	}
+	return false
+}
+
 // GenStructFromAllOf function generates an object that is the union of the objects in the
 // input array. In the case of Ref objects, we use an embedded struct, otherwise,
 // we inline the fields.
@@ -602,7 +1149,7 @@ func GenStructFromAllOf(allOf []*openapi3.SchemaRef, path []string) (string, err
 					addPropsType = goSchema.AdditionalPropertiesType.RefType
 				}
 
-				additionalPropertiesPart := fmt.Sprintf("AdditionalProperties map[string]%s `json:\"-\"`", addPropsType)
+				additionalPropertiesPart := additionalPropertiesField(addPropsType)
 				if !StringInArray(additionalPropertiesPart, objectParts) {
 					objectParts = append(objectParts, additionalPropertiesPart)
 				}
@@ -0,0 +1,527 @@
+package codegen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/kenshaw/snaker"
+)
+
+// SharedFileName is the file GenerateFiles puts everything in which doesn't
+// belong to a single tag.
+const SharedFileName = "types.gen.go"
+
+// GenerateFiles generates the same code as Generate, split into one file per
+// operation tag, plus SharedFileName. The returned map is keyed by file name.
+//
+// The types and functions of an operation go in the file of its first tag.
+// Component types go in the file of the one tag they are used by, or in the
+// shared file if they are used by several tags, or by none.
+func GenerateFiles(swagger *openapi3.T, packageName string, opts Options) (map[string]string, error) {
+	code, err := Generate(swagger, packageName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate filtered and pruned swagger, so these match the code.
+	ops, err := OperationDefinitions(swagger)
+	if err != nil {
+		return nil, fmt.Errorf("error creating operation definitions: %w", err)
+	}
+	t, err := parseTemplates(opts)
+	if err != nil {
+		return nil, err
+	}
+	components, err := componentTypeDefinitions(t, swagger, opts.ExcludeSchemas)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, packageName+".go", code, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing generated code: %w", err)
+	}
+
+	s := newSplitter(file, ops, components)
+	header, decls := s.header(fset, code), s.sources(fset, code)
+
+	var tags []string
+	for i := range decls {
+		if tag := s.home(i); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	names := tagFileNames(tags)
+
+	contents := map[string][]string{}
+	for i, decl := range decls {
+		name := SharedFileName
+		if tag := s.home(i); tag != "" {
+			name = names[tag]
+		}
+		contents[name] = append(contents[name], decl)
+	}
+
+	files := make(map[string]string, len(contents))
+	for name, decls := range contents {
+		h := header
+		if name != SharedFileName {
+			h = s.tagHeader(fset, code)
+		}
+		goCode := h + "\n\n" + strings.Join(decls, "\n\n") + "\n"
+		out, err := formatCode(name, goCode, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error formatting %s: %w", name, err)
+		}
+		files[name] = out
+	}
+	return files, nil
+}
+
+// buildSuffixes are the file name suffixes go build reads as constraints:
+// the known GOOS and GOARCH values, and _test.
+var buildSuffixes = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true,
+	"freebsd": true, "hurd": true, "illumos": true, "ios": true, "js": true,
+	"linux": true, "nacl": true, "netbsd": true, "openbsd": true,
+	"plan9": true, "solaris": true, "wasip1": true, "windows": true,
+	"zos": true,
+
+	"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true,
+	"arm64": true, "arm64be": true, "loong64": true, "mips": true,
+	"mipsle": true, "mips64": true, "mips64le": true, "mips64p32": true,
+	"mips64p32le": true, "ppc": true, "ppc64": true, "ppc64le": true,
+	"riscv": true, "riscv64": true, "s390": true, "s390x": true,
+	"sparc": true, "sparc64": true, "wasm": true,
+
+	"test": true,
+}
+
+// tagFileNames returns the file name of each of tags. Names are the snake
+// case of the tag, with only lowercase letters, digits and underscores, so
+// that a tag like admin/users doesn't name a path. Suffixes go build would
+// read as constraints, like the _linux of pets-linux, are dropped, and names
+// colliding with another tag or with SharedFileName are numbered.
+func tagFileNames(tags []string) map[string]string {
+	unique := map[string]bool{}
+	for _, tag := range tags {
+		unique[tag] = true
+	}
+
+	taken := map[string]bool{SharedFileName: true}
+	names := make(map[string]string, len(unique))
+	for _, tag := range sortedKeys(unique) {
+		base := tagFileBase(tag)
+		name := base + ".gen.go"
+		for n := 2; taken[name]; n++ {
+			name = fmt.Sprintf("%s_%d.gen.go", base, n)
+		}
+		taken[name] = true
+		names[tag] = name
+	}
+	return names
+}
+
+// tagFileBase returns the file name of tag, without its extension.
+func tagFileBase(tag string) string {
+	parts := strings.FieldsFunc(ToSnakeCase(tag), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	for len(parts) > 1 && buildSuffixes[parts[len(parts)-1]] {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) == 0 {
+		return "tag"
+	}
+	return strings.Join(parts, "_")
+}
+
+// splitter decides which tag every top-level declaration of a generated file
+// belongs to.
+type splitter struct {
+	file  *ast.File
+	decls []ast.Decl // All top-level declarations except imports
+
+	// groups maps the names of operation and component types, and of shared
+	// declarations, to the declarations which belong with them.
+	groups map[string][]int
+	// roots maps operation types and functions to their tag.
+	roots map[string]string
+	// components holds the names of component types.
+	components map[string]bool
+	// owner is the group of each declaration.
+	owner []string
+	// homes is the tag of each group, or "" if it is shared.
+	homes map[string]string
+}
+
+func newSplitter(file *ast.File, ops []OperationDefinition, components []TypeDefinition) *splitter {
+	s := &splitter{
+		file:       file,
+		groups:     map[string][]int{},
+		roots:      map[string]string{},
+		components: map[string]bool{},
+		homes:      map[string]string{},
+	}
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			continue
+		}
+		s.decls = append(s.decls, decl)
+	}
+
+	opIDs := make(map[string]string, len(ops))
+	for _, op := range ops {
+		tag := ""
+		if len(op.Spec.Tags) > 0 {
+			tag = op.Spec.Tags[0]
+		}
+		opIDs[op.OperationID] = tag
+
+		names := []string{op.OperationID}
+		for _, td := range op.TypeDefinitions {
+			names = append(names, td.TypeName)
+		}
+		for _, body := range op.Bodies {
+			names = append(names, body.TypeDef(op.OperationID).TypeName)
+		}
+		for _, name := range names {
+			if t, ok := s.roots[name]; ok && t != tag {
+				tag = ""
+			}
+			s.roots[name] = tag
+		}
+	}
+	for _, td := range components {
+		s.components[td.TypeName] = true
+	}
+
+	// Assign every declaration to a group. Operation wrappers and response
+	// constructors are named after the operation, methods belong with their
+	// receiver, and other declarations belong with the type they are named
+	// after, if any.
+	s.owner = make([]string, len(s.decls))
+	owners := map[string]string{}
+	for i, decl := range s.decls {
+		if isMethod(decl) {
+			continue
+		}
+		names := declNames(decl)
+		owner := s.named(names)
+		if owner == "" {
+			owner = responseOperation(names[0], opIDs)
+		}
+		if owner == "" {
+			owner = s.satellite(names, declRefs(decl))
+		}
+		if owner == "" {
+			owner = names[0]
+		}
+		s.owner[i] = owner
+		for _, name := range names {
+			owners[name] = owner
+		}
+	}
+	for i, decl := range s.decls {
+		if !isMethod(decl) {
+			continue
+		}
+		name, recv := declNames(decl)[0], receiverName(decl.(*ast.FuncDecl))
+		owner, ok := owners[recv]
+		if !ok {
+			owner = recv
+		}
+		if _, ok := opIDs[name]; ok && (recv == "ServerInterfaceWrapper" || recv == "Client") {
+			owner = name
+		}
+		s.owner[i] = owner
+	}
+	for i, owner := range s.owner {
+		s.groups[owner] = append(s.groups[owner], i)
+	}
+
+	s.resolveHomes()
+	return s
+}
+
+// named returns the first of names which is an operation or component type.
+func (s *splitter) named(names []string) string {
+	for _, name := range names {
+		if _, ok := s.roots[name]; ok || s.components[name] {
+			return name
+		}
+	}
+	return ""
+}
+
+// satellite returns the longest operation or component type in refs, which
+// one of names contains, such as Pet for NewPet or WithPetName.
+func (s *splitter) satellite(names []string, refs []string) string {
+	var owner string
+	for _, ref := range refs {
+		if ref == "" || len(ref) <= len(owner) {
+			continue
+		}
+		if _, ok := s.roots[ref]; !ok && !s.components[ref] {
+			continue
+		}
+		for _, name := range names {
+			if strings.Contains(name, ref) {
+				owner = ref
+			}
+		}
+	}
+	return owner
+}
+
+// responseOperation returns the operation a response constructor like
+// FindPets200Response belongs to, preferring the longest operation ID.
+func responseOperation(name string, opIDs map[string]string) string {
+	if !strings.HasSuffix(name, "Response") {
+		return ""
+	}
+	var owner string
+	for opID := range opIDs {
+		prefix := snaker.ForceCamelIdentifier(opID)
+		if strings.HasPrefix(name, prefix) && len(opID) > len(owner) {
+			owner = opID
+		}
+	}
+	return owner
+}
+
+// resolveHomes finds the tags of all groups. Operation groups are in the
+// file of their tag. Component groups are in the file of the one tag which
+// uses them, and shared otherwise. Everything else is shared.
+func (s *splitter) resolveHomes() {
+	refs := map[string]map[string]bool{}
+	for i, decl := range s.decls {
+		owner := s.owner[i]
+		if refs[owner] == nil {
+			refs[owner] = map[string]bool{}
+		}
+		for _, ref := range declRefs(decl) {
+			if _, ok := s.groups[ref]; ok && ref != owner {
+				refs[owner][ref] = true
+			}
+		}
+	}
+
+	users := map[string]map[string]bool{}
+	for _, group := range sortedGroups(s.groups) {
+		tag, ok := s.roots[group]
+		if s.components[group] {
+			continue
+		}
+		if !ok {
+			tag = ""
+		}
+		s.homes[group] = tag
+
+		// Mark every component reachable from group as used by tag.
+		seen := map[string]bool{group: true}
+		queue := []string{group}
+		for len(queue) > 0 {
+			next := queue[0]
+			queue = queue[1:]
+			for _, ref := range sortedKeys(refs[next]) {
+				if seen[ref] || !s.components[ref] {
+					continue
+				}
+				seen[ref] = true
+				if users[ref] == nil {
+					users[ref] = map[string]bool{}
+				}
+				users[ref][tag] = true
+				queue = append(queue, ref)
+			}
+		}
+	}
+
+	for group := range s.groups {
+		if !s.components[group] {
+			continue
+		}
+		s.homes[group] = ""
+		if len(users[group]) == 1 {
+			for tag := range users[group] {
+				s.homes[group] = tag
+			}
+		}
+	}
+}
+
+// home returns the tag of the i-th declaration, or "" if it is shared.
+func (s *splitter) home(i int) string {
+	return s.homes[s.owner[i]]
+}
+
+// header returns the package clause and imports of the generated file.
+func (s *splitter) header(fset *token.FileSet, code string) string {
+	end := s.file.Name.End()
+	for _, decl := range s.file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			end = gen.End()
+		}
+	}
+	return code[:fset.Position(end).Offset]
+}
+
+// tagHeader returns header without the package documentation, keeping only
+// the generated code notice and the //nolint directive.
+func (s *splitter) tagHeader(fset *token.FileSet, code string) string {
+	header := s.header(fset, code)
+	if s.file.Doc == nil {
+		return header
+	}
+
+	var notice []string
+	for _, c := range s.file.Doc.List {
+		if strings.HasPrefix(c.Text, "// Code generated") || strings.HasPrefix(c.Text, "//nolint:") {
+			notice = append(notice, c.Text)
+		}
+	}
+	start := fset.Position(s.file.Doc.Pos()).Offset
+	end := fset.Position(s.file.Doc.End()).Offset
+	return header[:start] + strings.Join(notice, "\n") + header[end:]
+}
+
+// sources returns the source of each declaration, including its doc comment.
+func (s *splitter) sources(fset *token.FileSet, code string) []string {
+	out := make([]string, len(s.decls))
+	for i, decl := range s.decls {
+		start := decl.Pos()
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+		case *ast.GenDecl:
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+		}
+		out[i] = code[fset.Position(start).Offset:fset.Position(decl.End()).Offset]
+	}
+	return out
+}
+
+// declNames returns the names declared by decl. Methods are named after the
+// method, not the receiver.
+func declNames(decl ast.Decl) []string {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		return []string{d.Name.Name}
+	case *ast.GenDecl:
+		var names []string
+		for _, spec := range d.Specs {
+			switch sp := spec.(type) {
+			case *ast.TypeSpec:
+				names = append(names, sp.Name.Name)
+			case *ast.ValueSpec:
+				for _, n := range sp.Names {
+					names = append(names, n.Name)
+				}
+			}
+		}
+		if len(names) == 0 {
+			names = append(names, "")
+		}
+		return names
+	}
+	return []string{""}
+}
+
+func isMethod(decl ast.Decl) bool {
+	fn, ok := decl.(*ast.FuncDecl)
+	return ok && fn.Recv != nil && len(fn.Recv.List) > 0
+}
+
+// receiverName returns the name of the receiver type of fn.
+func receiverName(fn *ast.FuncDecl) string {
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// declRefs returns the top-level names of the generated file referenced by
+// decl. Field names, selectors and composite literal keys aren't references.
+func declRefs(decl ast.Decl) []string {
+	var refs []string
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CommentGroup:
+			return false
+		case *ast.SelectorExpr:
+			ast.Inspect(n.X, visit)
+			return false
+		case *ast.Field:
+			ast.Inspect(n.Type, visit)
+			return false
+		case *ast.KeyValueExpr:
+			ast.Inspect(n.Value, visit)
+			return false
+		case *ast.Ident:
+			refs = append(refs, n.Name)
+		}
+		return true
+	}
+
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv != nil {
+			for _, f := range d.Recv.List {
+				ast.Inspect(f.Type, visit)
+			}
+		}
+		ast.Inspect(d.Type, visit)
+		if d.Body != nil {
+			ast.Inspect(d.Body, visit)
+		}
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch sp := spec.(type) {
+			case *ast.TypeSpec:
+				ast.Inspect(sp.Type, visit)
+			case *ast.ValueSpec:
+				if sp.Type != nil {
+					ast.Inspect(sp.Type, visit)
+				}
+				for _, v := range sp.Values {
+					ast.Inspect(v, visit)
+				}
+			}
+		}
+	}
+	return refs
+}
+
+func sortedGroups(groups map[string][]int) []string {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,129 @@
+package codegen
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateFiles(t *testing.T) {
+	packageName := "api"
+	opts := Options{
+		GenerateTypes:  true,
+		GenerateServer: true,
+		NoLint:         []string{"revive"},
+	}
+
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: tags
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      tags: [pets]
+      operationId: addPet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        default:
+          description: error
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Error'
+  /users/{id}:
+    get:
+      tags: [users, admin]
+      operationId: getUser
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+        default:
+          description: error
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Error'
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+    User:
+      type: object
+      properties:
+        role:
+          type: string
+          enum: [admin, member]
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+`))
+	require.NoError(t, err)
+
+	files, err := GenerateFiles(swagger, packageName, opts)
+	require.NoError(t, err)
+	assert.Len(t, files, 3)
+
+	for name, code := range files {
+		_, err = format.Source([]byte(code))
+		assert.NoError(t, err, name)
+		assert.Contains(t, code, "DO NOT EDIT.", name)
+		assert.Contains(t, code, "package api", name)
+		assert.Contains(t, code, "DO NOT EDIT.\n//\n//nolint:revive\npackage api", name)
+	}
+
+	// Operations and the types only they use go in the file of their tag:
+	assert.Contains(t, files["pets.gen.go"], "type Pet struct")
+	assert.Contains(t, files["pets.gen.go"], "func (siw *ServerInterfaceWrapper) AddPet(")
+	assert.Contains(t, files["users.gen.go"], "type User struct")
+	assert.Contains(t, files["users.gen.go"], "type UserRole struct")
+	assert.Contains(t, files["users.gen.go"], "func GetUserJSON200Response(body User) *Response")
+
+	// And everything else is shared:
+	assert.Contains(t, files[SharedFileName], "type Error struct")
+	assert.Contains(t, files[SharedFileName], "type ServerInterface interface")
+	assert.Contains(t, files[SharedFileName], "func Handler(")
+	assert.Contains(t, files[SharedFileName], "// Package api provides")
+	assert.NotContains(t, files["pets.gen.go"], "// Package api provides")
+}
+
+func TestTagFileNames(t *testing.T) {
+	names := tagFileNames([]string{
+		"pets", "Pet Store", "admin/users", "pets-linux", "pets_test",
+		"windows_amd64", "linux", "types", "../..", "pets",
+	})
+	assert.Equal(t, map[string]string{
+		"pets":          "pets.gen.go",
+		"Pet Store":     "pet_store.gen.go",
+		"admin/users":   "admin_users.gen.go",
+		"pets-linux":    "pets_2.gen.go",
+		"pets_test":     "pets_3.gen.go",
+		"windows_amd64": "windows.gen.go",
+		"linux":         "linux.gen.go",
+		"types":         "types_2.gen.go",
+		"../..":         "tag.gen.go",
+	}, names)
+}
@@ -0,0 +1,62 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"text/template"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// SwaggestDefinition describes a component schema, which RegisterSchemas
+// adds to the spec of a swaggest/openapi-go Reflector.
+type SwaggestDefinition struct {
+	SchemaName string // The name of the schema in the spec
+	TypeName   string // The generated type exposing the schema, empty if it can't have methods
+	Schema     string // The schema, as JSON
+}
+
+// GenerateSwaggest generates RegisterSchemas, adding the component schemas of
+// swagger to the spec of a swaggest/openapi-go Reflector, and JSONSchemaBytes
+// methods exposing them, for the reflector to use in place of the ones it
+// would reflect from the types of typeDefs generated for them. Aliases, and
+// pointer and interface types, which can't have methods, are reflected.
+func GenerateSwaggest(t *template.Template, swagger *openapi3.T, typeDefs []TypeDefinition) (string, error) {
+	exprs := map[string]ast.Expr{}
+	defined := map[string]TypeDefinition{}
+	for _, td := range typeDefs {
+		if _, ok := exprs[td.TypeName]; ok {
+			continue
+		}
+		expr, err := parser.ParseExpr(td.Schema.TypeDecl())
+		if err != nil {
+			return "", fmt.Errorf("error parsing type %s: %w", td.TypeName, err)
+		}
+		exprs[td.TypeName] = expr
+		defined[td.TypeName] = td
+	}
+
+	var defs []SwaggestDefinition
+	for _, name := range SortedSchemaKeys(swagger.Components.Schemas) {
+		td, ok := defined[componentTypeName(name)]
+		if !ok {
+			// The schema is excluded.
+			continue
+		}
+		schema, err := json.Marshal(swagger.Components.Schemas[name])
+		if err != nil {
+			return "", fmt.Errorf("error marshaling schema %s: %w", name, err)
+		}
+		def := SwaggestDefinition{SchemaName: name, Schema: string(schema)}
+		if !(td.MustAlias() || globalOptions.AliasTypes && td.CanAlias()) && hasMethods(exprs[td.TypeName], exprs) {
+			def.TypeName = td.TypeName
+		}
+		defs = append(defs, def)
+	}
+	if len(defs) == 0 {
+		return "", nil
+	}
+	return GenerateTemplates([]string{"swaggest.tmpl"}, t, defs)
+}
@@ -19,7 +19,9 @@ import (
 	"sort"
 	"strings"
 	"text/template"
+	"time"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/kenshaw/snaker"
 )
 
@@ -67,6 +69,63 @@ func getResponseTypeDefinitions(op *OperationDefinition) []ResponseTypeDefinitio
 	return td
 }
 
+// getClientResponseTypeDefinitions returns the JSON response types of op,
+// which the generated client decodes responses into.
+func getClientResponseTypeDefinitions(op *OperationDefinition) []ResponseTypeDefinition {
+	var tds []ResponseTypeDefinition
+	seen := map[string]bool{}
+	for _, td := range getResponseTypeDefinitions(op) {
+		if !StringInArray(td.ContentTypeName, contentTypesJSON) || seen[td.TypeName] {
+			continue
+		}
+		seen[td.TypeName] = true
+		tds = append(tds, td)
+	}
+	return tds
+}
+
+// getClientBinaryResponses returns the sorted names of the responses of op
+// with a binary body, which the generated client streams instead of reading
+// it: media types with a schema of the binary format, or which aren't JSON and
+// have no schema at all, like a download of application/pdf.
+func getClientBinaryResponses(op *OperationDefinition) []string {
+	var names []string
+	for name, ref := range op.Spec.Responses {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		for mediaType, content := range ref.Value.Content {
+			if isBinaryContent(mediaType, content) {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isBinaryContent returns if content, of mediaType, is a binary body.
+func isBinaryContent(mediaType string, content *openapi3.MediaType) bool {
+	if content == nil || content.Schema == nil || content.Schema.Value == nil {
+		return !strings.Contains(mediaType, "json")
+	}
+	return content.Schema.Value.Format == "binary"
+}
+
+// clientStatusCase returns the switch case of the generated client which
+// matches the status codes of responseName, against the response rsp.
+func clientStatusCase(responseName string) string {
+	switch strings.ToUpper(responseName) {
+	case "DEFAULT":
+		return "default:"
+	case "1XX", "2XX", "3XX", "4XX", "5XX":
+		return fmt.Sprintf("case rsp.StatusCode/100 == %s:", responseName[:1])
+	default:
+		return fmt.Sprintf("case rsp.StatusCode == %s:", responseName)
+	}
+}
+
 func getTaggedMiddlewares(ops []OperationDefinition) []string {
 	middlewares := make(map[string]struct{})
 	for _, op := range ops {
@@ -84,6 +143,116 @@ func getTaggedMiddlewares(ops []OperationDefinition) []string {
 	return keys
 }
 
+// TagOperations holds the operations with a tag.
+type TagOperations struct {
+	Tag        string
+	Operations []OperationDefinition
+}
+
+// getOperationsByTag returns the operations of ops by tag, sorted by tag.
+// Operations with several tags are listed for each of them.
+func getOperationsByTag(ops []OperationDefinition) []TagOperations {
+	byTag := make(map[string][]OperationDefinition)
+	for _, op := range ops {
+		for _, tag := range op.Spec.Tags {
+			byTag[tag] = append(byTag[tag], op)
+		}
+	}
+
+	tags := make([]TagOperations, 0, len(byTag))
+	for tag, tagOps := range byTag {
+		tags = append(tags, TagOperations{Tag: tag, Operations: tagOps})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Tag < tags[j].Tag })
+
+	return tags
+}
+
+// getMiddlewareFuncs returns the sorted names of the middleware functions of
+// x-go-middleware used by ops.
+func getMiddlewareFuncs(ops []OperationDefinition) []string {
+	funcs := make(map[string]struct{})
+	for _, op := range ops {
+		for _, f := range op.MiddlewareFuncs {
+			funcs[f] = struct{}{}
+		}
+	}
+
+	names := make([]string, 0, len(funcs))
+	for f := range funcs {
+		names = append(names, f)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// funcLinters are the linters reporting issues about functions, such as
+// their complexity or whitespace, which are exempted with a //nolint
+// directive on the generated functions raising them. The others of
+// Options.NoLint are exempted for the whole file.
+var funcLinters = map[string]bool{
+	"cyclop":   true,
+	"funlen":   true,
+	"gocognit": true,
+	"gocyclo":  true,
+	"wsl":      true,
+}
+
+// nolintLinters returns the linters of Options.NoLint which are, or aren't,
+// funcLinters.
+func nolintLinters(funcs bool) []string {
+	var linters []string
+	for _, l := range globalOptions.NoLint {
+		if l = strings.TrimSpace(l); l != "" && funcLinters[l] == funcs {
+			linters = append(linters, l)
+		}
+	}
+	return linters
+}
+
+// nolintFile returns the //nolint directive of the generated files, or an
+// empty string if there is none.
+func nolintFile() string {
+	if linters := nolintLinters(false); len(linters) != 0 {
+		return "//nolint:" + strings.Join(linters, ",")
+	}
+	return ""
+}
+
+// nolintFunc returns the //nolint directive of the generated functions,
+// preceded by a space so that it can be appended to their first line, or an
+// empty string if there is none.
+func nolintFunc() string {
+	if linters := nolintLinters(true); len(linters) != 0 {
+		return " //nolint:" + strings.Join(linters, ",")
+	}
+	return ""
+}
+
+// durationUnits are the units genDuration writes durations in, largest first.
+var durationUnits = []struct {
+	d    time.Duration
+	name string
+}{
+	{time.Hour, "time.Hour"},
+	{time.Minute, "time.Minute"},
+	{time.Second, "time.Second"},
+	{time.Millisecond, "time.Millisecond"},
+	{time.Microsecond, "time.Microsecond"},
+}
+
+// genDuration writes d as a Go expression, in the largest unit it is a
+// multiple of, eg. 90 * time.Second.
+func genDuration(d time.Duration) string {
+	for _, u := range durationUnits {
+		if d%u.d == 0 {
+			return fmt.Sprintf("%d * %s", d/u.d, u.name)
+		}
+	}
+	return fmt.Sprintf("%d * time.Nanosecond", d)
+}
+
 // This outputs a string array
 func toStringArray(sarr []string) string {
 	return `[]string{"` + strings.Join(sarr, `","`) + `"}`
@@ -100,16 +269,39 @@ func responseNameToStatusCode(responseName string) string {
 	}
 }
 
+// constructorArg returns the name of the argument of the functional options
+// constructor setting p, GoVariableName unless it is one of the names the
+// constructor declares itself, which is then suffixed with an underscore,
+// that GoVariableName never has.
+func constructorArg(p Property) string {
+	name := p.GoVariableName()
+	if name == "opts" || name == "v" {
+		return name + "_"
+	}
+	return name
+}
+
 // TemplateFunctions generates the list of utlity and helpfer functions used by
 // the templates.
 var TemplateFunctions = template.FuncMap{
-	"genParamArgs":               genParamArgs,
-	"genParamNames":              genParamNames,
-	"getResponseTypeDefinitions": getResponseTypeDefinitions,
-	"genTaggedMiddleware":        getTaggedMiddlewares,
-	"toStringArray":              toStringArray,
+	"genParamArgs":                     genParamArgs,
+	"genParamNames":                    genParamNames,
+	"getResponseTypeDefinitions":       getResponseTypeDefinitions,
+	"getClientResponseTypeDefinitions": getClientResponseTypeDefinitions,
+	"clientStatusCase":                 clientStatusCase,
+	"getClientBinaryResponses":         getClientBinaryResponses,
+	"genTaggedMiddleware":              getTaggedMiddlewares,
+	"genMiddlewareFuncs":               getMiddlewareFuncs,
+	"genOperationsByTag":               getOperationsByTag,
+	"genWebhookNames":                  getWebhookNames,
+	"toStringArray":                    toStringArray,
+	"genDuration":                      genDuration,
+	"nolintFile":                       nolintFile,
+	"nolintFunc":                       nolintFunc,
+	"constructorArg":                   constructorArg,
 
-	"swaggerURIToChiURI": SwaggerURIToChiURI,
+	"swaggerURIToChiURI":  SwaggerURIToChiURI,
+	"swaggerURIToEchoURI": SwaggerURIToEchoURI,
 
 	"statusCode": responseNameToStatusCode,
 
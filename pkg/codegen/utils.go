@@ -211,6 +211,12 @@ func refPathToGoType(refPath string, local bool) (string, error) {
 		} else if depth != 4 && depth != 2 {
 			return "", fmt.Errorf("unexpected reference depth: %d for ref: %s local: %t", depth, refPath, local)
 		}
+		if local && pathParts[1] == "components" && pathParts[2] == "schemas" {
+			return componentTypeName(pathParts[3]), nil
+		}
+		if local {
+			return versioned(SchemaNameToTypeName(pathParts[3])), nil
+		}
 		return SchemaNameToTypeName(pathParts[len(pathParts)-1]), nil
 	}
 	pathParts := strings.Split(refPath, "#")
@@ -265,6 +271,13 @@ func SwaggerURIToChiURI(uri string) string {
 	return pathParamRE.ReplaceAllString(uri, "{$1}")
 }
 
+// SwaggerURIToEchoURI converts uri to an Echo-style URI.
+// It replaces all swagger parameters with :param, accepting the same
+// parameters as SwaggerURIToChiURI.
+func SwaggerURIToEchoURI(uri string) string {
+	return pathParamRE.ReplaceAllString(uri, ":$1")
+}
+
 // OrderedParamsFromURI returns argument names in uri.
 // Given /path/{param1}/{.param2*}/{?param3},
 // returns [param1, param2, param3]
@@ -511,6 +524,12 @@ func SchemaHasAdditionalProperties(schema *openapi3.Schema) bool {
 	return false
 }
 
+// SchemaRejectsAdditionalProperties returns if schema is an object which
+// explicitly disallows additional properties, with additionalProperties: false.
+func SchemaRejectsAdditionalProperties(schema *openapi3.Schema) bool {
+	return schema.Type == "object" && schema.AdditionalPropertiesAllowed != nil && !*schema.AdditionalPropertiesAllowed
+}
+
 // PathToTypeName converts path to a go type name.
 // It converts each entry in path to camel case and joins them with _.
 func PathToTypeName(path []string) string {
@@ -531,10 +550,12 @@ func StringToGoComment(in string) string {
 	in = strings.ReplaceAll(in, "\r\n", "\n")
 	in = strings.ReplaceAll(in, "\r", "\n")
 
-	// Add comment to each line
+	// Add comment to each line, wrapping long ones
 	var lines []string
 	for _, line := range strings.Split(in, "\n") {
-		lines = append(lines, fmt.Sprintf("// %s", line))
+		for _, l := range wrapCommentLine(line) {
+			lines = append(lines, fmt.Sprintf("// %s", l))
+		}
 	}
 	in = strings.Join(lines, "\n")
 
@@ -544,6 +565,52 @@ func StringToGoComment(in string) string {
 	return in
 }
 
+// commentWidth is the width comment lines are wrapped at, leaving room for
+// the "// " prefix within 80 columns.
+const commentWidth = 77
+
+// wrapCommentLine wraps line at word boundaries to fit in commentWidth.
+// Indented lines, which may be code or lists, and words too long to fit are
+// left alone.
+func wrapCommentLine(line string) []string {
+	if len(line) <= commentWidth || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+		return []string{line}
+	}
+
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(line) {
+		if current != "" && len(current)+1+len(word) > commentWidth {
+			lines = append(lines, current)
+			current = ""
+		}
+		if current != "" {
+			current += " "
+		}
+		current += word
+	}
+	return append(lines, current)
+}
+
+// DeprecatedToGoComment returns the Deprecated paragraph of a comment for
+// something deprecated, explained by description.
+func DeprecatedToGoComment(description string) string {
+	if strings.TrimSpace(description) == "" {
+		description = "see OpenAPI spec"
+	}
+	return StringToGoComment("Deprecated: " + description)
+}
+
+// ExternalDocsToGoComment returns the See paragraph of a comment linking the
+// external documentation docs, or nothing if there is none.
+func ExternalDocsToGoComment(docs *openapi3.ExternalDocs) string {
+	if docs == nil || strings.TrimSpace(docs.URL) == "" {
+		return ""
+	}
+	see := append(strings.Fields(docs.Description), strings.TrimSpace(docs.URL))
+	return StringToGoComment("See: " + strings.Join(see, " "))
+}
+
 // EscapePathElements escapes non path parameters in path and url encodes them.
 func EscapePathElements(path string) string {
 	elems := strings.Split(path, "/")
@@ -245,6 +245,29 @@ func TestSwaggerUriToChiUri(t *testing.T) {
 	assert.Equal(t, "/path/{arg}/foo", SwaggerURIToChiURI("/path/{?arg*}/foo"))
 	assert.Equal(t, "/path/{arg}/foo", SwaggerURIToChiURI("/path/{;arg*}/foo"))
 	assert.Equal(t, "/path/{arg}/foo", SwaggerURIToChiURI("/path/{?arg}/foo"))
+
+	// Literals around parameters are kept, chi matches them within segments
+	assert.Equal(t, "/files/{name}:download", SwaggerURIToChiURI("/files/{name}:download"))
+	assert.Equal(t, "/v1/projects:batchGet", SwaggerURIToChiURI("/v1/projects:batchGet"))
+	assert.Equal(t, "/reports/{id}.json", SwaggerURIToChiURI("/reports/{id}.json"))
+	assert.Equal(t, "/reports/{id}.json", SwaggerURIToChiURI("/reports/{.id}.json"))
+	assert.Equal(t, "/users/{user-id}/pets/{pet_id}", SwaggerURIToChiURI("/users/{user-id}/pets/{pet_id}"))
+	assert.Equal(t, "/dates/{year}-{month}", SwaggerURIToChiURI("/dates/{year}-{month}"))
+	assert.Equal(t, "/files/{file.name}", SwaggerURIToChiURI("/files/{file.name}"))
+	assert.Equal(t, "/path/{arg}{rest}", SwaggerURIToChiURI("/path/{;arg*}{?rest}"))
+}
+
+func TestSwaggerUriToEchoUri(t *testing.T) {
+	assert.Equal(t, "/path", SwaggerURIToEchoURI("/path"))
+	assert.Equal(t, "/path/:arg", SwaggerURIToEchoURI("/path/{arg}"))
+	assert.Equal(t, "/path/:arg1/:arg2", SwaggerURIToEchoURI("/path/{arg1}/{arg2}"))
+	assert.Equal(t, "/path/:arg1/:arg2/foo", SwaggerURIToEchoURI("/path/{arg1}/{arg2}/foo"))
+
+	// Make sure all the exploded and alternate formats match too
+	assert.Equal(t, "/path/:arg/foo", SwaggerURIToEchoURI("/path/{arg*}/foo"))
+	assert.Equal(t, "/path/:arg/foo", SwaggerURIToEchoURI("/path/{.arg}/foo"))
+	assert.Equal(t, "/path/:arg/foo", SwaggerURIToEchoURI("/path/{;arg*}/foo"))
+	assert.Equal(t, "/path/:arg/foo", SwaggerURIToEchoURI("/path/{?arg}/foo"))
 }
 
 func TestOrderedParamsFromUri(t *testing.T) {
@@ -302,6 +325,14 @@ Line
 // 		Tabs`,
 			message: "multi line preserving whitespaces using tabs or spaces",
 		},
+		{
+			input: "A description which is much too long to fit on a single line of a comment, so it is wrapped.\n" +
+				"    An indented line which is much too long to fit on a single line is left alone.",
+			expected: "// A description which is much too long to fit on a single line of a comment, so\n" +
+				"// it is wrapped.\n" +
+				"//     An indented line which is much too long to fit on a single line is left alone.",
+			message: "long lines wrapped at word boundaries",
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.message, func(t *testing.T) {
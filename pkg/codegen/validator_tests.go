@@ -0,0 +1,157 @@
+package codegen
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ValidatorTest describes the table driven test of the FromValue method of a
+// generated enum type, which rejects the values missing from the spec.
+type ValidatorTest struct {
+	TypeName  string // The enum type
+	ValueType string // The Go type of its values
+	Cases     []ValidatorCase
+}
+
+// ValidatorCase is an entry of a ValidatorTest.
+type ValidatorCase struct {
+	Name    string // The name of the subtest
+	Value   string // The value, as a Go literal
+	WantErr bool   // Whether the value must be rejected
+}
+
+// GenerateValidatorTests generates a test file for packageName, holding a
+// table driven test for the validation of every enum type generated by
+// Generate. Each table has the values of the enum, boundary values, such as
+// the zero value or the values next to the range of a numeric enum, and a value
+// outside of the enum. They only use the standard library.
+func GenerateValidatorTests(swagger *openapi3.T, packageName string, opts Options) (string, error) {
+	t, err := prepare(swagger, opts)
+	if err != nil {
+		return "", err
+	}
+
+	types, err := componentTypeDefinitions(t, swagger, opts.ExcludeSchemas)
+	if err != nil {
+		return "", err
+	}
+
+	modulePath, moduleVersion := buildVersion()
+	context := struct {
+		Tests       []ValidatorTest
+		PackageName string
+		ModuleName  string
+		Version     string
+	}{
+		Tests:       ValidatorTests(types),
+		PackageName: packageName,
+		ModuleName:  modulePath,
+		Version:     moduleVersion,
+	}
+
+	goCode, err := GenerateTemplates([]string{"validator-tests.tmpl"}, t, context)
+	if err != nil {
+		return "", fmt.Errorf("error generating validator tests: %w", err)
+	}
+	goCode = SanitizeCode(goCode)
+
+	return formatCode(packageName+"_test.go", goCode, opts)
+}
+
+// ValidatorTests returns the tests of the enum types in types, the same ones
+// as GenerateEnumTypes. Enums of types which can't be written as literals are
+// skipped.
+func ValidatorTests(types []TypeDefinition) []ValidatorTest {
+	var tests []ValidatorTest
+	seen := map[string]bool{}
+	for _, td := range types {
+		if seen[td.TypeName] || len(td.Schema.EnumValues) == 0 {
+			continue
+		}
+		seen[td.TypeName] = true
+
+		cases := enumCases(td.Schema)
+		if len(cases) == 0 {
+			continue
+		}
+		tests = append(tests, ValidatorTest{
+			TypeName:  td.TypeName,
+			ValueType: td.Schema.TypeDecl(),
+			Cases:     cases,
+		})
+	}
+	return tests
+}
+
+// enumCases returns the test cases of the enum s: every value of the enum, then
+// its boundary and invalid values which aren't already in it.
+func enumCases(s Schema) []ValidatorCase {
+	consts := s.EnumConstants()
+	if len(consts) == 0 {
+		return nil
+	}
+
+	var cases []ValidatorCase
+	valid := map[string]bool{}
+	for _, name := range SortedStringKeys(consts) {
+		cases = append(cases, ValidatorCase{Name: "valid " + name, Value: consts[name]})
+		valid[consts[name]] = true
+	}
+
+	add := func(name, value string) {
+		if valid[value] {
+			return
+		}
+		valid[value] = true
+		cases = append(cases, ValidatorCase{Name: name, Value: value, WantErr: true})
+	}
+
+	switch s.GoType {
+	case "string":
+		add("boundary empty", `""`)
+		invalid := "invalid"
+		for valid[strconv.Quote(invalid)] {
+			invalid += "_"
+		}
+		add("invalid value", strconv.Quote(invalid))
+	case "bool":
+		add("boundary false", "false")
+		add("invalid true", "true")
+	case "int", "int32", "int64", "float32", "float64":
+		// Values are formatted with %v, so large integers may have an exponent.
+		values := make([]float64, 0, len(consts))
+		for _, v := range consts {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return cases
+			}
+			values = append(values, f)
+		}
+		sort.Float64s(values)
+		lo, hi := values[0]-1, values[len(values)-1]+1
+
+		format := func(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) }
+		inRange := func(float64) bool { return true }
+		switch s.GoType {
+		case "int32":
+			format = func(f float64) string { return strconv.FormatInt(int64(f), 10) }
+			inRange = func(f float64) bool { return f >= math.MinInt32 && f <= math.MaxInt32 }
+		case "int", "int64":
+			format = func(f float64) string { return strconv.FormatInt(int64(f), 10) }
+			inRange = func(f float64) bool { return f > math.MinInt64 && f < math.MaxInt64 }
+		}
+
+		add("boundary zero", "0")
+		if inRange(lo) {
+			add("boundary below minimum", format(lo))
+		}
+		if inRange(hi) {
+			add("invalid above maximum", format(hi))
+		}
+	}
+	return cases
+}
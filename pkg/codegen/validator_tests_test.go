@@ -0,0 +1,57 @@
+package codegen
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateValidatorTests(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: enums
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Color:
+      type: string
+      enum: [red, invalid]
+    Level:
+      type: integer
+      format: int32
+      enum: [2, 2147483647]
+    Ratio:
+      type: number
+      enum: [0, 0.5]
+`))
+	assert.NoError(t, err)
+
+	code, err := GenerateValidatorTests(swagger, "api", Options{SkipPrune: true})
+	assert.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "package api")
+	assert.NotContains(t, code, "testify")
+
+	assert.Contains(t, code, "func TestColorFromValue(t *testing.T) {")
+	assert.Contains(t, code, `{"valid ColorRed", "red", false},`)
+	assert.Contains(t, code, `{"boundary empty", "", true},`)
+	assert.Contains(t, code, `{"invalid value", "invalid_", true},`)
+
+	assert.Contains(t, code, "func TestLevelFromValue(t *testing.T) {")
+	assert.Contains(t, code, "value   int32")
+	assert.Contains(t, code, `{"boundary zero", 0, true},`)
+	assert.Contains(t, code, `{"boundary below minimum", 1, true},`)
+	// 2147483648 would overflow an int32.
+	assert.NotContains(t, code, "2147483648")
+
+	assert.Contains(t, code, "func TestRatioFromValue(t *testing.T) {")
+	assert.Contains(t, code, `{"valid RatioN0", 0, false},`)
+	assert.Contains(t, code, `{"boundary below minimum", -1, true},`)
+	assert.Contains(t, code, `{"invalid above maximum", 1.5, true},`)
+}
@@ -0,0 +1,128 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// WebhookDefinition describes an operation of a webhook, a request which the
+// API sends to its subscribers rather than serves. Its OperationID is the
+// name of the webhook followed by Webhook, which its payload types are named
+// by.
+type WebhookDefinition struct {
+	OperationDefinition
+
+	Name       string // The name of the webhook in the spec, which requests are dispatched by
+	MethodName string // The method of WebhookServer receiving the webhook
+}
+
+// loadWebhooks returns the path items of the webhooks of swagger, in its
+// x-webhooks extension, which the loader moves the webhooks of OpenAPI 3.1
+// specs to. Their references to the components of swagger are resolved.
+func loadWebhooks(swagger *openapi3.T) (map[string]*openapi3.PathItem, error) {
+	extension, ok := swagger.Extensions[extWebhooks]
+	if !ok {
+		return nil, nil
+	}
+	raw, ok := extension.(json.RawMessage)
+	if !ok {
+		return nil, fmt.Errorf("failed to convert type: %T", extension)
+	}
+	var webhooks map[string]*openapi3.PathItem
+	if err := json.Unmarshal(raw, &webhooks); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal json: %w", err)
+	}
+
+	// The loader resolves the references of the paths of a document, which
+	// the webhooks are the paths of.
+	doc := &openapi3.T{Components: swagger.Components, Paths: openapi3.Paths{}}
+	for name, pathItem := range webhooks {
+		if pathItem == nil {
+			return nil, fmt.Errorf("webhook %s has no operations", name)
+		}
+		doc.Paths["/"+name] = pathItem
+	}
+	if err := openapi3.NewLoader().ResolveRefsIn(doc, nil); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// WebhookDefinitions returns the operations of the webhooks of swagger, by
+// the name of their webhook. The method receiving a webhook is named after
+// it, and after its HTTP method as well if the webhook has several of them.
+func WebhookDefinitions(swagger *openapi3.T) ([]WebhookDefinition, error) {
+	webhooks, err := loadWebhooks(swagger)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %q: %w", extWebhooks, err)
+	}
+
+	names := make([]string, 0, len(webhooks))
+	for name := range webhooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var definitions []WebhookDefinition
+	for _, name := range names {
+		ops := webhooks[name].Operations()
+		for _, method := range SortedOperationsKeys(ops) {
+			op := ops[method]
+			methodName := ToCamelCase(name)
+			if len(ops) > 1 {
+				methodName += ToCamelCase(strings.ToLower(method))
+			}
+			if methodName == "" {
+				return nil, fmt.Errorf("invalid webhook name %q", name)
+			}
+			operationID := versioned(methodName + "Webhook")
+
+			bodyDefinitions, typeDefinitions, err := GenerateBodyDefinitions(operationID, op.RequestBody)
+			if err != nil {
+				return nil, fmt.Errorf("error generating body definitions of webhook %s: %w", name, err)
+			}
+
+			webhook := WebhookDefinition{
+				OperationDefinition: OperationDefinition{
+					OperationID:     operationID,
+					TypeDefinitions: typeDefinitions,
+					Bodies:          bodyDefinitions,
+					Summary:         op.Summary,
+					Method:          method,
+					Path:            name,
+					Spec:            op,
+				},
+				Name:       name,
+				MethodName: versioned(methodName),
+			}
+			if op.RequestBody != nil {
+				webhook.BodyRequired = op.RequestBody.Value.Required
+			}
+			definitions = append(definitions, webhook)
+		}
+	}
+	return definitions, nil
+}
+
+// GenerateWebhookServer generates the WebhookServer interface of webhooks,
+// and ServeWebhook dispatching webhook requests to it.
+func GenerateWebhookServer(t *template.Template, webhooks []WebhookDefinition) (string, error) {
+	return GenerateTemplates([]string{"webhook.tmpl"}, t, webhooks)
+}
+
+// getWebhookNames returns the names of webhooks, once each. The webhooks are
+// sorted by name, as WebhookDefinitions returns them.
+func getWebhookNames(webhooks []WebhookDefinition) []string {
+	var names []string
+	for _, webhook := range webhooks {
+		if len(names) == 0 || names[len(names)-1] != webhook.Name {
+			names = append(names, webhook.Name)
+		}
+	}
+	return names
+}
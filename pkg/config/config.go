@@ -0,0 +1,243 @@
+// Package config defines the configuration file of goapi-gen, passed with
+// --config, which holds the same settings as its flags. Flags which are set
+// override the values of the file.
+package config
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the configuration of goapi-gen. Each field is named after the
+// flag setting it.
+type Config struct {
+	// Package is the package name of the generated code, the name of the
+	// spec file by default.
+	Package string `yaml:"package"`
+	// Generate lists the generation options, see GenerateTargets.
+	Generate []string `yaml:"generate"`
+	// Out is the output file, stdout by default. It is set with --out.
+	Out string `yaml:"output"`
+	// OutputDir is the output directory, with one file per tag, instead of
+	// a single output file.
+	OutputDir string `yaml:"output-dir"`
+	// Format is how the generated code is formatted, goimports, gofmt or
+	// none. It is set with --format.
+	Format string `yaml:"format"`
+	// IncludeTags only includes the operations with one of these tags.
+	IncludeTags []string `yaml:"include-tags"`
+	// ExcludeTags excludes the operations with one of these tags.
+	ExcludeTags []string `yaml:"exclude-tags"`
+	// Templates is a directory of templates overriding the builtin ones.
+	Templates string `yaml:"templates"`
+	// ImportMapping maps the files of external references to the Go
+	// packages generated from them.
+	ImportMapping map[string]string `yaml:"import-mapping"`
+	// ExcludeSchemas excludes the matching schemas from generation.
+	ExcludeSchemas []string `yaml:"exclude-schemas"`
+	// Alias aliases type declarations when possible.
+	Alias bool `yaml:"alias"`
+	// Initialisms adds initialisms, such as ID or API, to the defaults.
+	Initialisms []string `yaml:"initialisms"`
+	// ContextFirst passes the request context as the first argument of
+	// server methods.
+	ContextFirst bool `yaml:"context-first"`
+	// NoOmitEmpty never adds omitempty to json tags.
+	NoOmitEmpty bool `yaml:"no-omitempty"`
+	// GenerateExamples generates a test file with constructors for the
+	// examples of the spec.
+	GenerateExamples bool `yaml:"generate-examples"`
+	// GenerateMock generates a test file with a mock server.
+	GenerateMock bool `yaml:"generate-mock"`
+	// GenerateTests generates a test file with tests of the enum validators.
+	GenerateTests bool `yaml:"generate-tests"`
+	// GenerateFixture generates a test file with a fixture serving the mock
+	// server.
+	GenerateFixture bool `yaml:"generate-fixture"`
+	// EmitProtoAnnotations writes a .proto file with the google.api.http
+	// annotations of the operations, for grpc-gateway.
+	EmitProtoAnnotations bool `yaml:"emit-proto-annotations"`
+	// GenerateClient generates a typed HTTP client.
+	GenerateClient bool `yaml:"generate-client"`
+	// ClientLinks generates functions following the links of the responses
+	// of the client.
+	ClientLinks bool `yaml:"client-links"`
+	// GenerateCallbacks generates clients delivering the callbacks of the
+	// operations.
+	GenerateCallbacks bool `yaml:"generate-callbacks"`
+	// StrictExtensions fails on x- extensions unknown to the generator.
+	StrictExtensions bool `yaml:"strict-extensions"`
+	// Framework is the framework of the server, chi, echo or connect.
+	Framework string `yaml:"framework"`
+	// FunctionalOptions generates constructors and functional options for
+	// struct types.
+	FunctionalOptions bool `yaml:"functional-options"`
+	// NullableAsPointer uses pointers for nullable properties, even when
+	// they're required. Unset means true.
+	NullableAsPointer *bool `yaml:"nullable-as-pointer"`
+	// ValidatorTags adds go-playground/validator tags.
+	ValidatorTags bool `yaml:"validator-tags"`
+	// ProtoTags adds protobuf tags.
+	ProtoTags bool `yaml:"proto-tags"`
+	// YAMLTags adds yaml tags, and YAML methods to the types with custom
+	// JSON handling.
+	YAMLTags bool `yaml:"yaml-tags"`
+	// SQLTypes uses types implementing sql.Scanner and driver.Valuer for
+	// uuid strings.
+	SQLTypes bool `yaml:"sql-types"`
+	// PgxTypes uses a type implementing the pgx/v5 scanner and valuer
+	// interfaces for uuid strings, in place of the SQLTypes one.
+	PgxTypes bool `yaml:"pgx-types"`
+	// EnforceTimeouts cancels the context of operations after their
+	// x-timeout, and responds with a 504.
+	EnforceTimeouts bool `yaml:"enforce-timeouts"`
+	// DeepCopy generates Kubernetes-style DeepCopy and DeepCopyInto methods
+	// for the types.
+	DeepCopy bool `yaml:"deep-copy"`
+	// Swaggest generates RegisterSchemas, registering the schemas of the
+	// types with a swaggest/openapi-go reflector.
+	Swaggest bool `yaml:"swaggest"`
+	// ServeSpec generates SpecHandler, serving the spec, and
+	// SwaggerUIHandler, serving a Swagger UI page for it.
+	ServeSpec bool `yaml:"serve-spec"`
+	// SpecPath is the path of the generated SpecPath constant, to register
+	// SpecHandler at.
+	SpecPath string `yaml:"spec-path"`
+	// NoLint lists the linters to add //nolint directives for.
+	NoLint []string `yaml:"nolint"`
+	// VersionPrefix is prepended to the names of all the generated types and
+	// operations, so that the code of several versions of an API can share a
+	// package.
+	VersionPrefix string `yaml:"version-prefix"`
+	// GoVersion is the Go version of the generated code, like 1.21. From
+	// 1.18, the code uses generics and any.
+	GoVersion string `yaml:"go-version"`
+	// HTTPTimeout is the timeout for fetching specs from URLs.
+	HTTPTimeout time.Duration `yaml:"http-timeout"`
+	// MaxRedirects is the maximum number of redirects followed when
+	// fetching specs from URLs.
+	MaxRedirects *int `yaml:"max-redirects"`
+	// DryRun prints a diff of the files which would change instead of
+	// writing them.
+	DryRun bool `yaml:"dry-run"`
+	// SpecDir is a directory holding a spec split into several files.
+	SpecDir string `yaml:"spec-dir"`
+	// ValidateSpec validates the spec before generating. It is set with
+	// --validate.
+	ValidateSpec bool `yaml:"validate"`
+}
+
+// GenerateTargets are the generation options of Config.Generate.
+var GenerateTargets = []string{"types", "server", "spec", "skip-fmt", "skip-prune"}
+
+// Frameworks are the frameworks of Config.Framework.
+var Frameworks = []string{"chi", "echo", "connect"}
+
+// Formats are the formatters of Config.Format.
+var Formats = []string{"goimports", "gofmt", "none"}
+
+// goVersionPattern matches the Go versions of Config.GoVersion, like 1.21 or
+// go1.21.3.
+var goVersionPattern = regexp.MustCompile(`^(go)?1\.\d+(\.\d+)?$`)
+
+// Default returns the configuration used when neither flags nor a
+// configuration file set anything.
+func Default() *Config {
+	nullableAsPointer := true
+	maxRedirects := 10
+	return &Config{
+		Generate:          []string{"types", "server", "spec"},
+		Framework:         "chi",
+		Format:            "goimports",
+		NoLint:            []string{"revive", "godot", "wsl", "cyclop"},
+		NullableAsPointer: &nullableAsPointer,
+		HTTPTimeout:       30 * time.Second,
+		MaxRedirects:      &maxRedirects,
+		SpecPath:          "/openapi.json",
+	}
+}
+
+// Starter is a configuration file with the default settings, and a comment
+// for each of them, written by goapi-gen init-config.
+//
+//go:embed starter.yaml
+var Starter string
+
+// Load reads and validates the configuration file at path. Fields which
+// aren't part of Config are an error, so that typos don't go unnoticed.
+func Load(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open configuration file: %w", err)
+	}
+	defer f.Close()
+
+	var cfg Config
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("could not decode yaml configuration: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Validate checks the values of the fields of c. Fields which are unset are
+// valid.
+func (c *Config) Validate() error {
+	if c.Package != "" && !token.IsIdentifier(c.Package) {
+		return fmt.Errorf("package: %q is not a Go identifier", c.Package)
+	}
+	for _, target := range c.Generate {
+		if !contains(GenerateTargets, target) {
+			return fmt.Errorf("generate: unknown generation option %q", target)
+		}
+	}
+	if c.Framework != "" && !contains(Frameworks, c.Framework) {
+		return fmt.Errorf("framework: unknown framework %q, expected one of %q", c.Framework, Frameworks)
+	}
+	if c.Format != "" && !contains(Formats, c.Format) {
+		return fmt.Errorf("format: unknown format %q, expected one of %q", c.Format, Formats)
+	}
+	for ref, pkg := range c.ImportMapping {
+		if ref == "" || pkg == "" {
+			return fmt.Errorf("import-mapping: %q: %q: both the reference and the package have to be set", ref, pkg)
+		}
+	}
+	if c.HTTPTimeout < 0 {
+		return fmt.Errorf("http-timeout: %s is negative", c.HTTPTimeout)
+	}
+	if c.MaxRedirects != nil && *c.MaxRedirects < 0 {
+		return fmt.Errorf("max-redirects: %d is negative", *c.MaxRedirects)
+	}
+	if c.SpecPath != "" && !strings.HasPrefix(c.SpecPath, "/") {
+		return fmt.Errorf("spec-path: %q doesn't start with /", c.SpecPath)
+	}
+	if c.VersionPrefix != "" && (!token.IsIdentifier(c.VersionPrefix) || !token.IsExported(c.VersionPrefix)) {
+		return fmt.Errorf("version-prefix: %q is not an exported Go identifier", c.VersionPrefix)
+	}
+	if c.GoVersion != "" && !goVersionPattern.MatchString(c.GoVersion) {
+		return fmt.Errorf("go-version: %q is not a Go version, like 1.21", c.GoVersion)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+		return path
+	}
+
+	cfg, err := Load(write("valid.yaml", `
+package: petstore
+generate: [types, server]
+output: petstore.gen.go
+framework: echo
+include-tags: [pets]
+import-mapping:
+  shared.yaml: example.com/shared
+http-timeout: 5s
+`))
+	require.NoError(t, err)
+	assert.Equal(t, &Config{
+		Package:       "petstore",
+		Generate:      []string{"types", "server"},
+		Out:           "petstore.gen.go",
+		Framework:     "echo",
+		IncludeTags:   []string{"pets"},
+		ImportMapping: map[string]string{"shared.yaml": "example.com/shared"},
+		HTTPTimeout:   5e9,
+	}, cfg)
+
+	cfg, err = Load(write("empty.yaml", ""))
+	require.NoError(t, err)
+	assert.Equal(t, &Config{}, cfg)
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"unknown field", "pakage: petstore", "field pakage not found"},
+		{"package", "package: pet-store", `package: "pet-store" is not a Go identifier`},
+		{"generate", "generate: [types, models]", `generate: unknown generation option "models"`},
+		{"framework", "framework: gin", `framework: unknown framework "gin"`},
+		{"format", "format: gofumpt", `format: unknown format "gofumpt"`},
+		{"max redirects", "max-redirects: -1", "max-redirects: -1 is negative"},
+		{"spec path", "spec-path: openapi.json", `spec-path: "openapi.json" doesn't start with /`},
+		{"version prefix", "version-prefix: v2", `version-prefix: "v2" is not an exported Go identifier`},
+		{"go version", "go-version: 2.0", `go-version: "2.0" is not a Go version`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(write("invalid.yaml", tt.data))
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+
+	_, err = Load(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestStarter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goapi-gen.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(Starter), 0o644))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	// Empty values only set empty lists and maps.
+	cfg.IncludeTags, cfg.ExcludeTags, cfg.ExcludeSchemas, cfg.Initialisms, cfg.ImportMapping = nil, nil, nil, nil, nil
+	assert.Equal(t, Default(), cfg)
+
+	// Every setting is part of the starter.
+	typ := reflect.TypeOf(Config{})
+	for i := 0; i < typ.NumField(); i++ {
+		tag := typ.Field(i).Tag.Get("yaml")
+		assert.True(t, strings.Contains(Starter, "\n"+tag+":"), "missing %s", tag)
+	}
+}
@@ -0,0 +1,35 @@
+// Package loader loads OpenAPI specs from an fs.FS, such as one embedded in
+// the binary with //go:embed, so that the spec doesn't have to be on disk to
+// validate requests against it, or from disk along with a full validation of
+// the spec.
+package loader
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// LoadSwaggerFromFS loads the spec at name in fsys. External references are
+// read from fsys as well, relative to the file which references them, and
+// may not point outside of fsys.
+func LoadSwaggerFromFS(fsys fs.FS, name string) (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(_ *openapi3.Loader, location *url.URL) ([]byte, error) {
+		if location.Scheme != "" || location.Host != "" || location.RawQuery != "" {
+			return nil, fmt.Errorf("unsupported URI: %q", location.String())
+		}
+		return fs.ReadFile(fsys, path.Clean(strings.TrimPrefix(location.Path, "/")))
+	}
+
+	swagger, err := loader.LoadFromURI(&url.URL{Path: name})
+	if err != nil {
+		return nil, fmt.Errorf("could not load %s: %w", name, err)
+	}
+	return swagger, nil
+}
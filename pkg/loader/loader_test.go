@@ -0,0 +1,301 @@
+package loader
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSwaggerFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"api/spec.yaml": {Data: []byte(`openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: pets
+          content:
+            application/json:
+              schema:
+                $ref: 'schemas/pet.yaml#/Pet'
+`)},
+		"api/schemas/pet.yaml": {Data: []byte(`Pet:
+  type: object
+  properties:
+    name:
+      type: string
+`)},
+		"outside.yaml": {Data: []byte(`openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: pets
+          content:
+            application/json:
+              schema:
+                $ref: 'https://example.com/pet.yaml#/Pet'
+`)},
+	}
+
+	swagger, err := LoadSwaggerFromFS(fsys, "api/spec.yaml")
+	require.NoError(t, err)
+	schema := swagger.Paths["/pets"].Get.Responses["200"].Value.Content["application/json"].Schema
+	require.NotNil(t, schema.Value)
+	assert.Contains(t, schema.Value.Properties, "name")
+
+	_, err = LoadSwaggerFromFS(fsys, "missing.yaml")
+	assert.Error(t, err)
+
+	_, err = LoadSwaggerFromFS(fsys, "outside.yaml")
+	assert.Error(t, err)
+}
+
+func TestLoadAndValidateSwagger(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+		return path
+	}
+
+	write("pet.yaml", `
+type: object
+properties:
+  name:
+    type: string
+  children:
+    type: array
+    items:
+      $ref: '#/components/schemas/Tree'
+`)
+	valid := write("valid.yaml", `openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+paths:
+  /pets:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/limit'
+      responses:
+        '200':
+          description: pets
+          content:
+            application/json:
+              schema:
+                $ref: 'pet.yaml'
+  /trees:
+    get:
+      responses:
+        '200':
+          description: trees
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Tree'
+components:
+  parameters:
+    limit:
+      name: limit
+      in: query
+      schema:
+        type: integer
+  schemas:
+    Tree:
+      type: object
+      properties:
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Tree'
+`)
+	invalid := write("invalid.yaml", `openapi: "3.0.3"
+info:
+  title: TestServer
+paths:
+  /pets/{id}:
+    get:
+      responses:
+        '200':
+          description: pet
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+          format: custom
+`)
+
+	swagger, err := LoadAndValidateSwagger(valid)
+	require.NoError(t, err)
+	assert.Equal(t, "#/components/parameters/limit", swagger.Paths["/pets"].Get.Parameters[0].Ref)
+
+	swagger, err = LoadAndValidateSwaggerWithOptions(valid, &Options{Dereference: true})
+	require.NoError(t, err)
+	pets := swagger.Paths["/pets"].Get
+	assert.Empty(t, pets.Parameters[0].Ref)
+	assert.Equal(t, "limit", pets.Parameters[0].Value.Name)
+	schema := pets.Responses["200"].Value.Content["application/json"].Schema
+	assert.Empty(t, schema.Ref)
+	assert.Contains(t, schema.Value.Properties, "name")
+	// The recursive references of the tree can't be inlined.
+	trees := swagger.Paths["/trees"].Get.Responses["200"].Value.Content["application/json"].Schema
+	assert.Empty(t, trees.Ref)
+	assert.Equal(t, "#/components/schemas/Tree", trees.Value.Properties["children"].Value.Items.Ref)
+	assert.Empty(t, schema.Value.Properties["children"].Value.Items.Ref)
+	_, err = swagger.MarshalJSON()
+	assert.NoError(t, err)
+
+	_, err = LoadAndValidateSwagger(invalid)
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr), err)
+	assert.Len(t, verr.Errors, 3)
+	assert.Contains(t, err.Error(), "invalid spec, 3 errors:\n")
+	assert.Contains(t, err.Error(), "\t#/components/schemas/Pet: ")
+	assert.Contains(t, err.Error(), "\t#/info: ")
+	assert.Contains(t, err.Error(), "\t#/paths/~1pets~1{id}: ")
+
+	_, err = LoadAndValidateSwagger(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestConvertOpenAPI31(t *testing.T) {
+	spec30 := []byte("openapi: 3.0.3\n")
+	data, err := ConvertOpenAPI31(spec30)
+	require.NoError(t, err)
+	assert.Equal(t, spec30, data)
+
+	data, err = ConvertOpenAPI31([]byte(`openapi: 3.1.0
+info:
+  version: 1.0.0
+  title: TestServer
+webhooks:
+  newPet:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: [string, "null"]
+      responses:
+        '200':
+          description: received
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: [string, "null"]
+          examples: [Rex]
+        kind:
+          const: dog
+        age:
+          type: integer
+          exclusiveMinimum: 0
+        photo:
+          type: string
+          contentEncoding: base64
+        owner:
+          anyOf:
+            - $ref: '#/components/schemas/Owner'
+            - type: "null"
+        tag:
+          oneOf:
+            - type: string
+            - type: "null"
+        parent:
+          $ref: '#/components/schemas/Pet'
+          description: The parent of the pet
+    Owner:
+      type: object
+`))
+	require.NoError(t, err)
+	swagger, err := openapi3.NewLoader().LoadFromData(data)
+	require.NoError(t, err)
+	assert.Equal(t, "3.0.3", swagger.OpenAPI)
+	assert.NotNil(t, swagger.Paths)
+
+	props := swagger.Components.Schemas["Pet"].Value.Properties
+	assert.Equal(t, "string", props["name"].Value.Type)
+	assert.True(t, props["name"].Value.Nullable)
+	assert.Equal(t, "Rex", props["name"].Value.Example)
+	assert.Equal(t, []interface{}{"dog"}, props["kind"].Value.Enum)
+	require.NotNil(t, props["age"].Value.Min)
+	assert.Equal(t, 0.0, *props["age"].Value.Min)
+	assert.True(t, props["age"].Value.ExclusiveMin)
+	assert.Equal(t, "byte", props["photo"].Value.Format)
+	assert.True(t, props["owner"].Value.Nullable)
+	require.Len(t, props["owner"].Value.AllOf, 1)
+	assert.Equal(t, "#/components/schemas/Owner", props["owner"].Value.AllOf[0].Ref)
+	assert.Empty(t, props["owner"].Value.AnyOf)
+	assert.Equal(t, "string", props["tag"].Value.Type)
+	assert.True(t, props["tag"].Value.Nullable)
+	assert.Empty(t, props["tag"].Value.OneOf)
+	assert.Equal(t, "#/components/schemas/Pet", props["parent"].Ref)
+
+	// The webhooks are moved to x-webhooks, with their schemas converted.
+	assert.NotContains(t, string(data), `"webhooks"`)
+	assert.Contains(t, swagger.Extensions, "x-webhooks")
+	assert.Contains(t, string(swagger.Extensions["x-webhooks"].(json.RawMessage)), `"nullable":true`)
+
+	_, err = ConvertOpenAPI31([]byte(`openapi: 3.1.0
+info:
+  version: 1.0.0
+  title: TestServer
+webhooks: {}
+x-webhooks: {}
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: filter
+          in: query
+          schema:
+            type: [string, integer]
+      responses:
+        '200':
+          description: pets
+          content:
+            application/json:
+              schema:
+                type: array
+                prefixItems:
+                  - type: string
+components:
+  schemas:
+    Pet:
+      $ref: '#/components/schemas/Animal'
+      required: [name]
+`))
+	var uerr *UnsupportedError
+	require.True(t, errors.As(err, &uerr), err)
+	assert.Equal(t, []string{
+		"#/webhooks: webhooks alongside x-webhooks",
+		"#/components/schemas/Pet: required alongside $ref",
+		"#/paths/~1pets/get/parameters/0/schema: multiple types [string integer]",
+		"#/paths/~1pets/get/responses/200/content/application~1json/schema: prefixItems",
+	}, uerr.Features)
+	assert.Contains(t, err.Error(), "4 unsupported OpenAPI 3.1 features:\n\t#/webhooks: webhooks alongside x-webhooks\n")
+}
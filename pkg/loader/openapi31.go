@@ -0,0 +1,369 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	specyaml "github.com/ghodss/yaml"
+)
+
+// dialect31 is the default JSON Schema dialect of OpenAPI 3.1 specs.
+const dialect31 = "https://spec.openapis.org/oas/3.1/dialect/base"
+
+// annotations31 are the keywords a 3.1 schema may have alongside a $ref
+// which don't change its type. They are dropped, as a 3.0 $ref ignores
+// its siblings.
+var annotations31 = map[string]bool{
+	"$comment":    true,
+	"default":     true,
+	"deprecated":  true,
+	"description": true,
+	"example":     true,
+	"examples":    true,
+	"readOnly":    true,
+	"summary":     true,
+	"title":       true,
+	"writeOnly":   true,
+}
+
+// unsupported31 are the JSON Schema 2020-12 keywords of 3.1 schemas which
+// have no OpenAPI 3.0 equivalent.
+var unsupported31 = []string{
+	"$anchor",
+	"$defs",
+	"$dynamicAnchor",
+	"$dynamicRef",
+	"$id",
+	"contains",
+	"dependentRequired",
+	"dependentSchemas",
+	"else",
+	"if",
+	"maxContains",
+	"minContains",
+	"patternProperties",
+	"prefixItems",
+	"propertyNames",
+	"then",
+	"unevaluatedItems",
+	"unevaluatedProperties",
+}
+
+// UnsupportedError lists the features of an OpenAPI 3.1 spec which can't be
+// converted to OpenAPI 3.0, prefixed by the JSON pointer of the part of the
+// spec they were found in.
+type UnsupportedError struct {
+	Features []string
+}
+
+// Error implements error.
+func (e *UnsupportedError) Error() string {
+	if len(e.Features) == 1 {
+		return "unsupported OpenAPI 3.1 feature: " + e.Features[0]
+	}
+	msgs := make([]string, len(e.Features))
+	for i, f := range e.Features {
+		msgs[i] = "\t" + f
+	}
+	return fmt.Sprintf("%d unsupported OpenAPI 3.1 features:\n%s", len(e.Features), strings.Join(msgs, "\n"))
+}
+
+// ConvertOpenAPI31 converts data, a JSON or YAML spec, to OpenAPI 3.0 if it
+// is an OpenAPI 3.1 spec, and returns other specs unchanged. kin-openapi
+// only reads 3.0 specs, and would otherwise fail on, or silently drop, the
+// JSON Schema 2020-12 keywords of 3.1 schemas.
+//
+// The schemas are converted to their 3.0 equivalents:
+//   - a type of [T, "null"] is a type of T which is nullable, as is an anyOf
+//     or oneOf of a schema and {type: "null"}
+//   - const is an enum of a single value
+//   - the first of the examples of a schema is its example
+//   - a numeric exclusiveMinimum or exclusiveMaximum is a minimum or maximum
+//     which is exclusive
+//   - a contentEncoding of base64 is a format of byte, and a contentMediaType
+//     of a string is a format of binary
+//   - the annotations alongside a $ref, such as its description, are dropped
+//
+// The webhooks of the spec are moved to its x-webhooks extension, which the
+// generator reads them from. The features without an equivalent, such as
+// multiple types or the keywords of unsupported31, are all reported in an
+// *UnsupportedError.
+// The references to other files are loaded as 3.0 documents.
+func ConvertOpenAPI31(data []byte) ([]byte, error) {
+	var version struct {
+		OpenAPI string `json:"openapi"`
+	}
+	if err := specyaml.Unmarshal(data, &version); err != nil {
+		return nil, err
+	}
+	if version.OpenAPI != "3.1" && !strings.HasPrefix(version.OpenAPI, "3.1.") {
+		return data, nil
+	}
+
+	var doc map[string]interface{}
+	if err := specyaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	c := &converter31{}
+	c.document(doc)
+	if len(c.unsupported) != 0 {
+		return nil, &UnsupportedError{Features: c.unsupported}
+	}
+	return json.Marshal(doc)
+}
+
+// converter31 holds the state of ConvertOpenAPI31.
+type converter31 struct {
+	unsupported []string
+}
+
+// fail records an unsupported feature found at ptr.
+func (c *converter31) fail(ptr, format string, args ...interface{}) {
+	c.unsupported = append(c.unsupported, ptr+": "+fmt.Sprintf(format, args...))
+}
+
+// document converts the root of a spec.
+func (c *converter31) document(doc map[string]interface{}) {
+	doc["openapi"] = "3.0.3"
+
+	if dialect, ok := doc["jsonSchemaDialect"]; ok {
+		if dialect != dialect31 {
+			c.fail("#/jsonSchemaDialect", "dialect %v", dialect)
+		}
+		delete(doc, "jsonSchemaDialect")
+	}
+	if _, ok := doc["webhooks"]; ok {
+		if _, ok := doc["x-webhooks"]; ok {
+			c.fail("#/webhooks", "webhooks alongside x-webhooks")
+		}
+	}
+	if _, ok := doc["paths"]; !ok {
+		// Paths are optional in 3.1.
+		doc["paths"] = map[string]interface{}{}
+	}
+
+	for _, k := range sortedKeys(doc) {
+		ptr := "#/" + jsonPointerEscaper.Replace(k)
+		if k != "components" {
+			c.walk(ptr, doc[k])
+			continue
+		}
+		components, _ := doc[k].(map[string]interface{})
+		for _, ck := range sortedKeys(components) {
+			cptr := ptr + "/" + jsonPointerEscaper.Replace(ck)
+			switch ck {
+			case "schemas":
+				schemas, _ := components[ck].(map[string]interface{})
+				for _, name := range sortedKeys(schemas) {
+					c.schema(cptr+"/"+jsonPointerEscaper.Replace(name), schemas[name])
+				}
+			case "pathItems":
+				c.fail(cptr, "path items components")
+			default:
+				c.walk(cptr, components[ck])
+			}
+		}
+	}
+
+	if webhooks, ok := doc["webhooks"]; ok {
+		doc["x-webhooks"] = webhooks
+		delete(doc, "webhooks")
+	}
+}
+
+// walk converts the schemas of the parameters, headers and media types
+// within v, which isn't a schema.
+func (c *converter31) walk(ptr string, v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(v) {
+			kptr := ptr + "/" + jsonPointerEscaper.Replace(k)
+			switch {
+			case k == "schema":
+				c.schema(kptr, v[k])
+			case k == "example" || k == "examples" || strings.HasPrefix(k, "x-"):
+				// Values which may look like parts of the spec.
+			default:
+				c.walk(kptr, v[k])
+			}
+		}
+	case []interface{}:
+		for i, e := range v {
+			c.walk(fmt.Sprintf("%s/%d", ptr, i), e)
+		}
+	}
+}
+
+// schema converts the schema v, and the schemas nested in it.
+func (c *converter31) schema(ptr string, v interface{}) {
+	s, ok := v.(map[string]interface{})
+	if !ok {
+		if b, ok := v.(bool); ok {
+			c.fail(ptr, "boolean schema %t", b)
+		}
+		return
+	}
+
+	if _, ok := s["$ref"]; ok {
+		for _, k := range sortedKeys(s) {
+			switch {
+			case k == "$ref":
+			case annotations31[k]:
+				delete(s, k)
+			default:
+				c.fail(ptr, "%s alongside $ref", k)
+			}
+		}
+		return
+	}
+
+	c.nullableUnion(ptr, s, "anyOf")
+	c.nullableUnion(ptr, s, "oneOf")
+	for _, k := range unsupported31 {
+		if _, ok := s[k]; ok {
+			c.fail(ptr, "%s", k)
+		}
+	}
+	if dialect, ok := s["$schema"]; ok {
+		if dialect != dialect31 {
+			c.fail(ptr, "dialect %v", dialect)
+		}
+		delete(s, "$schema")
+	}
+	delete(s, "$comment")
+
+	c.types(ptr, s)
+
+	if value, ok := s["const"]; ok {
+		if _, ok := s["enum"]; !ok {
+			s["enum"] = []interface{}{value}
+		}
+		delete(s, "const")
+	}
+	if examples, ok := s["examples"].([]interface{}); ok {
+		if _, ok := s["example"]; !ok && len(examples) != 0 {
+			s["example"] = examples[0]
+		}
+		delete(s, "examples")
+	}
+	for _, k := range [...]string{"Minimum", "Maximum"} {
+		exclusive := "exclusive" + k
+		if value, ok := s[exclusive].(float64); ok {
+			s[strings.ToLower(k)] = value
+			s[exclusive] = true
+		}
+	}
+	if encoding, ok := s["contentEncoding"]; ok {
+		if encoding == "base64" {
+			if _, ok := s["format"]; !ok {
+				s["format"] = "byte"
+			}
+		} else {
+			c.fail(ptr, "contentEncoding %v", encoding)
+		}
+		delete(s, "contentEncoding")
+	}
+	if _, ok := s["contentMediaType"]; ok {
+		if _, ok := s["format"]; !ok && s["type"] == "string" {
+			s["format"] = "binary"
+		}
+		delete(s, "contentMediaType")
+	}
+
+	if props, ok := s["properties"].(map[string]interface{}); ok {
+		for _, name := range sortedKeys(props) {
+			c.schema(ptr+"/properties/"+jsonPointerEscaper.Replace(name), props[name])
+		}
+	}
+	for _, k := range [...]string{"items", "additionalProperties", "not"} {
+		if _, ok := s[k].(map[string]interface{}); ok {
+			c.schema(ptr+"/"+k, s[k])
+		}
+	}
+	for _, k := range [...]string{"allOf", "anyOf", "oneOf"} {
+		schemas, _ := s[k].([]interface{})
+		for i, e := range schemas {
+			c.schema(fmt.Sprintf("%s/%s/%d", ptr, k, i), e)
+		}
+	}
+}
+
+// types converts a type of s listing several types to a single one, which
+// is nullable if one of them was null.
+func (c *converter31) types(ptr string, s map[string]interface{}) {
+	var types []interface{}
+	switch t := s["type"].(type) {
+	case []interface{}:
+		types = t
+	case string:
+		types = []interface{}{t}
+	default:
+		return
+	}
+
+	var nonNull []interface{}
+	for _, t := range types {
+		if t == "null" {
+			s["nullable"] = true
+		} else {
+			nonNull = append(nonNull, t)
+		}
+	}
+	switch len(nonNull) {
+	case 0:
+		c.fail(ptr, "type null")
+	case 1:
+		s["type"] = nonNull[0]
+	default:
+		c.fail(ptr, "multiple types %v", nonNull)
+	}
+}
+
+// nullableUnion removes the {type: "null"} variants of the anyOf or oneOf of
+// s, named by k, making s nullable instead. A single variant left is merged
+// into s, in an allOf if it's a reference, before s itself is converted.
+func (c *converter31) nullableUnion(ptr string, s map[string]interface{}, k string) {
+	variants, ok := s[k].([]interface{})
+	if !ok {
+		return
+	}
+
+	var nonNull []interface{}
+	for _, v := range variants {
+		if m, ok := v.(map[string]interface{}); ok && len(m) == 1 && m["type"] == "null" {
+			continue
+		}
+		nonNull = append(nonNull, v)
+	}
+	if len(nonNull) == len(variants) {
+		return
+	}
+	s["nullable"] = true
+
+	if len(nonNull) != 1 {
+		s[k] = nonNull
+		return
+	}
+	variant, ok := nonNull[0].(map[string]interface{})
+	if !ok {
+		s[k] = nonNull
+		return
+	}
+	delete(s, k)
+	if _, ok := variant["$ref"]; ok {
+		if _, ok := s["allOf"]; ok {
+			c.fail(ptr, "allOf alongside a nullable %s", k)
+			return
+		}
+		s["allOf"] = []interface{}{variant}
+		return
+	}
+	for vk, value := range variant {
+		if _, ok := s[vk]; ok {
+			c.fail(ptr, "%s in %s and alongside it", vk, k)
+			continue
+		}
+		s[vk] = value
+	}
+}
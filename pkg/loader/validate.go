@@ -0,0 +1,236 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Options to customize LoadAndValidateSwaggerWithOptions.
+type Options struct {
+	// Dereference clears the $ref of every reference of the spec, see
+	// Dereference.
+	Dereference bool
+}
+
+// LoadAndValidateSwagger loads the spec in the file at path, resolving its
+// external references relative to the file, and validates it. Unlike
+// openapi3.T.Validate, which stops at the first error, every invalid part of
+// the spec is reported, in a *ValidationError.
+func LoadAndValidateSwagger(path string) (*openapi3.T, error) {
+	return LoadAndValidateSwaggerWithOptions(path, nil)
+}
+
+// LoadAndValidateSwaggerWithOptions is LoadAndValidateSwagger, customized by
+// options.
+func LoadAndValidateSwaggerWithOptions(path string, options *Options) (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+
+	swagger, err := loader.LoadFromFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not load %s: %w", path, err)
+	}
+	if err := Validate(context.Background(), swagger); err != nil {
+		return nil, err
+	}
+
+	if options != nil && options.Dereference {
+		Dereference(swagger)
+	}
+	return swagger, nil
+}
+
+// ValidationError lists the errors of an invalid spec, prefixed by the JSON
+// pointer of the part of the spec they were found in.
+type ValidationError struct {
+	Errors []error
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	if len(e.Errors) == 1 {
+		return "invalid spec: " + e.Errors[0].Error()
+	}
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = "\t" + err.Error()
+	}
+	return fmt.Sprintf("invalid spec, %d errors:\n%s", len(e.Errors), strings.Join(msgs, "\n"))
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// Validate runs the checks of openapi3.T.Validate on each part of swagger in
+// turn, and returns a *ValidationError listing all of their errors, if any.
+func Validate(ctx context.Context, swagger *openapi3.T) error {
+	var errs []error
+	check := func(location string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", location, err))
+		}
+	}
+
+	if swagger.OpenAPI == "" {
+		check("#/openapi", errors.New("value of openapi must be a non-empty string"))
+	}
+
+	c := swagger.Components
+	for _, name := range sortedKeys(c.Schemas) {
+		check("#/components/schemas/"+jsonPointerEscaper.Replace(name), validateComponent(ctx, name, c.Schemas[name]))
+	}
+	for _, name := range sortedKeys(c.Parameters) {
+		check("#/components/parameters/"+jsonPointerEscaper.Replace(name), validateComponent(ctx, name, c.Parameters[name]))
+	}
+	for _, name := range sortedKeys(c.RequestBodies) {
+		check("#/components/requestBodies/"+jsonPointerEscaper.Replace(name), validateComponent(ctx, name, c.RequestBodies[name]))
+	}
+	for _, name := range sortedKeys(c.Responses) {
+		check("#/components/responses/"+jsonPointerEscaper.Replace(name), validateComponent(ctx, name, c.Responses[name]))
+	}
+	for _, name := range sortedKeys(c.Headers) {
+		check("#/components/headers/"+jsonPointerEscaper.Replace(name), validateComponent(ctx, name, c.Headers[name]))
+	}
+	for _, name := range sortedKeys(c.SecuritySchemes) {
+		check("#/components/securitySchemes/"+jsonPointerEscaper.Replace(name), validateComponent(ctx, name, c.SecuritySchemes[name]))
+	}
+
+	if swagger.Info == nil {
+		check("#/info", errors.New("must be an object"))
+	} else {
+		check("#/info", swagger.Info.Validate(ctx))
+	}
+
+	if swagger.Paths == nil {
+		check("#/paths", errors.New("must be an object"))
+	}
+	for _, path := range sortedKeys(swagger.Paths) {
+		paths := openapi3.Paths{path: swagger.Paths[path]}
+		check("#/paths/"+jsonPointerEscaper.Replace(path), paths.Validate(ctx))
+		swagger.Paths[path] = paths[path]
+	}
+
+	if swagger.Security != nil {
+		check("#/security", swagger.Security.Validate(ctx))
+	}
+	for i, server := range swagger.Servers {
+		if server != nil {
+			check(fmt.Sprintf("#/servers/%d", i), server.Validate(ctx))
+		}
+	}
+
+	if len(errs) != 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// validateComponent checks the name of a component, and its value.
+func validateComponent(ctx context.Context, name string, ref interface{ Validate(context.Context) error }) error {
+	if err := openapi3.ValidateIdentifier(name); err != nil {
+		return err
+	}
+	return ref.Validate(ctx)
+}
+
+// sortedKeys returns the sorted keys of m, a map with string keys.
+func sortedKeys(m interface{}) []string {
+	v := reflect.ValueOf(m)
+	keys := make([]string, 0, v.Len())
+	for _, k := range v.MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Dereference clears the $ref of every reference of swagger, whose values
+// are already resolved, so that downstream code sees a single document
+// without references: a parameter or schema defined once and referenced
+// twice is then repeated in both places when swagger is serialized. The
+// components are kept. References which are part of a cycle, such as the
+// children of a recursive tree schema, are kept as well, since they can't be
+// inlined.
+func Dereference(swagger *openapi3.T) {
+	d := dereferencer{
+		done:   map[uintptr]bool{},
+		active: map[uintptr]bool{},
+	}
+	for _, p := range swagger.Paths {
+		// The items of other files are copied into the spec when loaded.
+		p.Ref = ""
+	}
+	d.walk(reflect.ValueOf(swagger))
+}
+
+// dereferencer holds the state of Dereference.
+type dereferencer struct {
+	done   map[uintptr]bool // Values which were walked entirely
+	active map[uintptr]bool // Values being walked, which are cycles if reached again
+}
+
+// walk clears the references within v, and v itself if it's one of the
+// openapi3 references, a struct with a Ref and a Value.
+func (d *dereferencer) walk(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		if ref := v.Elem(); ref.Kind() == reflect.Struct && isRef(ref.Type()) {
+			value := ref.FieldByName("Value")
+			if value.IsNil() || d.active[value.Pointer()] {
+				return
+			}
+			ref.FieldByName("Ref").SetString("")
+			d.walk(value)
+			return
+		}
+
+		p := v.Pointer()
+		if d.done[p] || d.active[p] {
+			return
+		}
+		d.active[p] = true
+		d.walk(v.Elem())
+		delete(d.active, p)
+		d.done[p] = true
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				d.walk(v.Field(i))
+			}
+		}
+
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			d.walk(iter.Value())
+		}
+
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			d.walk(v.Index(i))
+		}
+	}
+}
+
+// isRef returns if t is one of the reference types of openapi3, such as
+// openapi3.SchemaRef.
+func isRef(t reflect.Type) bool {
+	if t.PkgPath() != reflect.TypeOf(openapi3.T{}).PkgPath() || t.NumField() != 2 {
+		return false
+	}
+	ref, ok := t.FieldByName("Ref")
+	if !ok || ref.Type.Kind() != reflect.String {
+		return false
+	}
+	value, ok := t.FieldByName("Value")
+	return ok && value.Type.Kind() == reflect.Ptr
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// CORSOptions configures the CORS headers of OapiRequestValidatorWithCORS.
+type CORSOptions struct {
+	// AllowedOrigins lists the origins allowed to make requests, "*" allowing
+	// any of them. Empty means any origin is allowed.
+	AllowedOrigins []string
+	// AllowedHeaders lists the headers which preflight requests may ask for.
+	// Empty means the requested headers are allowed.
+	AllowedHeaders []string
+	// AllowCredentials lets requests of other origins include credentials,
+	// like cookies. The origin of requests is then allowed rather than "*".
+	AllowCredentials bool
+	// MaxAge is the number of seconds the response to a preflight request
+	// may be cached for. Zero leaves it to the browser.
+	MaxAge int
+}
+
+// OapiRequestValidatorWithCORS creates middleware to validate request by
+// swagger spec, like OapiRequestValidatorWithOptions, which answers CORS
+// preflight requests. Preflight requests aren't validated, nor passed to
+// the next handler: they are answered with an HTTP/200 and the CORS headers
+// of cors if the spec has an operation for the requested method and path,
+// and an HTTP/404 otherwise. Requests of other origins are validated as
+// usual, with the CORS headers set on their response.
+func OapiRequestValidatorWithCORS(swagger *openapi3.T, cors CORSOptions, options *Options) func(next http.Handler) http.Handler {
+	var opts Options
+	if options != nil {
+		opts = *options
+	}
+	opts.CORS = &cors
+	return requestValidator(swagger, &opts, nil)
+}
+
+// isPreflight returns whether r is a CORS preflight request, when
+// options.CORS is set.
+func isPreflight(r *http.Request, options *Options) bool {
+	return options != nil && options.CORS != nil &&
+		r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// findPreflightRoute returns the route of the method the preflight request r
+// asks for, failing with an HTTP/404 if the spec has none, or with an
+// HTTP/403 if the origin of r isn't allowed, along with the status code to
+// fail with.
+func findPreflightRoute(r *http.Request, router routers.Router, cors *CORSOptions) (*routers.Route, map[string]string, int, error) {
+	origin := r.Header.Get("Origin")
+	if !cors.allowsOrigin(origin) {
+		return nil, nil, http.StatusForbidden, fmt.Errorf("origin %s isn't allowed", origin)
+	}
+
+	method := strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))
+	requested := r.Clone(r.Context())
+	requested.Method = method
+	route, pathParams, err := router.FindRoute(requested)
+	if err != nil {
+		if errors.Is(err, routers.ErrMethodNotAllowed) {
+			err = fmt.Errorf("method %s isn't allowed", method)
+		}
+		return nil, nil, http.StatusNotFound, err
+	}
+	return route, pathParams, 0, nil
+}
+
+// allowsOrigin returns whether requests of origin are allowed.
+func (cors *CORSOptions) allowsOrigin(origin string) bool {
+	if len(cors.AllowedOrigins) == 0 {
+		return true
+	}
+	for _, allowed := range cors.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// setOrigin sets the Access-Control-Allow-Origin header of the response to
+// r, if r has an allowed Origin.
+func (cors *CORSOptions) setOrigin(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" || !cors.allowsOrigin(origin) {
+		return
+	}
+	h := w.Header()
+	h.Add("Vary", "Origin")
+	if cors.AllowCredentials {
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Set("Access-Control-Allow-Credentials", "true")
+		return
+	}
+	if len(cors.AllowedOrigins) == 0 || cors.allowsOrigin("*") {
+		h.Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	h.Set("Access-Control-Allow-Origin", origin)
+}
+
+// writePreflight answers the preflight request r for route, allowing the
+// methods of its path.
+func (cors *CORSOptions) writePreflight(w http.ResponseWriter, r *http.Request, route *routers.Route) {
+	h := w.Header()
+	methods := make([]string, 0, 8)
+	for method := range route.PathItem.Operations() {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+
+	headers := r.Header.Get("Access-Control-Request-Headers")
+	if len(cors.AllowedHeaders) > 0 {
+		headers = strings.Join(cors.AllowedHeaders, ", ")
+	}
+	if headers != "" {
+		h.Set("Access-Control-Allow-Headers", headers)
+	}
+	if cors.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(cors.MaxAge))
+	}
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+	w.WriteHeader(http.StatusOK)
+}
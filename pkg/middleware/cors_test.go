@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOapiRequestValidatorWithCORS(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	called := false
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorWithCORS(swagger, CORSOptions{
+		AllowedOrigins: []string{"https://app.example.com"},
+		MaxAge:         600,
+	}, nil))
+	r.Get("/resource", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+	r.Post("/resource", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	preflight := func(origin, method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "http://example.com"+path, nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", method)
+		req.Header.Set("Access-Control-Request-Headers", "Content-Type, Authorization")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("preflight", func(t *testing.T) {
+		called = false
+		// The body of the POST isn't there to be validated.
+		rec := preflight("https://app.example.com", http.MethodPost, "/resource")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.False(t, called)
+		assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET, POST", rec.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Content-Type, Authorization", rec.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("preflight skips security", func(t *testing.T) {
+		rec := preflight("https://app.example.com", http.MethodGet, "/protected_resource")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "GET", rec.Header().Get("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("preflight of unknown routes", func(t *testing.T) {
+		rec := preflight("https://app.example.com", http.MethodGet, "/missing")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Methods"))
+
+		rec = preflight("https://app.example.com", http.MethodDelete, "/resource")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("preflight of other origins", func(t *testing.T) {
+		rec := preflight("https://evil.example.com", http.MethodGet, "/resource")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("requests are validated", func(t *testing.T) {
+		called = false
+		req := httptest.NewRequest(http.MethodPost, "http://example.com/resource", strings.NewReader(`{"name": 1}`))
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.False(t, called)
+		// Browsers can read the error.
+		assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+
+		req = httptest.NewRequest(http.MethodGet, "http://example.com/resource?id=50", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		rec = httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.True(t, called)
+		assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("OPTIONS without CORS", func(t *testing.T) {
+		// Without options.CORS, preflight requests are validated as any other.
+		r := chi.NewRouter()
+		r.Use(OapiRequestValidator(swagger))
+		req := httptest.NewRequest(http.MethodOptions, "http://example.com/resource", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.NotEqual(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestCORSOptionsOrigin(t *testing.T) {
+	tests := []struct {
+		name string
+		cors CORSOptions
+		want string
+	}{
+		{"any", CORSOptions{}, "*"},
+		{"wildcard", CORSOptions{AllowedOrigins: []string{"*"}}, "*"},
+		{"listed", CORSOptions{AllowedOrigins: []string{"https://app.example.com"}}, "https://app.example.com"},
+		{"not listed", CORSOptions{AllowedOrigins: []string{"https://other.example.com"}}, ""},
+		{"credentials", CORSOptions{AllowCredentials: true}, "https://app.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/resource", nil)
+			req.Header.Set("Origin", "https://app.example.com")
+			rec := httptest.NewRecorder()
+			tt.cors.setOrigin(rec, req)
+			assert.Equal(t, tt.want, rec.Header().Get("Access-Control-Allow-Origin"))
+		})
+	}
+}
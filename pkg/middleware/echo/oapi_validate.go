@@ -0,0 +1,25 @@
+// Package echo implements the request validation middleware for Echo.
+// Validation is delegated to the net/http middleware, so requests are
+// validated exactly as they would be with go-chi or net/http.
+package echo
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+
+	"github.com/discord-gophers/goapi-gen/pkg/middleware"
+)
+
+// Options to customize request validation. It is the same type used by the
+// net/http middleware.
+type Options = middleware.Options
+
+// OapiRequestValidator Creates middleware to validate request by swagger spec.
+func OapiRequestValidator(swagger *openapi3.T) echo.MiddlewareFunc {
+	return OapiRequestValidatorWithOptions(swagger, nil)
+}
+
+// OapiRequestValidatorWithOptions Creates middleware to validate request by swagger spec.
+func OapiRequestValidatorWithOptions(swagger *openapi3.T, options *Options) echo.MiddlewareFunc {
+	return echo.WrapMiddleware(middleware.OapiRequestValidatorWithOptions(swagger, options))
+}
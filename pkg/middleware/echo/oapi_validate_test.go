@@ -0,0 +1,101 @@
+package echo
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = `openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /resource:
+    get:
+      operationId: getResource
+      parameters:
+        - name: id
+          in: query
+          schema:
+            type: integer
+            minimum: 10
+            maximum: 100
+      responses:
+        '200':
+            description: success
+    post:
+      operationId: createResource
+      responses:
+        '204':
+          description: No content
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              properties:
+                name:
+                  type: string
+`
+
+func TestOapiRequestValidator(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	e := echo.New()
+	e.Use(OapiRequestValidator(swagger))
+
+	called := false
+	e.GET("/resource", func(c echo.Context) error {
+		called = true
+		return c.NoContent(http.StatusOK)
+	})
+	e.POST("/resource", func(c echo.Context) error {
+		called = true
+		// The body which was validated can be read again.
+		body, err := io.ReadAll(c.Request().Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"name":"Marcin"}`, string(body))
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name       string
+		method     string
+		url        string
+		body       string
+		wantStatus int
+	}{
+		{"wrong server", http.MethodGet, "http://not.example.com/resource", "", http.StatusNotFound},
+		{"wrong method", http.MethodDelete, "http://example.com/resource", "", http.StatusMethodNotAllowed},
+		{"good request", http.MethodGet, "http://example.com/resource", "", http.StatusOK},
+		{"out of spec parameter", http.MethodGet, "http://example.com/resource?id=500", "", http.StatusBadRequest},
+		{"bad parameter type", http.MethodGet, "http://example.com/resource?id=foo", "", http.StatusBadRequest},
+		{"good body", http.MethodPost, "http://example.com/resource", `{"name":"Marcin"}`, http.StatusNoContent},
+		{"malformed body", http.MethodPost, "http://example.com/resource", `{"name":7}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantStatus < 300, called)
+		})
+	}
+}
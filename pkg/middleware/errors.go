@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// ValidationError is returned when a request or response does not conform to
+// the spec. It is passed to Options.ErrorHandler, and written as the response
+// body by default.
+type ValidationError struct {
+	XMLName xml.Name `json:"-" xml:"error"`
+
+	// StatusCode is the HTTP status code matching the failure.
+	StatusCode int `json:"status" xml:"status"`
+	// Message is the short description of the failure.
+	Message string `json:"message" xml:"message"`
+	// Details holds the full, line by line, description of the failure.
+	Details []string `json:"details,omitempty" xml:"details>detail,omitempty"`
+	// Errors holds the short description of every error, when the request
+	// failed with several of them at once, with Options.Options.MultiError.
+	// Message is the first one.
+	Errors []string `json:"errors,omitempty" xml:"errors>error,omitempty"`
+	// RequestID is the ID of the request, when Options.RequestIDHeader is set.
+	RequestID string `json:"requestId,omitempty" xml:"requestId,omitempty"`
+	// Err is the error the request failed validation with, when
+	// Options.WrapErrors is set.
+	Err error `json:"-" xml:"-"`
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns e.Err, for errors.Is and errors.As.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// newValidationError creates a ValidationError from err.
+// openapi errors seem to be multi-line with a decent message on the first, so
+// the first line is used as the message and all lines are kept as details.
+// The errors of an openapi3.MultiError are described one after the other,
+// with the message of each of them in Errors.
+func newValidationError(statusCode int, err error) *ValidationError {
+	verr := &ValidationError{StatusCode: statusCode}
+	var messages []string
+	for _, err := range multiErrors(err) {
+		first := true
+		for _, line := range strings.Split(err.Error(), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				verr.Details = append(verr.Details, line)
+				if first {
+					messages = append(messages, line)
+					first = false
+				}
+			}
+		}
+	}
+
+	if len(messages) > 0 {
+		verr.Message = messages[0]
+	}
+	if len(messages) > 1 {
+		verr.Errors = messages
+	}
+	return verr
+}
+
+// multiErrors returns the errors err is made of: the errors of an
+// openapi3.MultiError, including the ones of the schema errors of a request
+// parameter or body, each as an error of the parameter or body, or err
+// itself.
+func multiErrors(err error) []error {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var errs []error
+		for _, err := range e {
+			errs = append(errs, multiErrors(err)...)
+		}
+		return errs
+	case *openapi3filter.RequestError:
+		if me, ok := e.Err.(openapi3.MultiError); ok {
+			var errs []error
+			for _, err := range multiErrors(me) {
+				re := *e
+				re.Err = err
+				errs = append(errs, &re)
+			}
+			return errs
+		}
+	}
+	return []error{err}
+}
+
+// writeError writes err to w, encoded based on the Accept header of r.
+// Clients which accept neither JSON nor XML receive a plain text message.
+func writeError(w http.ResponseWriter, r *http.Request, err *ValidationError) {
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "application/json"):
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(err.StatusCode)
+		_ = json.NewEncoder(w).Encode(err)
+	case strings.Contains(accept, "application/xml"), strings.Contains(accept, "text/xml"):
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(err.StatusCode)
+		_ = xml.NewEncoder(w).Encode(err)
+	default:
+		message := err.Message
+		if len(err.Errors) > 0 {
+			message = strings.Join(err.Errors, "\n")
+		}
+		if err.RequestID != "" {
+			message += " (request ID " + err.RequestID + ")"
+		}
+		http.Error(w, message, err.StatusCode)
+	}
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewValidationError(t *testing.T) {
+	err := newValidationError(http.StatusBadRequest, errors.New("first line\n  second line\n\n"))
+	assert.Equal(t, http.StatusBadRequest, err.StatusCode)
+	assert.Equal(t, "first line", err.Message)
+	assert.Equal(t, []string{"first line", "second line"}, err.Details)
+	assert.Equal(t, "first line", err.Error())
+}
+
+func TestValidationErrorEncoding(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidator(swagger))
+	r.Get("/resource", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		decode      func([]byte, interface{}) error
+	}{
+		{"json", "application/json", "application/json; charset=utf-8", json.Unmarshal},
+		{"xml", "application/xml", "application/xml; charset=utf-8", xml.Unmarshal},
+		{"plain text", "", "text/plain; charset=utf-8", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/resource?id=500", nil)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, tt.contentType, rec.Header().Get("Content-Type"))
+			if tt.decode == nil {
+				return
+			}
+
+			var verr ValidationError
+			require.NoError(t, tt.decode(rec.Body.Bytes(), &verr))
+			assert.Equal(t, http.StatusBadRequest, verr.StatusCode)
+			assert.NotEmpty(t, verr.Message)
+			assert.NotEmpty(t, verr.Details)
+		})
+	}
+}
+
+func TestValidationErrorHandler(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	var verr *ValidationError
+	options := Options{
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+			assert.True(t, errors.As(err, &verr))
+			w.WriteHeader(statusCode)
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorWithOptions(swagger, &options))
+	r.Get("/resource", func(w http.ResponseWriter, r *http.Request) {})
+
+	rec := doGet(t, r, "http://example.com/resource?id=500")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	require.NotNil(t, verr)
+	assert.Equal(t, http.StatusBadRequest, verr.StatusCode)
+}
@@ -0,0 +1,95 @@
+// Package fiber implements the request validation middleware for gofiber.
+// Validation is delegated to the net/http middleware, so requests are
+// validated exactly as they would be with go-chi or net/http.
+package fiber
+
+import (
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+
+	"github.com/discord-gophers/goapi-gen/pkg/middleware"
+)
+
+// Options to customize request validation. It is the same type used by the
+// net/http middleware.
+type Options = middleware.Options
+
+// OapiRequestValidator Creates middleware to validate request by swagger spec.
+func OapiRequestValidator(swagger *openapi3.T) fiber.Handler {
+	return OapiRequestValidatorWithOptions(swagger, nil)
+}
+
+// OapiRequestValidatorWithOptions Creates middleware to validate request by swagger spec.
+func OapiRequestValidatorWithOptions(swagger *openapi3.T, options *Options) fiber.Handler {
+	// The wrapped handler is only reached once the request passed validation.
+	validator := middleware.OapiRequestValidatorWithOptions(swagger, options)(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.(*responseWriter).valid = true
+		}),
+	)
+
+	return func(c *fiber.Ctx) error {
+		var r http.Request
+		if err := fasthttpadaptor.ConvertRequest(c.Context(), &r, true); err != nil {
+			return fiber.NewError(http.StatusInternalServerError, err.Error())
+		}
+
+		w := &responseWriter{ctx: c, header: make(http.Header)}
+		validator.ServeHTTP(w, &r)
+		if !w.valid {
+			return nil
+		}
+
+		// The headers the middleware set for valid requests, such as the
+		// request ID and the CORS ones, are only written with errors.
+		w.copyHeader()
+		if options != nil && options.RequestIDHeader != "" {
+			c.Request().Header.Set(options.RequestIDHeader, r.Header.Get(options.RequestIDHeader))
+		}
+		return c.Next()
+	}
+}
+
+// responseWriter implements http.ResponseWriter on top of a fiber context, so
+// errors written by the net/http middleware end up in the fiber response.
+type responseWriter struct {
+	ctx         *fiber.Ctx
+	header      http.Header
+	wroteHeader bool
+
+	valid bool
+}
+
+// Header implements http.ResponseWriter.
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.copyHeader()
+	w.ctx.Status(statusCode)
+}
+
+// copyHeader adds the headers of w to the fiber response.
+func (w *responseWriter) copyHeader() {
+	for k, vv := range w.header {
+		for _, v := range vv {
+			w.ctx.Response().Header.Add(k, v)
+		}
+	}
+}
+
+// Write implements http.ResponseWriter.
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	w.ctx.Response().AppendBody(b)
+	return len(b), nil
+}
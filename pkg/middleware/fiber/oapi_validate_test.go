@@ -0,0 +1,184 @@
+package fiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/discord-gophers/goapi-gen/pkg/middleware"
+)
+
+var testSchema = `openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /resource:
+    get:
+      operationId: getResource
+      parameters:
+        - name: id
+          in: query
+          schema:
+            type: integer
+            minimum: 10
+            maximum: 100
+      responses:
+        '200':
+            description: success
+    post:
+      operationId: createResource
+      responses:
+        '204':
+          description: No content
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              properties:
+                name:
+                  type: string
+`
+
+func TestOapiRequestValidator(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	app := fiber.New()
+	app.Use(OapiRequestValidator(swagger))
+
+	called := false
+	app.Get("/resource", func(c *fiber.Ctx) error {
+		called = true
+		return c.SendStatus(http.StatusOK)
+	})
+	app.Post("/resource", func(c *fiber.Ctx) error {
+		called = true
+		return c.SendStatus(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name       string
+		method     string
+		url        string
+		body       string
+		wantStatus int
+	}{
+		{"wrong server", http.MethodGet, "http://not.example.com/resource", "", http.StatusNotFound},
+		{"wrong method", http.MethodDelete, "http://example.com/resource", "", http.StatusMethodNotAllowed},
+		{"good request", http.MethodGet, "http://example.com/resource", "", http.StatusOK},
+		{"out of spec parameter", http.MethodGet, "http://example.com/resource?id=500", "", http.StatusBadRequest},
+		{"bad parameter type", http.MethodGet, "http://example.com/resource?id=foo", "", http.StatusBadRequest},
+		{"good body", http.MethodPost, "http://example.com/resource", `{"name":"Marcin"}`, http.StatusNoContent},
+		{"malformed body", http.MethodPost, "http://example.com/resource", `{"name":7}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+
+			res, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, res.StatusCode)
+			assert.Equal(t, tt.wantStatus < 300, called)
+		})
+	}
+}
+
+func TestOapiRequestValidatorRequestID(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	app := fiber.New()
+	app.Use(OapiRequestValidatorWithOptions(swagger, &Options{RequestIDHeader: "X-Request-ID"}))
+
+	var handlerID string
+	app.Get("/resource", func(c *fiber.Ctx) error {
+		handlerID = c.Get("X-Request-ID")
+		return c.SendStatus(http.StatusOK)
+	})
+
+	t.Run("generated ID", func(t *testing.T) {
+		res, err := app.Test(httptest.NewRequest(http.MethodGet, "http://example.com/resource", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		id := res.Header.Get("X-Request-ID")
+		assert.NotEmpty(t, id)
+		// The handler sees the ID the request was given.
+		assert.Equal(t, id, handlerID)
+	})
+
+	t.Run("client ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/resource", nil)
+		req.Header.Set("X-Request-ID", "abc-123")
+		res, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "abc-123", res.Header.Get("X-Request-ID"))
+		assert.Equal(t, "abc-123", handlerID)
+	})
+}
+
+func TestOapiRequestValidatorCORS(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	app := fiber.New()
+	app.Use(OapiRequestValidatorWithOptions(swagger, &Options{CORS: &middleware.CORSOptions{
+		AllowedOrigins: []string{"https://app.example.com"},
+	}}))
+	app.Get("/resource", func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+
+	t.Run("valid request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/resource", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		res, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "https://app.example.com", res.Header.Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "Origin", res.Header.Get("Vary"))
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/resource?id=500", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		res, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, "https://app.example.com", res.Header.Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("preflight", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "http://example.com/resource", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		res, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "GET, POST", res.Header.Get("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("other origin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/resource", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		res, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Empty(t, res.Header.Get("Access-Control-Allow-Origin"))
+	})
+}
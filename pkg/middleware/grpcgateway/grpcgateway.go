@@ -0,0 +1,70 @@
+// Package grpcgateway implements the request validation middleware for the
+// runtime.ServeMux of grpc-gateway, which transcodes HTTP requests to gRPC
+// calls. Requests are validated before they are transcoded, and the errors of
+// the gRPC calls, such as an InvalidArgument status for a request the service
+// rejects, are written in the same format as validation errors, with the HTTP
+// status matching their gRPC status code.
+//
+//	mux := runtime.NewServeMux(grpcgateway.WithErrorHandler(options))
+//	pb.RegisterPetStoreHandlerServer(ctx, mux, server)
+//	http.ListenAndServe(":8080", grpcgateway.OapiRequestValidatorWithOptions(swagger, options)(mux))
+//
+// The validation wraps the runtime.ServeMux, rather than being one of its
+// options, since runtime.WithMiddlewares was only added after grpc-gateway
+// v2.18.0, the version this module requires, and a wrapper works with any
+// runtime.ServeMux, whichever its version.
+package grpcgateway
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/status"
+
+	"github.com/discord-gophers/goapi-gen/pkg/middleware"
+)
+
+// Options to customize request validation. It is the same type used by the
+// net/http middleware.
+type Options = middleware.Options
+
+// OapiRequestValidator creates middleware to validate requests by swagger
+// spec, before they are served by a runtime.ServeMux.
+func OapiRequestValidator(swagger *openapi3.T) func(next http.Handler) http.Handler {
+	return OapiRequestValidatorWithOptions(swagger, nil)
+}
+
+// OapiRequestValidatorWithOptions creates middleware to validate requests by
+// swagger spec, before they are served by a runtime.ServeMux.
+func OapiRequestValidatorWithOptions(swagger *openapi3.T, options *Options) func(next http.Handler) http.Handler {
+	return middleware.OapiRequestValidatorWithOptions(swagger, options)
+}
+
+// WithErrorHandler returns a runtime.ServeMuxOption writing the errors of
+// the gRPC calls of the runtime.ServeMux as a middleware.ValidationError,
+// with options.ErrorHandler if it is set. The status code is the one
+// grpc-gateway maps the gRPC status code to, eg. an HTTP/400 for
+// InvalidArgument, or an HTTP/404 for NotFound. Errors which aren't a gRPC
+// status are an HTTP/500.
+func WithErrorHandler(options *Options) runtime.ServeMuxOption {
+	return runtime.WithErrorHandler(func(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+		middleware.WriteError(w, r, options, statusError(r, options, err))
+	})
+}
+
+// statusError converts err, returned by a gRPC call for r, to a
+// ValidationError.
+func statusError(r *http.Request, options *Options, err error) *middleware.ValidationError {
+	st := status.Convert(err)
+	e := &middleware.ValidationError{
+		StatusCode: runtime.HTTPStatusFromCode(st.Code()),
+		Message:    st.Message(),
+		Details:    []string{st.Code().String() + ": " + st.Message()},
+	}
+	if options != nil && options.RequestIDHeader != "" {
+		e.RequestID = r.Header.Get(options.RequestIDHeader)
+	}
+	return e
+}
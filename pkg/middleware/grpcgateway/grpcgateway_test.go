@@ -0,0 +1,99 @@
+package grpcgateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/discord-gophers/goapi-gen/pkg/middleware"
+)
+
+const testSchema = `openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /v1/pets:
+    post:
+      operationId: addPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+      responses:
+        '200':
+          description: The pet
+`
+
+func TestOapiRequestValidator(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	options := &Options{RequestIDHeader: "X-Request-ID"}
+	mux := runtime.NewServeMux(WithErrorHandler(options))
+	err = mux.HandlePath(http.MethodPost, "/v1/pets", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		var pet struct{ Name string }
+		_ = json.NewDecoder(r.Body).Decode(&pet)
+		// The service rejects what the spec doesn't describe.
+		if pet.Name == "taken" {
+			runtime.HTTPError(r.Context(), mux, &runtime.JSONPb{}, w, r, status.Error(codes.AlreadyExists, "pet taken already exists"))
+			return
+		}
+		if pet.Name == "" {
+			runtime.HTTPError(r.Context(), mux, &runtime.JSONPb{}, w, r, status.Error(codes.InvalidArgument, "name must not be empty"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	require.NoError(t, err)
+	h := OapiRequestValidatorWithOptions(swagger, options)(mux)
+
+	tests := []struct {
+		name        string
+		body        string
+		wantStatus  int
+		wantMessage string
+	}{
+		{"valid", `{"name":"Rex"}`, http.StatusOK, ""},
+		{"invalid body", `{"name":7}`, http.StatusBadRequest, "request body has an error"},
+		{"invalid argument", `{"name":""}`, http.StatusBadRequest, "name must not be empty"},
+		{"already exists", `{"name":"taken"}`, http.StatusConflict, "pet taken already exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/v1/pets", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept", "application/json")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantStatus == http.StatusOK {
+				return
+			}
+
+			var verr middleware.ValidationError
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &verr))
+			assert.Equal(t, tt.wantStatus, verr.StatusCode)
+			assert.Contains(t, verr.Message, tt.wantMessage)
+			assert.NotEmpty(t, verr.RequestID)
+			assert.Equal(t, rec.Header().Get("X-Request-ID"), verr.RequestID)
+		})
+	}
+}
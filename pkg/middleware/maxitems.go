@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// checkMaxItems counts the elements of the JSON array in the body of r, as it
+// is read, and fails, for an HTTP/400, as soon as there are more than the
+// maxItems of its schema, before ValidateRequest decodes the whole body. The
+// elements themselves are skipped token by token, without being decoded.
+// Bodies which aren't a JSON array, or whose schema has no maxItems, are left
+// to ValidateRequest, as are syntax errors. The body is replaced so that it
+// can be read again.
+func checkMaxItems(r *http.Request, route *routers.Route) error {
+	maxItems := requestMaxItems(r, route)
+	if maxItems == nil || r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+
+	var read bytes.Buffer
+	body := r.Body
+	exceeded := exceedsItems(json.NewDecoder(io.TeeReader(body, &read)), *maxItems)
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(&read, body), body}
+
+	if exceeded {
+		return fmt.Errorf("request body has more than %d items", *maxItems)
+	}
+	return nil
+}
+
+// requestMaxItems returns the maxItems of the schema of the JSON request body
+// of r for route, or nil if it has none.
+func requestMaxItems(r *http.Request, route *routers.Route) *uint64 {
+	if route.Operation == nil || route.Operation.RequestBody == nil || route.Operation.RequestBody.Value == nil {
+		return nil
+	}
+	contentType := r.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+		return nil
+	}
+
+	content := route.Operation.RequestBody.Value.Content
+	mt := content.Get(contentType)
+	if mt == nil {
+		mt = content.Get(mediaType)
+	}
+	if mt == nil || mt.Schema == nil || mt.Schema.Value == nil {
+		return nil
+	}
+	return mt.Schema.Value.MaxItems
+}
+
+// exceedsItems reports whether dec holds an array of more than maxItems
+// elements. It stops reading at the first element past maxItems.
+func exceedsItems(dec *json.Decoder, maxItems uint64) bool {
+	tok, err := dec.Token()
+	if err != nil || tok != json.Delim('[') {
+		return false
+	}
+
+	var n uint64
+	for dec.More() {
+		n++
+		if n > maxItems {
+			return true
+		}
+		if err := skipValue(dec); err != nil {
+			return false
+		}
+	}
+	return false
+}
+
+// skipValue reads the next value of dec, and the values nested in it, as
+// tokens.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('['), json.Delim('{'):
+			depth++
+		case json.Delim(']'), json.Delim('}'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+func init() {
+	openapi3filter.RegisterBodyDecoder("multipart/form-data", multipartBodyDecoder)
+}
+
+// multipartBodyDecoder decodes multipart/form-data request bodies for
+// validation. Unlike the decoder of openapi3filter, parts are decoded by the
+// schema of their property rather than by their Content-Type, so that files of
+// any media type are accepted, and numbers and booleans sent as text are
+// validated as such.
+func multipartBodyDecoder(body io.Reader, header http.Header, schema *openapi3.SchemaRef, _ openapi3filter.EncodingFn) (interface{}, error) {
+	if schema.Value.Type != "object" {
+		return nil, errors.New("unsupported schema of request body")
+	}
+
+	_, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+	}
+
+	values := map[string][]interface{}{}
+	mr := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+		}
+
+		name := part.FormName()
+		valueSchema := schema.Value.Properties[name]
+		if valueSchema != nil && valueSchema.Value.Type == "array" {
+			valueSchema = valueSchema.Value.Items
+		}
+		value, err := decodeFormValue(string(data), valueSchema)
+		if err != nil {
+			return nil, fmt.Errorf("part %s: %w", name, err)
+		}
+		values[name] = append(values[name], value)
+	}
+
+	return formObject(values, schema), nil
+}
+
+// formObject returns the object of the form values, keeping all the values of
+// array properties and the first one of the others.
+func formObject(values map[string][]interface{}, schema *openapi3.SchemaRef) map[string]interface{} {
+	obj := make(map[string]interface{}, len(values))
+	for name, vv := range values {
+		if prop := schema.Value.Properties[name]; prop != nil && prop.Value.Type == "array" {
+			obj[name] = vv
+		} else {
+			obj[name] = vv[0]
+		}
+	}
+	return obj
+}
+
+// decodeFormValue converts the text of a form field to the type of schema.
+// Fields which aren't in the schema, strings and files are kept as text.
+func decodeFormValue(s string, schema *openapi3.SchemaRef) (interface{}, error) {
+	if schema == nil || schema.Value == nil {
+		return s, nil
+	}
+
+	switch schema.Value.Type {
+	case "integer", "number":
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Value: s, Reason: "a number is expected"}
+		}
+		return f, nil
+	case "boolean":
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Value: s, Reason: "a boolean is expected"}
+		}
+		return b, nil
+	case "object", "array":
+		var v interface{}
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Value: s, Cause: err}
+		}
+		return v, nil
+	default:
+		return s, nil
+	}
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"testing"
+
+	"github.com/discord-gophers/goapi-gen/pkg/testutil"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOapiRequestValidatorMultipart(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /upload:
+    post:
+      responses:
+        '204':
+          description: No content
+      requestBody:
+        required: true
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              required: [file, name]
+              properties:
+                file:
+                  type: string
+                  format: binary
+                name:
+                  type: string
+                  maxLength: 5
+                count:
+                  type: integer
+                  minimum: 1
+                tags:
+                  type: array
+                  items:
+                    type: boolean
+`))
+	require.NoError(t, err, "Error initializing swagger")
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidator(swagger))
+	r.Post("/upload", func(w http.ResponseWriter, r *http.Request) {
+		// The body is still readable after validation.
+		file, _, err := r.FormFile("file")
+		require.NoError(t, err)
+		file.Close()
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name       string
+		fields     map[string][]string
+		file       bool
+		wantStatus int
+	}{
+		{"valid", map[string][]string{"name": {"pet"}, "count": {"2"}, "tags": {"true", "false"}}, true, http.StatusNoContent},
+		{"missing file", map[string][]string{"name": {"pet"}}, false, http.StatusBadRequest},
+		{"missing field", map[string][]string{"count": {"2"}}, true, http.StatusBadRequest},
+		{"too long", map[string][]string{"name": {"kitten"}}, true, http.StatusBadRequest},
+		{"below minimum", map[string][]string{"name": {"pet"}, "count": {"0"}}, true, http.StatusBadRequest},
+		{"not a number", map[string][]string{"name": {"pet"}, "count": {"two"}}, true, http.StatusBadRequest},
+		{"not a boolean", map[string][]string{"name": {"pet"}, "tags": {"yes"}}, true, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			if tt.file {
+				// Files are accepted whatever their media type.
+				header := textproto.MIMEHeader{}
+				header.Set("Content-Disposition", `form-data; name="file"; filename="pet.png"`)
+				header.Set("Content-Type", "image/png")
+				part, err := mw.CreatePart(header)
+				require.NoError(t, err)
+				_, err = part.Write([]byte("png"))
+				require.NoError(t, err)
+			}
+			for name, values := range tt.fields {
+				for _, value := range values {
+					require.NoError(t, mw.WriteField(name, value))
+				}
+			}
+			require.NoError(t, mw.Close())
+
+			rec := testutil.NewRequest().Post("/upload").WithHost("example.com").
+				WithContentType(mw.FormDataContentType()).WithBody(body.Bytes()).
+				GoWithHTTPHandler(t, r).Recorder
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
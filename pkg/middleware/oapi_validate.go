@@ -1,25 +1,111 @@
 // Package middleware implements middleware function for go-chi or net/http,
 // which validates incoming HTTP requests to make sure that they conform to the given OAPI 3.0 specification.
-// When OAPI validation failes on the request, we return an HTTP/400.
+// When OAPI validation failes on the request, we return an HTTP/400, an HTTP/404
+// or HTTP/405 when the request matches no operation of the spec, an HTTP/401
+// when its security requirements aren't met, or an HTTP/403 when its
+// credentials are valid but insufficient, like a token lacking the scopes of
+// an oauth2 requirement, an HTTP/413 when the request
+// body is larger than Options.MaxBodyBytes or Options.MaxContentLength, an
+// HTTP/411 when a required body has no Content-Length with
+// Options.MaxContentLength, or an HTTP/415 when the request body has a media
+// type the operation doesn't accept. JSON array bodies with
+// more items than their maxItems are rejected while they are read, before
+// they are decoded. Request bodies which were read for validation are
+// replaced by a reader of the same bytes, so that handlers can read them
+// again; bodies which were empty or not read are left as they are.
+// CORS preflight requests can be answered with Options.CORS, checking only
+// that the spec has the requested operation.
+// Outgoing responses can be validated as well, in which case an HTTP/500 is
+// returned when a handler does not conform to the specification.
 package middleware
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
-	"strings"
+	"path"
 
+	"github.com/discord-gophers/goapi-gen/pkg/apierrors"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/getkin/kin-openapi/routers"
-	"github.com/getkin/kin-openapi/routers/gorillamux"
 )
 
 // Options to customize request validation, openapi3filter specified options will be passed through.
 type Options struct {
 	Options openapi3filter.Options
+	// ErrorHandler is called instead of writing the default plain text
+	// response when validation fails.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, statusCode int, err error)
+	// ExcludeRoutes lists request paths which are passed through without
+	// validation, eg. routes which are not described by the spec. Entries
+	// are either exact paths or patterns as understood by path.Match.
+	ExcludeRoutes []string
+	// InjectValidated stores a ValidatedRequest in the context of requests
+	// which passed validation, see ValidatedRequestFromContext.
+	InjectValidated bool
+	// MaxBodyBytes limits the size of request bodies read for validation.
+	// Larger requests fail with an HTTP/413. Zero means no limit.
+	MaxBodyBytes int64
+	// MaxContentLength rejects requests whose Content-Length is larger with
+	// an HTTP/413, before their body is read, rather than reading it up to
+	// MaxBodyBytes. Requests without a Content-Length, eg. chunked ones, to
+	// operations requiring a body are rejected with an HTTP/411, as their
+	// size isn't known. Zero means no limit.
+	MaxContentLength int64
+	// RequestIDHeader is the header, eg. X-Request-ID, holding the ID of
+	// requests, which is added to validation errors and to the response
+	// headers, to correlate failures with the server logs. Requests without
+	// the header are given a UUID.
+	RequestIDHeader string
+	// StripUndocumentedFields removes the properties which the spec doesn't
+	// describe from the JSON bodies of valid responses, eg. internal data a
+	// handler included by mistake, before they are sent to the client. It
+	// only applies to the response validator.
+	StripUndocumentedFields bool
+	// OAuth2ScopeValidator is called instead of Options.AuthenticationFunc
+	// for the security requirements of type oauth2, with the scopes the
+	// operation requires. It checks the token of r, and returns an error
+	// wrapping ErrInsufficientScope if the token is valid but lacks any of
+	// scopes, for an HTTP/403 rather than an HTTP/401.
+	OAuth2ScopeValidator func(r *http.Request, scopes []string) error
+	// APIKeyValidators are called instead of Options.AuthenticationFunc for
+	// the security requirements of type apiKey, by the name of their security
+	// scheme. They check the key of r, which is in the header, query
+	// parameter or cookie of the scheme, and return an error if it isn't
+	// valid, for an HTTP/401. Requests without the key fail without calling
+	// them. The apiKey schemes without a validator are left to
+	// Options.AuthenticationFunc.
+	APIKeyValidators map[string]func(r *http.Request, schemeName string) error
+	// ExcludeOperationsFromSecurityValidation lists the operationIds of the
+	// operations whose security requirements aren't validated, eg. those
+	// authenticated by an API gateway in front of the server. The rest of
+	// their requests is still validated.
+	ExcludeOperationsFromSecurityValidation []string
+	// WrapErrors keeps the error a request failed validation with, such as
+	// an *openapi3filter.RequestError, in the Err of its ValidationError, so
+	// that ErrorHandler can inspect it with errors.As.
+	WrapErrors bool
+	// CORS answers CORS preflight requests, checking only that the spec has
+	// the requested operation, and sets the CORS headers of the responses to
+	// other origins, see OapiRequestValidatorWithCORS.
+	CORS *CORSOptions
+	// SecurityErrorStatus returns the status code of the requests whose
+	// security requirements aren't met with err, instead of
+	// DefaultSecurityErrorStatus. The err is an
+	// *openapi3filter.SecurityRequirementsError, or an openapi3.MultiError
+	// holding one with Options.MultiError.
+	SecurityErrorStatus func(err error) int
 }
 
+// ErrInsufficientScope is wrapped by the errors of
+// Options.OAuth2ScopeValidator for tokens lacking required scopes.
+var ErrInsufficientScope = errors.New("insufficient scope")
+
 // OapiRequestValidator Creates middleware to validate request by swagger spec.
 // This middleware is good for net/http either since go-chi is 100% compatible with net/http.
 func OapiRequestValidator(swagger *openapi3.T) func(next http.Handler) http.Handler {
@@ -28,36 +114,185 @@ func OapiRequestValidator(swagger *openapi3.T) func(next http.Handler) http.Hand
 
 // OapiRequestValidatorWithOptions Creates middleware to validate request by swagger spec.
 // This middleware is good for net/http either since go-chi is 100% compatible with net/http.
+// It panics if no router can be compiled from swagger, use NewValidatorMiddleware
+// to handle the error instead.
 func OapiRequestValidatorWithOptions(swagger *openapi3.T, options *Options) func(next http.Handler) http.Handler {
-	router, err := gorillamux.NewRouter(swagger)
+	return requestValidator(swagger, options, nil)
+}
+
+// NewValidatorMiddleware creates middleware to validate request by swagger
+// spec, like OapiRequestValidatorWithOptions, but returns the error of
+// compiling the gorilla/mux router of swagger instead of panicking.
+//
+// routerOpts are passed to the router of kin-openapi. Its gorillamux.NewRouter
+// doesn't take any options yet, so any routerOpts are reported as an error
+// rather than being ignored.
+func NewValidatorMiddleware(swagger *openapi3.T, opts *Options, routerOpts ...interface{}) (func(http.Handler) http.Handler, error) {
+	if len(routerOpts) > 0 {
+		return nil, fmt.Errorf("unsupported router option %T", routerOpts[0])
+	}
+	router, err := cachedRouter(swagger)
+	if err != nil {
+		return nil, fmt.Errorf("error creating router: %w", err)
+	}
+	return routerValidator(router, opts, nil), nil
+}
+
+// OapiRequestValidatorWithRouter Creates middleware to validate request by
+// the spec of the routes found by router, instead of the gorilla/mux router
+// compiled from the spec by OapiRequestValidatorWithOptions, eg. the router of
+// the stdmux package.
+func OapiRequestValidatorWithRouter(router routers.Router, options *Options) func(next http.Handler) http.Handler {
+	return routerValidator(router, options, nil)
+}
+
+// FindRouteForRequest returns the route of the spec swagger matching r, and
+// the values of its path parameters, without validating r, eg. to configure
+// some per-route behaviour in a middleware. swagger is hashed on every call, to
+// share the router of the validation middleware of an equivalent spec. Without
+// such middleware, the router is compiled again once it has been collected, so
+// a router of gorillamux.NewRouter should rather be kept to find the routes of
+// every request.
+//
+// When no route matches r, the error is routers.ErrPathNotFound, or
+// routers.ErrMethodNotAllowed when its path matches but none of its methods
+// do.
+func FindRouteForRequest(r *http.Request, swagger *openapi3.T) (*routers.Route, map[string]string, error) {
+	router, err := cachedRouter(swagger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating router: %w", err)
+	}
+	return router.FindRoute(r)
+}
+
+// requestValidator creates the request validation middleware. If observe is
+// set, it is called with the outcome of every validated request, err being
+// nil for valid requests.
+func requestValidator(swagger *openapi3.T, options *Options, observe func(r *http.Request, err *ValidationError)) func(next http.Handler) http.Handler {
+	router, err := cachedRouter(swagger)
 	if err != nil {
 		panic(err)
 	}
+	return routerValidator(router, options, observe)
+}
 
+// routerValidator creates the request validation middleware of
+// requestValidator, matching requests to routes with router.
+func routerValidator(router routers.Router, options *Options, observe func(r *http.Request, err *ValidationError)) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isExcludedRoute(r, options) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			id := requestID(w, r, options)
+			if options != nil && options.CORS != nil {
+				options.CORS.setOrigin(w, r)
+			}
 
 			// validate request
-			if statusCode, err := validateRequest(r, router, options); err != nil {
-				http.Error(w, err.Error(), statusCode)
+			input, err := validateRequest(r, router, options)
+			if err != nil {
+				err.RequestID = id
+			}
+			if observe != nil {
+				observe(r, err)
+			}
+			if err != nil {
+				handleError(w, r, options, err)
+				return
+			}
+			if isPreflight(r, options) {
+				options.CORS.writePreflight(w, r, input.Route)
 				return
 			}
 
+			if options != nil && options.InjectValidated {
+				r = r.WithContext(withValidatedRequest(r.Context(), input))
+			}
+
 			// serve
 			next.ServeHTTP(w, r)
 		})
 	}
+}
+
+// ValidateRequest validates r by swagger and options, like the middleware of
+// OapiRequestValidatorWithOptions, without serving it. A body which is read
+// for validation is replaced by a reader of the same bytes. The error is a
+// *ValidationError, with the status code the middleware responds with. Like
+// FindRouteForRequest, it hashes swagger on every call to find its router.
+func ValidateRequest(swagger *openapi3.T, r *http.Request, options *Options) error {
+	router, err := cachedRouter(swagger)
+	if err != nil {
+		return fmt.Errorf("error creating router: %w", err)
+	}
+	if _, err := validateRequest(r, router, options); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleError writes err to w, deferring to options.ErrorHandler if it is set.
+func handleError(w http.ResponseWriter, r *http.Request, options *Options, err *ValidationError) {
+	if options != nil && options.ErrorHandler != nil {
+		options.ErrorHandler(w, r, err.StatusCode, err)
+		return
+	}
+	writeError(w, r, err)
+}
+
+// WriteError writes err to w the way the middleware writes validation
+// errors: with options.ErrorHandler if it is set, or encoded based on the
+// Accept header of r. It lets the adapters of other frameworks report their
+// own failures in the same format.
+func WriteError(w http.ResponseWriter, r *http.Request, options *Options, err *ValidationError) {
+	handleError(w, r, options, err)
+}
 
+// isExcludedRoute returns whether the path of r matches any of
+// options.ExcludeRoutes.
+func isExcludedRoute(r *http.Request, options *Options) bool {
+	if options == nil {
+		return false
+	}
+	for _, route := range options.ExcludeRoutes {
+		if route == r.URL.Path {
+			return true
+		}
+		if ok, _ := path.Match(route, r.URL.Path); ok {
+			return true
+		}
+	}
+	return false
 }
 
 // This function is called from the middleware above and actually does the work
 // of validating a request.
-func validateRequest(r *http.Request, router routers.Router, options *Options) (int, error) {
+func validateRequest(r *http.Request, router routers.Router, options *Options) (*openapi3filter.RequestValidationInput, *ValidationError) {
+	fail := func(statusCode int, err error) *ValidationError {
+		verr := newValidationError(statusCode, err)
+		if options != nil && options.WrapErrors {
+			verr.Err = err
+		}
+		return verr
+	}
+
+	// Preflight requests have no body nor credentials, only their route is
+	// checked.
+	if isPreflight(r, options) {
+		route, pathParams, status, err := findPreflightRoute(r, router, options.CORS)
+		if err != nil {
+			return nil, fail(status, err)
+		}
+		return &openapi3filter.RequestValidationInput{Request: r, PathParams: pathParams, Route: route}, nil
+	}
 
 	// Find route
 	route, pathParams, err := router.FindRoute(r)
 	if err != nil {
-		return http.StatusBadRequest, err // We failed to find a matching route for the request.
+		return nil, fail(routeErrorStatus(err), err)
 	}
 
 	// Validate request
@@ -67,36 +302,211 @@ func validateRequest(r *http.Request, router routers.Router, options *Options) (
 		Route:      route,
 	}
 
+	skipSecurity := options != nil && excludesSecurity(options, route.Operation)
 	if options != nil {
 		requestValidationInput.Options = &options.Options
+		if options.OAuth2ScopeValidator != nil {
+			opts := options.Options
+			opts.AuthenticationFunc = oauth2Authenticator(options.OAuth2ScopeValidator, options.Options.AuthenticationFunc)
+			requestValidationInput.Options = &opts
+		}
+		if len(options.APIKeyValidators) > 0 {
+			opts := *requestValidationInput.Options
+			opts.AuthenticationFunc = apiKeyAuthenticator(options.APIKeyValidators, opts.AuthenticationFunc)
+			requestValidationInput.Options = &opts
+		}
+		if skipSecurity {
+			// ValidateRequest checks the security requirements as well.
+			opts := options.Options
+			opts.AuthenticationFunc = openapi3filter.NoopAuthenticationFunc
+			requestValidationInput.Options = &opts
+		}
 	}
 
 	// Validate security before any other validation, unless options.Options.MultiError is true
-	if options == nil || !options.Options.MultiError {
+	if !skipSecurity && (options == nil || !options.Options.MultiError) {
 		if err := validateSecurity(requestValidationInput); err != nil {
-			return http.StatusUnauthorized, err
+			return nil, fail(securityErrorStatus(options, err), err)
+		}
+		// Don't authenticate the request again in ValidateRequest.
+		var opts openapi3filter.Options
+		if requestValidationInput.Options != nil {
+			opts = *requestValidationInput.Options
+		}
+		opts.AuthenticationFunc = openapi3filter.NoopAuthenticationFunc
+		requestValidationInput.Options = &opts
+	}
+
+	// Check the media type of the body before it is read.
+	if err := validateContentType(r, route); err != nil {
+		return nil, fail(http.StatusUnsupportedMediaType, err)
+	}
+
+	if options != nil && options.MaxContentLength > 0 {
+		if status, err := checkContentLength(r, route, options.MaxContentLength); err != nil {
+			return nil, fail(status, err)
+		}
+	}
+
+	// An empty body which would be read is swapped for http.NoBody during the
+	// validation, so that it isn't replaced.
+	if readsBody(route, options) && isEmptyBody(r) {
+		body := r.Body
+		r.Body = http.NoBody
+		defer func() { r.Body = body }()
+	}
+
+	if options != nil && options.MaxBodyBytes > 0 {
+		if status, err := limitBody(r, options.MaxBodyBytes); err != nil {
+			return nil, fail(status, err)
+		}
+	}
+
+	// Reject arrays with too many items before the body is decoded.
+	if options == nil || !options.Options.ExcludeRequestBody {
+		if err := checkMaxItems(r, route); err != nil {
+			return nil, fail(http.StatusBadRequest, err)
 		}
 	}
 
 	// Validate the rest of the request
 	if err := openapi3filter.ValidateRequest(context.Background(), requestValidationInput); err != nil {
-		switch e := err.(type) {
+		switch err.(type) {
 		case *openapi3filter.RequestError:
 			// We've got a bad request
-			// Split up the verbose error by lines and return the first one
-			// openapi errors seem to be multi-line with a decent message on the first
-			errorLines := strings.Split(e.Error(), "\n")
-			return http.StatusBadRequest, fmt.Errorf(errorLines[0])
+			return nil, fail(http.StatusBadRequest, err)
 		case *openapi3filter.SecurityRequirementsError:
-			return http.StatusUnauthorized, err
+			return nil, fail(securityErrorStatus(options, err), err)
+		case openapi3.MultiError:
+			// This case occurs when options.Options.MultiError is true, and
+			// every error is reported at once. The security requirements
+			// which aren't met still fail the request with their status.
+			if errors.As(err, new(*openapi3filter.SecurityRequirementsError)) {
+				return nil, fail(securityErrorStatus(options, err), err)
+			}
+			return nil, fail(http.StatusBadRequest, err)
 		default:
-			// This case occurs when options.Options.MultiError is true.
-			// TODO(zlb): Find a better way to handle this.
-			return http.StatusInternalServerError, fmt.Errorf("error validating route: %s", err.Error())
+			return nil, fail(http.StatusInternalServerError, fmt.Errorf("error validating route: %w", err))
 		}
 	}
 
-	return http.StatusOK, nil
+	return requestValidationInput, nil
+}
+
+// readsBody returns whether the validation of a request for route with
+// options reads its body.
+func readsBody(route *routers.Route, options *Options) bool {
+	if options != nil && options.MaxBodyBytes > 0 {
+		return true
+	}
+	return route.Operation.RequestBody != nil && (options == nil || !options.Options.ExcludeRequestBody)
+}
+
+// isEmptyBody returns whether the body of r is empty, reading its first byte.
+// A body which isn't empty is replaced by a reader starting with that byte.
+func isEmptyBody(r *http.Request) bool {
+	if r.Body == nil || r.Body == http.NoBody {
+		return false
+	}
+	var b [1]byte
+	body := r.Body
+	n, err := body.Read(b[:])
+	if n == 0 && err == io.EOF {
+		return true
+	}
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(b[:n]), body), body}
+	return false
+}
+
+// limitBody reads the body of r, failing with an HTTP/413 if it is larger
+// than limit bytes, along with the status code to fail with. The body is
+// replaced so that it can be read again.
+func limitBody(r *http.Request, limit int64) (int, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return 0, nil
+	}
+
+	if r.ContentLength <= limit {
+		data, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+		r.Body.Close()
+		if err != nil {
+			return http.StatusBadRequest, fmt.Errorf("error reading request body: %w", err)
+		}
+		if int64(len(data)) <= limit {
+			r.Body = io.NopCloser(bytes.NewReader(data))
+			return 0, nil
+		}
+	}
+	return http.StatusRequestEntityTooLarge, fmt.Errorf("request body is larger than %d bytes", limit)
+}
+
+// checkContentLength rejects r, without reading its body, with an HTTP/413 if
+// its Content-Length is larger than limit bytes, or with an HTTP/411 if it
+// has none and route requires a body, along with the status code to fail
+// with.
+func checkContentLength(r *http.Request, route *routers.Route, limit int64) (int, error) {
+	if r.ContentLength > limit {
+		return http.StatusRequestEntityTooLarge, fmt.Errorf("request body is larger than %d bytes", limit)
+	}
+	body := route.Operation.RequestBody
+	if r.ContentLength < 0 && body != nil && body.Value != nil && body.Value.Required {
+		return http.StatusLengthRequired, errors.New("request body has no Content-Length")
+	}
+	return 0, nil
+}
+
+// routeErrorStatus returns the status code for err, returned when no route of
+// the spec matches a request: a 404 when no path matches, and a 405 when the
+// path matches but none of its methods do.
+func routeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, routers.ErrPathNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, routers.ErrMethodNotAllowed):
+		return http.StatusMethodNotAllowed
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// validateContentType checks that the Content-Type of r is one of the request
+// body media types of route. Wildcards in the spec, such as application/*,
+// match as well. Requests without a Content-Type are left to ValidateRequest.
+func validateContentType(r *http.Request, route *routers.Route) error {
+	if route.Operation.RequestBody == nil || route.Operation.RequestBody.Value == nil {
+		return nil
+	}
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("invalid Content-Type %q: %w", contentType, err)
+	}
+	content := route.Operation.RequestBody.Value.Content
+	if content.Get(contentType) == nil && content.Get(mediaType) == nil {
+		return fmt.Errorf("unsupported Content-Type %q", mediaType)
+	}
+	return nil
+}
+
+// excludesSecurity returns if the security requirements of op aren't
+// validated, as it is one of options.ExcludeOperationsFromSecurityValidation.
+func excludesSecurity(options *Options, op *openapi3.Operation) bool {
+	if op == nil || op.OperationID == "" {
+		return false
+	}
+	for _, id := range options.ExcludeOperationsFromSecurityValidation {
+		if id == op.OperationID {
+			return true
+		}
+	}
+	return false
 }
 
 func validateSecurity(input *openapi3filter.RequestValidationInput) error {
@@ -111,3 +521,93 @@ func validateSecurity(input *openapi3filter.RequestValidationInput) error {
 
 	return openapi3filter.ValidateSecurityRequirements(context.Background(), input, *security)
 }
+
+// oauth2Authenticator returns an openapi3filter.AuthenticationFunc checking
+// the oauth2 security schemes with validate, and the others with next.
+func oauth2Authenticator(validate func(r *http.Request, scopes []string) error, next openapi3filter.AuthenticationFunc) openapi3filter.AuthenticationFunc {
+	return func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+		if input.SecurityScheme != nil && input.SecurityScheme.Type == "oauth2" {
+			return validate(input.RequestValidationInput.Request, input.Scopes)
+		}
+		if next == nil {
+			return openapi3filter.ErrAuthenticationServiceMissing
+		}
+		return next(ctx, input)
+	}
+}
+
+// apiKeyAuthenticator returns an openapi3filter.AuthenticationFunc checking
+// the apiKey security schemes with their validator of validators, and the
+// others with next.
+func apiKeyAuthenticator(validators map[string]func(r *http.Request, schemeName string) error, next openapi3filter.AuthenticationFunc) openapi3filter.AuthenticationFunc {
+	return func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+		scheme := input.SecurityScheme
+		validate := validators[input.SecuritySchemeName]
+		if scheme == nil || scheme.Type != "apiKey" || validate == nil {
+			if next == nil {
+				return openapi3filter.ErrAuthenticationServiceMissing
+			}
+			return next(ctx, input)
+		}
+
+		r := input.RequestValidationInput.Request
+		var found bool
+		switch scheme.In {
+		case "header":
+			found = r.Header.Get(scheme.Name) != ""
+		case "query":
+			found = r.URL.Query().Get(scheme.Name) != ""
+		case "cookie":
+			_, err := r.Cookie(scheme.Name)
+			found = err == nil
+		}
+		if !found {
+			return fmt.Errorf("missing API key %s in %s", scheme.Name, scheme.In)
+		}
+		return validate(r, input.SecuritySchemeName)
+	}
+}
+
+// securityErrorStatus returns the status code for err, returned when the
+// security requirements of a request aren't met, by
+// options.SecurityErrorStatus if it is set.
+func securityErrorStatus(options *Options, err error) int {
+	if options != nil && options.SecurityErrorStatus != nil {
+		return options.SecurityErrorStatus(err)
+	}
+	return DefaultSecurityErrorStatus(err)
+}
+
+// DefaultSecurityErrorStatus returns the status code for err, returned when
+// the security requirements of a request aren't met: an HTTP/401 when the
+// request has no valid credentials, such as a missing or expired token, and
+// an HTTP/403 when they are valid but insufficient for any of the
+// requirements. Insufficient credentials are reported by the authentication
+// functions with an error wrapping ErrInsufficientScope, or an
+// *apierrors.APIError of status 403 like apierrors.ErrForbidden.
+func DefaultSecurityErrorStatus(err error) int {
+	if isForbidden(err) {
+		return http.StatusForbidden
+	}
+	return http.StatusUnauthorized
+}
+
+// isForbidden returns whether err, or any of the errors of the security
+// requirements it holds, reports insufficient credentials.
+func isForbidden(err error) bool {
+	forbidden := func(err error) bool {
+		return errors.Is(err, ErrInsufficientScope) || errors.Is(err, apierrors.ErrForbidden)
+	}
+	if forbidden(err) {
+		return true
+	}
+	var serr *openapi3filter.SecurityRequirementsError
+	if errors.As(err, &serr) {
+		for _, err := range serr.Errors {
+			if forbidden(err) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+//go:build go1.18
+// +build go1.18
+
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// OapiValidateAndBind validates r by the spec of router and options, like the
+// middleware of OapiRequestValidatorWithRouter, and decodes its JSON body into
+// a T, for the handlers to do both in one call. A request without a body,
+// which its operation doesn't require, binds the zero T.
+//
+// The status code is the one to respond with: http.StatusOK along with the
+// body, or the status code of the *ValidationError err is. Bodies which don't
+// decode into a T are an HTTP/400.
+func OapiValidateAndBind[T any](r *http.Request, router routers.Router, options *Options) (T, int, error) {
+	var body T
+	if _, err := validateRequest(r, router, options); err != nil {
+		return body, err.StatusCode, err
+	}
+	if r.Body == nil || r.Body == http.NoBody {
+		return body, http.StatusOK, nil
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		err = fmt.Errorf("error decoding request body: %w", err)
+		verr := newValidationError(http.StatusBadRequest, err)
+		if options != nil && options.WrapErrors {
+			verr.Err = err
+		}
+		return body, verr.StatusCode, verr
+	}
+	return body, http.StatusOK, nil
+}
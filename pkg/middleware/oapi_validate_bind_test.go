@@ -0,0 +1,67 @@
+//go:build go1.18
+// +build go1.18
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOapiValidateAndBind(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+	router, err := gorillamux.NewRouter(swagger)
+	require.NoError(t, err)
+
+	type resource struct {
+		Name string `json:"name"`
+	}
+	post := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "http://example.com/resource", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	// Valid requests bind their body
+	{
+		body, status, err := OapiValidateAndBind[resource](post(`{"name": "Rex"}`), router, nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, resource{Name: "Rex"}, body)
+	}
+
+	// Invalid requests fail with the status of their validation
+	{
+		_, status, err := OapiValidateAndBind[resource](post(`{"name": 7}`), router, nil)
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, http.StatusBadRequest, verr.StatusCode)
+	}
+
+	// Bodies which don't decode into T are an HTTP/400
+	{
+		_, status, err := OapiValidateAndBind[[]resource](post(`{"name": "Rex"}`), router, nil)
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Contains(t, verr.Message, "error decoding request body")
+	}
+
+	// Requests without a body bind the zero T
+	{
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/resource?id=50", nil)
+		body, status, err := OapiValidateAndBind[resource](req, router, nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Zero(t, body)
+	}
+}
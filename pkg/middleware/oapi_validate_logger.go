@@ -0,0 +1,41 @@
+//go:build go1.21
+// +build go1.21
+
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// OapiRequestValidatorWithLogger Creates middleware to validate request by swagger spec,
+// like OapiRequestValidatorWithOptions. Validated requests are logged to logger at debug
+// level, and validation failures at warn level. A nil logger logs nothing.
+func OapiRequestValidatorWithLogger(swagger *openapi3.T, options *Options, logger *slog.Logger) func(next http.Handler) http.Handler {
+	if logger == nil {
+		return requestValidator(swagger, options, nil)
+	}
+
+	return requestValidator(swagger, options, func(r *http.Request, err *ValidationError) {
+		if err != nil {
+			attrs := []interface{}{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", err.StatusCode),
+				slog.String("error", err.Message),
+			}
+			if err.RequestID != "" {
+				attrs = append(attrs, slog.String("request_id", err.RequestID))
+			}
+			logger.WarnContext(r.Context(), "request validation failed", attrs...)
+			return
+		}
+
+		logger.DebugContext(r.Context(), "request validated",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+		)
+	})
+}
@@ -0,0 +1,71 @@
+//go:build go1.21
+// +build go1.21
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOapiRequestValidatorWithLogger(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorWithLogger(swagger, nil, logger))
+	r.Get("/resource", func(w http.ResponseWriter, r *http.Request) {})
+
+	readEntry := func() map[string]interface{} {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		buf.Reset()
+		return entry
+	}
+
+	// Valid requests are logged at debug level
+	{
+		rec := doGet(t, r, "http://example.com/resource?id=50")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		entry := readEntry()
+		assert.Equal(t, "DEBUG", entry["level"])
+		assert.Equal(t, "GET", entry["method"])
+		assert.Equal(t, "/resource", entry["path"])
+	}
+
+	// Validation failures are logged at warn level
+	{
+		rec := doGet(t, r, "http://example.com/resource?id=500")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		entry := readEntry()
+		assert.Equal(t, "WARN", entry["level"])
+		assert.Equal(t, "/resource", entry["path"])
+		assert.EqualValues(t, http.StatusBadRequest, entry["status"])
+		assert.NotEmpty(t, entry["error"])
+	}
+}
+
+func TestOapiRequestValidatorWithNilLogger(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorWithLogger(swagger, nil, nil))
+	r.Get("/resource", func(w http.ResponseWriter, r *http.Request) {})
+
+	assert.Equal(t, http.StatusOK, doGet(t, r, "http://example.com/resource?id=50").Code)
+	assert.Equal(t, http.StatusBadRequest, doGet(t, r, "http://example.com/resource?id=500").Code)
+}
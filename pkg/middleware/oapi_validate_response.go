@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// OapiResponseValidator Creates middleware to validate responses by swagger spec.
+// Responses which do not conform to the spec are replaced with an HTTP/500.
+func OapiResponseValidator(swagger *openapi3.T) func(next http.Handler) http.Handler {
+	return OapiResponseValidatorWithOptions(swagger, nil)
+}
+
+// OapiResponseValidatorWithOptions Creates middleware to validate responses by swagger spec.
+// Responses which do not conform to the spec are replaced with an HTTP/500,
+// and the undocumented fields of valid ones are removed when
+// options.StripUndocumentedFields is set.
+func OapiResponseValidatorWithOptions(swagger *openapi3.T, options *Options) func(next http.Handler) http.Handler {
+	router, err := cachedRouter(swagger)
+	if err != nil {
+		panic(err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isExcludedRoute(r, options) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			id := requestID(w, r, options)
+
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				// Without a route there is nothing to validate against, finding
+				// unknown routes is the job of the request validator.
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			before := w.Header().Clone()
+			bw := &bufferedResponseWriter{ResponseWriter: w}
+			next.ServeHTTP(bw, r)
+
+			// Streamed responses have already been sent to the client, there
+			// is nothing left for us to check.
+			if bw.streaming {
+				return
+			}
+
+			if err := validateResponse(r, bw.status(), bw.Header(), bw.body.Bytes(), route, pathParams, options); err != nil {
+				err.RequestID = id
+				resetHeader(w.Header(), before)
+				handleError(w, r, options, err)
+				return
+			}
+
+			if options != nil && options.StripUndocumentedFields {
+				stripUndocumentedFields(route, bw)
+			}
+
+			bw.flush()
+		})
+	}
+}
+
+// ValidateResponse validates resp, the response to r, by swagger and
+// options, like the middleware of OapiResponseValidatorWithOptions. The body
+// of resp is replaced by a reader of the same bytes, so that it can be read
+// again. The error is a *ValidationError, with an HTTP/404 or HTTP/405 if r
+// matches no operation of swagger. Like FindRouteForRequest, it hashes
+// swagger on every call to find its router.
+func ValidateResponse(swagger *openapi3.T, r *http.Request, resp *http.Response, options *Options) error {
+	router, err := cachedRouter(swagger)
+	if err != nil {
+		return fmt.Errorf("error creating router: %w", err)
+	}
+	route, pathParams, err := router.FindRoute(r)
+	if err != nil {
+		return newValidationError(routeErrorStatus(err), err)
+	}
+
+	var body []byte
+	if resp.Body != nil {
+		body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("error reading response body: %w", err)
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if err := validateResponse(r, resp.StatusCode, resp.Header, body, route, pathParams, options); err != nil {
+		return err
+	}
+	return nil
+}
+
+// This function is called from the middleware above and actually does the work
+// of validating a response.
+func validateResponse(r *http.Request, status int, header http.Header, body []byte, route *routers.Route, pathParams map[string]string, options *Options) *ValidationError {
+	responseValidationInput := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+		},
+		Status: status,
+		Header: header,
+	}
+	responseValidationInput.SetBodyBytes(body)
+
+	var opts openapi3filter.Options
+	if options != nil {
+		opts = options.Options
+	}
+	// A handler which wrote no body can only be checked for its status code.
+	if len(body) == 0 {
+		opts.ExcludeResponseBody = true
+	}
+	responseValidationInput.Options = &opts
+
+	if err := openapi3filter.ValidateResponse(context.Background(), responseValidationInput); err != nil {
+		return newValidationError(http.StatusInternalServerError, err)
+	}
+
+	return nil
+}
+
+// resetHeader sets header back to before, dropping the headers the handler
+// set for the response it wrote, such as its Content-Length and
+// Content-Encoding, which don't hold for the error replacing it.
+func resetHeader(header, before http.Header) {
+	for name := range header {
+		delete(header, name)
+	}
+	for name, values := range before {
+		header[name] = values
+	}
+}
+
+// bufferedResponseWriter holds on to the status code and body written by a
+// handler, so they can be validated before being sent to the client.
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+
+	statusCode int
+	body       bytes.Buffer
+
+	// streaming is set once the handler flushes, at which point everything
+	// is written through to the underlying writer.
+	streaming bool
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (bw *bufferedResponseWriter) WriteHeader(statusCode int) {
+	if bw.streaming {
+		bw.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	if bw.statusCode == 0 {
+		bw.statusCode = statusCode
+	}
+}
+
+// Write implements http.ResponseWriter.
+func (bw *bufferedResponseWriter) Write(b []byte) (int, error) {
+	if bw.streaming {
+		return bw.ResponseWriter.Write(b)
+	}
+	return bw.body.Write(b)
+}
+
+// Flush implements http.Flusher. Flushing switches the writer to
+// pass-through mode, as the response can no longer be validated as a whole.
+func (bw *bufferedResponseWriter) Flush() {
+	if !bw.streaming {
+		bw.flush()
+		bw.streaming = true
+	}
+	if f, ok := bw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (bw *bufferedResponseWriter) status() int {
+	if bw.statusCode == 0 {
+		return http.StatusOK
+	}
+	return bw.statusCode
+}
+
+// flush writes the buffered status code and body to the underlying writer.
+func (bw *bufferedResponseWriter) flush() {
+	bw.ResponseWriter.WriteHeader(bw.status())
+	if bw.body.Len() > 0 {
+		_, _ = bw.ResponseWriter.Write(bw.body.Bytes())
+	}
+}
@@ -0,0 +1,205 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOapiResponseValidator(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	r := chi.NewRouter()
+
+	// register middleware
+	r.Use(OapiResponseValidator(swagger))
+
+	var body interface{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if body == nil {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}
+	r.Get("/resource", handler)
+	r.Head("/resource", handler)
+
+	// A response matching the spec should be passed through
+	{
+		body = map[string]interface{}{"name": "Marcin", "id": 11}
+		rec := doGet(t, r, "http://example.com/resource")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"name":"Marcin","id":11}`, rec.Body.String())
+	}
+
+	// A response with a malformed body should be rejected
+	{
+		body = map[string]interface{}{"name": 7}
+		rec := doGet(t, r, "http://example.com/resource")
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	}
+
+	// A response without a body is only checked for its status
+	{
+		body = nil
+		rec := doGet(t, r, "http://example.com/resource")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	}
+
+	// HEAD requests are never validated
+	{
+		body = map[string]interface{}{"name": 7}
+		req := httptest.NewRequest(http.MethodHead, "http://example.com/resource", nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	// Flushed responses are streamed to the client without validation
+	r.Get("/resource", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":`))
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte(`7}`))
+	})
+	{
+		rec := doGet(t, r, "http://example.com/resource")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, rec.Flushed)
+		assert.Equal(t, `{"name":7}`, rec.Body.String())
+	}
+}
+
+func TestOapiResponseValidatorErrorHeaders(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Frame-Options", "DENY")
+			next.ServeHTTP(w, r)
+		})
+	})
+	r.Use(OapiResponseValidatorWithOptions(swagger, &Options{RequestIDHeader: "X-Request-ID"}))
+	r.Get("/resource", func(w http.ResponseWriter, r *http.Request) {
+		body := []byte(`{"name":7}`)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Cache-Control", "max-age=3600")
+		_, _ = w.Write(body)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/resource", nil)
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	// The headers of the invalid response don't hold for the error.
+	for _, name := range []string{"Content-Length", "Content-Encoding", "ETag", "Cache-Control"} {
+		assert.Empty(t, rec.Header().Get(name), name)
+	}
+	// The headers set before the handler are kept.
+	assert.Equal(t, "DENY", rec.Header().Get("X-Frame-Options"))
+	assert.NotEmpty(t, rec.Header().Get("X-Request-ID"))
+
+	var verr ValidationError
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &verr), rec.Body.String())
+	assert.Equal(t, http.StatusInternalServerError, verr.StatusCode)
+}
+
+const stripSchema = `openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: success
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  allOf:
+                    - $ref: '#/components/schemas/Named'
+                    - properties:
+                        owner:
+                          properties:
+                            name:
+                              type: string
+                        labels:
+                          additionalProperties:
+                            properties:
+                              value:
+                                type: string
+                        extra:
+                          type: object
+            text/plain:
+              schema:
+                type: string
+components:
+  schemas:
+    Named:
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+`
+
+func TestOapiResponseValidatorStripUndocumentedFields(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(stripSchema))
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Use(OapiResponseValidatorWithOptions(swagger, &Options{StripUndocumentedFields: true}))
+
+	var contentType, body string
+	r.Get("/pets", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		_, _ = w.Write([]byte(body))
+	})
+
+	// Undocumented fields are removed, at any depth
+	{
+		contentType = "application/json; charset=utf-8"
+		body = `[{"name":"Rex","debug":true,"owner":{"name":"<Jo>","password":"x"},` +
+			`"labels":{"color":{"value":"red","id":1}},"extra":{"any":1},"id":12345678901234567890}]`
+		rec := doGet(t, r, "http://example.com/pets")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `[{"name":"Rex","owner":{"name":"<Jo>"},"labels":{"color":{"value":"red"}},"extra":{"any":1},"id":12345678901234567890}]`, rec.Body.String())
+		// Values are written back as they were
+		assert.Contains(t, rec.Body.String(), `"<Jo>"`)
+		assert.Contains(t, rec.Body.String(), `12345678901234567890`)
+		assert.Equal(t, strconv.Itoa(rec.Body.Len()), rec.Header().Get("Content-Length"))
+	}
+
+	// Other media types are left untouched
+	{
+		contentType = "text/plain"
+		body = `{"secret":1}`
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/pets", nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, body, rec.Body.String())
+	}
+}
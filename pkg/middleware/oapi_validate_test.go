@@ -2,15 +2,22 @@ package middleware
 
 import (
 	"context"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
+	"github.com/discord-gophers/goapi-gen/pkg/apierrors"
 	"github.com/discord-gophers/goapi-gen/pkg/testutil"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -142,6 +149,68 @@ func TestOapiRequestValidator(t *testing.T) {
 	testRequestValidatorBasicFunctions(t, r)
 }
 
+func TestNewValidatorMiddleware(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	mw, err := NewValidatorMiddleware(swagger, nil)
+	require.NoError(t, err)
+	r := chi.NewRouter()
+	r.Use(mw)
+	testRequestValidatorBasicFunctions(t, r)
+
+	_, err = NewValidatorMiddleware(swagger, nil, struct{}{})
+	assert.ErrorContains(t, err, "unsupported router option struct {}")
+
+	invalid, err := openapi3.NewLoader().LoadFromData([]byte(`openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: "http://example.com:port"
+paths: {}
+`))
+	require.NoError(t, err, "Error initializing swagger")
+	_, err = NewValidatorMiddleware(invalid, nil)
+	assert.ErrorContains(t, err, "error creating router")
+	assert.Panics(t, func() { OapiRequestValidatorWithOptions(invalid, nil) })
+}
+
+func TestFindRouteForRequest(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        '204':
+          description: No content
+`))
+	require.NoError(t, err, "Error initializing swagger")
+
+	// The route is found without validating the request.
+	route, pathParams, err := FindRouteForRequest(httptest.NewRequest(http.MethodGet, "http://example.com/pets/rex", nil), swagger)
+	require.NoError(t, err)
+	assert.Equal(t, "getPet", route.Operation.OperationID)
+	assert.Equal(t, map[string]string{"id": "rex"}, pathParams)
+
+	_, _, err = FindRouteForRequest(httptest.NewRequest(http.MethodDelete, "http://example.com/pets/7", nil), swagger)
+	assert.ErrorIs(t, err, routers.ErrMethodNotAllowed)
+	_, _, err = FindRouteForRequest(httptest.NewRequest(http.MethodGet, "http://example.com/owners", nil), swagger)
+	assert.ErrorIs(t, err, routers.ErrPathNotFound)
+}
+
 func TestOapiRequestValidatorWithOptions(t *testing.T) {
 	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
 	require.NoError(t, err, "Error initializing swagger")
@@ -222,6 +291,288 @@ func TestOapiRequestValidatorWithOptions(t *testing.T) {
 	}
 }
 
+const oauth2Schema = `openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /pets:
+    get:
+      security:
+        - OAuth2: [pets:read]
+      responses:
+        '204':
+          description: no content
+    post:
+      security:
+        - OAuth2: [pets:write]
+      responses:
+        '204':
+          description: no content
+  /owners:
+    get:
+      security:
+        - ApiKey: []
+      responses:
+        '204':
+          description: no content
+components:
+  securitySchemes:
+    OAuth2:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: http://example.com/token
+          scopes:
+            pets:read: read pets
+            pets:write: write pets
+    ApiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+`
+
+func TestOAuth2ScopeValidator(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(oauth2Schema))
+	require.NoError(t, err)
+
+	var gotScopes []string
+	options := Options{
+		OAuth2ScopeValidator: func(r *http.Request, scopes []string) error {
+			gotScopes = scopes
+			if r.Header.Get("Authorization") != "Bearer reader" {
+				return errors.New("invalid token")
+			}
+			for _, s := range scopes {
+				if s != "pets:read" {
+					return fmt.Errorf("missing scope %s: %w", s, ErrInsufficientScope)
+				}
+			}
+			return nil
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorWithOptions(swagger, &options))
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+	r.Get("/pets", handler)
+	r.Post("/pets", handler)
+	r.Get("/owners", handler)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"valid token", http.MethodGet, "/pets", "reader", http.StatusNoContent},
+		{"invalid token", http.MethodGet, "/pets", "unknown", http.StatusUnauthorized},
+		{"insufficient scope", http.MethodPost, "/pets", "reader", http.StatusForbidden},
+		{"other scheme", http.MethodGet, "/owners", "reader", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := testutil.NewRequest().WithMethod(tt.method, tt.path).WithHost("example.com").
+				WithHeader("Authorization", "Bearer "+tt.token).GoWithHTTPHandler(t, r).Recorder
+			assert.Equal(t, tt.want, rec.Code, rec.Body.String())
+		})
+	}
+	assert.Equal(t, []string{"pets:write"}, gotScopes)
+
+	// The scopes are checked as part of the other checks with MultiError.
+	options.Options.MultiError = true
+	r = chi.NewRouter()
+	r.Use(OapiRequestValidatorWithOptions(swagger, &options))
+	r.Post("/pets", handler)
+	rec := testutil.NewRequest().Post("/pets").WithHost("example.com").
+		WithHeader("Authorization", "Bearer reader").GoWithHTTPHandler(t, r).Recorder
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+}
+
+func TestSecurityErrorStatus(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(oauth2Schema))
+	require.NoError(t, err)
+
+	options := Options{}
+	options.Options.AuthenticationFunc = func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+		switch input.RequestValidationInput.Request.Header.Get("Authorization") {
+		case "":
+			return errors.New("missing token")
+		case "Bearer admin":
+			return nil
+		default:
+			return fmt.Errorf("not an admin: %w", apierrors.ErrForbidden)
+		}
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+	do := func(token string) int {
+		r := chi.NewRouter()
+		r.Use(OapiRequestValidatorWithOptions(swagger, &options))
+		r.Get("/pets", handler)
+		req := testutil.NewRequest().Get("/pets").WithHost("example.com")
+		if token != "" {
+			req = req.WithHeader("Authorization", "Bearer "+token)
+		}
+		return req.GoWithHTTPHandler(t, r).Recorder.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, do("admin"))
+	assert.Equal(t, http.StatusUnauthorized, do(""))
+	assert.Equal(t, http.StatusForbidden, do("reader"))
+
+	options.Options.MultiError = true
+	assert.Equal(t, http.StatusUnauthorized, do(""))
+	assert.Equal(t, http.StatusForbidden, do("reader"))
+
+	// The mapping can be overridden, eg. to hide the operations from the
+	// users lacking permissions.
+	options.Options.MultiError = false
+	options.SecurityErrorStatus = func(err error) int {
+		if DefaultSecurityErrorStatus(err) == http.StatusForbidden {
+			return http.StatusNotFound
+		}
+		return http.StatusUnauthorized
+	}
+	assert.Equal(t, http.StatusUnauthorized, do(""))
+	assert.Equal(t, http.StatusNotFound, do("reader"))
+}
+
+const apiKeySchema = `openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /header:
+    get:
+      security:
+        - HeaderKey: []
+      responses:
+        '204':
+          description: no content
+  /query:
+    get:
+      security:
+        - QueryKey: []
+      responses:
+        '204':
+          description: no content
+  /cookie:
+    get:
+      security:
+        - CookieKey: []
+      responses:
+        '204':
+          description: no content
+  /either:
+    get:
+      security:
+        - HeaderKey: []
+        - Bearer: []
+      responses:
+        '204':
+          description: no content
+components:
+  securitySchemes:
+    HeaderKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+    QueryKey:
+      type: apiKey
+      in: query
+      name: api_key
+    CookieKey:
+      type: apiKey
+      in: cookie
+      name: session
+    Bearer:
+      type: http
+      scheme: bearer
+`
+
+func TestAPIKeyValidators(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(apiKeySchema))
+	require.NoError(t, err)
+
+	var schemes []string
+	check := func(key func(r *http.Request) string) func(r *http.Request, schemeName string) error {
+		return func(r *http.Request, schemeName string) error {
+			schemes = append(schemes, schemeName)
+			if key(r) != "secret" {
+				return errors.New("invalid API key")
+			}
+			return nil
+		}
+	}
+	options := Options{
+		APIKeyValidators: map[string]func(r *http.Request, schemeName string) error{
+			"HeaderKey": check(func(r *http.Request) string { return r.Header.Get("X-API-Key") }),
+			"QueryKey":  check(func(r *http.Request) string { return r.URL.Query().Get("api_key") }),
+			"CookieKey": check(func(r *http.Request) string {
+				c, _ := r.Cookie("session")
+				return c.Value
+			}),
+		},
+	}
+	options.Options.AuthenticationFunc = func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+		if input.RequestValidationInput.Request.Header.Get("Authorization") != "Bearer token" {
+			return errors.New("invalid token")
+		}
+		return nil
+	}
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorWithOptions(swagger, &options))
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+	for _, path := range []string{"/header", "/query", "/cookie", "/either"} {
+		r.Get(path, handler)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		header  [2]string
+		schemes []string
+		want    int
+	}{
+		{"valid header", "/header", [2]string{"X-API-Key", "secret"}, []string{"HeaderKey"}, http.StatusNoContent},
+		{"invalid header", "/header", [2]string{"X-API-Key", "guess"}, []string{"HeaderKey"}, http.StatusUnauthorized},
+		{"missing header", "/header", [2]string{}, nil, http.StatusUnauthorized},
+		{"valid query", "/query?api_key=secret", [2]string{}, []string{"QueryKey"}, http.StatusNoContent},
+		{"invalid query", "/query?api_key=guess", [2]string{}, []string{"QueryKey"}, http.StatusUnauthorized},
+		{"missing query", "/query", [2]string{}, nil, http.StatusUnauthorized},
+		{"valid cookie", "/cookie", [2]string{"Cookie", "session=secret"}, []string{"CookieKey"}, http.StatusNoContent},
+		{"invalid cookie", "/cookie", [2]string{"Cookie", "session=guess"}, []string{"CookieKey"}, http.StatusUnauthorized},
+		{"missing cookie", "/cookie", [2]string{"Cookie", "other=secret"}, nil, http.StatusUnauthorized},
+		// Other schemes are left to AuthenticationFunc.
+		{"other scheme", "/either", [2]string{"Authorization", "Bearer token"}, nil, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemes = nil
+			req := testutil.NewRequest().Get(tt.path).WithHost("example.com")
+			if tt.header[0] != "" {
+				req = req.WithHeader(tt.header[0], tt.header[1])
+			}
+			rec := req.GoWithHTTPHandler(t, r).Recorder
+			assert.Equal(t, tt.want, rec.Code, rec.Body.String())
+			assert.Equal(t, tt.schemes, schemes)
+		})
+	}
+}
+
 func testRequestValidatorBasicFunctions(t *testing.T, r *chi.Mux) {
 	called := false
 
@@ -234,7 +585,14 @@ func testRequestValidatorBasicFunctions(t *testing.T, r *chi.Mux) {
 	// Let's send the request to the wrong server, this should fail validation
 	{
 		rec := doGet(t, r, "http://not.example.com/resource")
-		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.False(t, called, "Handler should not have been called")
+	}
+
+	// Methods which aren't in the spec fail validation as well
+	{
+		rec := testutil.NewRequest().Delete("/resource").WithHost("example.com").GoWithHTTPHandler(t, r).Recorder
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
 		assert.False(t, called, "Handler should not have been called")
 	}
 
@@ -265,6 +623,10 @@ func testRequestValidatorBasicFunctions(t *testing.T, r *chi.Mux) {
 	// Add a handler for the POST message
 	r.Post("/resource", func(w http.ResponseWriter, r *http.Request) {
 		called = true
+		// The body which was validated can be read again.
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"name":"Marcin"}`, string(body))
 		w.WriteHeader(http.StatusNoContent)
 	})
 
@@ -295,3 +657,736 @@ func testRequestValidatorBasicFunctions(t *testing.T, r *chi.Mux) {
 		called = false
 	}
 }
+
+func TestOapiRequestValidatorWithErrorHandler(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	var handledStatus int
+	options := Options{
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+			handledStatus = statusCode
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTeapot)
+			_, _ = w.Write([]byte(`{"error":"` + err.Error() + `"}`))
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorWithOptions(swagger, &options))
+
+	called := false
+	r.Get("/resource", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	// The error handler is in charge of the response for invalid requests
+	{
+		rec := doGet(t, r, "http://example.com/resource?id=500")
+		assert.Equal(t, http.StatusTeapot, rec.Code)
+		assert.Equal(t, http.StatusBadRequest, handledStatus)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.False(t, called, "Handler should not have been called")
+	}
+
+	// Valid requests never reach the error handler
+	{
+		handledStatus = 0
+		rec := doGet(t, r, "http://example.com/resource?id=50")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Zero(t, handledStatus)
+		assert.True(t, called, "Handler should have been called")
+	}
+}
+
+func TestOapiRequestValidatorWrapErrors(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	var handled error
+	options := Options{
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+			handled = err
+			w.WriteHeader(statusCode)
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorWithOptions(swagger, &options))
+	r.Get("/resource", func(w http.ResponseWriter, r *http.Request) {})
+
+	// By default, the cause of validation errors isn't kept
+	{
+		rec := doGet(t, r, "http://example.com/resource?id=500")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var reqErr *openapi3filter.RequestError
+		assert.False(t, errors.As(handled, &reqErr))
+	}
+
+	options.WrapErrors = true
+
+	// The structured error of openapi3filter can be inspected
+	{
+		rec := doGet(t, r, "http://example.com/resource?id=500")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var reqErr *openapi3filter.RequestError
+		require.True(t, errors.As(handled, &reqErr))
+		assert.Equal(t, "id", reqErr.Parameter.Name)
+
+		var verr *ValidationError
+		require.True(t, errors.As(handled, &verr))
+		assert.Equal(t, http.StatusBadRequest, verr.StatusCode)
+	}
+
+	// As well as the errors of the router
+	{
+		rec := doGet(t, r, "http://example.com/unknown")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.True(t, errors.Is(handled, routers.ErrPathNotFound))
+	}
+
+	// And the errors of the body limits
+	r.Post("/resource", func(w http.ResponseWriter, r *http.Request) {})
+	for _, limit := range []func(){
+		func() { options.MaxContentLength, options.MaxBodyBytes = 4, 0 },
+		func() { options.MaxContentLength, options.MaxBodyBytes = 0, 4 },
+	} {
+		limit()
+		rec := doPost(t, r, "http://example.com/resource", map[string]interface{}{"name": "Marcin"})
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+		var verr *ValidationError
+		require.True(t, errors.As(handled, &verr))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, verr.StatusCode)
+		require.Error(t, verr.Err)
+		assert.Equal(t, "request body is larger than 4 bytes", verr.Err.Error())
+	}
+}
+
+func TestOapiRequestValidatorWithExcludeRoutes(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	options := Options{
+		ExcludeRoutes: []string{"/healthz", "/internal/*"},
+	}
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorWithOptions(swagger, &options))
+
+	called := false
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}
+	r.Get("/healthz", handler)
+	r.Get("/internal/metrics", handler)
+	r.Get("/unknown", handler)
+
+	// Exact matches are not validated
+	{
+		rec := doGet(t, r, "http://example.com/healthz")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, called, "Handler should have been called")
+		called = false
+	}
+
+	// Wildcard matches are not validated
+	{
+		rec := doGet(t, r, "http://example.com/internal/metrics")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, called, "Handler should have been called")
+		called = false
+	}
+
+	// Routes not in the spec and not excluded still fail validation
+	{
+		rec := doGet(t, r, "http://example.com/unknown")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.False(t, called, "Handler should not have been called")
+	}
+}
+
+func TestOapiRequestValidatorExcludeOperationsFromSecurityValidation(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	options := Options{
+		Options: openapi3filter.Options{
+			AuthenticationFunc: func(c context.Context, input *openapi3filter.AuthenticationInput) error {
+				return errors.New("unauthorized")
+			},
+		},
+		ExcludeOperationsFromSecurityValidation: []string{"createProtectedResource"},
+	}
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorWithOptions(swagger, &options))
+
+	called := false
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusNoContent)
+	}
+	r.Get("/protected_resource_401", handler)
+	r.Post("/protected_resource_401", handler)
+
+	// The security of excluded operations isn't validated
+	{
+		rec := doPost(t, r, "http://example.com/protected_resource_401", map[string]string{"name": "Rex"})
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.True(t, called, "Handler should have been called")
+		called = false
+	}
+
+	// The rest of their requests still is
+	{
+		rec := doPost(t, r, "http://example.com/protected_resource_401", map[string]int{"name": 7})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.False(t, called, "Handler should not have been called")
+	}
+
+	// Other operations are still authenticated
+	{
+		rec := doGet(t, r, "http://example.com/protected_resource_401")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.False(t, called, "Handler should not have been called")
+	}
+
+	// Even when every error is reported at once
+	options.Options.MultiError = true
+	{
+		rec := doPost(t, r, "http://example.com/protected_resource_401", map[string]string{"name": "Rex"})
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.True(t, called, "Handler should have been called")
+	}
+}
+
+func TestOapiRequestValidatorUnsupportedMediaType(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /json:
+    post:
+      responses:
+        '204':
+          description: No content
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+  /images:
+    post:
+      responses:
+        '204':
+          description: No content
+      requestBody:
+        content:
+          image/*: {}
+  /anything:
+    post:
+      responses:
+        '204':
+          description: No content
+      requestBody:
+        content:
+          '*/*': {}
+`))
+	require.NoError(t, err, "Error initializing swagger")
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidator(swagger))
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+	r.Post("/json", handler)
+	r.Post("/images", handler)
+	r.Post("/anything", handler)
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{"exact match", "/json", "application/json", `{}`, http.StatusNoContent},
+		{"match with parameters", "/json", "application/json; charset=utf-8", `{}`, http.StatusNoContent},
+		{"unknown media type", "/json", "text/plain", `{}`, http.StatusUnsupportedMediaType},
+		{"malformed media type", "/json", "application/", `{}`, http.StatusUnsupportedMediaType},
+		{"missing media type", "/json", "", `{}`, http.StatusBadRequest},
+		{"subtype wildcard", "/images", "image/png", "png", http.StatusNoContent},
+		{"subtype wildcard mismatch", "/images", "text/plain", "png", http.StatusUnsupportedMediaType},
+		{"full wildcard", "/anything", "text/plain", "text", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := testutil.NewRequest().Post(tt.path).WithHost("example.com").WithBody([]byte(tt.body))
+			if tt.contentType != "" {
+				req = req.WithContentType(tt.contentType)
+			}
+			rec := req.GoWithHTTPHandler(t, r).Recorder
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestOapiRequestValidatorMaxBodyBytes(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /text:
+    post:
+      responses:
+        '204':
+          description: No content
+      requestBody:
+        content:
+          text/plain:
+            schema:
+              type: string
+`))
+	require.NoError(t, err, "Error initializing swagger")
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorWithOptions(swagger, &Options{MaxBodyBytes: 8}))
+	r.Post("/text", func(w http.ResponseWriter, r *http.Request) {
+		// The body is still readable after validation.
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.Header().Set("X-Body", string(body))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name          string
+		body          string
+		unknownLength bool
+		wantStatus    int
+	}{
+		{"empty", "", false, http.StatusNoContent},
+		{"below limit", "short", false, http.StatusNoContent},
+		{"at limit", "12345678", false, http.StatusNoContent},
+		{"above limit", "123456789", false, http.StatusRequestEntityTooLarge},
+		{"unknown length below limit", "short", true, http.StatusNoContent},
+		{"unknown length above limit", "123456789", true, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/text", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "text/plain")
+			if tt.unknownLength {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantStatus == http.StatusNoContent {
+				assert.Equal(t, tt.body, rec.Header().Get("X-Body"))
+			}
+		})
+	}
+}
+
+func TestOapiRequestValidatorEmptyBody(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /text:
+    post:
+      responses:
+        '204':
+          description: No content
+      requestBody:
+        content:
+          text/plain:
+            schema:
+              type: string
+              minLength: 2
+`))
+	require.NoError(t, err, "Error initializing swagger")
+
+	for _, options := range []*Options{nil, {MaxBodyBytes: 8}} {
+		// Empty bodies are left as they are, rather than replaced.
+		body := io.NopCloser(&countingReader{Reader: strings.NewReader("")})
+		req := httptest.NewRequest(http.MethodPost, "http://example.com/text", nil)
+		req.Header.Set("Content-Type", "text/plain")
+		req.Body = body
+		assert.NoError(t, ValidateRequest(swagger, req, options))
+		assert.Equal(t, body, req.Body)
+
+		// Other bodies are still validated, and readable afterwards.
+		req = httptest.NewRequest(http.MethodPost, "http://example.com/text", nil)
+		req.Header.Set("Content-Type", "text/plain")
+		req.Body = io.NopCloser(strings.NewReader("a"))
+		assert.Error(t, ValidateRequest(swagger, req, options))
+
+		req.Body = io.NopCloser(strings.NewReader("abc"))
+		assert.NoError(t, ValidateRequest(swagger, req, options))
+		data, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", string(data))
+	}
+}
+
+// countingReader counts the reads of its body.
+type countingReader struct {
+	io.Reader
+	reads int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	c.reads++
+	return c.Reader.Read(p)
+}
+
+func TestOapiRequestValidatorMaxContentLength(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /required:
+    post:
+      responses:
+        '204':
+          description: No content
+      requestBody:
+        required: true
+        content:
+          text/plain:
+            schema:
+              type: string
+  /optional:
+    post:
+      responses:
+        '204':
+          description: No content
+      requestBody:
+        content:
+          text/plain:
+            schema:
+              type: string
+`))
+	require.NoError(t, err, "Error initializing swagger")
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorWithOptions(swagger, &Options{MaxContentLength: 8}))
+	noContent := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+	r.Post("/required", noContent)
+	r.Post("/optional", noContent)
+
+	tests := []struct {
+		name          string
+		path          string
+		body          string
+		contentLength int64
+		wantStatus    int
+	}{
+		{"at limit", "/required", "12345678", 8, http.StatusNoContent},
+		{"above limit", "/required", "123456789", 9, http.StatusRequestEntityTooLarge},
+		{"declared above limit", "/required", "short", 1 << 20, http.StatusRequestEntityTooLarge},
+		{"unknown length", "/required", "short", -1, http.StatusLengthRequired},
+		{"unknown length of optional body", "/optional", "short", -1, http.StatusNoContent},
+		{"optional body above limit", "/optional", "123456789", 9, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := &countingReader{Reader: strings.NewReader(tt.body)}
+			req := httptest.NewRequest(http.MethodPost, "http://example.com"+tt.path, body)
+			req.Header.Set("Content-Type", "text/plain")
+			req.ContentLength = tt.contentLength
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantStatus != http.StatusNoContent {
+				// Rejected requests are rejected before their body is read.
+				assert.Zero(t, body.reads)
+			}
+		})
+	}
+}
+
+// endlessItems is a body continuing an array forever.
+type endlessItems struct{}
+
+func (endlessItems) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = "0,"[i%2]
+	}
+	return len(p) - len(p)%2, nil
+}
+
+func TestOapiRequestValidatorMaxItems(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /list:
+    post:
+      responses:
+        '204':
+          description: No content
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: array
+              maxItems: 3
+              items:
+                type: array
+                items:
+                  type: integer
+`))
+	require.NoError(t, err, "Error initializing swagger")
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidator(swagger))
+	r.Post("/list", func(w http.ResponseWriter, r *http.Request) {
+		// The body is still readable after validation.
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.Header().Set("X-Body", string(body))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name       string
+		body       string
+		endless    bool
+		wantStatus int
+		wantBody   string
+	}{
+		{"empty array", `[]`, false, http.StatusNoContent, ""},
+		{"at limit", `[[1, 2, 3, 4], [], [[5]]]`, false, http.StatusBadRequest, "Field must be set to integer"},
+		{"nested at limit", `[[1, 2, 3, 4], [], [5]]`, false, http.StatusNoContent, ""},
+		{"above limit", `[[], [], [], []]`, false, http.StatusBadRequest, "request body has more than 3 items"},
+		{"endless", `[[], [], [], `, true, http.StatusBadRequest, "request body has more than 3 items"},
+		{"not an array", `{"a": [1, 2, 3, 4]}`, false, http.StatusBadRequest, "Field must be set to array"},
+		{"invalid", `[[], [}`, false, http.StatusBadRequest, "request body has an error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader = strings.NewReader(tt.body)
+			if tt.endless {
+				body = io.MultiReader(body, endlessItems{})
+			}
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/list", body)
+			req.ContentLength = -1
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+			if tt.wantStatus == http.StatusNoContent {
+				assert.Equal(t, tt.body, rec.Header().Get("X-Body"))
+			}
+		})
+	}
+}
+
+func TestOapiRequestValidatorRequestID(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /text:
+    post:
+      responses:
+        '204':
+          description: No content
+      requestBody:
+        required: true
+        content:
+          text/plain:
+            schema:
+              type: string
+`))
+	require.NoError(t, err, "Error initializing swagger")
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorWithOptions(swagger, &Options{RequestIDHeader: "X-Request-ID"}))
+	r.Post("/text", func(w http.ResponseWriter, r *http.Request) {
+		// Handlers see the ID the request was given.
+		w.Header().Set("X-Handler-ID", r.Header.Get("X-Request-ID"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	send := func(contentType, id, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "http://example.com/text", strings.NewReader("text"))
+		req.Header.Set("Content-Type", contentType)
+		if id != "" {
+			req.Header.Set("X-Request-ID", id)
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("error with ID", func(t *testing.T) {
+		rec := send("application/json", "abc-123", "application/json")
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+		assert.Equal(t, "abc-123", rec.Header().Get("X-Request-ID"))
+
+		var body ValidationError
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "abc-123", body.RequestID)
+	})
+
+	t.Run("error without ID", func(t *testing.T) {
+		rec := send("application/json", "", "application/json")
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+		id := rec.Header().Get("X-Request-ID")
+		assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, id)
+
+		var body ValidationError
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, id, body.RequestID)
+	})
+
+	t.Run("plain text error", func(t *testing.T) {
+		rec := send("application/json", "abc-123", "")
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+		assert.Contains(t, rec.Body.String(), "(request ID abc-123)")
+	})
+
+	t.Run("valid request", func(t *testing.T) {
+		rec := send("text/plain", "", "")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		id := rec.Header().Get("X-Request-ID")
+		assert.NotEmpty(t, id)
+		assert.Equal(t, id, rec.Header().Get("X-Handler-ID"))
+	})
+}
+
+func TestOapiRequestValidatorMultiError(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /pets:
+    post:
+      parameters:
+        - name: limit
+          in: query
+          required: true
+          schema:
+            type: integer
+        - name: dry
+          in: query
+          schema:
+            type: boolean
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                age:
+                  type: integer
+      responses:
+        '204':
+          description: No content
+`))
+	require.NoError(t, err, "Error initializing swagger")
+
+	options := &Options{}
+	options.Options.MultiError = true
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorWithOptions(swagger, options))
+	r.Post("/pets", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	send := func(query, body, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "http://example.com/pets"+query, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("json", func(t *testing.T) {
+		rec := send("?dry=maybe", `{"age": "old"}`, "application/json")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		var body ValidationError
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body), rec.Body.String())
+		// The missing parameter, the invalid one, and both body errors.
+		require.Len(t, body.Errors, 4, body.Errors)
+		assert.Equal(t, body.Errors[0], body.Message)
+		assert.Contains(t, body.Errors[0], `"limit"`)
+		assert.Contains(t, body.Errors[1], `"dry"`)
+		joined := strings.Join(body.Errors[2:], "\n")
+		assert.Contains(t, joined, "/name")
+		assert.Contains(t, joined, "/age")
+		assert.GreaterOrEqual(t, len(body.Details), len(body.Errors))
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		rec := send("?dry=maybe", `{}`, "application/xml")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		var body ValidationError
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &body), rec.Body.String())
+		assert.Len(t, body.Errors, 3, body.Errors)
+	})
+
+	t.Run("plain text", func(t *testing.T) {
+		rec := send("?dry=maybe", `{"name": "Rex"}`, "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		assert.Len(t, lines, 2, rec.Body.String())
+	})
+
+	t.Run("single error", func(t *testing.T) {
+		rec := send("?limit=1", `{}`, "application/json")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		var body ValidationError
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body), rec.Body.String())
+		assert.Empty(t, body.Errors)
+		assert.Contains(t, body.Message, `"name"`)
+	})
+
+	t.Run("valid request", func(t *testing.T) {
+		rec := send("?limit=1", `{"name": "Rex"}`, "")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+}
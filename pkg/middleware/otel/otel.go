@@ -0,0 +1,61 @@
+// Package otel implements a middleware which annotates the current
+// OpenTelemetry span of a request with the operation of the spec it matches.
+package otel
+
+import (
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The attributes set on the span of every request.
+const (
+	// RouteKey is the path template of the matched operation, like
+	// /pets/{id}, or "unknown" when no operation matches.
+	RouteKey = attribute.Key("http.route")
+	// OperationIDKey is the operationId of the matched operation.
+	OperationIDKey = attribute.Key("api.operation_id")
+	// TagKey is the first tag of the matched operation.
+	TagKey = attribute.Key("api.tag")
+)
+
+// UnknownRoute is the RouteKey of requests which match no operation.
+const UnknownRoute = "unknown"
+
+// OapiTracingMiddleware creates middleware which sets the RouteKey,
+// OperationIDKey and TagKey attributes on the span in the request context.
+// Spans are started by a tracing middleware, like otelhttp, which must run
+// before this one.
+func OapiTracingMiddleware(swagger *openapi3.T) func(next http.Handler) http.Handler {
+	router, err := gorillamux.NewRouter(swagger)
+	if err != nil {
+		panic(err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			span := trace.SpanFromContext(r.Context())
+
+			route, _, err := router.FindRoute(r)
+			if err != nil {
+				span.SetAttributes(RouteKey.String(UnknownRoute))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			attrs := []attribute.KeyValue{
+				RouteKey.String(route.Path),
+				OperationIDKey.String(route.Operation.OperationID),
+			}
+			if len(route.Operation.Tags) != 0 {
+				attrs = append(attrs, TagKey.String(route.Operation.Tags[0]))
+			}
+			span.SetAttributes(attrs...)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
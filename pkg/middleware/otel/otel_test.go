@@ -0,0 +1,95 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/discord-gophers/goapi-gen/pkg/testutil"
+)
+
+// recordingSpan records the attributes set on it.
+type recordingSpan struct {
+	trace.Span
+	attrs []attribute.KeyValue
+}
+
+func (s *recordingSpan) SetAttributes(kv ...attribute.KeyValue) {
+	s.attrs = append(s.attrs, kv...)
+}
+
+func TestOapiTracingMiddleware(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+      tags: [pets, animals]
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        '204':
+          description: No content
+  /health:
+    get:
+      operationId: health
+      responses:
+        '204':
+          description: No content
+`))
+	require.NoError(t, err, "Error initializing swagger")
+
+	tests := []struct {
+		path string
+		want []attribute.KeyValue
+	}{
+		{"/pets/42", []attribute.KeyValue{
+			RouteKey.String("/pets/{id}"),
+			OperationIDKey.String("getPet"),
+			TagKey.String("pets"),
+		}},
+		{"/health", []attribute.KeyValue{
+			RouteKey.String("/health"),
+			OperationIDKey.String("health"),
+		}},
+		{"/owners", []attribute.KeyValue{
+			RouteKey.String(UnknownRoute),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			span := &recordingSpan{Span: trace.SpanFromContext(context.Background())}
+
+			r := chi.NewRouter()
+			r.Use(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					next.ServeHTTP(w, r.WithContext(trace.ContextWithSpan(r.Context(), span)))
+				})
+			})
+			r.Use(OapiTracingMiddleware(swagger))
+			r.Get("/*", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			rec := testutil.NewRequest().Get(tt.path).WithHost("example.com").GoWithHTTPHandler(t, r).Recorder
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+			assert.Equal(t, tt.want, span.attrs)
+		})
+	}
+}
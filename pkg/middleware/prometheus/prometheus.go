@@ -0,0 +1,115 @@
+// Package prometheus implements a request validation middleware which records
+// Prometheus metrics about the requests it validates.
+package prometheus
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/discord-gophers/goapi-gen/pkg/middleware"
+)
+
+// The names of the recorded metrics.
+const (
+	// DurationMetric is the histogram of the time spent validating requests.
+	DurationMetric = "oapi_request_validation_duration_seconds"
+	// ErrorsMetric counts the requests which failed validation, by the
+	// status_code of their response.
+	ErrorsMetric = "oapi_request_validation_errors_total"
+	// RequestsMetric counts the requests, by the route and method of the
+	// operation they match.
+	RequestsMetric = "oapi_requests_total"
+)
+
+// UnknownRoute is the route label of requests which match no operation.
+const UnknownRoute = "unknown"
+
+// OapiRequestValidatorWithMetrics creates middleware which validates requests
+// like middleware.OapiRequestValidatorWithOptions, and records the
+// DurationMetric, ErrorsMetric and RequestsMetric metrics with reg, or with
+// prometheus.DefaultRegisterer if reg is nil. Requests are labelled by the
+// path template of their operation, like /pets/{id}, rather than their path,
+// so that the number of series stays bounded.
+//
+// It panics if the metrics are already registered with reg.
+func OapiRequestValidatorWithMetrics(swagger *openapi3.T, options *middleware.Options, reg prometheus.Registerer) func(next http.Handler) http.Handler {
+	router, err := gorillamux.NewRouter(swagger)
+	if err != nil {
+		panic(err)
+	}
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	duration := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    DurationMetric,
+		Help:    "Time spent validating requests against the OpenAPI spec.",
+		Buckets: prometheus.DefBuckets,
+	})
+	validationErrors := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: ErrorsMetric,
+		Help: "Requests which failed validation against the OpenAPI spec.",
+	}, []string{"status_code"})
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: RequestsMetric,
+		Help: "Requests received, by the operation of the OpenAPI spec they match.",
+	}, []string{"route", "method"})
+	reg.MustRegister(duration, validationErrors, requests)
+
+	validator := middleware.OapiRequestValidatorWithOptions(swagger, options)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := UnknownRoute
+			if rt, _, err := router.FindRoute(r); err == nil {
+				route = rt.Path
+			}
+			requests.WithLabelValues(route, r.Method).Inc()
+
+			start := time.Now()
+			validated := false
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			validator(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				validated = true
+				duration.Observe(time.Since(start).Seconds())
+				// The handler writes to w, so that it keeps its optional
+				// interfaces, like http.Flusher.
+				next.ServeHTTP(w, r)
+			})).ServeHTTP(sw, r)
+
+			if !validated {
+				duration.Observe(time.Since(start).Seconds())
+				// The middleware answers the CORS preflight requests itself,
+				// which only failed if they weren't allowed.
+				if !isPreflight(r, options) || sw.status >= http.StatusBadRequest {
+					validationErrors.WithLabelValues(strconv.Itoa(sw.status)).Inc()
+				}
+			}
+		})
+	}
+}
+
+// isPreflight returns whether r is a CORS preflight request, which the
+// middleware answers when options.CORS is set.
+func isPreflight(r *http.Request, options *middleware.Options) bool {
+	return options != nil && options.CORS != nil &&
+		r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// statusWriter records the status code of the validation errors.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
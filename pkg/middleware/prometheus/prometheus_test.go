@@ -0,0 +1,116 @@
+package prometheus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	promtest "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/discord-gophers/goapi-gen/pkg/middleware"
+	"github.com/discord-gophers/goapi-gen/pkg/testutil"
+)
+
+const testSchema = `openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        '204':
+          description: No content
+`
+
+func TestOapiRequestValidatorWithMetrics(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err)
+
+	reg := prometheus.NewRegistry()
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorWithMetrics(swagger, nil, reg))
+	r.Get("/pets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	r.Get("/other", func(w http.ResponseWriter, r *http.Request) {})
+
+	for _, path := range []string{"/pets/1", "/pets/2", "/pets/cat", "/other"} {
+		testutil.NewRequest().Get(path).WithHost("example.com").GoWithHTTPHandler(t, r)
+	}
+
+	metrics, err := reg.Gather()
+	require.NoError(t, err)
+	var observed uint64
+	for _, m := range metrics {
+		if m.GetName() == DurationMetric {
+			require.Len(t, m.GetMetric(), 1)
+			observed = m.GetMetric()[0].GetHistogram().GetSampleCount()
+		}
+	}
+	assert.Equal(t, uint64(4), observed)
+
+	// Series are labelled by route, not by path.
+
+	expected := `
+# HELP oapi_requests_total Requests received, by the operation of the OpenAPI spec they match.
+# TYPE oapi_requests_total counter
+oapi_requests_total{method="GET",route="/pets/{id}"} 3
+oapi_requests_total{method="GET",route="unknown"} 1
+# HELP oapi_request_validation_errors_total Requests which failed validation against the OpenAPI spec.
+# TYPE oapi_request_validation_errors_total counter
+oapi_request_validation_errors_total{status_code="400"} 1
+oapi_request_validation_errors_total{status_code="404"} 1
+`
+	assert.NoError(t, promtest.GatherAndCompare(reg, strings.NewReader(expected), RequestsMetric, ErrorsMetric))
+}
+
+func TestOapiRequestValidatorWithMetricsCORS(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err)
+
+	reg := prometheus.NewRegistry()
+	options := &middleware.Options{CORS: &middleware.CORSOptions{AllowedOrigins: []string{"https://app.example.com"}}}
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorWithMetrics(swagger, options, reg))
+	r.Get("/pets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	preflight := func(path, origin string) int {
+		req := httptest.NewRequest(http.MethodOptions, "http://example.com"+path, nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusOK, preflight("/pets/1", "https://app.example.com"))
+	assert.Equal(t, http.StatusOK, preflight("/pets/2", "https://app.example.com"))
+	// Preflights which aren't allowed are still counted as errors.
+	assert.Equal(t, http.StatusForbidden, preflight("/pets/1", "https://evil.example.com"))
+	assert.Equal(t, http.StatusNotFound, preflight("/other", "https://app.example.com"))
+
+	expected := `
+# HELP oapi_request_validation_errors_total Requests which failed validation against the OpenAPI spec.
+# TYPE oapi_request_validation_errors_total counter
+oapi_request_validation_errors_total{status_code="403"} 1
+oapi_request_validation_errors_total{status_code="404"} 1
+`
+	assert.NoError(t, promtest.GatherAndCompare(reg, strings.NewReader(expected), ErrorsMetric))
+}
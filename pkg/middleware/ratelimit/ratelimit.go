@@ -0,0 +1,164 @@
+// Package ratelimit implements a middleware which enforces the rate limits
+// set on the operations of the spec with the x-rate-limit extension:
+//
+//	paths:
+//	  /pets:
+//	    get:
+//	      x-rate-limit:
+//	        requests: 100
+//	        window: 1m
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// Extension is the extension of the operations setting their rate limit.
+const Extension = "x-rate-limit"
+
+// RateLimitStore counts the requests made to the operations.
+type RateLimitStore interface {
+	// Allow records a request for key, and returns whether fewer than limit
+	// requests were allowed for key in the last window.
+	Allow(key string, limit int, window time.Duration) bool
+}
+
+// limit is the value of the x-rate-limit extension.
+type limit struct {
+	Requests int    `json:"requests"`
+	Window   string `json:"window"`
+
+	window time.Duration
+}
+
+// OapiRateLimitMiddleware creates middleware which enforces the x-rate-limit
+// of the operations of swagger with store, responding with an HTTP/429 and a
+// Retry-After header to the requests over the limit. The requests of an
+// operation are counted together, with the method and path template of the
+// operation as key, like "GET /pets/{id}". Requests which match no operation,
+// or an operation without x-rate-limit, are passed on.
+//
+// It panics if an x-rate-limit is invalid.
+func OapiRateLimitMiddleware(swagger *openapi3.T, store RateLimitStore) func(next http.Handler) http.Handler {
+	router, err := gorillamux.NewRouter(swagger)
+	if err != nil {
+		panic(err)
+	}
+	limits, err := operationLimits(swagger)
+	if err != nil {
+		panic(err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, _, err := router.FindRoute(r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			l, ok := limits[route.Operation]
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !store.Allow(route.Method+" "+route.Path, l.Requests, l.window) {
+				retryAfter := int(math.Ceil(l.window.Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// operationLimits returns the x-rate-limit of the operations of swagger
+// which have one.
+func operationLimits(swagger *openapi3.T) (map[*openapi3.Operation]limit, error) {
+	limits := map[*openapi3.Operation]limit{}
+	for path, pathItem := range swagger.Paths {
+		for method, op := range pathItem.Operations() {
+			ext, ok := op.Extensions[Extension]
+			if !ok {
+				continue
+			}
+			l, err := parseLimit(ext)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for %q of %s %s: %w", Extension, method, path, err)
+			}
+			limits[op] = l
+		}
+	}
+	return limits, nil
+}
+
+func parseLimit(ext interface{}) (limit, error) {
+	raw, ok := ext.(json.RawMessage)
+	if !ok {
+		return limit{}, fmt.Errorf("failed to convert type: %T", ext)
+	}
+	var l limit
+	if err := json.Unmarshal(raw, &l); err != nil {
+		return limit{}, fmt.Errorf("failed to unmarshal json: %w", err)
+	}
+	if l.Requests <= 0 {
+		return limit{}, fmt.Errorf("requests: %d is not positive", l.Requests)
+	}
+	window, err := time.ParseDuration(l.Window)
+	if err != nil {
+		return limit{}, fmt.Errorf("window: %w", err)
+	}
+	if window <= 0 {
+		return limit{}, fmt.Errorf("window: %s is not positive", l.Window)
+	}
+	l.window = window
+	return l, nil
+}
+
+// MemoryStore is a RateLimitStore counting the requests in memory, in fixed
+// windows starting with the first request of a key. It is safe for
+// concurrent use, but isn't shared by several instances of a server.
+type MemoryStore struct {
+	mu      sync.Mutex
+	windows map[string]*window
+	now     func() time.Time
+}
+
+// window counts the requests of a key since its start.
+type window struct {
+	start time.Time
+	count int
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{windows: map[string]*window{}, now: time.Now}
+}
+
+// Allow implements RateLimitStore.
+func (s *MemoryStore) Allow(key string, limit int, d time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	w, ok := s.windows[key]
+	if !ok || now.Sub(w.start) >= d {
+		w = &window{start: now}
+		s.windows[key] = w
+	}
+	if w.count >= limit {
+		return false
+	}
+	w.count++
+	return true
+}
@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+      x-rate-limit:
+        requests: 2
+        window: 1m30s
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        '204':
+          description: No content
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        '204':
+          description: No content
+`
+
+func TestOapiRateLimitMiddleware(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Use(OapiRateLimitMiddleware(swagger, NewMemoryStore()))
+	r.Get("/pets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	r.Get("/pets", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil))
+		return rec
+	}
+
+	// The requests of the operation are counted together, whatever their id.
+	assert.Equal(t, http.StatusNoContent, get("/pets/1").Code)
+	assert.Equal(t, http.StatusNoContent, get("/pets/2").Code)
+	rec := get("/pets/3")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "90", rec.Header().Get("Retry-After"))
+
+	// Operations without x-rate-limit, and unknown routes, aren't limited.
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusNoContent, get("/pets").Code)
+	}
+	assert.Equal(t, http.StatusNotFound, get("/owners").Code)
+}
+
+func TestInvalidRateLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"requests", "{requests: 0, window: 1m}"},
+		{"window", "{requests: 10, window: soon}"},
+		{"negative window", "{requests: 10, window: -1s}"},
+		{"type", "100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			swagger, err := openapi3.NewLoader().LoadFromData([]byte(`openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+paths:
+  /pets:
+    get:
+      x-rate-limit: ` + tt.value + `
+      responses:
+        '204':
+          description: No content
+`))
+			require.NoError(t, err)
+			assert.Panics(t, func() { OapiRateLimitMiddleware(swagger, NewMemoryStore()) })
+		})
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	assert.True(t, s.Allow("a", 2, time.Minute))
+	assert.True(t, s.Allow("a", 2, time.Minute))
+	assert.False(t, s.Allow("a", 2, time.Minute))
+	assert.True(t, s.Allow("b", 2, time.Minute))
+
+	now = now.Add(59 * time.Second)
+	assert.False(t, s.Allow("a", 2, time.Minute))
+	now = now.Add(time.Second)
+	assert.True(t, s.Allow("a", 2, time.Minute))
+}
@@ -0,0 +1,297 @@
+// Package replay implements a request validation middleware which records
+// the requests passing validation in a HAR log, for them to be replayed
+// while debugging, eg. by importing the log in a browser or an HTTP client.
+package replay
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/discord-gophers/goapi-gen/pkg/middleware"
+)
+
+// Options configures the requests a Recorder records.
+type Options struct {
+	// SampleRate records 1 in SampleRate requests, starting with the first
+	// one. 0 and 1 record every request.
+	SampleRate int
+	// Headers are the names of the headers which are recorded, in any case.
+	// The other headers, like Authorization, are left out, so that the log
+	// doesn't hold credentials.
+	Headers []string
+	// Gzip compresses the log with gzip. Every entry is flushed, so that the
+	// log can be decompressed up to its last entry before it is closed.
+	Gzip bool
+}
+
+// harHeader opens the log, before its first entry.
+const harHeader = `{"log":{"version":"1.2","creator":{"name":"goapi-gen","version":"1.0"},"entries":[`
+
+// errClosed is the error of the requests recorded after the log is closed.
+var errClosed = errors.New("replay: the recorder is closed")
+
+// Recorder writes requests as the entries of a HAR log. It is safe for
+// concurrent use.
+type Recorder struct {
+	options Options
+	headers []string
+
+	mu      sync.Mutex
+	w       io.Writer
+	gz      *gzip.Writer
+	seen    int
+	entries int
+	err     error
+}
+
+// NewRecorder returns a Recorder writing the HAR log to w, which is written
+// its end when the Recorder is closed.
+func NewRecorder(w io.Writer, options Options) *Recorder {
+	rec := &Recorder{options: options, w: w}
+	for _, name := range options.Headers {
+		rec.headers = append(rec.headers, http.CanonicalHeaderKey(name))
+	}
+	if options.Gzip {
+		rec.gz = gzip.NewWriter(w)
+		rec.w = rec.gz
+	}
+	return rec
+}
+
+// OapiRequestValidatorWithReplay creates middleware which validates requests
+// like middleware.OapiRequestValidatorWithOptions, and records the requests
+// passing validation with rec, sampled by its SampleRate, before they are
+// served. The body of a recorded request is replaced by a reader of the same
+// bytes for the handler. The failures to write the log are returned by
+// rec.Close, rather than failing the requests.
+func OapiRequestValidatorWithReplay(swagger *openapi3.T, options *middleware.Options, rec *Recorder) func(next http.Handler) http.Handler {
+	validator := middleware.OapiRequestValidatorWithOptions(swagger, options)
+
+	return func(next http.Handler) http.Handler {
+		return validator(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if rec.sample() {
+				_ = rec.Record(r)
+			}
+			next.ServeHTTP(w, r)
+		}))
+	}
+}
+
+// sample returns whether the next request is recorded, by the SampleRate of
+// the options of rec.
+func (rec *Recorder) sample() bool {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	rec.seen++
+	return rec.options.SampleRate <= 1 || (rec.seen-1)%rec.options.SampleRate == 0
+}
+
+// Record writes r as an entry of the log, regardless of the SampleRate, and
+// replaces its body with a reader of the same bytes. Once writing the log
+// failed, it returns the error without recording further requests.
+func (rec *Recorder) Record(r *http.Request) error {
+	var body []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			return fmt.Errorf("error reading request body: %w", err)
+		}
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	entry, err := json.Marshal(rec.entry(r, body, time.Now()))
+	if err != nil {
+		return fmt.Errorf("error encoding HAR entry: %w", err)
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	if rec.err != nil {
+		return rec.err
+	}
+	prefix := ","
+	if rec.entries == 0 {
+		prefix = harHeader
+	}
+	if err := rec.write(prefix, string(entry)); err != nil {
+		return err
+	}
+	rec.entries++
+	if rec.gz != nil {
+		if err := rec.gz.Flush(); err != nil {
+			rec.err = fmt.Errorf("error compressing HAR log: %w", err)
+			return rec.err
+		}
+	}
+	return nil
+}
+
+// Close writes the end of the log, and flushes it if it is compressed. It
+// doesn't close the io.Writer of rec. It returns the first failure to write
+// the log, if any.
+func (rec *Recorder) Close() error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	if rec.err != nil {
+		if rec.err == errClosed {
+			return nil
+		}
+		return rec.err
+	}
+	if rec.entries == 0 {
+		if err := rec.write(harHeader); err != nil {
+			return err
+		}
+	}
+	if err := rec.write("]}}\n"); err != nil {
+		return err
+	}
+	if rec.gz != nil {
+		if err := rec.gz.Close(); err != nil {
+			return fmt.Errorf("error compressing HAR log: %w", err)
+		}
+	}
+	rec.err = errClosed
+	return nil
+}
+
+// write writes parts to the log, keeping the error of a failure for the
+// next requests.
+func (rec *Recorder) write(parts ...string) error {
+	for _, part := range parts {
+		if _, err := io.WriteString(rec.w, part); err != nil {
+			rec.err = fmt.Errorf("error writing HAR log: %w", err)
+			return rec.err
+		}
+	}
+	return nil
+}
+
+// The entries of a HAR log, as specified by http://www.softwareishard.com/blog/har-12-spec/.
+// Only the requests are recorded, and the entries have an empty response.
+type (
+	entry struct {
+		StartedDateTime string   `json:"startedDateTime"`
+		Time            int      `json:"time"`
+		Request         request  `json:"request"`
+		Response        response `json:"response"`
+		Cache           struct{} `json:"cache"`
+		Timings         timings  `json:"timings"`
+	}
+
+	request struct {
+		Method      string      `json:"method"`
+		URL         string      `json:"url"`
+		HTTPVersion string      `json:"httpVersion"`
+		Cookies     []nameValue `json:"cookies"`
+		Headers     []nameValue `json:"headers"`
+		QueryString []nameValue `json:"queryString"`
+		PostData    *postData   `json:"postData,omitempty"`
+		HeadersSize int         `json:"headersSize"`
+		BodySize    int         `json:"bodySize"`
+	}
+
+	postData struct {
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+		// Encoding is base64 for the bodies which aren't UTF-8 text.
+		Encoding string `json:"_encoding,omitempty"`
+	}
+
+	response struct {
+		Status      int         `json:"status"`
+		StatusText  string      `json:"statusText"`
+		HTTPVersion string      `json:"httpVersion"`
+		Cookies     []nameValue `json:"cookies"`
+		Headers     []nameValue `json:"headers"`
+		Content     content     `json:"content"`
+		RedirectURL string      `json:"redirectURL"`
+		HeadersSize int         `json:"headersSize"`
+		BodySize    int         `json:"bodySize"`
+	}
+
+	content struct {
+		Size     int    `json:"size"`
+		MimeType string `json:"mimeType"`
+	}
+
+	timings struct {
+		Send    int `json:"send"`
+		Wait    int `json:"wait"`
+		Receive int `json:"receive"`
+	}
+
+	nameValue struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+)
+
+// entry returns the HAR entry of r, started at start, whose body is body.
+func (rec *Recorder) entry(r *http.Request, body []byte, start time.Time) entry {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	req := request{
+		Method:      r.Method,
+		URL:         scheme + "://" + r.Host + r.URL.RequestURI(),
+		HTTPVersion: r.Proto,
+		Cookies:     []nameValue{},
+		Headers:     []nameValue{},
+		QueryString: []nameValue{},
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+	for _, name := range rec.headers {
+		for _, value := range r.Header[name] {
+			req.Headers = append(req.Headers, nameValue{Name: name, Value: value})
+		}
+	}
+	query := r.URL.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range query[name] {
+			req.QueryString = append(req.QueryString, nameValue{Name: name, Value: value})
+		}
+	}
+	if len(body) > 0 {
+		req.PostData = &postData{MimeType: r.Header.Get("Content-Type"), Text: string(body)}
+		if !utf8.Valid(body) {
+			req.PostData.Text = base64.StdEncoding.EncodeToString(body)
+			req.PostData.Encoding = "base64"
+		}
+	}
+
+	return entry{
+		StartedDateTime: start.UTC().Format(time.RFC3339Nano),
+		Request:         req,
+		Response: response{
+			Cookies:     []nameValue{},
+			Headers:     []nameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+	}
+}
@@ -0,0 +1,136 @@
+package replay
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+paths:
+  /pets:
+    post:
+      operationId: addPet
+      parameters:
+        - name: dry
+          in: query
+          schema:
+            type: boolean
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+      responses:
+        '204':
+          description: No content
+`
+
+// harLog is the part of a HAR log the tests check.
+type harLog struct {
+	Log struct {
+		Version string `json:"version"`
+		Entries []struct {
+			Request struct {
+				Method      string      `json:"method"`
+				URL         string      `json:"url"`
+				Headers     []nameValue `json:"headers"`
+				QueryString []nameValue `json:"queryString"`
+				PostData    *postData   `json:"postData"`
+			} `json:"request"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+func TestOapiRequestValidatorWithReplay(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf, Options{SampleRate: 2, Headers: []string{"content-type", "X-Request-Id"}})
+
+	var bodies []string
+	handler := OapiRequestValidatorWithReplay(swagger, nil, rec)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	post := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "http://example.com/pets?dry=true", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set("X-Request-Id", "42")
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		return rw.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, post(`{"name": "Rex"}`))
+	assert.Equal(t, http.StatusNoContent, post(`{"name": "Fido"}`))
+	// Invalid requests aren't recorded, nor counted by the sampling.
+	assert.Equal(t, http.StatusBadRequest, post(`{}`))
+	assert.Equal(t, http.StatusNoContent, post(`{"name": "Max"}`))
+	require.NoError(t, rec.Close())
+
+	// The handler reads the bodies of the recorded requests as well.
+	assert.Equal(t, []string{`{"name": "Rex"}`, `{"name": "Fido"}`, `{"name": "Max"}`}, bodies)
+
+	var log harLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log), buf.String())
+	assert.Equal(t, "1.2", log.Log.Version)
+	require.Len(t, log.Log.Entries, 2)
+
+	req := log.Log.Entries[0].Request
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "http://example.com/pets?dry=true", req.URL)
+	assert.Equal(t, []nameValue{{Name: "Content-Type", Value: "application/json"}, {Name: "X-Request-Id", Value: "42"}}, req.Headers)
+	assert.Equal(t, []nameValue{{Name: "dry", Value: "true"}}, req.QueryString)
+	assert.Equal(t, &postData{MimeType: "application/json", Text: `{"name": "Rex"}`}, req.PostData)
+	assert.Equal(t, `{"name": "Max"}`, log.Log.Entries[1].Request.PostData.Text)
+
+	assert.Equal(t, errClosed, rec.Record(httptest.NewRequest(http.MethodGet, "/pets", nil)))
+}
+
+func TestRecorderGzip(t *testing.T) {
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf, Options{Gzip: true})
+	require.NoError(t, rec.Record(httptest.NewRequest(http.MethodPut, "http://example.com/pets", bytes.NewReader([]byte{0xff, 0x00}))))
+	require.NoError(t, rec.Close())
+
+	gz, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	data, err := io.ReadAll(gz)
+	require.NoError(t, err)
+
+	var log harLog
+	require.NoError(t, json.Unmarshal(data, &log), string(data))
+	require.Len(t, log.Log.Entries, 1)
+	// Bodies which aren't text are encoded in base64.
+	assert.Equal(t, &postData{Text: "/wA=", Encoding: "base64"}, log.Log.Entries[0].Request.PostData)
+	assert.Empty(t, log.Log.Entries[0].Request.Headers)
+
+	// Logs without entries are valid too.
+	buf.Reset()
+	require.NoError(t, NewRecorder(&buf, Options{}).Close())
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Empty(t, log.Log.Entries)
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// requestID returns the ID of r, read from the options.RequestIDHeader header,
+// or "" if the option isn't set. Requests without the header are given a new
+// UUID, which is set on r so that handlers log the same ID. The ID is set on
+// the response as well.
+func requestID(w http.ResponseWriter, r *http.Request, options *Options) string {
+	if options == nil || options.RequestIDHeader == "" {
+		return ""
+	}
+
+	id := r.Header.Get(options.RequestIDHeader)
+	if id == "" {
+		id = newUUID()
+		r.Header.Set(options.RequestIDHeader, id)
+	}
+	w.Header().Set(options.RequestIDHeader, id)
+	return id
+}
+
+// newUUID returns a random, version 4, UUID.
+func newUUID() string {
+	var b [16]byte
+	// crypto/rand only fails if the OS has no source of randomness.
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("error generating request ID: %v", err))
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
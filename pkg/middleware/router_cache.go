@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// routerCache maps the hash of a serialized spec to its *routerCacheEntry.
+var routerCache sync.Map
+
+// routerCacheEntry is a compiled router, shared by all middleware created
+// from an equivalent spec.
+type routerCacheEntry struct {
+	mu     sync.Mutex
+	router routers.Router
+	// refs counts the routerHandles which are still reachable. The entry is
+	// evicted from the cache once it drops to zero.
+	refs    int
+	evicted bool
+}
+
+// routerHandle is held by the middleware. It is never referenced by the
+// cache, so once all middleware using a router are unreachable the handles
+// are finalized, and the router (along with its spec) can be collected.
+type routerHandle struct {
+	routers.Router
+}
+
+// FlushRouterCache removes all compiled routers from the cache. Middleware
+// which has already been created keeps using its router.
+func FlushRouterCache() {
+	routerCache.Range(func(key, value interface{}) bool {
+		e := value.(*routerCacheEntry)
+		e.mu.Lock()
+		e.evicted = true
+		routerCache.Delete(key)
+		e.mu.Unlock()
+		return true
+	})
+}
+
+// cachedRouter returns a router for swagger, reusing a previously compiled
+// router of an equivalent spec if there is one.
+func cachedRouter(swagger *openapi3.T) (routers.Router, error) {
+	key, err := specHash(swagger)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		v, _ := routerCache.LoadOrStore(key, &routerCacheEntry{})
+		e := v.(*routerCacheEntry)
+
+		e.mu.Lock()
+		if e.evicted {
+			// The entry is being removed, wait for a fresh one.
+			e.mu.Unlock()
+			continue
+		}
+
+		if e.router == nil {
+			router, err := gorillamux.NewRouter(swagger)
+			if err != nil {
+				e.evicted = true
+				routerCache.Delete(key)
+				e.mu.Unlock()
+				return nil, err
+			}
+			e.router = router
+		}
+		e.refs++
+		e.mu.Unlock()
+
+		h := &routerHandle{Router: e.router}
+		runtime.SetFinalizer(h, func(*routerHandle) {
+			e.release(key)
+		})
+		return h, nil
+	}
+}
+
+// release drops a reference to e, evicting it once it is no longer used.
+func (e *routerCacheEntry) release(key interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.refs--
+	if e.refs == 0 && !e.evicted {
+		e.evicted = true
+		routerCache.Delete(key)
+	}
+}
+
+// specHash returns a key identifying the contents of swagger.
+func specHash(swagger *openapi3.T) (string, error) {
+	data, err := swagger.MarshalJSON()
+	if err != nil {
+		return "", fmt.Errorf("error marshaling swagger: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
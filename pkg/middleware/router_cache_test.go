@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cacheLen() int {
+	n := 0
+	routerCache.Range(func(_, _ interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+func TestCachedRouter(t *testing.T) {
+	FlushRouterCache()
+	defer FlushRouterCache()
+
+	// Load the spec twice, so the cache is keyed by contents and not pointers.
+	swagger1, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+	swagger2, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	r1, err := cachedRouter(swagger1)
+	require.NoError(t, err)
+	r2, err := cachedRouter(swagger2)
+	require.NoError(t, err)
+
+	assert.Same(t, r1.(*routerHandle).Router, r2.(*routerHandle).Router)
+	assert.Equal(t, 1, cacheLen())
+
+	// Flushing gives subsequent calls a freshly compiled router.
+	FlushRouterCache()
+	assert.Equal(t, 0, cacheLen())
+
+	r3, err := cachedRouter(swagger1)
+	require.NoError(t, err)
+	assert.NotSame(t, r1.(*routerHandle).Router, r3.(*routerHandle).Router)
+}
+
+func TestCachedRouterRelease(t *testing.T) {
+	FlushRouterCache()
+	defer FlushRouterCache()
+
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	r1, err := cachedRouter(swagger)
+	require.NoError(t, err)
+	r2, err := cachedRouter(swagger)
+	require.NoError(t, err)
+	// Keep the handles alive, so their finalizers don't interfere.
+	defer runtime.KeepAlive(r1)
+	defer runtime.KeepAlive(r2)
+
+	key, err := specHash(swagger)
+	require.NoError(t, err)
+	v, ok := routerCache.Load(key)
+	require.True(t, ok)
+	e := v.(*routerCacheEntry)
+
+	// The entry is only evicted once the last handle has been released.
+	e.release(key)
+	assert.Equal(t, 1, cacheLen())
+	e.release(key)
+	assert.Equal(t, 0, cacheLen())
+}
+
+func BenchmarkFindRouteForRequest(b *testing.B) {
+	FlushRouterCache()
+	defer FlushRouterCache()
+
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(b, err, "Error initializing swagger")
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/resource", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := FindRouteForRequest(req, swagger); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidateRequest(b *testing.B) {
+	FlushRouterCache()
+	defer FlushRouterCache()
+
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(b, err, "Error initializing swagger")
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/resource?id=50", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := ValidateRequest(swagger, req, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
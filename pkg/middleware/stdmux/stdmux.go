@@ -0,0 +1,233 @@
+// Package stdmux implements the request validation middleware for the
+// http.ServeMux of the standard library. Requests are matched to the
+// operations of the spec by their path alone, the way http.ServeMux matches
+// patterns, rather than by the gorilla/mux router of the net/http middleware,
+// which also matches the host and scheme of the servers of the spec.
+package stdmux
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+
+	"github.com/discord-gophers/goapi-gen/pkg/middleware"
+)
+
+// Options to customize request validation. It is the same type used by the
+// net/http middleware.
+type Options = middleware.Options
+
+// OapiRequestValidator Creates middleware to validate request by swagger spec.
+func OapiRequestValidator(swagger *openapi3.T) func(next http.Handler) http.Handler {
+	return OapiRequestValidatorWithOptions(swagger, nil)
+}
+
+// OapiRequestValidatorWithOptions Creates middleware to validate request by
+// swagger spec. It panics if the paths of the spec can't be matched, see
+// NewRouter.
+func OapiRequestValidatorWithOptions(swagger *openapi3.T, options *Options) func(next http.Handler) http.Handler {
+	router, err := NewRouter(swagger)
+	if err != nil {
+		panic(err)
+	}
+	return middleware.OapiRequestValidatorWithRouter(router, options)
+}
+
+// Router finds the operations of the spec matching requests, by their path.
+// The path of a server of the spec, like /v1 for https://example.com/v1, is a
+// prefix of the paths of its operations.
+//
+// As with http.ServeMux patterns, path parameters match a whole path
+// segment, and when several paths match a request the most specific one
+// wins: /pets/mine is preferred over /pets/{id}.
+type Router struct {
+	spec   *openapi3.T
+	routes []*route
+}
+
+// route is a path of the spec, prefixed by the path of a server.
+type route struct {
+	server   *openapi3.Server
+	path     string
+	pathItem *openapi3.PathItem
+	segments []segment
+}
+
+// segment is a path segment of a route: either a literal, or a parameter
+// matching any segment.
+type segment struct {
+	literal string
+	param   string // The name of the parameter, for path parameters
+	isParam bool   // Whether the segment matches any value
+}
+
+// NewRouter returns a Router for the paths of swagger. Paths whose parameters
+// aren't whole path segments, like /books/{id}.json, are an error.
+func NewRouter(swagger *openapi3.T) (*Router, error) {
+	servers := swagger.Servers
+	if len(servers) == 0 {
+		servers = openapi3.Servers{nil}
+	}
+
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	r := &Router{spec: swagger}
+	for _, server := range servers {
+		var base []segment
+		if server != nil {
+			var err error
+			if base, err = serverSegments(server.URL); err != nil {
+				return nil, err
+			}
+		}
+		for _, path := range paths {
+			segments, err := parseSegments(path, true)
+			if err != nil {
+				return nil, err
+			}
+			r.routes = append(r.routes, &route{
+				server:   server,
+				path:     path,
+				pathItem: swagger.Paths[path],
+				segments: append(append([]segment{}, base...), segments...),
+			})
+		}
+	}
+	return r, nil
+}
+
+// serverSegments returns the segments of the path of the server URL, whose
+// variables match any segment. The scheme and host of the URL, which may
+// have variables too, are ignored.
+func serverSegments(serverURL string) ([]segment, error) {
+	path := serverURL
+	if i := strings.Index(path, "://"); i != -1 {
+		path = path[i+len("://"):]
+		if i = strings.IndexByte(path, '/'); i == -1 {
+			return nil, nil
+		}
+		path = path[i:]
+	}
+	if i := strings.IndexAny(path, "?#"); i != -1 {
+		path = path[:i]
+	}
+	if path = strings.TrimSuffix(path, "/"); path == "" {
+		return nil, nil
+	}
+	return parseSegments(path, false)
+}
+
+// parseSegments splits the path template into segments. The names of the
+// parameters are kept when params is set.
+func parseSegments(path string, params bool) ([]segment, error) {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	segments := make([]segment, len(parts))
+	for i, part := range parts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") && strings.Count(part, "{") == 1 && strings.Count(part, "}") == 1 {
+			segments[i].isParam = true
+			if params {
+				segments[i].param = part[1 : len(part)-1]
+			}
+			continue
+		}
+		if strings.ContainsAny(part, "{}") {
+			return nil, fmt.Errorf("path %s: parameters must be whole path segments", path)
+		}
+		literal, err := url.PathUnescape(part)
+		if err != nil {
+			return nil, fmt.Errorf("path %s: %w", path, err)
+		}
+		segments[i].literal = literal
+	}
+	return segments, nil
+}
+
+// FindRoute returns the route of the operation matching req, and the values
+// of its path parameters. It fails with routers.ErrPathNotFound when no path
+// matches, and with routers.ErrMethodNotAllowed when no operation of the
+// matching paths has the method of req.
+func (r *Router) FindRoute(req *http.Request) (*routers.Route, map[string]string, error) {
+	parts := strings.Split(strings.TrimPrefix(req.URL.EscapedPath(), "/"), "/")
+	for i, part := range parts {
+		unescaped, err := url.PathUnescape(part)
+		if err != nil {
+			return nil, nil, routers.ErrPathNotFound
+		}
+		parts[i] = unescaped
+	}
+
+	var best *route
+	var pathFound bool
+	for _, rt := range r.routes {
+		if !rt.matches(parts) {
+			continue
+		}
+		pathFound = true
+		if rt.pathItem.GetOperation(req.Method) == nil {
+			continue
+		}
+		if best == nil || rt.moreSpecific(best) {
+			best = rt
+		}
+	}
+	if best == nil {
+		if pathFound {
+			return nil, nil, routers.ErrMethodNotAllowed
+		}
+		return nil, nil, routers.ErrPathNotFound
+	}
+
+	pathParams := map[string]string{}
+	for i, s := range best.segments {
+		if s.param != "" {
+			pathParams[s.param] = parts[i]
+		}
+	}
+	return &routers.Route{
+		Spec:      r.spec,
+		Server:    best.server,
+		Path:      best.path,
+		PathItem:  best.pathItem,
+		Method:    req.Method,
+		Operation: best.pathItem.GetOperation(req.Method),
+	}, pathParams, nil
+}
+
+// matches returns if the unescaped segments of a request path match rt.
+func (rt *route) matches(parts []string) bool {
+	if len(parts) != len(rt.segments) {
+		return false
+	}
+	for i, s := range rt.segments {
+		if s.isParam {
+			if parts[i] == "" {
+				return false
+			}
+			continue
+		}
+		if s.literal != parts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// moreSpecific returns if rt is preferred over other, which matches the same
+// paths: a literal segment wins over a parameter, from the left.
+func (rt *route) moreSpecific(other *route) bool {
+	for i, s := range rt.segments {
+		if s.isParam != other.segments[i].isParam {
+			return !s.isParam
+		}
+	}
+	return false
+}
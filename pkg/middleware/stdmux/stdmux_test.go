@@ -0,0 +1,116 @@
+package stdmux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/discord-gophers/goapi-gen/pkg/testutil"
+)
+
+const testSchema = `openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: https://{region}.example.com/v1
+    variables:
+      region:
+        default: eu
+paths:
+  /pets:
+    post:
+      operationId: addPet
+      responses:
+        '204':
+          description: No content
+  /pets/mine:
+    get:
+      operationId: getMyPet
+      responses:
+        '204':
+          description: No content
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        '204':
+          description: No content
+  /owners/{name}:
+    get:
+      operationId: getOwner
+      parameters:
+        - name: name
+          in: path
+          required: true
+          schema:
+            type: string
+            maxLength: 5
+      responses:
+        '204':
+          description: No content
+`
+
+func TestOapiRequestValidator(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	h := OapiRequestValidator(swagger)(mux)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{"valid", http.MethodGet, "/v1/pets/1", http.StatusNoContent},
+		{"invalid parameter", http.MethodGet, "/v1/pets/cat", http.StatusBadRequest},
+		{"literal before parameter", http.MethodGet, "/v1/pets/mine", http.StatusNoContent},
+		{"escaped parameter", http.MethodGet, "/v1/owners/a%2Fb", http.StatusNoContent},
+		{"long parameter", http.MethodGet, "/v1/owners/abcdef", http.StatusBadRequest},
+		{"without server path", http.MethodGet, "/pets/1", http.StatusNotFound},
+		{"unknown path", http.MethodGet, "/v1/owners", http.StatusNotFound},
+		{"method not allowed", http.MethodDelete, "/v1/pets/1", http.StatusMethodNotAllowed},
+		{"method of another path", http.MethodGet, "/v1/pets", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The host doesn't match the server of the spec.
+			rec := testutil.NewRequest().WithMethod(tt.method, tt.path).WithHost("localhost").GoWithHTTPHandler(t, h).Recorder
+			assert.Equal(t, tt.want, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestNewRouter(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err)
+
+	router, err := NewRouter(swagger)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, "/v1/owners/a%20b", nil)
+	require.NoError(t, err)
+	route, pathParams, err := router.FindRoute(req)
+	require.NoError(t, err)
+	assert.Equal(t, "/owners/{name}", route.Path)
+	assert.Equal(t, "getOwner", route.Operation.OperationID)
+	assert.Equal(t, map[string]string{"name": "a b"}, pathParams)
+
+	swagger.Paths["/books/{id}.json"] = &openapi3.PathItem{}
+	_, err = NewRouter(swagger)
+	assert.EqualError(t, err, "path /books/{id}.json: parameters must be whole path segments")
+}
@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// stripUndocumentedFields re-encodes the buffered JSON body of bw without
+// the properties which its schema in the spec doesn't describe. Bodies which
+// aren't JSON, or have no schema, are left untouched.
+func stripUndocumentedFields(route *routers.Route, bw *bufferedResponseWriter) {
+	schema := responseSchema(route, bw)
+	if schema == nil || bw.body.Len() == 0 {
+		return
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(bw.body.Bytes()))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return
+	}
+
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(stripValue(value, []*openapi3.Schema{schema})); err != nil {
+		return
+	}
+	// Encode terminates the body with a newline, which handlers encoding
+	// their response with json.Marshal don't.
+	if !bytes.HasSuffix(bw.body.Bytes(), []byte("\n")) {
+		body.Truncate(body.Len() - 1)
+	}
+
+	bw.body = body
+	if bw.Header().Get("Content-Length") != "" {
+		bw.Header().Set("Content-Length", strconv.Itoa(body.Len()))
+	}
+}
+
+// responseSchema returns the schema of the JSON response written to bw for
+// route, or nil if the response isn't JSON or isn't described by the spec.
+func responseSchema(route *routers.Route, bw *bufferedResponseWriter) *openapi3.Schema {
+	contentType := bw.Header().Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+		return nil
+	}
+	if route.Operation == nil {
+		return nil
+	}
+
+	responseRef := route.Operation.Responses.Get(bw.status())
+	if responseRef == nil {
+		responseRef = route.Operation.Responses.Default()
+	}
+	if responseRef == nil || responseRef.Value == nil {
+		return nil
+	}
+	content := responseRef.Value.Content.Get(contentType)
+	if content == nil || content.Schema == nil {
+		return nil
+	}
+	return content.Schema.Value
+}
+
+// stripValue removes the properties of the objects of value which none of
+// schemas describe. A property is kept if any of schemas, or the schemas they
+// are composed of with allOf, anyOf or oneOf, describes it, so that a value
+// matching one alternative keeps the properties of that alternative. Objects
+// whose schemas have no properties at all are free-form, and kept as a whole.
+func stripValue(value interface{}, schemas []*openapi3.Schema) interface{} {
+	schemas = flattenSchemas(schemas, nil, map[*openapi3.Schema]bool{})
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		var constrained, additional bool
+		for _, s := range schemas {
+			if len(s.Properties) > 0 || s.AdditionalPropertiesAllowed != nil || s.AdditionalProperties != nil {
+				constrained = true
+			}
+			if s.AdditionalPropertiesAllowed != nil && *s.AdditionalPropertiesAllowed {
+				additional = true
+			}
+		}
+		if !constrained {
+			return v
+		}
+		for name, prop := range v {
+			var propSchemas []*openapi3.Schema
+			for _, s := range schemas {
+				if p := s.Properties[name]; p != nil && p.Value != nil {
+					propSchemas = append(propSchemas, p.Value)
+				} else if s.AdditionalProperties != nil && s.AdditionalProperties.Value != nil {
+					propSchemas = append(propSchemas, s.AdditionalProperties.Value)
+				}
+			}
+			switch {
+			case len(propSchemas) > 0:
+				v[name] = stripValue(prop, propSchemas)
+			case !additional:
+				delete(v, name)
+			}
+		}
+		return v
+
+	case []interface{}:
+		var itemSchemas []*openapi3.Schema
+		for _, s := range schemas {
+			if s.Items != nil && s.Items.Value != nil {
+				itemSchemas = append(itemSchemas, s.Items.Value)
+			}
+		}
+		if len(itemSchemas) == 0 {
+			return v
+		}
+		for i, item := range v {
+			v[i] = stripValue(item, itemSchemas)
+		}
+		return v
+
+	default:
+		return v
+	}
+}
+
+// flattenSchemas appends schemas and the schemas they are composed of to
+// flat, once each.
+func flattenSchemas(schemas []*openapi3.Schema, flat []*openapi3.Schema, seen map[*openapi3.Schema]bool) []*openapi3.Schema {
+	for _, s := range schemas {
+		if s == nil || seen[s] {
+			continue
+		}
+		seen[s] = true
+		flat = append(flat, s)
+
+		for _, refs := range []openapi3.SchemaRefs{s.AllOf, s.AnyOf, s.OneOf} {
+			for _, ref := range refs {
+				if ref != nil {
+					flat = flattenSchemas([]*openapi3.Schema{ref.Value}, flat, seen)
+				}
+			}
+		}
+	}
+	return flat
+}
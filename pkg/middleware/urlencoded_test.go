@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/discord-gophers/goapi-gen/pkg/testutil"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOapiRequestValidatorURLEncoded(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /login:
+    post:
+      responses:
+        '204':
+          description: No content
+      requestBody:
+        required: true
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                  maxLength: 5
+                count:
+                  type: integer
+                  minimum: 1
+                tags:
+                  type: array
+                  items:
+                    type: boolean
+                meta:
+                  type: object
+                  required: [id]
+                  properties:
+                    id:
+                      type: integer
+`))
+	require.NoError(t, err, "Error initializing swagger")
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidator(swagger))
+	r.Post("/login", func(w http.ResponseWriter, r *http.Request) {
+		// The body is still readable after validation.
+		require.NoError(t, r.ParseForm())
+		assert.NotEmpty(t, r.PostForm.Get("name"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name       string
+		form       url.Values
+		wantStatus int
+	}{
+		{"valid", url.Values{"name": {"pet"}, "count": {"2"}, "tags": {"true", "false"}, "meta": {`{"id":1}`}}, http.StatusNoContent},
+		{"missing field", url.Values{"count": {"2"}}, http.StatusBadRequest},
+		{"too long", url.Values{"name": {"kitten"}}, http.StatusBadRequest},
+		{"below minimum", url.Values{"name": {"pet"}, "count": {"0"}}, http.StatusBadRequest},
+		{"not a number", url.Values{"name": {"pet"}, "count": {"two"}}, http.StatusBadRequest},
+		{"not a boolean", url.Values{"name": {"pet"}, "tags": {"yes"}}, http.StatusBadRequest},
+		{"invalid object", url.Values{"name": {"pet"}, "meta": {`{}`}}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := testutil.NewRequest().Post("/login").WithHost("example.com").
+				WithContentType("application/x-www-form-urlencoded; charset=utf-8").
+				WithBody([]byte(tt.form.Encode())).
+				GoWithHTTPHandler(t, r).Recorder
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
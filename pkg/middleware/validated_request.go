@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// validatedRequestKey is the context key of the ValidatedRequest.
+type validatedRequestKey struct{}
+
+// ValidatedRequest describes a request which passed validation, so handlers
+// can reuse its route without finding it again.
+type ValidatedRequest struct {
+	// Route is the route of the spec which matched the request.
+	Route *routers.Route
+	// PathParams holds the raw values of the path parameters, by name.
+	PathParams map[string]string
+	// QueryParams holds the query parameters of the request.
+	QueryParams url.Values
+}
+
+// ValidatedRequestFromContext returns the ValidatedRequest stored in ctx by a
+// validator with Options.InjectValidated, or nil if there is none.
+func ValidatedRequestFromContext(ctx context.Context) *ValidatedRequest {
+	v, _ := ctx.Value(validatedRequestKey{}).(*ValidatedRequest)
+	return v
+}
+
+// withValidatedRequest returns a copy of ctx holding the ValidatedRequest of
+// input.
+func withValidatedRequest(ctx context.Context, input *openapi3filter.RequestValidationInput) context.Context {
+	v := &ValidatedRequest{
+		Route:       input.Route,
+		PathParams:  input.PathParams,
+		QueryParams: input.QueryParams,
+	}
+	if v.QueryParams == nil {
+		v.QueryParams = input.Request.URL.Query()
+	}
+	return context.WithValue(ctx, validatedRequestKey{}, v)
+}
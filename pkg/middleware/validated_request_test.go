@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOapiRequestValidatorInjectValidated(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(`openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: http://example.com
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: fields
+          in: query
+          schema:
+            type: string
+      responses:
+        '204':
+          description: No content
+`))
+	require.NoError(t, err, "Error initializing swagger")
+
+	for _, inject := range []bool{true, false} {
+		r := chi.NewRouter()
+		r.Use(OapiRequestValidatorWithOptions(swagger, &Options{InjectValidated: inject}))
+
+		var validated *ValidatedRequest
+		r.Get("/pets/{id}", func(w http.ResponseWriter, r *http.Request) {
+			validated = ValidatedRequestFromContext(r.Context())
+		})
+
+		rec := doGet(t, r, "http://example.com/pets/42?fields=name")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		if !inject {
+			assert.Nil(t, validated)
+			continue
+		}
+		require.NotNil(t, validated)
+		assert.Equal(t, "getPet", validated.Route.Operation.OperationID)
+		assert.Equal(t, map[string]string{"id": "42"}, validated.PathParams)
+		assert.Equal(t, "name", validated.QueryParams.Get("fields"))
+	}
+}
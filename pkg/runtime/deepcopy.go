@@ -0,0 +1,37 @@
+package runtime
+
+import "encoding/json"
+
+// DeepCopyJSONValue returns a deep copy of v, a value of a free-form schema
+// as decoded by encoding/json: maps of strings to values and slices of
+// values are copied, along with json.RawMessage. Other values, like the
+// strings, numbers and booleans JSON decodes to, are returned as they are.
+func DeepCopyJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		out := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			out[key] = DeepCopyJSONValue(val)
+		}
+		return out
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		out := make([]interface{}, len(v))
+		for i, val := range v {
+			out[i] = DeepCopyJSONValue(val)
+		}
+		return out
+	case json.RawMessage:
+		if v == nil {
+			return v
+		}
+		return append(json.RawMessage(nil), v...)
+	default:
+		return v
+	}
+}
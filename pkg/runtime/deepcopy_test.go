@@ -0,0 +1,29 @@
+package runtime
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeepCopyJSONValue(t *testing.T) {
+	var v interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"name": "Rex", "tags": ["dog", {"color": "brown"}], "age": 3}`), &v))
+
+	c := DeepCopyJSONValue(v)
+	assert.Equal(t, v, c)
+
+	c.(map[string]interface{})["name"] = "Max"
+	c.(map[string]interface{})["tags"].([]interface{})[1].(map[string]interface{})["color"] = "black"
+	assert.Equal(t, "Rex", v.(map[string]interface{})["name"])
+	assert.Equal(t, "brown", v.(map[string]interface{})["tags"].([]interface{})[1].(map[string]interface{})["color"])
+
+	raw := json.RawMessage(`{}`)
+	rawCopy := DeepCopyJSONValue(raw).(json.RawMessage)
+	rawCopy[0] = '['
+	assert.Equal(t, json.RawMessage(`{}`), raw)
+
+	assert.Nil(t, DeepCopyJSONValue(nil))
+	assert.Equal(t, 1.5, DeepCopyJSONValue(1.5))
+}
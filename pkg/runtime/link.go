@@ -0,0 +1,154 @@
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// LinkValue evaluates expr, the runtime expression of a parameter of an
+// OpenAPI link, against rsp, the response the link is defined on, and body,
+// the body of rsp. path is the path of the operation of rsp, like
+// /users/{id}, which $request.path expressions are evaluated against. The
+// value is returned as JSON, to be bound with BindLinkValue.
+//
+// The supported expressions are $url, $method, $statusCode, and
+// $request.path, $request.query, $request.header, $response.header and
+// $response.body, with an optional JSON pointer into the body.
+func LinkValue(expr, path string, rsp *http.Response, body []byte) (json.RawMessage, error) {
+	if rsp == nil {
+		return nil, errors.New("no response")
+	}
+	if expr == "$statusCode" {
+		return json.Marshal(rsp.StatusCode)
+	}
+	if expr == "$response.body" || strings.HasPrefix(expr, "$response.body#") {
+		var pointer string
+		if i := strings.IndexByte(expr, '#'); i != -1 {
+			var err error
+			if pointer, err = url.PathUnescape(expr[i+1:]); err != nil {
+				return nil, fmt.Errorf("invalid JSON pointer: %w", err)
+			}
+		}
+		return ResolveJSONPointer(body, pointer)
+	}
+	if name := strings.TrimPrefix(expr, "$response.header."); name != expr {
+		if _, ok := rsp.Header[http.CanonicalHeaderKey(name)]; !ok {
+			return nil, fmt.Errorf("no header %s in the response", name)
+		}
+		return json.Marshal(rsp.Header.Get(name))
+	}
+
+	req := rsp.Request
+	if req == nil {
+		return nil, errors.New("no request in the response")
+	}
+	switch {
+	case expr == "$url":
+		return json.Marshal(req.URL.String())
+	case expr == "$method":
+		return json.Marshal(req.Method)
+	case strings.HasPrefix(expr, "$request.path."):
+		value, err := pathSegment(path, req.URL.Path, strings.TrimPrefix(expr, "$request.path."))
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(value)
+	case strings.HasPrefix(expr, "$request.query."):
+		name := strings.TrimPrefix(expr, "$request.query.")
+		query := req.URL.Query()
+		if _, ok := query[name]; !ok {
+			return nil, fmt.Errorf("no query parameter %s in the request", name)
+		}
+		return json.Marshal(query.Get(name))
+	case strings.HasPrefix(expr, "$request.header."):
+		name := strings.TrimPrefix(expr, "$request.header.")
+		if _, ok := req.Header[http.CanonicalHeaderKey(name)]; !ok {
+			return nil, fmt.Errorf("no header %s in the request", name)
+		}
+		return json.Marshal(req.Header.Get(name))
+	}
+	return nil, fmt.Errorf("unsupported runtime expression %s", expr)
+}
+
+// pathSegment returns the segment of requestPath matching the path parameter
+// name of path, counting from the end, since requestPath may have the prefix
+// of the server URL.
+func pathSegment(path, requestPath, name string) (string, error) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	requested := strings.Split(strings.Trim(requestPath, "/"), "/")
+	for i, segment := range segments {
+		if segment != "{"+name+"}" {
+			continue
+		}
+		j := len(requested) - len(segments) + i
+		if j < 0 {
+			break
+		}
+		return requested[j], nil
+	}
+	return "", fmt.Errorf("no path parameter %s in %s", name, requestPath)
+}
+
+// ResolveJSONPointer returns the value of the JSON document doc pointed to
+// by pointer, as defined by RFC 6901. An empty pointer is the whole document.
+func ResolveJSONPointer(doc []byte, pointer string) (json.RawMessage, error) {
+	if pointer == "" {
+		return json.RawMessage(doc), nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+
+	value := json.RawMessage(doc)
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(value, &object); err == nil {
+			v, ok := object[token]
+			if !ok {
+				return nil, fmt.Errorf("no %q in %s", token, pointer)
+			}
+			value = v
+			continue
+		}
+		var array []json.RawMessage
+		if err := json.Unmarshal(value, &array); err != nil {
+			return nil, fmt.Errorf("%q of %s isn't in an object or array", token, pointer)
+		}
+		i, err := strconv.Atoi(token)
+		if err != nil || i < 0 || i >= len(array) {
+			return nil, fmt.Errorf("no index %q in %s", token, pointer)
+		}
+		value = array[i]
+	}
+	return value, nil
+}
+
+// BindLinkValue binds value, returned by LinkValue, to dest, a parameter of
+// the linked operation. The value is decoded as JSON, falling back to
+// BindStringToObject with the string or the JSON text of value, so that eg.
+// the string of a header can be bound to an integer, or a number of a body
+// to a string.
+func BindLinkValue(value json.RawMessage, dest interface{}) error {
+	err := json.Unmarshal(value, dest)
+	if err == nil {
+		return nil
+	}
+	var s string
+	if json.Unmarshal(value, &s) != nil {
+		if len(value) != 0 && (value[0] == '{' || value[0] == '[') {
+			return err
+		}
+		s = string(value)
+	}
+	if BindStringToObject(s, dest) != nil {
+		return err
+	}
+	return nil
+}
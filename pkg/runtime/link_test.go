@@ -0,0 +1,83 @@
+package runtime
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkValue(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/v1/users/42/orders?dry-run=true", nil)
+	req.Header.Set("X-Request-ID", "abc")
+	rsp := &http.Response{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Location": []string{"/orders/7"}},
+		Request:    req,
+	}
+	body := []byte(`{"id":7,"user":{"id":"42"},"items":[{"sku":"a/b"}],"a/b":true}`)
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"$statusCode", `201`},
+		{"$method", `"POST"`},
+		{"$url", `"http://example.com/v1/users/42/orders?dry-run=true"`},
+		{"$request.path.userId", `"42"`},
+		{"$request.query.dry-run", `"true"`},
+		{"$request.header.x-request-id", `"abc"`},
+		{"$response.header.Location", `"/orders/7"`},
+		{"$response.body#/id", `7`},
+		{"$response.body#/user/id", `"42"`},
+		{"$response.body#/items/0/sku", `"a/b"`},
+		{"$response.body#/a~1b", `true`},
+		{"$response.body", string(body)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			value, err := LinkValue(tt.expr, "/users/{userId}/orders", rsp, body)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(value))
+		})
+	}
+
+	for _, expr := range []string{
+		"$request.body#/id",
+		"$request.path.orderId",
+		"$request.query.missing",
+		"$response.header.Missing",
+		"$response.body#/user/name",
+		"$response.body#/items/1",
+		"$response.body#/id/0",
+	} {
+		_, err := LinkValue(expr, "/users/{userId}/orders", rsp, body)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestBindLinkValue(t *testing.T) {
+	var i int
+	require.NoError(t, BindLinkValue(json.RawMessage(`7`), &i))
+	assert.Equal(t, 7, i)
+	require.NoError(t, BindLinkValue(json.RawMessage(`"8"`), &i))
+	assert.Equal(t, 8, i)
+	assert.Error(t, BindLinkValue(json.RawMessage(`"eight"`), &i))
+
+	var s string
+	require.NoError(t, BindLinkValue(json.RawMessage(`42`), &s))
+	assert.Equal(t, "42", s)
+
+	var p *string
+	require.NoError(t, BindLinkValue(json.RawMessage(`"a"`), &p))
+	require.NotNil(t, p)
+	assert.Equal(t, "a", *p)
+
+	var ids []int
+	require.NoError(t, BindLinkValue(json.RawMessage(`[1,2]`), &ids))
+	assert.Equal(t, []int{1, 2}, ids)
+	assert.Error(t, BindLinkValue(json.RawMessage(`{"id":1}`), &s))
+}
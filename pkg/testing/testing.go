@@ -0,0 +1,62 @@
+// Package testing asserts in tests that requests and responses conform to
+// an OpenAPI spec, with the validators of the middleware package. As its
+// name is the one of the standard testing package, it is imported with
+// another one:
+//
+//	import oapitesting "github.com/discord-gophers/goapi-gen/pkg/testing"
+//
+//	req := httptest.NewRequest(http.MethodGet, "/pets?limit=10", nil)
+//	oapitesting.AssertRequestValid(t, swagger, req)
+package testing
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/discord-gophers/goapi-gen/pkg/middleware"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// AssertRequestValid fails t with t.Error if req doesn't conform to spec,
+// and returns the error req failed validation with, so that the test can go
+// on with assertions of its own, or stop with t.FailNow. The body of req is
+// replaced by a reader of the same bytes, so that it can be read again.
+// middleware.ValidateRequest returns the error without failing t, for
+// assertions of invalid requests.
+func AssertRequestValid(t testing.TB, spec *openapi3.T, req *http.Request) error {
+	t.Helper()
+	err := middleware.ValidateRequest(spec, req, nil)
+	if err != nil {
+		t.Error(failure("request", req, err))
+	}
+	return err
+}
+
+// AssertResponseValid fails t with t.Error if resp, the response to req,
+// doesn't conform to spec, and returns the error resp failed validation
+// with, like AssertRequestValid. The body of resp is replaced by a reader of
+// the same bytes, so that it can be read again. middleware.ValidateResponse
+// returns the error without failing t, for assertions of invalid responses.
+func AssertResponseValid(t testing.TB, spec *openapi3.T, req *http.Request, resp *http.Response) error {
+	t.Helper()
+	err := middleware.ValidateResponse(spec, req, resp, nil)
+	if err != nil {
+		t.Error(failure(fmt.Sprintf("HTTP/%d response", resp.StatusCode), req, err))
+	}
+	return err
+}
+
+// failure returns the message of err, which what of req failed validation
+// with, along with the details of a *middleware.ValidationError, one per
+// line.
+func failure(what string, req *http.Request, err error) string {
+	msg := fmt.Sprintf("%s of %s %s doesn't conform to the spec", what, req.Method, req.URL.RequestURI())
+	var verr *middleware.ValidationError
+	if !errors.As(err, &verr) || len(verr.Details) == 0 {
+		return msg + ": " + err.Error()
+	}
+	return msg + ":\n\t" + strings.Join(verr.Details, "\n\t")
+}
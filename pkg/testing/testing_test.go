@@ -0,0 +1,127 @@
+package testing
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/discord-gophers/goapi-gen/pkg/middleware"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `openapi: "3.0.3"
+info:
+  version: 1.0.0
+  title: TestServer
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            maximum: 100
+      responses:
+        '200':
+          description: pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: object
+                  required: [name]
+                  properties:
+                    name:
+                      type: string
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+      responses:
+        '204':
+          description: added
+`
+
+// recorder records the errors of the assertions, instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Error(args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func TestAssertRequestValid(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader(`{"name": "Rex"}`))
+	req.Header.Set("Content-Type", "application/json")
+	assert.NoError(t, AssertRequestValid(t, swagger, req))
+	// The body can be read again.
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"name": "Rex"}`, string(body))
+
+	req = httptest.NewRequest(http.MethodGet, "/pets?limit=1000", nil)
+	rec := &recorder{TB: t}
+	err = AssertRequestValid(rec, swagger, req)
+	// The test goes on with the error, to make assertions on.
+	var verr *middleware.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, http.StatusBadRequest, verr.StatusCode)
+	require.Len(t, rec.errors, 1)
+	msg := rec.errors[0]
+	assert.True(t, strings.HasPrefix(msg, "request of GET /pets?limit=1000 doesn't conform to the spec:\n\t"), msg)
+	assert.Contains(t, msg, `parameter "limit" in query has an error`)
+}
+
+func TestAssertResponseValid(t *testing.T) {
+	swagger, err := openapi3.NewLoader().LoadFromData([]byte(testSchema))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	rec.WriteHeader(http.StatusOK)
+	rec.WriteString(`[{"name": "Rex"}]`)
+	resp := rec.Result()
+	assert.NoError(t, AssertResponseValid(t, swagger, req, resp))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `[{"name": "Rex"}]`, string(body))
+
+	rec = httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	rec.WriteHeader(http.StatusOK)
+	rec.WriteString(`[{"tag": "dog"}]`)
+	r := &recorder{TB: t}
+	err = AssertResponseValid(r, swagger, req, rec.Result())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `property "name" is missing`)
+	require.Len(t, r.errors, 1)
+	assert.Contains(t, r.errors[0], "HTTP/200 response of GET /pets doesn't conform to the spec:\n\t")
+
+	// Requests without an operation have no response to validate against.
+	err = middleware.ValidateResponse(swagger, httptest.NewRequest(http.MethodGet, "/owners", nil), rec.Result(), nil)
+	var verr *middleware.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, http.StatusNotFound, verr.StatusCode)
+}
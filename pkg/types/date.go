@@ -1,7 +1,9 @@
 package types
 
 import (
+	"database/sql/driver"
 	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -11,7 +13,8 @@ const DateFormat = "2006-01-02"
 // Date represents a time that must conform DateFormat.
 //
 // Date implements the JSON.Marshaler and JSON.Unmarshaler interfaces, and
-// validates that date matches DateFormat.
+// validates that date matches DateFormat. It also implements the sql.Scanner
+// and driver.Valuer interfaces, so that it can be stored in a date column.
 type Date struct {
 	time.Time
 }
@@ -28,7 +31,32 @@ func (d *Date) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
-	parsed, err := time.Parse(DateFormat, dateStr)
+	return d.parse(dateStr)
+}
+
+func (d Date) String() string {
+	return d.Time.Format(DateFormat)
+}
+
+// Scan implements the sql.Scanner interface.
+func (d *Date) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case time.Time:
+		d.Time = src
+	case string:
+		return d.parse(src)
+	case []byte:
+		return d.parse(string(src))
+	case nil:
+		d.Time = time.Time{}
+	default:
+		return fmt.Errorf("date: cannot scan type %T", src)
+	}
+	return nil
+}
+
+func (d *Date) parse(s string) error {
+	parsed, err := time.Parse(DateFormat, s)
 	if err != nil {
 		return err
 	}
@@ -36,6 +64,7 @@ func (d *Date) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (d Date) String() string {
-	return d.Time.Format(DateFormat)
+// Value implements the driver.Valuer interface.
+func (d Date) Value() (driver.Value, error) {
+	return d.Time.Format(DateFormat), nil
 }
@@ -52,3 +52,22 @@ func TestDate_Stringer(t *testing.T) {
 		assert.Equal(t, "2019-04-01", fmt.Sprintf("%v", d))
 	})
 }
+
+func TestDate_Scan(t *testing.T) {
+	testDate := time.Date(2019, 4, 1, 0, 0, 0, 0, time.UTC)
+	for _, src := range []interface{}{testDate, "2019-04-01", []byte("2019-04-01")} {
+		var d Date
+		assert.NoError(t, d.Scan(src))
+		assert.Equal(t, testDate, d.Time)
+	}
+
+	var d Date
+	assert.Error(t, d.Scan("2019-04-01T00:00:00Z"))
+	assert.Error(t, d.Scan(42))
+}
+
+func TestDate_Value(t *testing.T) {
+	v, err := Date{time.Date(2019, 4, 1, 0, 0, 0, 0, time.UTC)}.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "2019-04-01", v)
+}
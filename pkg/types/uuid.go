@@ -0,0 +1,32 @@
+package types
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// UUID represents a string of the uuid format, used for it with --sql-types.
+//
+// UUID implements the sql.Scanner and driver.Valuer interfaces, so that it
+// can be stored in a database column of a text or uuid type.
+type UUID string
+
+// Scan implements the sql.Scanner interface.
+func (u *UUID) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case string:
+		*u = UUID(src)
+	case []byte:
+		*u = UUID(src)
+	case nil:
+		*u = ""
+	default:
+		return fmt.Errorf("uuid: cannot scan type %T", src)
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (u UUID) Value() (driver.Value, error) {
+	return string(u), nil
+}
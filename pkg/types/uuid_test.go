@@ -0,0 +1,25 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUUID_Scan(t *testing.T) {
+	testUUID := UUID("c0d9f5d4-2b5c-4f0e-9a3a-6f3d3c1b2a10")
+	for _, src := range []interface{}{string(testUUID), []byte(testUUID)} {
+		var u UUID
+		assert.NoError(t, u.Scan(src))
+		assert.Equal(t, testUUID, u)
+	}
+
+	var u UUID
+	assert.Error(t, u.Scan(42))
+}
+
+func TestUUID_Value(t *testing.T) {
+	v, err := UUID("c0d9f5d4-2b5c-4f0e-9a3a-6f3d3c1b2a10").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "c0d9f5d4-2b5c-4f0e-9a3a-6f3d3c1b2a10", v)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// specMediaTypes are the media types of specs fetched over HTTP. text/plain
+// and application/octet-stream are allowed, as static file servers often
+// don't know better for YAML files.
+var specMediaTypes = []string{
+	"application/json",
+	"application/yaml",
+	"application/x-yaml",
+	"text/yaml",
+	"text/x-yaml",
+	"application/vnd.oai.openapi",
+	"text/plain",
+	"application/octet-stream",
+}
+
+// isRemoteSpec returns if the spec at path has to be fetched over HTTP.
+func isRemoteSpec(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// specClient returns the client which fetches remote specs, and the files
+// they reference.
+func specClient(timeout time.Duration, maxRedirects int) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return nil
+		},
+	}
+}
+
+// parseRemoteSwagger fetches the spec at rawURL with client. Relative
+// references of the spec are fetched from the same server.
+func parseRemoteSwagger(rawURL string, client *http.Client) (*openapi3.T, error) {
+	location, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %v", err)
+	}
+
+	data, err := fetchSpec(client, location)
+	if err != nil {
+		return nil, err
+	}
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(_ *openapi3.Loader, location *url.URL) ([]byte, error) {
+		return fetchSpec(client, location)
+	}
+	return loadSwagger(loader, data, location)
+}
+
+// fetchSpec fetches the document at location with client. Responses which
+// can't be a spec, such as HTML error pages, are rejected by their media type.
+func fetchSpec(client *http.Client, location *url.URL) ([]byte, error) {
+	resp, err := client.Get(location.String())
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch %s: %v", location, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch %s: %s", location, resp.Status)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || !isSpecMediaType(mediaType) {
+			return nil, fmt.Errorf("could not fetch %s: unsupported Content-Type %q", location, contentType)
+		}
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch %s: %v", location, err)
+	}
+	return data, nil
+}
+
+// isSpecMediaType returns if mediaType is one of specMediaTypes, or a
+// structured syntax suffixed JSON or YAML type like
+// application/vnd.oai.openapi+json.
+func isSpecMediaType(mediaType string) bool {
+	for _, t := range specMediaTypes {
+		if mediaType == t {
+			return true
+		}
+	}
+	return strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+yaml")
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/discord-gophers/goapi-gen/pkg/codegen"
+	"github.com/discord-gophers/goapi-gen/pkg/config"
+	"github.com/getkin/kin-openapi/openapi3"
+	specyaml "github.com/ghodss/yaml"
+	"github.com/urfave/cli/v2"
+)
+
+// rootSpecNames are the names of the root file of a spec split into the
+// files of a directory.
+var rootSpecNames = []string{"openapi.yaml", "openapi.yml", "openapi.json"}
+
+// stdinSpec is the spec argument reading the spec from stdin, as in
+// curl https://api.example.com/openapi.yaml | goapi-gen --package=api -
+const stdinSpec = "-"
+
+// specFile returns the spec to load: the argument of c, or the root file of
+// specDir. It is empty when the spec is read from stdin, without an argument
+// or with an argument of stdinSpec.
+func specFile(c *cli.Context, specDir string) (string, error) {
+	if specDir == "" {
+		if file := c.Args().First(); file != stdinSpec {
+			return file, nil
+		}
+		return "", nil
+	}
+	if c.Args().Len() != 0 {
+		return "", errors.New("only one of a spec file and a spec directory can be set")
+	}
+	return findRootSpec(specDir)
+}
+
+// findRootSpec returns the root file of the spec in dir, which references
+// the other files.
+func findRootSpec(dir string) (string, error) {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("could not open spec directory: %v", err)
+	}
+	if !fi.IsDir() {
+		return "", fmt.Errorf("spec directory %s is not a directory", dir)
+	}
+
+	var found []string
+	for _, name := range rootSpecNames {
+		path := filepath.Join(dir, name)
+		if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+			found = append(found, path)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("no root file in spec directory %s, expected one of %s", dir, strings.Join(rootSpecNames, ", "))
+	case 1:
+		return found[0], nil
+	default:
+		return "", fmt.Errorf("several root files in spec directory %s: %s", dir, strings.Join(found, ", "))
+	}
+}
+
+// loadSpec loads the spec in file, which is fetched when it's a URL, and
+// read from stdin when it's empty or stdinSpec.
+func loadSpec(file string, cfg *config.Config) (*openapi3.T, error) {
+	switch {
+	case isRemoteSpec(file):
+		return parseRemoteSwagger(file, specClient(cfg.HTTPTimeout, *cfg.MaxRedirects))
+	case file != "" && file != stdinSpec:
+		return parseSwaggerFile(file)
+	default:
+		return parseSwagger(os.Stdin)
+	}
+}
+
+// bundle writes the spec given to the bundle command as a single file, with
+// the parts it references from other files moved into it. It is written as
+// JSON when the output file, or the spec when writing to stdout, is JSON,
+// and as YAML otherwise.
+func bundle(c *cli.Context) error {
+	file, err := specFile(c, c.String(SpecDirKey))
+	if err != nil {
+		return err
+	}
+	maxRedirects := c.Int(MaxRedirectsKey)
+	swagger, err := loadSpec(file, &config.Config{HTTPTimeout: c.Duration(HTTPTimeoutKey), MaxRedirects: &maxRedirects})
+	if err != nil {
+		return fmt.Errorf("could not load spec: %v", err)
+	}
+	codegen.Bundle(swagger, nil)
+
+	out := c.String("out")
+	data, err := json.MarshalIndent(swagger, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode spec: %v", err)
+	}
+	format := file
+	if out != "" {
+		format = out
+	}
+	if filepath.Ext(format) != ".json" {
+		if data, err = specyaml.JSONToYAML(data); err != nil {
+			return fmt.Errorf("could not encode spec: %v", err)
+		}
+	} else {
+		data = append(data, '\n')
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = os.WriteFile(out, data, 0o644)
+	}
+	if err != nil {
+		return fmt.Errorf("could not write spec: %v", err)
+	}
+	return nil
+}